@@ -181,42 +181,50 @@ export const GET_USER_PROFILE = `
 export const GET_PROJECTS = `
   query GetProjects {
     projects {
-      id
-      name
-      description
-      color
-      status
-      priority
-      startDate
-      endDate
-      isArchived
-      analytics {
-        totalTasks
-        completedTasks
-        overdueTasks
-        completionRate
-        timeSpent
-        xpEarned
-      }
-      tasks {
-        id
-        title
-        status
-        priority
-        xpValue
-        dueDate
-        completedAt
-      }
-      collaborators {
-        id
-        role
-        user {
+      edges {
+        node {
           id
-          firstName
-          lastName
-          avatarUrl
+          name
+          description
+          color
+          status
+          priority
+          startDate
+          endDate
+          isArchived
+          analytics {
+            totalTasks
+            completedTasks
+            overdueTasks
+            completionRate
+            timeSpent
+            xpEarned
+          }
+          tasks {
+            id
+            title
+            status
+            priority
+            xpValue
+            dueDate
+            completedAt
+          }
+          collaborators {
+            id
+            role
+            user {
+              id
+              firstName
+              lastName
+              avatarUrl
+            }
+          }
         }
       }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
     }
   }
 `
@@ -224,48 +232,56 @@ export const GET_PROJECTS = `
 export const GET_TASKS = `
   query GetTasks($status: TaskStatus, $projectId: ID, $sprintId: ID) {
     tasks(status: $status, projectId: $projectId, sprintId: $sprintId) {
-      id
-      title
-      description
-      status
-      priority
-      xpValue
-      estimatedDuration
-      actualDuration
-      tags
-      dueDate
-      completedAt
-      isArchived
-      project {
-        id
-        name
-        color
-      }
-      sprint {
-        id
-        name
-      }
-      pomodoroSessions {
-        id
-        duration
-        completed
-        startTime
-        endTime
-        focusScore
-      }
-      subtasks {
-        id
-        title
-        completed
-      }
-      comments {
-        id
-        content
-        user {
-          firstName
-          lastName
+      edges {
+        node {
+          id
+          title
+          description
+          status
+          priority
+          xpValue
+          estimatedDuration
+          actualDuration
+          tags
+          dueDate
+          completedAt
+          isArchived
+          project {
+            id
+            name
+            color
+          }
+          sprint {
+            id
+            name
+          }
+          pomodoroSessions {
+            id
+            duration
+            completed
+            startTime
+            endTime
+            focusScore
+          }
+          subtasks {
+            id
+            title
+            completed
+          }
+          comments {
+            id
+            content
+            user {
+              firstName
+              lastName
+            }
+            createdAt
+          }
         }
-        createdAt
+      }
+      pageInfo {
+        hasNextPage
+        endCursor
       }
     }
   }
@@ -274,36 +290,44 @@ export const GET_TASKS = `
 export const GET_SPRINTS = `
   query GetSprints($status: SprintStatus) {
     sprints(status: $status) {
-      id
-      name
-      description
-      goal
-      status
-      startDate
-      endDate
-      velocity
-      analytics {
-        plannedStoryPoints
-        completedStoryPoints
-        burndownData {
-          date
-          remainingPoints
-          idealRemaining
-        }
-        velocityTrend
-        completionRate
-      }
-      tasks {
-        id
-        storyPoints
-        task {
+      edges {
+        node {
           id
-          title
+          name
+          description
+          goal
           status
-          priority
-          xpValue
+          startDate
+          endDate
+          velocity
+          analytics {
+            plannedStoryPoints
+            completedStoryPoints
+            burndownData {
+              date
+              remainingPoints
+              idealRemaining
+            }
+            velocityTrend
+            completionRate
+          }
+          tasks {
+            id
+            storyPoints
+            task {
+              id
+              title
+              status
+              priority
+              xpValue
+            }
+          }
         }
       }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
     }
   }
 `
@@ -4,25 +4,33 @@ import { gql } from '@apollo/client'
 export const GET_PROJECTS = gql`
   query GetProjects {
     projects {
-      id
-      name
-      description
-      color
-      icon
-      status
-      priority
-      startDate
-      endDate
-      isArchived
-      userId
-      folderId
-      createdAt
-      updatedAt
-      analytics {
-        totalTasks
-        completedTasks
-        timeSpent
-        xpEarned
+      edges {
+        node {
+          id
+          name
+          description
+          color
+          icon
+          status
+          priority
+          startDate
+          endDate
+          isArchived
+          userId
+          folderId
+          createdAt
+          updatedAt
+          analytics {
+            totalTasks
+            completedTasks
+            timeSpent
+            xpEarned
+          }
+        }
+      }
+      pageInfo {
+        hasNextPage
+        endCursor
       }
     }
   }
@@ -89,7 +89,7 @@ class ProjectService {
         fetchPolicy: 'cache-first'
       })
 
-      return data.projects.map((project: GraphQLProject) => this.mapGraphQLToLocal(project))
+      return data.projects.edges.map(({ node }: { node: GraphQLProject }) => this.mapGraphQLToLocal(node))
     } catch (error) {
       console.error('Error fetching projects:', error)
       // Return empty array on error - the UI will use local data
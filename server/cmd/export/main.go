@@ -0,0 +1,55 @@
+// Command export writes a user's data export archive to a local file.
+//
+//	go run ./cmd/export -user <userID> [-out lifequest-export.zip]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/export"
+)
+
+func main() {
+	userID := flag.String("user", "", "ID of the user to export (required)")
+	out := flag.String("out", "", "archive path (default lifequest-export-<user>.zip)")
+	flag.Parse()
+
+	if *userID == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *out == "" {
+		*out = "lifequest-export-" + *userID + ".zip"
+	}
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found")
+	}
+
+	// Connect to database
+	database.Connect()
+	defer database.Disconnect()
+
+	file, err := os.Create(*out)
+	if err != nil {
+		log.Fatal("Failed to create archive: ", err)
+	}
+
+	if err := export.Write(context.Background(), database.GetClient(), *userID, file); err != nil {
+		file.Close()
+		os.Remove(*out)
+		log.Fatal("Failed to export: ", err)
+	}
+	if err := file.Close(); err != nil {
+		log.Fatal("Failed to write archive: ", err)
+	}
+
+	log.Printf("Exported user %s to %s", *userID, *out)
+}
@@ -9,7 +9,14 @@ import (
 
 	"lifequest-server/graph"
 	"lifequest-server/graph/generated"
+	"lifequest-server/internal/auth"
+	"lifequest-server/internal/blob"
 	"lifequest-server/internal/database"
+	"lifequest-server/internal/live"
+	"lifequest-server/internal/persisted"
+	"lifequest-server/internal/querylimit"
+	"lifequest-server/internal/ratelimit"
+	"lifequest-server/internal/webhooks"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
@@ -31,12 +38,22 @@ func main() {
 	}
 
 	// Initialize database
-	ctx := context.Background()
-	db, err := database.Connect(ctx)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+	database.Connect()
+	defer database.Disconnect()
+
+	// Refuse to start without a complete attachment store, such as a local
+	// store missing the signing key both servers share
+	if _, err := blob.Default(); err != nil {
+		log.Fatal("Failed to configure blob store: ", err)
 	}
-	defer db.Disconnect(ctx)
+
+	// Start webhook delivery retries
+	webhooks.Start(context.Background(), database.GetClient())
+
+	// Relay live events through Postgres, so subscriptions also see the
+	// comments, attachments and notifications the REST server publishes
+	live.Start(database.GetClient())
+	live.Listen(context.Background(), os.Getenv("DATABASE_URL"))
 
 	// Create router
 	router := chi.NewRouter()
@@ -58,9 +75,9 @@ func main() {
 
 	// Create GraphQL server
 	srv := handler.New(generated.NewExecutableSchema(generated.Config{
-		Resolvers: &graph.Resolver{
-			DB: db,
-		},
+		Resolvers:  &graph.Resolver{},
+		Complexity: graph.Complexity(),
+		Directives: graph.Directives(),
 	}))
 
 	// Add transports
@@ -80,12 +97,31 @@ func main() {
 	// Add extensions
 	srv.Use(extension.Introspection{})
 	srv.Use(extension.AutomaticPersistedQuery{
-		Cache: lru.New(100),
+		Cache: lru.New[string](100),
 	})
 
+	// Limit operation rates per caller
+	srv.Use(querylimit.RateLimit{Limiter: ratelimit.FromEnv(database.GetClient())})
+
+	// Only run the client's operations, and ad-hoc ones for admin tokens,
+	// when a persisted query manifest is configured
+	allowlist, err := persisted.FromEnv()
+	if err != nil {
+		log.Fatal("Failed to load persisted queries: ", err)
+	}
+	if allowlist != nil {
+		srv.Use(allowlist)
+	}
+
+	// Query limits; the budget charges the complexity computed before it
+	limits := querylimit.FromEnv()
+	srv.Use(querylimit.Depth{Limit: limits.MaxDepth})
+	srv.Use(extension.FixedComplexityLimit(limits.MaxComplexity))
+	srv.Use(querylimit.NewBudget(limits.Budget))
+
 	// Routes
 	router.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	router.Handle("/query", srv)
+	router.With(auth.Middleware, graph.LoaderMiddleware, querylimit.Middleware).Handle("/query", srv)
 
 	// Health check
 	router.Get("/health", func(w http.ResponseWriter, r *http.Request) {
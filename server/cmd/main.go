@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
@@ -8,9 +9,17 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 
+	"lifequest-server/internal/attachments"
+	"lifequest-server/internal/blob"
 	"lifequest-server/internal/database"
+	"lifequest-server/internal/export"
 	"lifequest-server/internal/handlers"
+	"lifequest-server/internal/live"
 	"lifequest-server/internal/middleware"
+	"lifequest-server/internal/ratelimit"
+	"lifequest-server/internal/tasks"
+	"lifequest-server/internal/trash"
+	"lifequest-server/internal/webhooks"
 )
 
 func main() {
@@ -24,6 +33,37 @@ func main() {
 	database.Connect()
 	defer database.Disconnect()
 
+	// Refuse to start without a complete attachment store, such as a local
+	// store missing the signing key both servers share
+	if _, err := blob.Default(); err != nil {
+		log.Fatal("Failed to configure blob store: ", err)
+	}
+
+	// Start webhook delivery retries
+	webhooks.Start(context.Background(), database.GetClient())
+
+	// Publish live events through Postgres for the GraphQL server's
+	// subscriptions
+	live.Start(database.GetClient())
+
+	// Remove expired data export archives
+	export.Start(context.Background(), database.GetClient())
+
+	// Purge trashed items past the retention period
+	trash.Start(context.Background(), database.GetClient())
+
+	// Remove the files of attachments whose task was deleted
+	attachments.Start(context.Background(), database.GetClient())
+
+	// Create due occurrences of recurring tasks
+	tasks.Start(context.Background(), database.GetClient())
+
+	// Delete idle rate limit buckets
+	ratelimit.Start(context.Background(), database.GetClient())
+
+	// Limit request rates per user, or per IP address before sign-in
+	limiter := ratelimit.FromEnv(database.GetClient())
+
 	// Initialize Gin router
 	r := gin.Default()
 
@@ -45,7 +85,7 @@ func main() {
 	{
 		// Auth routes (protected by middleware)
 		auth := api.Group("/auth")
-		auth.Use(middleware.AuthMiddleware())
+		auth.Use(middleware.AuthRateLimit(limiter), middleware.AuthMiddleware())
 		{
 			auth.GET("/me", handlers.GetCurrentUser)
 			auth.PUT("/me", handlers.UpdateCurrentUser)
@@ -53,29 +93,34 @@ func main() {
 
 		// Folders routes
 		folders := api.Group("/folders")
-		folders.Use(middleware.AuthMiddleware())
+		folders.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
 		{
 			folders.GET("", handlers.GetFolders)
 			folders.POST("", handlers.CreateFolder)
+			folders.GET("/tree", handlers.GetFolderTree)
 			folders.GET("/:id", handlers.GetFolder)
 			folders.PUT("/:id", handlers.UpdateFolder)
 			folders.DELETE("/:id", handlers.DeleteFolder)
+			folders.GET("/:id/path", handlers.GetFolderPath)
+			folders.POST("/:id/move", handlers.MoveFolder)
 		}
 
 		// Projects routes
 		projects := api.Group("/projects")
-		projects.Use(middleware.AuthMiddleware())
+		projects.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
 		{
 			projects.GET("", handlers.GetProjects)
 			projects.POST("", handlers.CreateProject)
 			projects.GET("/:id", handlers.GetProject)
 			projects.PUT("/:id", handlers.UpdateProject)
 			projects.DELETE("/:id", handlers.DeleteProject)
+			projects.POST("/:id/move", handlers.MoveProject)
+			projects.GET("/:id/critical-path", handlers.GetProjectCriticalPath)
 		}
 
 		// Tasks routes
 		tasks := api.Group("/tasks")
-		tasks.Use(middleware.AuthMiddleware())
+		tasks.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
 		{
 			tasks.GET("", handlers.GetTasks)
 			tasks.POST("", handlers.CreateTask)
@@ -83,21 +128,115 @@ func main() {
 			tasks.PUT("/:id", handlers.UpdateTask)
 			tasks.DELETE("/:id", handlers.DeleteTask)
 			tasks.POST("/:id/complete", handlers.CompleteTask)
+			tasks.PUT("/:id/auto-complete", handlers.SetTaskAutoComplete)
+			tasks.POST("/:id/convert-to-subtask", handlers.ConvertTaskToSubtask)
+			tasks.GET("/:id/subtasks", handlers.GetSubtasks)
+			tasks.POST("/:id/subtasks", handlers.CreateSubtask)
+			tasks.PUT("/:id/subtasks/order", handlers.ReorderSubtasks)
+			tasks.GET("/:id/dependencies", handlers.GetTaskDependencies)
+			tasks.POST("/:id/dependencies", handlers.AddTaskDependency)
+			tasks.DELETE("/:id/dependencies/:dependsOnId", handlers.RemoveTaskDependency)
+			tasks.GET("/:id/comments", handlers.GetTaskComments)
+			tasks.POST("/:id/comments", handlers.CreateTaskComment)
+			tasks.GET("/:id/attachments", handlers.GetTaskAttachments)
+			tasks.POST("/:id/attachments", handlers.UploadTaskAttachment)
+			tasks.GET("/:id/recurrence", handlers.GetTaskRecurrence)
+			tasks.PUT("/:id/recurrence", handlers.SetTaskRecurrence)
+			tasks.DELETE("/:id/recurrence", handlers.StopTaskRecurrence)
+			tasks.PUT("/:id/tags", handlers.SetTaskTags)
+		}
+
+		// Tags routes
+		tagRoutes := api.Group("/tags")
+		tagRoutes.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			tagRoutes.GET("", handlers.GetTags)
+			tagRoutes.POST("", handlers.CreateTag)
+			tagRoutes.POST("/merge", handlers.MergeTags)
+			tagRoutes.GET("/analytics", handlers.GetTagAnalytics)
+			tagRoutes.GET("/:id", handlers.GetTag)
+			tagRoutes.PUT("/:id", handlers.UpdateTag)
+			tagRoutes.DELETE("/:id", handlers.DeleteTag)
+		}
+
+		// Saved views routes
+		viewRoutes := api.Group("/views")
+		viewRoutes.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			viewRoutes.GET("", handlers.GetViews)
+			viewRoutes.POST("", handlers.CreateView)
+			viewRoutes.GET("/:id", handlers.GetView)
+			viewRoutes.PUT("/:id", handlers.UpdateView)
+			viewRoutes.DELETE("/:id", handlers.DeleteView)
+			viewRoutes.GET("/:id/tasks", handlers.RunView)
+		}
+
+		// Task comments routes
+		taskComments := api.Group("/comments")
+		taskComments.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			taskComments.PUT("/:id", handlers.UpdateTaskComment)
+			taskComments.DELETE("/:id", handlers.DeleteTaskComment)
+		}
+
+		// Task attachments routes
+		taskAttachments := api.Group("/attachments")
+		taskAttachments.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			taskAttachments.GET("/:id", handlers.GetTaskAttachment)
+			taskAttachments.DELETE("/:id", handlers.DeleteTaskAttachment)
+		}
+
+		// Local blob downloads (authenticated by the signed link)
+		api.GET("/blobs/*key", middleware.RateLimit(limiter), handlers.DownloadBlob)
+
+		// Subtasks routes
+		subtasks := api.Group("/subtasks")
+		subtasks.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			subtasks.PUT("/:id", handlers.UpdateSubtask)
+			subtasks.DELETE("/:id", handlers.DeleteSubtask)
+			subtasks.POST("/:id/convert", handlers.ConvertSubtask)
 		}
 
 		// Pomodoro sessions routes
 		pomodoro := api.Group("/pomodoro")
-		pomodoro.Use(middleware.AuthMiddleware())
+		pomodoro.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
 		{
 			pomodoro.GET("/sessions", handlers.GetPomodoroSessions)
 			pomodoro.POST("/sessions", handlers.CreatePomodoroSession)
 			pomodoro.PUT("/sessions/:id", handlers.UpdatePomodoroSession)
+			pomodoro.DELETE("/sessions/:id", handlers.DeletePomodoroSession)
 			pomodoro.POST("/sessions/:id/complete", handlers.CompletePomodoroSession)
 		}
 
+		// Habits routes
+		habitRoutes := api.Group("/habits")
+		habitRoutes.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			habitRoutes.GET("", handlers.GetHabits)
+			habitRoutes.POST("", handlers.CreateHabit)
+			habitRoutes.GET("/heatmap", handlers.GetHabitHeatmap)
+			habitRoutes.GET("/:id", handlers.GetHabit)
+			habitRoutes.PUT("/:id", handlers.UpdateHabit)
+			habitRoutes.DELETE("/:id", handlers.DeleteHabit)
+			habitRoutes.GET("/:id/check-ins", handlers.GetHabitCheckIns)
+			habitRoutes.POST("/:id/check-ins", handlers.CheckInHabit)
+		}
+
+		// Habit check-ins routes
+		checkIns := api.Group("/check-ins")
+		checkIns.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			checkIns.DELETE("/:id", handlers.UndoHabitCheckIn)
+		}
+
+		// Daily, weekly and monthly statistics
+		api.GET("/analytics", middleware.AuthMiddleware(), middleware.RateLimit(limiter), handlers.GetAnalytics)
+
 		// Sprints routes
 		sprints := api.Group("/sprints")
-		sprints.Use(middleware.AuthMiddleware())
+		sprints.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
 		{
 			sprints.GET("", handlers.GetSprints)
 			sprints.POST("", handlers.CreateSprint)
@@ -107,6 +246,74 @@ func main() {
 			sprints.POST("/:id/tasks", handlers.AddTaskToSprint)
 			sprints.DELETE("/:id/tasks/:taskId", handlers.RemoveTaskFromSprint)
 		}
+
+		// Notifications routes
+		notifications := api.Group("/notifications")
+		notifications.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			notifications.GET("", handlers.GetNotifications)
+			notifications.POST("/read-all", handlers.MarkAllNotificationsRead)
+			notifications.POST("/:id/read", handlers.MarkNotificationRead)
+		}
+
+		// Trash bin for deleted folders, projects, tasks, sprints and sessions
+		trashBin := api.Group("/trash")
+		trashBin.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			trashBin.GET("", handlers.GetTrash)
+			trashBin.POST("/:kind/:id/restore", handlers.RestoreTrashItem)
+		}
+
+		// Webhooks routes
+		hooks := api.Group("/webhooks")
+		hooks.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			hooks.GET("", handlers.GetWebhooks)
+			hooks.POST("", handlers.CreateWebhook)
+			hooks.GET("/:id", handlers.GetWebhook)
+			hooks.PUT("/:id", handlers.UpdateWebhook)
+			hooks.DELETE("/:id", handlers.DeleteWebhook)
+			hooks.GET("/:id/deliveries", handlers.GetWebhookDeliveries)
+			hooks.POST("/:id/deliveries/:deliveryId/redeliver", handlers.RedeliverWebhookDelivery)
+			hooks.POST("/:id/test", handlers.SendTestWebhook)
+		}
+
+		// Repository mappings for commit-driven task updates
+		repositories := api.Group("/repositories")
+		repositories.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			repositories.GET("", handlers.GetProjectRepositories)
+			repositories.POST("", handlers.CreateProjectRepository)
+			repositories.PUT("/:id", handlers.UpdateProjectRepository)
+			repositories.DELETE("/:id", handlers.DeleteProjectRepository)
+		}
+
+		// Inbound git push hooks (authenticated by the mapping's shared secret)
+		api.POST("/hooks/git/:id", middleware.AuthRateLimit(limiter), handlers.ReceiveGitPush)
+
+		// Calendar feed token management
+		calendar := api.Group("/calendar")
+		calendar.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			calendar.GET("", handlers.GetCalendarSubscription)
+			calendar.POST("/token", handlers.RotateCalendarToken)
+			calendar.DELETE("/token", handlers.RevokeCalendarToken)
+		}
+
+		// Subscribable .ics feed (authenticated by the token in the path)
+		api.GET("/calendar/feed/:token", middleware.AuthRateLimit(limiter), handlers.ServeCalendarFeed)
+
+		// Account data exports
+		exports := api.Group("/exports")
+		exports.Use(middleware.AuthMiddleware(), middleware.RateLimit(limiter))
+		{
+			exports.GET("", handlers.GetDataExports)
+			exports.POST("", handlers.CreateDataExport)
+			exports.GET("/:id", handlers.GetDataExport)
+		}
+
+		// Export downloads (authenticated by the signed link)
+		api.GET("/exports/:id/download", middleware.RateLimit(limiter), handlers.DownloadDataExport)
 	}
 
 	// Start server
@@ -0,0 +1,53 @@
+// Command persisted generates the persisted query manifest from the
+// operations declared in the client's source files.
+//
+//	go run ./cmd/persisted [-out persisted-queries.json] [files...]
+//
+// Without files it reads ../client/src/lib/apollo.ts and
+// ../client/src/lib/graphql/*.ts.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"lifequest-server/graph"
+	"lifequest-server/graph/generated"
+	"lifequest-server/internal/persisted"
+)
+
+func main() {
+	out := flag.String("out", "persisted-queries.json", "manifest path")
+	flag.Parse()
+
+	files := flag.Args()
+	if len(files) == 0 {
+		modules, _ := filepath.Glob("../client/src/lib/graphql/*.ts")
+		files = append([]string{"../client/src/lib/apollo.ts"}, modules...)
+	}
+
+	schema := generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{}}).Schema()
+	manifest := &persisted.Manifest{Format: persisted.ManifestFormat, Version: 1}
+	for _, file := range files {
+		source, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal("Failed to read client source: ", err)
+		}
+		for _, skipped := range manifest.Generate(string(source), schema) {
+			log.Printf("Skipped %s in %s: %s", skipped.Name, file, skipped.Reason)
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		log.Fatal("Failed to encode manifest: ", err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatal("Failed to write manifest: ", err)
+	}
+
+	log.Printf("Wrote %d operations to %s", len(manifest.Operations), *out)
+}
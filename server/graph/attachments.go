@@ -0,0 +1,42 @@
+package graph
+
+import (
+	"log"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/attachments"
+	"lifequest-server/internal/db"
+)
+
+func attachmentToModel(attachment *attachments.Attachment) *model.TaskAttachment {
+	result := &model.TaskAttachment{
+		ID:           attachment.ID,
+		Filename:     attachment.Filename,
+		URL:          attachment.URL,
+		URLExpiresAt: attachment.URLExpiresAt,
+		Size:         attachment.Size,
+		MimeType:     attachment.MimeType,
+		UploadedBy:   attachment.UserID,
+		CreatedAt:    attachment.CreatedAt,
+	}
+	if taskID, ok := attachment.TaskID(); ok {
+		result.TaskID = taskID
+	}
+	return result
+}
+
+// attachmentsToModels signs download URLs for fetched attachments. An
+// attachment that can't be signed is left out rather than failing the whole
+// task.
+func attachmentsToModels(stored []db.TaskAttachmentModel) []*model.TaskAttachment {
+	result := make([]*model.TaskAttachment, 0, len(stored))
+	for i := range stored {
+		signed, err := attachments.Sign(&stored[i])
+		if err != nil {
+			log.Printf("graph: failed to sign attachment %s: %v", stored[i].ID, err)
+			continue
+		}
+		result = append(result, attachmentToModel(signed))
+	}
+	return result
+}
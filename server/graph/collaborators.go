@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"strings"
+	"time"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/collaborators"
+	"lifequest-server/internal/db"
+)
+
+func collaboratorToModel(collaborator *db.ProjectCollaboratorModel) *model.ProjectCollaborator {
+	result := &model.ProjectCollaborator{
+		ID:        collaborator.ID,
+		ProjectID: collaborator.ProjectID,
+		UserID:    collaborator.UserID,
+		Role:      model.CollaboratorRole(strings.ToUpper(collaborator.Role)),
+		InvitedAt: collaborator.InvitedAt,
+	}
+	if joinedAt, ok := collaborator.JoinedAt(); ok {
+		result.JoinedAt = &joinedAt
+	}
+	return result
+}
+
+// invitationToModel converts an invitation fetched with its project. token
+// is only set when the invitation was just sent.
+func invitationToModel(invitation *db.ProjectInvitationModel, token string) *model.ProjectInvitation {
+	status := model.InvitationStatus(strings.ToUpper(invitation.Status))
+	if collaborators.Expired(invitation, time.Now()) {
+		status = model.InvitationStatusExpired
+	}
+	result := &model.ProjectInvitation{
+		ID:          invitation.ID,
+		ProjectID:   invitation.ProjectID,
+		ProjectName: invitation.Project().Name,
+		Email:       invitation.Email,
+		Role:        model.CollaboratorRole(strings.ToUpper(invitation.Role)),
+		Status:      status,
+		InvitedByID: invitation.InvitedByID,
+		ExpiresAt:   invitation.ExpiresAt,
+		CreatedAt:   invitation.CreatedAt,
+	}
+	if inviteeID, ok := invitation.InviteeID(); ok {
+		result.InviteeID = &inviteeID
+	}
+	if token != "" {
+		result.Token = &token
+	}
+	if respondedAt, ok := invitation.RespondedAt(); ok {
+		result.RespondedAt = &respondedAt
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package graph
+
+import (
+	"strings"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/db"
+)
+
+// commentToModel converts a comment, with its author and history when they
+// were fetched.
+func commentToModel(comment *db.TaskCommentModel) *model.TaskComment {
+	result := &model.TaskComment{
+		ID:        comment.ID,
+		Content:   comment.Body,
+		TaskID:    comment.TaskID,
+		UserID:    comment.UserID,
+		Mentions:  comment.Mentions,
+		History:   make([]*model.TaskCommentRevision, len(comment.RelationsTaskComment.Revisions)),
+		CreatedAt: comment.CreatedAt,
+	}
+	if result.Mentions == nil {
+		result.Mentions = []string{}
+	}
+	if user := comment.RelationsTaskComment.User; user != nil {
+		result.User = userToModel(user)
+	}
+	if editedAt, ok := comment.EditedAt(); ok {
+		result.EditedAt = &editedAt
+	}
+	for i, revision := range comment.RelationsTaskComment.Revisions {
+		result.History[i] = &model.TaskCommentRevision{
+			ID:         revision.ID,
+			Content:    revision.Body,
+			ReplacedAt: revision.CreatedAt,
+		}
+	}
+	return result
+}
+
+// userToModel converts the profile fields of a user.
+func userToModel(user *db.UserModel) *model.User {
+	result := &model.User{
+		ID:            user.ID,
+		Email:         user.Email,
+		Level:         user.Level,
+		TotalXp:       user.TotalXp,
+		CurrentStreak: user.Streak,
+		SkillTrees:    []*model.SkillTree{},
+		Badges:        []*model.Badge{},
+		Achievements:  []*model.Achievement{},
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+	if firstName, ok := user.FirstName(); ok {
+		result.FirstName = &firstName
+	}
+	if lastName, ok := user.LastName(); ok {
+		result.LastName = &lastName
+	}
+	if avatar, ok := user.Avatar(); ok {
+		result.AvatarURL = &avatar
+	}
+	return result
+}
+
+func notificationToModel(notification *db.NotificationModel) *model.Notification {
+	result := &model.Notification{
+		ID:        notification.ID,
+		Title:     notification.Title,
+		Message:   notification.Message,
+		Type:      model.NotificationType(strings.ToUpper(notification.Type)),
+		UserID:    notification.UserID,
+		CreatedAt: notification.CreatedAt,
+	}
+	if _, read := notification.ReadAt(); read {
+		result.Read = true
+	}
+	if data, ok := notification.Data(); ok {
+		result.Data = &data
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"time"
+
+	"lifequest-server/graph/generated"
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/pagination"
+	"lifequest-server/internal/search"
+)
+
+// NestedListSize is the number of items a nested list such as
+// Task.subtasks, which takes no page size, is assumed to hold when scoring
+// a query.
+const NestedListSize = 10
+
+// Complexity scores every field 1 plus the cost of its selection, and a list
+// field that many times over for each item it can return: the page size for
+// connections, searches and Project.tasks, up to the most they serve, and
+// NestedListSize for nested lists.
+func Complexity() generated.ComplexityRoot {
+	var c generated.ComplexityRoot
+
+	c.Query.Projects = func(child int, first *int, _ *string, _ *model.ProjectSortKey, _ *bool) int {
+		return page(child, first, pagination.DefaultLimit, pagination.MaxLimit)
+	}
+	c.Query.Tasks = func(child int, _ *model.TaskStatus, _ *string, _ *string, _ []string, first *int, _ *string, _ *model.TaskSortKey, _ *bool) int {
+		return page(child, first, pagination.DefaultLimit, pagination.MaxLimit)
+	}
+	c.Query.Sprints = func(child int, _ *model.SprintStatus, first *int, _ *string, _ *model.SprintSortKey, _ *bool) int {
+		return page(child, first, pagination.DefaultLimit, pagination.MaxLimit)
+	}
+	c.Query.PomodoroSessions = func(child int, _ *time.Time, first *int, _ *string, _ *model.PomodoroSessionSortKey, _ *bool) int {
+		return page(child, first, pagination.DefaultLimit, pagination.MaxLimit)
+	}
+	c.Query.Notifications = func(child int, _ *bool, first *int, _ *string) int {
+		return page(child, first, pagination.DefaultLimit, pagination.MaxLimit)
+	}
+	c.Query.SearchTasks = func(child int, _ string, _ *model.TaskSort, _ *bool, limit *int) int {
+		return page(child, limit, search.DefaultLimit, search.MaxLimit)
+	}
+
+	c.Folder.Children = nested
+	c.Folder.Projects = nested
+	c.Project.Tasks = func(child int, first *int) int {
+		return page(child, first, pagination.DefaultLimit, pagination.MaxLimit)
+	}
+	c.Project.Sprints = nested
+	c.Project.Collaborators = nested
+	c.Sprint.Tasks = nested
+	c.Task.Subtasks = nested
+	c.Task.Comments = nested
+	c.Task.Dependencies = nested
+	c.Task.Dependents = nested
+	c.Task.PomodoroSessions = nested
+	return c
+}
+
+// page scores a list of up to first items, or fallback when first is unset.
+// first is clamped to limit, the most the field serves, so an oversized
+// page size can't overflow the score.
+func page(child int, first *int, fallback, limit int) int {
+	n := fallback
+	if first != nil && *first > 0 {
+		n = min(*first, limit)
+	}
+	return 1 + n*child
+}
+
+func nested(child int) int {
+	return 1 + NestedListSize*child
+}
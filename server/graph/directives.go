@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"lifequest-server/graph/generated"
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/auth"
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/database"
+)
+
+// Directives implements the schema's directives.
+func Directives() generated.DirectiveRoot {
+	return generated.DirectiveRoot{HasRole: HasRole}
+}
+
+// HasRole implements @hasRole: it resolves the field only when the user
+// auth.Middleware authenticated holds at least role on the resource of kind
+// resource whose ID is the field's arg argument. Users with no role on the
+// resource are told it does not exist.
+func HasRole(ctx context.Context, obj any, next graphql.Resolver, role model.CollaboratorRole, resource model.ResourceKind, arg string) (any, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	id, _ := graphql.GetFieldContext(ctx).Args[arg].(string)
+	target, err := authz.Lookup(ctx, client, authz.Kind(strings.ToLower(string(resource))), id)
+	if err != nil {
+		return nil, err
+	}
+	viewer, err := authz.ViewerOf(ctx, client, userID, target)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case viewer.Role == authz.RoleNone:
+		return nil, authz.ErrNotFound
+	case viewer.Role < authz.ParseRole(string(role)):
+		return nil, authz.ErrForbidden
+	}
+	return next(ctx)
+}
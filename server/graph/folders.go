@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"strings"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/folders"
+)
+
+// fetchDepth applies the default to an optional depth argument.
+func fetchDepth(depth *int) int {
+	if depth == nil {
+		return folders.DefaultFetchDepth
+	}
+	return *depth
+}
+
+func folderNodeToModel(node *folders.Node) *model.Folder {
+	result := &model.Folder{
+		ID:            node.ID,
+		Name:          node.Name,
+		Color:         &node.Color,
+		Icon:          &node.Icon,
+		UserID:        node.UserID,
+		Children:      make([]*model.Folder, len(node.Children)),
+		Projects:      []*model.Project{},
+		ProjectCount:  node.ProjectCount,
+		OpenTaskCount: node.OpenTaskCount,
+		CreatedAt:     node.CreatedAt,
+		UpdatedAt:     node.UpdatedAt,
+	}
+	if description, ok := node.Description(); ok {
+		result.Description = &description
+	}
+	if parentID, ok := node.ParentID(); ok {
+		result.ParentID = &parentID
+	}
+	if _, archived := node.ArchivedAt(); archived {
+		result.IsArchived = true
+	}
+	for i, child := range node.Children {
+		result.Children[i] = folderNodeToModel(child)
+	}
+	return result
+}
+
+var projectStatuses = map[string]model.ProjectStatus{
+	"active":    model.ProjectStatusInProgress,
+	"completed": model.ProjectStatusCompleted,
+	"on-hold":   model.ProjectStatusOnHold,
+	"cancelled": model.ProjectStatusCancelled,
+}
+
+func projectToModel(project *db.ProjectModel) *model.Project {
+	status, ok := projectStatuses[project.Status]
+	if !ok {
+		status = model.ProjectStatusPlanning
+	}
+	completionRate := 0.0
+	if project.TaskCount > 0 {
+		completionRate = float64(project.CompletedTaskCount) / float64(project.TaskCount)
+	}
+
+	result := &model.Project{
+		ID:       project.ID,
+		Name:     project.Name,
+		Status:   status,
+		Priority: model.Priority(strings.ToUpper(project.Priority)),
+		UserID:   project.UserID,
+		FolderID: &project.FolderID,
+		Sprints:  []*model.Sprint{},
+		Analytics: &model.ProjectAnalytics{
+			TotalTasks:     project.TaskCount,
+			CompletedTasks: project.CompletedTaskCount,
+			CompletionRate: completionRate,
+			XpEarned:       project.XpEarned,
+		},
+		CreatedAt: project.CreatedAt,
+		UpdatedAt: project.UpdatedAt,
+	}
+	if description, ok := project.Description(); ok {
+		result.Description = &description
+	}
+	if dueDate, ok := project.DueDate(); ok {
+		result.EndDate = &dueDate
+	}
+	return result
+}
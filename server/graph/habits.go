@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"strings"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/analytics"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/habits"
+	"lifequest-server/internal/xp"
+)
+
+func habitToModel(habit *habits.Status) *model.Habit {
+	_, archived := habit.ArchivedAt()
+	result := &model.Habit{
+		ID:                  habit.ID,
+		Name:                habit.Name,
+		Category:            model.SkillCategory(strings.ToUpper(habit.Category)),
+		Period:              model.HabitPeriod(strings.ToUpper(habit.Period)),
+		TargetCount:         habit.TargetCount,
+		XpValue:             habit.XpValue,
+		CurrentStreak:       habit.CurrentStreak,
+		LongestStreak:       habit.LongestStreak,
+		PeriodStart:         habit.PeriodStart,
+		PeriodCount:         habit.PeriodCount,
+		CompletedThisPeriod: habit.Completed,
+		Archived:            archived,
+		UserID:              habit.UserID,
+		CreatedAt:           habit.CreatedAt,
+		UpdatedAt:           habit.UpdatedAt,
+	}
+	if description, ok := habit.Description(); ok {
+		result.Description = &description
+	}
+	if icon, ok := habit.Icon(); ok {
+		result.Icon = &icon
+	}
+	if color, ok := habit.Color(); ok {
+		result.Color = &color
+	}
+	return result
+}
+
+func habitCheckInToModel(checkIn *db.HabitCheckInModel) *model.HabitCheckIn {
+	result := &model.HabitCheckIn{
+		ID:        checkIn.ID,
+		HabitID:   checkIn.HabitID,
+		Date:      checkIn.Date,
+		Count:     checkIn.Count,
+		CreatedAt: checkIn.CreatedAt,
+	}
+	if note, ok := checkIn.Note(); ok {
+		result.Note = &note
+	}
+	return result
+}
+
+// habitFields maps GraphQL input onto habits.Fields, lowercasing enums to
+// their stored form.
+func habitFields(name, description, icon, color *string, category *model.SkillCategory, period *model.HabitPeriod, targetCount, xpValue *int, archived *bool) habits.Fields {
+	fields := habits.Fields{
+		Name:        name,
+		Description: description,
+		Icon:        icon,
+		Color:       color,
+		TargetCount: targetCount,
+		XpValue:     xpValue,
+		Archived:    archived,
+	}
+	if category != nil {
+		value := strings.ToLower(string(*category))
+		fields.Category = &value
+	}
+	if period != nil {
+		value := strings.ToLower(string(*period))
+		fields.Period = &value
+	}
+	return fields
+}
+
+// skillTreesToModels returns one tree per skill category, with the XP the
+// category has earned; skills within a tree aren't tracked yet.
+func skillTreesToModels(userID string, totals map[string]int) []*model.SkillTree {
+	trees := make([]*model.SkillTree, 0, len(habits.Categories))
+	for _, category := range habits.Categories {
+		total := totals[category]
+		trees = append(trees, &model.SkillTree{
+			ID:       userID + ":" + category,
+			Name:     strings.ToUpper(category[:1]) + category[1:],
+			Category: model.SkillCategory(strings.ToUpper(category)),
+			TotalXp:  total,
+			Level:    max(total, 0)/xp.PerLevel + 1,
+			Skills:   []*model.Skill{},
+		})
+	}
+	return trees
+}
+
+func analyticsToModel(userID string, report *analytics.Report) *model.UserAnalytics {
+	result := &model.UserAnalytics{
+		ID:           userID + ":" + report.From.Format("2006-01-02") + ":" + report.To.Format("2006-01-02"),
+		UserID:       userID,
+		DailyStats:   make([]*model.DailyStat, len(report.Days)),
+		WeeklyStats:  make([]*model.WeeklyStat, len(report.Weeks)),
+		MonthlyStats: make([]*model.MonthlyStat, len(report.Months)),
+		Productivity: &model.ProductivityAnalytics{
+			AverageTasksPerDay:   report.Productivity.AverageTasksPerDay,
+			PeakProductivityHour: report.Productivity.PeakHour,
+			MostProductiveDay:    report.Productivity.MostProductiveDay,
+			TaskCompletionRate:   report.Productivity.CompletionRate,
+			AverageTaskDuration:  report.Productivity.AverageTaskMinutes,
+		},
+		Focus: &model.FocusAnalytics{
+			AverageSessionDuration: report.Focus.AverageSessionMinutes,
+			TotalFocusTime:         report.Focus.TotalMinutes,
+			FocusStreakDays:        report.Focus.StreakDays,
+			PreferredFocusTime:     report.Focus.PreferredTime,
+			FocusEfficiency:        report.Focus.Efficiency,
+		},
+	}
+	for i, day := range report.Days {
+		result.DailyStats[i] = &model.DailyStat{
+			Date:             day.Date,
+			TasksCompleted:   day.TasksCompleted,
+			XpEarned:         day.XpEarned,
+			PomodoroSessions: day.PomodoroSessions,
+			FocusTime:        day.FocusMinutes,
+			ProjectsWorkedOn: day.ProjectsWorkedOn,
+			HabitCheckIns:    day.HabitCheckIns,
+		}
+	}
+	for i, week := range report.Weeks {
+		result.WeeklyStats[i] = &model.WeeklyStat{
+			WeekStart:           week.WeekStart,
+			TasksCompleted:      week.TasksCompleted,
+			XpEarned:            week.XpEarned,
+			PomodoroSessions:    week.PomodoroSessions,
+			FocusTime:           week.FocusMinutes,
+			AverageProductivity: week.AverageTasksPerDay,
+		}
+	}
+	for i, month := range report.Months {
+		result.MonthlyStats[i] = &model.MonthlyStat{
+			Month:            month.Month,
+			Year:             month.Year,
+			TasksCompleted:   month.TasksCompleted,
+			XpEarned:         month.XpEarned,
+			PomodoroSessions: month.PomodoroSessions,
+			FocusTime:        month.FocusMinutes,
+			GoalsAchieved:    month.GoalsAchieved,
+		}
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"strings"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/importer"
+)
+
+// maxImportUploadSize caps files accepted by import mutations.
+const maxImportUploadSize = 5 << 20
+
+// importFormat maps an ImportFormat onto the importer's format names.
+func importFormat(format model.ImportFormat) string {
+	return strings.ReplaceAll(strings.ToLower(string(format)), "_", "-")
+}
+
+func importReportToModel(report *importer.Report) *model.ImportReport {
+	result := &model.ImportReport{
+		DryRun:     report.DryRun,
+		Created:    report.Created,
+		Duplicates: report.Duplicates,
+		Skipped:    report.Skipped,
+		Items:      make([]*model.ImportItem, len(report.Items)),
+	}
+	for i, item := range report.Items {
+		result.Items[i] = &model.ImportItem{
+			Kind:    item.Kind,
+			UID:     item.UID,
+			Title:   item.Title,
+			DueDate: item.DueDate,
+			Action:  model.ImportAction(strings.ToUpper(item.Action)),
+		}
+		if item.Reason != "" {
+			reason := item.Reason
+			result.Items[i].Reason = &reason
+		}
+		if item.ID != "" {
+			id := item.ID
+			result.Items[i].ID = &id
+		}
+	}
+	return result
+}
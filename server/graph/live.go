@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"context"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/live"
+	"lifequest-server/internal/notifications"
+)
+
+// taskUpdates streams the tasks named by the events published for the
+// project until ctx is done. Tasks that can no longer be loaded, such as
+// ones moved to the trash since, are skipped.
+func taskUpdates(ctx context.Context, projectID string) <-chan *model.Task {
+	events, unsubscribe := live.Subscribe(live.ProjectTopic(projectID))
+	out := make(chan *model.Task)
+
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events:
+				taskEvent, ok := event.(live.TaskEvent)
+				if !ok {
+					continue
+				}
+				task, err := loadTaskModel(ctx, database.GetClient(), taskEvent.UserID, taskEvent.TaskID)
+				if err != nil {
+					continue
+				}
+				select {
+				case out <- task:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// notificationUpdates streams the user's new notifications until ctx is
+// done.
+func notificationUpdates(ctx context.Context, userID string) <-chan *model.Notification {
+	events, unsubscribe := live.Subscribe(live.UserTopic(userID))
+	out := make(chan *model.Notification)
+
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events:
+				notificationEvent, ok := event.(live.NotificationEvent)
+				if !ok {
+					continue
+				}
+				notification, err := notifications.Get(ctx, database.GetClient(), userID, notificationEvent.NotificationID)
+				if err != nil {
+					continue
+				}
+				select {
+				case out <- notificationToModel(notification):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
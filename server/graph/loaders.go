@@ -0,0 +1,141 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/dataloader"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/tasks"
+)
+
+// Loaders batch the lookups behind nested fields, so that resolving
+// project { tasks { ... } } for a page of projects costs one query per
+// relation instead of one per project.
+type Loaders struct {
+	Projects               *dataloader.Loader[string, *db.ProjectModel]
+	Users                  *dataloader.Loader[string, *db.UserModel]
+	Tasks                  *dataloader.Loader[string, *db.TaskModel]
+	TasksByProject         *dataloader.Loader[string, []db.TaskModel]
+	SprintTasksBySprint    *dataloader.Loader[string, []db.SprintTaskModel]
+	CollaboratorsByProject *dataloader.Loader[string, []db.ProjectCollaboratorModel]
+	Dependencies           *dataloader.Loader[string, *tasks.Dependencies]
+}
+
+type loadersKey struct{}
+
+// NewLoaders returns loaders reading from client.
+func NewLoaders(client *db.PrismaClient) *Loaders {
+	return &Loaders{
+		Projects: dataloader.New(func(ctx context.Context, ids []string) (map[string]*db.ProjectModel, error) {
+			list, err := client.Project.FindMany(
+				db.Project.ID.In(ids),
+				db.Project.DeletedAt.IsNull(),
+			).Exec(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string]*db.ProjectModel, len(list))
+			for i := range list {
+				result[list[i].ID] = &list[i]
+			}
+			return result, nil
+		}),
+		Users: dataloader.New(func(ctx context.Context, ids []string) (map[string]*db.UserModel, error) {
+			list, err := client.User.FindMany(
+				db.User.ID.In(ids),
+			).Exec(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string]*db.UserModel, len(list))
+			for i := range list {
+				result[list[i].ID] = &list[i]
+			}
+			return result, nil
+		}),
+		Tasks: dataloader.New(func(ctx context.Context, ids []string) (map[string]*db.TaskModel, error) {
+			list, err := client.Task.FindMany(
+				db.Task.ID.In(ids),
+				db.Task.DeletedAt.IsNull(),
+			).With(taskRelations()...).Exec(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string]*db.TaskModel, len(list))
+			for i := range list {
+				result[list[i].ID] = &list[i]
+			}
+			return result, nil
+		}),
+		TasksByProject: dataloader.New(func(ctx context.Context, ids []string) (map[string][]db.TaskModel, error) {
+			list, err := client.Task.FindMany(
+				db.Task.ProjectID.In(ids),
+				db.Task.DeletedAt.IsNull(),
+			).With(taskRelations()...).OrderBy(
+				db.Task.CreatedAt.Order(db.SortOrderAsc),
+			).Exec(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result := map[string][]db.TaskModel{}
+			for _, task := range list {
+				result[task.ProjectID] = append(result[task.ProjectID], task)
+			}
+			return result, nil
+		}),
+		SprintTasksBySprint: dataloader.New(func(ctx context.Context, ids []string) (map[string][]db.SprintTaskModel, error) {
+			list, err := client.SprintTask.FindMany(
+				db.SprintTask.SprintID.In(ids),
+				db.SprintTask.Task.Where(db.Task.DeletedAt.IsNull()),
+			).Exec(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result := map[string][]db.SprintTaskModel{}
+			for _, link := range list {
+				result[link.SprintID] = append(result[link.SprintID], link)
+			}
+			return result, nil
+		}),
+		CollaboratorsByProject: dataloader.New(func(ctx context.Context, ids []string) (map[string][]db.ProjectCollaboratorModel, error) {
+			list, err := client.ProjectCollaborator.FindMany(
+				db.ProjectCollaborator.ProjectID.In(ids),
+				db.ProjectCollaborator.Not(db.ProjectCollaborator.JoinedAt.IsNull()),
+			).OrderBy(
+				db.ProjectCollaborator.JoinedAt.Order(db.SortOrderAsc),
+			).Exec(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result := map[string][]db.ProjectCollaboratorModel{}
+			for _, collaborator := range list {
+				result[collaborator.ProjectID] = append(result[collaborator.ProjectID], collaborator)
+			}
+			return result, nil
+		}),
+		Dependencies: dataloader.New(func(ctx context.Context, ids []string) (map[string]*tasks.Dependencies, error) {
+			return tasks.LoadDependencies(ctx, client, ids)
+		}),
+	}
+}
+
+// LoaderMiddleware gives each request its own loaders, so values are cached
+// for the request only. A subscription keeps the loaders of the request that
+// opened its connection.
+func LoaderMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loaders := NewLoaders(database.GetClient())
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), loadersKey{}, loaders)))
+	})
+}
+
+// loadersFor returns the request's loaders, or fresh ones outside a request
+// served through LoaderMiddleware.
+func loadersFor(ctx context.Context) *Loaders {
+	if loaders, ok := ctx.Value(loadersKey{}).(*Loaders); ok {
+		return loaders
+	}
+	return NewLoaders(database.GetClient())
+}
@@ -47,6 +47,17 @@ type CreateFolderInput struct {
 	ParentID    *string `json:"parentId,omitempty"`
 }
 
+type CreateHabitInput struct {
+	Name        string         `json:"name"`
+	Description *string        `json:"description,omitempty"`
+	Icon        *string        `json:"icon,omitempty"`
+	Color       *string        `json:"color,omitempty"`
+	Category    *SkillCategory `json:"category,omitempty"`
+	Period      *HabitPeriod   `json:"period,omitempty"`
+	TargetCount *int           `json:"targetCount,omitempty"`
+	XpValue     *int           `json:"xpValue,omitempty"`
+}
+
 type CreatePomodoroSessionInput struct {
 	Duration    int         `json:"duration"`
 	TaskID      *string     `json:"taskId,omitempty"`
@@ -64,6 +75,17 @@ type CreateProjectInput struct {
 	FolderID    *string    `json:"folderId,omitempty"`
 }
 
+type CreateSavedViewInput struct {
+	Name      string        `json:"name"`
+	Query     *string       `json:"query,omitempty"`
+	Sort      *TaskSort     `json:"sort,omitempty"`
+	Reverse   *bool         `json:"reverse,omitempty"`
+	GroupBy   *ViewGrouping `json:"groupBy,omitempty"`
+	Pinned    *bool         `json:"pinned,omitempty"`
+	ProjectID *string       `json:"projectId,omitempty"`
+	Shared    *bool         `json:"shared,omitempty"`
+}
+
 type CreateSprintInput struct {
 	Name        string    `json:"name"`
 	Description *string   `json:"description,omitempty"`
@@ -92,6 +114,18 @@ type CreateUserInput struct {
 	AvatarURL *string `json:"avatarUrl,omitempty"`
 }
 
+type CreateWebhookInput struct {
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	Description *string  `json:"description,omitempty"`
+	Secret      *string  `json:"secret,omitempty"`
+}
+
+type CriticalPath struct {
+	Tasks              []*Task `json:"tasks"`
+	EstimatedPomodoros int     `json:"estimatedPomodoros"`
+}
+
 type DailyStat struct {
 	Date             time.Time `json:"date"`
 	TasksCompleted   int       `json:"tasksCompleted"`
@@ -99,6 +133,7 @@ type DailyStat struct {
 	PomodoroSessions int       `json:"pomodoroSessions"`
 	FocusTime        int       `json:"focusTime"`
 	ProjectsWorkedOn int       `json:"projectsWorkedOn"`
+	HabitCheckIns    int       `json:"habitCheckIns"`
 }
 
 type FocusAnalytics struct {
@@ -110,18 +145,73 @@ type FocusAnalytics struct {
 }
 
 type Folder struct {
-	ID          string     `json:"id"`
-	Name        string     `json:"name"`
-	Description *string    `json:"description,omitempty"`
-	Color       *string    `json:"color,omitempty"`
-	Icon        *string    `json:"icon,omitempty"`
-	IsArchived  bool       `json:"isArchived"`
-	UserID      string     `json:"userId"`
-	ParentID    *string    `json:"parentId,omitempty"`
-	Children    []*Folder  `json:"children"`
-	Projects    []*Project `json:"projects"`
-	CreatedAt   time.Time  `json:"createdAt"`
-	UpdatedAt   time.Time  `json:"updatedAt"`
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Description   *string    `json:"description,omitempty"`
+	Color         *string    `json:"color,omitempty"`
+	Icon          *string    `json:"icon,omitempty"`
+	IsArchived    bool       `json:"isArchived"`
+	UserID        string     `json:"userId"`
+	ParentID      *string    `json:"parentId,omitempty"`
+	Children      []*Folder  `json:"children"`
+	Projects      []*Project `json:"projects"`
+	ProjectCount  int        `json:"projectCount"`
+	OpenTaskCount int        `json:"openTaskCount"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+type Habit struct {
+	ID                  string        `json:"id"`
+	Name                string        `json:"name"`
+	Description         *string       `json:"description,omitempty"`
+	Icon                *string       `json:"icon,omitempty"`
+	Color               *string       `json:"color,omitempty"`
+	Category            SkillCategory `json:"category"`
+	Period              HabitPeriod   `json:"period"`
+	TargetCount         int           `json:"targetCount"`
+	XpValue             int           `json:"xpValue"`
+	CurrentStreak       int           `json:"currentStreak"`
+	LongestStreak       int           `json:"longestStreak"`
+	PeriodStart         time.Time     `json:"periodStart"`
+	PeriodCount         int           `json:"periodCount"`
+	CompletedThisPeriod bool          `json:"completedThisPeriod"`
+	Archived            bool          `json:"archived"`
+	UserID              string        `json:"userId"`
+	CreatedAt           time.Time     `json:"createdAt"`
+	UpdatedAt           time.Time     `json:"updatedAt"`
+}
+
+type HabitCheckIn struct {
+	ID        string    `json:"id"`
+	HabitID   string    `json:"habitId"`
+	Date      time.Time `json:"date"`
+	Count     int       `json:"count"`
+	Note      *string   `json:"note,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type HeatmapDay struct {
+	Date  time.Time `json:"date"`
+	Count int       `json:"count"`
+}
+
+type ImportItem struct {
+	Kind    string       `json:"kind"`
+	UID     string       `json:"uid"`
+	Title   string       `json:"title"`
+	DueDate *time.Time   `json:"dueDate,omitempty"`
+	Action  ImportAction `json:"action"`
+	Reason  *string      `json:"reason,omitempty"`
+	ID      *string      `json:"id,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun     bool          `json:"dryRun"`
+	Created    int           `json:"created"`
+	Duplicates int           `json:"duplicates"`
+	Skipped    int           `json:"skipped"`
+	Items      []*ImportItem `json:"items"`
 }
 
 type MonthlyStat struct {
@@ -148,6 +238,16 @@ type Notification struct {
 	CreatedAt time.Time        `json:"createdAt"`
 }
 
+type NotificationConnection struct {
+	Edges    []*NotificationEdge `json:"edges"`
+	PageInfo *PageInfo           `json:"pageInfo"`
+}
+
+type NotificationEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *Notification `json:"node"`
+}
+
 type NotificationSettings struct {
 	Email            bool `json:"email"`
 	Push             bool `json:"push"`
@@ -164,6 +264,13 @@ type NotificationSettingsInput struct {
 	WeeklyReports    *bool `json:"weeklyReports,omitempty"`
 }
 
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
 type PomodoroSession struct {
 	ID            string      `json:"id"`
 	Duration      int         `json:"duration"`
@@ -181,6 +288,16 @@ type PomodoroSession struct {
 	CreatedAt     time.Time   `json:"createdAt"`
 }
 
+type PomodoroSessionConnection struct {
+	Edges    []*PomodoroSessionEdge `json:"edges"`
+	PageInfo *PageInfo              `json:"pageInfo"`
+}
+
+type PomodoroSessionEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *PomodoroSession `json:"node"`
+}
+
 type PomodoroSettings struct {
 	WorkDuration           int  `json:"workDuration"`
 	ShortBreakDuration     int  `json:"shortBreakDuration"`
@@ -249,9 +366,60 @@ type ProjectCollaborator struct {
 	JoinedAt  *time.Time       `json:"joinedAt,omitempty"`
 }
 
+type ProjectConnection struct {
+	Edges    []*ProjectEdge `json:"edges"`
+	PageInfo *PageInfo      `json:"pageInfo"`
+}
+
+type ProjectEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Project `json:"node"`
+}
+
+type ProjectInvitation struct {
+	ID          string           `json:"id"`
+	ProjectID   string           `json:"projectId"`
+	ProjectName string           `json:"projectName"`
+	Email       string           `json:"email"`
+	Role        CollaboratorRole `json:"role"`
+	Status      InvitationStatus `json:"status"`
+	InvitedByID string           `json:"invitedById"`
+	InviteeID   *string          `json:"inviteeId,omitempty"`
+	Token       *string          `json:"token,omitempty"`
+	ExpiresAt   time.Time        `json:"expiresAt"`
+	RespondedAt *time.Time       `json:"respondedAt,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+}
+
 type Query struct {
 }
 
+type SavedView struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Query     string       `json:"query"`
+	Sort      *TaskSort    `json:"sort,omitempty"`
+	Reverse   bool         `json:"reverse"`
+	GroupBy   ViewGrouping `json:"groupBy"`
+	Pinned    bool         `json:"pinned"`
+	Shared    bool         `json:"shared"`
+	ProjectID *string      `json:"projectId,omitempty"`
+	UserID    string       `json:"userId"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+type SavedViewResult struct {
+	View   *SavedView   `json:"view"`
+	Total  int          `json:"total"`
+	Groups []*TaskGroup `json:"groups"`
+}
+
+type SearchHighlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
+
 type Skill struct {
 	ID          string        `json:"id"`
 	Name        string        `json:"name"`
@@ -300,6 +468,16 @@ type SprintAnalytics struct {
 	CompletionRate       float64          `json:"completionRate"`
 }
 
+type SprintConnection struct {
+	Edges    []*SprintEdge `json:"edges"`
+	PageInfo *PageInfo     `json:"pageInfo"`
+}
+
+type SprintEdge struct {
+	Cursor string  `json:"cursor"`
+	Node   *Sprint `json:"node"`
+}
+
 type SprintTask struct {
 	ID          string    `json:"id"`
 	SprintID    string    `json:"sprintId"`
@@ -313,11 +491,34 @@ type Subscription struct {
 }
 
 type Subtask struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Completed   bool       `json:"completed"`
+	Position    int        `json:"position"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	TaskID      string     `json:"taskId"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+type Tag struct {
 	ID        string    `json:"id"`
-	Title     string    `json:"title"`
-	Completed bool      `json:"completed"`
-	TaskID    string    `json:"taskId"`
+	Name      string    `json:"name"`
+	Color     *string   `json:"color,omitempty"`
+	UserID    string    `json:"userId"`
 	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type TagStat struct {
+	Tag            *Tag `json:"tag"`
+	TasksCompleted int  `json:"tasksCompleted"`
+	FocusTime      int  `json:"focusTime"`
+	XpEarned       int  `json:"xpEarned"`
+}
+
+type TagUsage struct {
+	Tag       *Tag `json:"tag"`
+	TaskCount int  `json:"taskCount"`
 }
 
 type Task struct {
@@ -329,7 +530,7 @@ type Task struct {
 	XpValue           int                `json:"xpValue"`
 	EstimatedDuration *int               `json:"estimatedDuration,omitempty"`
 	ActualDuration    *int               `json:"actualDuration,omitempty"`
-	Tags              []string           `json:"tags"`
+	Tags              []*Tag             `json:"tags"`
 	DueDate           *time.Time         `json:"dueDate,omitempty"`
 	CompletedAt       *time.Time         `json:"completedAt,omitempty"`
 	IsArchived        bool               `json:"isArchived"`
@@ -342,33 +543,88 @@ type Task struct {
 	Assignee          *User              `json:"assignee,omitempty"`
 	PomodoroSessions  []*PomodoroSession `json:"pomodoroSessions"`
 	Subtasks          []*Subtask         `json:"subtasks"`
+	Progress          int                `json:"progress"`
+	AutoComplete      bool               `json:"autoComplete"`
 	Comments          []*TaskComment     `json:"comments"`
 	Attachments       []*TaskAttachment  `json:"attachments"`
 	Dependencies      []*Task            `json:"dependencies"`
 	Dependents        []*Task            `json:"dependents"`
+	IsBlocked         bool               `json:"isBlocked"`
+	Recurrence        *TaskRecurrence    `json:"recurrence,omitempty"`
 	SkillCategory     *SkillCategory     `json:"skillCategory,omitempty"`
 	CreatedAt         time.Time          `json:"createdAt"`
 	UpdatedAt         time.Time          `json:"updatedAt"`
 }
 
 type TaskAttachment struct {
-	ID         string    `json:"id"`
-	Filename   string    `json:"filename"`
-	URL        string    `json:"url"`
-	Size       int       `json:"size"`
-	MimeType   string    `json:"mimeType"`
-	TaskID     string    `json:"taskId"`
-	UploadedBy string    `json:"uploadedBy"`
-	CreatedAt  time.Time `json:"createdAt"`
+	ID           string    `json:"id"`
+	Filename     string    `json:"filename"`
+	URL          string    `json:"url"`
+	URLExpiresAt time.Time `json:"urlExpiresAt"`
+	Size         int       `json:"size"`
+	MimeType     string    `json:"mimeType"`
+	TaskID       string    `json:"taskId"`
+	UploadedBy   string    `json:"uploadedBy"`
+	CreatedAt    time.Time `json:"createdAt"`
 }
 
 type TaskComment struct {
-	ID        string    `json:"id"`
-	Content   string    `json:"content"`
-	TaskID    string    `json:"taskId"`
-	UserID    string    `json:"userId"`
-	User      *User     `json:"user"`
-	CreatedAt time.Time `json:"createdAt"`
+	ID        string                 `json:"id"`
+	Content   string                 `json:"content"`
+	TaskID    string                 `json:"taskId"`
+	UserID    string                 `json:"userId"`
+	User      *User                  `json:"user"`
+	Mentions  []string               `json:"mentions"`
+	EditedAt  *time.Time             `json:"editedAt,omitempty"`
+	History   []*TaskCommentRevision `json:"history"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+type TaskCommentRevision struct {
+	ID         string    `json:"id"`
+	Content    string    `json:"content"`
+	ReplacedAt time.Time `json:"replacedAt"`
+}
+
+type TaskConnection struct {
+	Edges    []*TaskEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type TaskEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Task  `json:"node"`
+}
+
+type TaskGroup struct {
+	Key   string           `json:"key"`
+	Label string           `json:"label"`
+	Tasks []*TaskSearchHit `json:"tasks"`
+}
+
+type TaskRecurrence struct {
+	ID              string     `json:"id"`
+	Rule            string     `json:"rule"`
+	AfterCompletion bool       `json:"afterCompletion"`
+	StartsAt        time.Time  `json:"startsAt"`
+	CurrentStreak   int        `json:"currentStreak"`
+	LongestStreak   int        `json:"longestStreak"`
+	EndedAt         *time.Time `json:"endedAt,omitempty"`
+	Occurrences     []*Task    `json:"occurrences"`
+}
+
+type TaskSearchHit struct {
+	Task       *Task              `json:"task"`
+	Rank       float64            `json:"rank"`
+	Highlights []*SearchHighlight `json:"highlights"`
+}
+
+type TrashItem struct {
+	Kind      TrashItemKind `json:"kind"`
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	DeletedAt time.Time     `json:"deletedAt"`
+	ExpiresAt time.Time     `json:"expiresAt"`
 }
 
 type UpdateFolderInput struct {
@@ -379,6 +635,18 @@ type UpdateFolderInput struct {
 	IsArchived  *bool   `json:"isArchived,omitempty"`
 }
 
+type UpdateHabitInput struct {
+	Name        *string        `json:"name,omitempty"`
+	Description *string        `json:"description,omitempty"`
+	Icon        *string        `json:"icon,omitempty"`
+	Color       *string        `json:"color,omitempty"`
+	Category    *SkillCategory `json:"category,omitempty"`
+	Period      *HabitPeriod   `json:"period,omitempty"`
+	TargetCount *int           `json:"targetCount,omitempty"`
+	XpValue     *int           `json:"xpValue,omitempty"`
+	Archived    *bool          `json:"archived,omitempty"`
+}
+
 type UpdatePomodoroSessionInput struct {
 	Completed     *bool      `json:"completed,omitempty"`
 	EndTime       *time.Time `json:"endTime,omitempty"`
@@ -400,6 +668,18 @@ type UpdateProjectInput struct {
 	IsArchived  *bool          `json:"isArchived,omitempty"`
 }
 
+type UpdateSavedViewInput struct {
+	Name      *string       `json:"name,omitempty"`
+	Query     *string       `json:"query,omitempty"`
+	Sort      *TaskSort     `json:"sort,omitempty"`
+	ClearSort *bool         `json:"clearSort,omitempty"`
+	Reverse   *bool         `json:"reverse,omitempty"`
+	GroupBy   *ViewGrouping `json:"groupBy,omitempty"`
+	Pinned    *bool         `json:"pinned,omitempty"`
+	ProjectID *string       `json:"projectId,omitempty"`
+	Shared    *bool         `json:"shared,omitempty"`
+}
+
 type UpdateSprintInput struct {
 	Name        *string       `json:"name,omitempty"`
 	Description *string       `json:"description,omitempty"`
@@ -409,6 +689,11 @@ type UpdateSprintInput struct {
 	EndDate     *time.Time    `json:"endDate,omitempty"`
 }
 
+type UpdateSubtaskInput struct {
+	Title     *string `json:"title,omitempty"`
+	Completed *bool   `json:"completed,omitempty"`
+}
+
 type UpdateTaskInput struct {
 	Title             *string        `json:"title,omitempty"`
 	Description       *string        `json:"description,omitempty"`
@@ -434,6 +719,13 @@ type UpdateUserPreferencesInput struct {
 	Timezone         *string                    `json:"timezone,omitempty"`
 }
 
+type UpdateWebhookInput struct {
+	URL         *string  `json:"url,omitempty"`
+	Events      []string `json:"events,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
 type User struct {
 	ID            string           `json:"id"`
 	Email         string           `json:"email"`
@@ -472,6 +764,33 @@ type UserPreferences struct {
 	Timezone         string                `json:"timezone"`
 }
 
+type Webhook struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Description *string   `json:"description,omitempty"`
+	Events      []string  `json:"events"`
+	Active      bool      `json:"active"`
+	Secret      string    `json:"secret"`
+	UserID      string    `json:"userId"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type WebhookDelivery struct {
+	ID            string                `json:"id"`
+	WebhookID     string                `json:"webhookId"`
+	Event         string                `json:"event"`
+	Payload       string                `json:"payload"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int                   `json:"attempts"`
+	ResponseCode  *int                  `json:"responseCode,omitempty"`
+	ResponseBody  *string               `json:"responseBody,omitempty"`
+	Error         *string               `json:"error,omitempty"`
+	NextAttemptAt *time.Time            `json:"nextAttemptAt,omitempty"`
+	DeliveredAt   *time.Time            `json:"deliveredAt,omitempty"`
+	CreatedAt     time.Time             `json:"createdAt"`
+}
+
 type WeeklyStat struct {
 	WeekStart           time.Time `json:"weekStart"`
 	TasksCompleted      int       `json:"tasksCompleted"`
@@ -599,58 +918,52 @@ func (e CollaboratorRole) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-type NotificationType string
+type FolderDeleteMode string
 
 const (
-	NotificationTypeTaskDue             NotificationType = "TASK_DUE"
-	NotificationTypeSessionReminder     NotificationType = "SESSION_REMINDER"
-	NotificationTypeAchievementUnlocked NotificationType = "ACHIEVEMENT_UNLOCKED"
-	NotificationTypeBadgeEarned         NotificationType = "BADGE_EARNED"
-	NotificationTypeSprintCompleted     NotificationType = "SPRINT_COMPLETED"
-	NotificationTypeCollaborationInvite NotificationType = "COLLABORATION_INVITE"
-	NotificationTypeSystemUpdate        NotificationType = "SYSTEM_UPDATE"
+	FolderDeleteModeRefuse  FolderDeleteMode = "REFUSE"
+	FolderDeleteModeMove    FolderDeleteMode = "MOVE"
+	FolderDeleteModeArchive FolderDeleteMode = "ARCHIVE"
+	FolderDeleteModeTrash   FolderDeleteMode = "TRASH"
 )
 
-var AllNotificationType = []NotificationType{
-	NotificationTypeTaskDue,
-	NotificationTypeSessionReminder,
-	NotificationTypeAchievementUnlocked,
-	NotificationTypeBadgeEarned,
-	NotificationTypeSprintCompleted,
-	NotificationTypeCollaborationInvite,
-	NotificationTypeSystemUpdate,
+var AllFolderDeleteMode = []FolderDeleteMode{
+	FolderDeleteModeRefuse,
+	FolderDeleteModeMove,
+	FolderDeleteModeArchive,
+	FolderDeleteModeTrash,
 }
 
-func (e NotificationType) IsValid() bool {
+func (e FolderDeleteMode) IsValid() bool {
 	switch e {
-	case NotificationTypeTaskDue, NotificationTypeSessionReminder, NotificationTypeAchievementUnlocked, NotificationTypeBadgeEarned, NotificationTypeSprintCompleted, NotificationTypeCollaborationInvite, NotificationTypeSystemUpdate:
+	case FolderDeleteModeRefuse, FolderDeleteModeMove, FolderDeleteModeArchive, FolderDeleteModeTrash:
 		return true
 	}
 	return false
 }
 
-func (e NotificationType) String() string {
+func (e FolderDeleteMode) String() string {
 	return string(e)
 }
 
-func (e *NotificationType) UnmarshalGQL(v any) error {
+func (e *FolderDeleteMode) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = NotificationType(str)
+	*e = FolderDeleteMode(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid NotificationType", str)
+		return fmt.Errorf("%s is not a valid FolderDeleteMode", str)
 	}
 	return nil
 }
 
-func (e NotificationType) MarshalGQL(w io.Writer) {
+func (e FolderDeleteMode) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *NotificationType) UnmarshalJSON(b []byte) error {
+func (e *FolderDeleteMode) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -658,58 +971,56 @@ func (e *NotificationType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e NotificationType) MarshalJSON() ([]byte, error) {
+func (e FolderDeleteMode) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type Priority string
+type HabitPeriod string
 
 const (
-	PriorityLow    Priority = "LOW"
-	PriorityMedium Priority = "MEDIUM"
-	PriorityHigh   Priority = "HIGH"
-	PriorityUrgent Priority = "URGENT"
+	HabitPeriodDaily   HabitPeriod = "DAILY"
+	HabitPeriodWeekly  HabitPeriod = "WEEKLY"
+	HabitPeriodMonthly HabitPeriod = "MONTHLY"
 )
 
-var AllPriority = []Priority{
-	PriorityLow,
-	PriorityMedium,
-	PriorityHigh,
-	PriorityUrgent,
+var AllHabitPeriod = []HabitPeriod{
+	HabitPeriodDaily,
+	HabitPeriodWeekly,
+	HabitPeriodMonthly,
 }
 
-func (e Priority) IsValid() bool {
+func (e HabitPeriod) IsValid() bool {
 	switch e {
-	case PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+	case HabitPeriodDaily, HabitPeriodWeekly, HabitPeriodMonthly:
 		return true
 	}
 	return false
 }
 
-func (e Priority) String() string {
+func (e HabitPeriod) String() string {
 	return string(e)
 }
 
-func (e *Priority) UnmarshalGQL(v any) error {
+func (e *HabitPeriod) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = Priority(str)
+	*e = HabitPeriod(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid Priority", str)
+		return fmt.Errorf("%s is not a valid HabitPeriod", str)
 	}
 	return nil
 }
 
-func (e Priority) MarshalGQL(w io.Writer) {
+func (e HabitPeriod) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *Priority) UnmarshalJSON(b []byte) error {
+func (e *HabitPeriod) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -717,60 +1028,56 @@ func (e *Priority) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e Priority) MarshalJSON() ([]byte, error) {
+func (e HabitPeriod) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type ProjectStatus string
+type ImportAction string
 
 const (
-	ProjectStatusPlanning   ProjectStatus = "PLANNING"
-	ProjectStatusInProgress ProjectStatus = "IN_PROGRESS"
-	ProjectStatusOnHold     ProjectStatus = "ON_HOLD"
-	ProjectStatusCompleted  ProjectStatus = "COMPLETED"
-	ProjectStatusCancelled  ProjectStatus = "CANCELLED"
+	ImportActionCreate    ImportAction = "CREATE"
+	ImportActionDuplicate ImportAction = "DUPLICATE"
+	ImportActionSkip      ImportAction = "SKIP"
 )
 
-var AllProjectStatus = []ProjectStatus{
-	ProjectStatusPlanning,
-	ProjectStatusInProgress,
-	ProjectStatusOnHold,
-	ProjectStatusCompleted,
-	ProjectStatusCancelled,
+var AllImportAction = []ImportAction{
+	ImportActionCreate,
+	ImportActionDuplicate,
+	ImportActionSkip,
 }
 
-func (e ProjectStatus) IsValid() bool {
+func (e ImportAction) IsValid() bool {
 	switch e {
-	case ProjectStatusPlanning, ProjectStatusInProgress, ProjectStatusOnHold, ProjectStatusCompleted, ProjectStatusCancelled:
+	case ImportActionCreate, ImportActionDuplicate, ImportActionSkip:
 		return true
 	}
 	return false
 }
 
-func (e ProjectStatus) String() string {
+func (e ImportAction) String() string {
 	return string(e)
 }
 
-func (e *ProjectStatus) UnmarshalGQL(v any) error {
+func (e *ImportAction) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = ProjectStatus(str)
+	*e = ImportAction(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid ProjectStatus", str)
+		return fmt.Errorf("%s is not a valid ImportAction", str)
 	}
 	return nil
 }
 
-func (e ProjectStatus) MarshalGQL(w io.Writer) {
+func (e ImportAction) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *ProjectStatus) UnmarshalJSON(b []byte) error {
+func (e *ImportAction) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -778,56 +1085,58 @@ func (e *ProjectStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e ProjectStatus) MarshalJSON() ([]byte, error) {
+func (e ImportAction) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type SessionType string
+type ImportFormat string
 
 const (
-	SessionTypeWork       SessionType = "WORK"
-	SessionTypeShortBreak SessionType = "SHORT_BREAK"
-	SessionTypeLongBreak  SessionType = "LONG_BREAK"
+	ImportFormatCSV         ImportFormat = "CSV"
+	ImportFormatTrello      ImportFormat = "TRELLO"
+	ImportFormatTodoistJSON ImportFormat = "TODOIST_JSON"
+	ImportFormatTodoistCSV  ImportFormat = "TODOIST_CSV"
 )
 
-var AllSessionType = []SessionType{
-	SessionTypeWork,
-	SessionTypeShortBreak,
-	SessionTypeLongBreak,
+var AllImportFormat = []ImportFormat{
+	ImportFormatCSV,
+	ImportFormatTrello,
+	ImportFormatTodoistJSON,
+	ImportFormatTodoistCSV,
 }
 
-func (e SessionType) IsValid() bool {
+func (e ImportFormat) IsValid() bool {
 	switch e {
-	case SessionTypeWork, SessionTypeShortBreak, SessionTypeLongBreak:
+	case ImportFormatCSV, ImportFormatTrello, ImportFormatTodoistJSON, ImportFormatTodoistCSV:
 		return true
 	}
 	return false
 }
 
-func (e SessionType) String() string {
+func (e ImportFormat) String() string {
 	return string(e)
 }
 
-func (e *SessionType) UnmarshalGQL(v any) error {
+func (e *ImportFormat) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = SessionType(str)
+	*e = ImportFormat(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid SessionType", str)
+		return fmt.Errorf("%s is not a valid ImportFormat", str)
 	}
 	return nil
 }
 
-func (e SessionType) MarshalGQL(w io.Writer) {
+func (e ImportFormat) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *SessionType) UnmarshalJSON(b []byte) error {
+func (e *ImportFormat) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -835,64 +1144,60 @@ func (e *SessionType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e SessionType) MarshalJSON() ([]byte, error) {
+func (e ImportFormat) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type SkillCategory string
+type InvitationStatus string
 
 const (
-	SkillCategoryProductivity SkillCategory = "PRODUCTIVITY"
-	SkillCategoryHealth       SkillCategory = "HEALTH"
-	SkillCategoryLearning     SkillCategory = "LEARNING"
-	SkillCategoryCreativity   SkillCategory = "CREATIVITY"
-	SkillCategorySocial       SkillCategory = "SOCIAL"
-	SkillCategoryFinance      SkillCategory = "FINANCE"
-	SkillCategoryPersonal     SkillCategory = "PERSONAL"
+	InvitationStatusPending  InvitationStatus = "PENDING"
+	InvitationStatusAccepted InvitationStatus = "ACCEPTED"
+	InvitationStatusDeclined InvitationStatus = "DECLINED"
+	InvitationStatusRevoked  InvitationStatus = "REVOKED"
+	InvitationStatusExpired  InvitationStatus = "EXPIRED"
 )
 
-var AllSkillCategory = []SkillCategory{
-	SkillCategoryProductivity,
-	SkillCategoryHealth,
-	SkillCategoryLearning,
-	SkillCategoryCreativity,
-	SkillCategorySocial,
-	SkillCategoryFinance,
-	SkillCategoryPersonal,
+var AllInvitationStatus = []InvitationStatus{
+	InvitationStatusPending,
+	InvitationStatusAccepted,
+	InvitationStatusDeclined,
+	InvitationStatusRevoked,
+	InvitationStatusExpired,
 }
 
-func (e SkillCategory) IsValid() bool {
+func (e InvitationStatus) IsValid() bool {
 	switch e {
-	case SkillCategoryProductivity, SkillCategoryHealth, SkillCategoryLearning, SkillCategoryCreativity, SkillCategorySocial, SkillCategoryFinance, SkillCategoryPersonal:
+	case InvitationStatusPending, InvitationStatusAccepted, InvitationStatusDeclined, InvitationStatusRevoked, InvitationStatusExpired:
 		return true
 	}
 	return false
 }
 
-func (e SkillCategory) String() string {
+func (e InvitationStatus) String() string {
 	return string(e)
 }
 
-func (e *SkillCategory) UnmarshalGQL(v any) error {
+func (e *InvitationStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = SkillCategory(str)
+	*e = InvitationStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid SkillCategory", str)
+		return fmt.Errorf("%s is not a valid InvitationStatus", str)
 	}
 	return nil
 }
 
-func (e SkillCategory) MarshalGQL(w io.Writer) {
+func (e InvitationStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *SkillCategory) UnmarshalJSON(b []byte) error {
+func (e *InvitationStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -900,58 +1205,66 @@ func (e *SkillCategory) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e SkillCategory) MarshalJSON() ([]byte, error) {
+func (e InvitationStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type SprintStatus string
+type NotificationType string
 
 const (
-	SprintStatusPlanning  SprintStatus = "PLANNING"
-	SprintStatusActive    SprintStatus = "ACTIVE"
-	SprintStatusCompleted SprintStatus = "COMPLETED"
-	SprintStatusCancelled SprintStatus = "CANCELLED"
+	NotificationTypeTaskDue             NotificationType = "TASK_DUE"
+	NotificationTypeSessionReminder     NotificationType = "SESSION_REMINDER"
+	NotificationTypeAchievementUnlocked NotificationType = "ACHIEVEMENT_UNLOCKED"
+	NotificationTypeBadgeEarned         NotificationType = "BADGE_EARNED"
+	NotificationTypeSprintCompleted     NotificationType = "SPRINT_COMPLETED"
+	NotificationTypeCollaborationInvite NotificationType = "COLLABORATION_INVITE"
+	NotificationTypeCommentMention      NotificationType = "COMMENT_MENTION"
+	NotificationTypeSystemUpdate        NotificationType = "SYSTEM_UPDATE"
 )
 
-var AllSprintStatus = []SprintStatus{
-	SprintStatusPlanning,
-	SprintStatusActive,
-	SprintStatusCompleted,
-	SprintStatusCancelled,
+var AllNotificationType = []NotificationType{
+	NotificationTypeTaskDue,
+	NotificationTypeSessionReminder,
+	NotificationTypeAchievementUnlocked,
+	NotificationTypeBadgeEarned,
+	NotificationTypeSprintCompleted,
+	NotificationTypeCollaborationInvite,
+	NotificationTypeCommentMention,
+	NotificationTypeSystemUpdate,
 }
 
-func (e SprintStatus) IsValid() bool {
+func (e NotificationType) IsValid() bool {
 	switch e {
-	case SprintStatusPlanning, SprintStatusActive, SprintStatusCompleted, SprintStatusCancelled:
+	case NotificationTypeTaskDue, NotificationTypeSessionReminder, NotificationTypeAchievementUnlocked, NotificationTypeBadgeEarned, NotificationTypeSprintCompleted, NotificationTypeCollaborationInvite, NotificationTypeCommentMention, NotificationTypeSystemUpdate:
 		return true
 	}
 	return false
 }
 
-func (e SprintStatus) String() string {
+func (e NotificationType) String() string {
 	return string(e)
 }
 
-func (e *SprintStatus) UnmarshalGQL(v any) error {
+func (e *NotificationType) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = SprintStatus(str)
+	*e = NotificationType(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid SprintStatus", str)
+		return fmt.Errorf("%s is not a valid NotificationType", str)
 	}
 	return nil
 }
 
-func (e SprintStatus) MarshalGQL(w io.Writer) {
+func (e NotificationType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *SprintStatus) UnmarshalJSON(b []byte) error {
+func (e *NotificationType) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -959,27 +1272,684 @@ func (e *SprintStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e SprintStatus) MarshalJSON() ([]byte, error) {
+func (e NotificationType) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type TaskStatus string
+type PomodoroSessionSortKey string
 
 const (
-	TaskStatusTodo       TaskStatus = "TODO"
-	TaskStatusInProgress TaskStatus = "IN_PROGRESS"
-	TaskStatusInReview   TaskStatus = "IN_REVIEW"
-	TaskStatusCompleted  TaskStatus = "COMPLETED"
-	TaskStatusCancelled  TaskStatus = "CANCELLED"
+	PomodoroSessionSortKeyStartTime PomodoroSessionSortKey = "START_TIME"
+	PomodoroSessionSortKeyCreatedAt PomodoroSessionSortKey = "CREATED_AT"
 )
 
-var AllTaskStatus = []TaskStatus{
-	TaskStatusTodo,
-	TaskStatusInProgress,
-	TaskStatusInReview,
-	TaskStatusCompleted,
+var AllPomodoroSessionSortKey = []PomodoroSessionSortKey{
+	PomodoroSessionSortKeyStartTime,
+	PomodoroSessionSortKeyCreatedAt,
+}
+
+func (e PomodoroSessionSortKey) IsValid() bool {
+	switch e {
+	case PomodoroSessionSortKeyStartTime, PomodoroSessionSortKeyCreatedAt:
+		return true
+	}
+	return false
+}
+
+func (e PomodoroSessionSortKey) String() string {
+	return string(e)
+}
+
+func (e *PomodoroSessionSortKey) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PomodoroSessionSortKey(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PomodoroSessionSortKey", str)
+	}
+	return nil
+}
+
+func (e PomodoroSessionSortKey) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PomodoroSessionSortKey) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PomodoroSessionSortKey) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type Priority string
+
+const (
+	PriorityLow    Priority = "LOW"
+	PriorityMedium Priority = "MEDIUM"
+	PriorityHigh   Priority = "HIGH"
+	PriorityUrgent Priority = "URGENT"
+)
+
+var AllPriority = []Priority{
+	PriorityLow,
+	PriorityMedium,
+	PriorityHigh,
+	PriorityUrgent,
+}
+
+func (e Priority) IsValid() bool {
+	switch e {
+	case PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
+}
+
+func (e Priority) String() string {
+	return string(e)
+}
+
+func (e *Priority) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Priority(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Priority", str)
+	}
+	return nil
+}
+
+func (e Priority) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Priority) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Priority) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ProjectSortKey string
+
+const (
+	ProjectSortKeyCreatedAt ProjectSortKey = "CREATED_AT"
+	ProjectSortKeyUpdatedAt ProjectSortKey = "UPDATED_AT"
+	ProjectSortKeyName      ProjectSortKey = "NAME"
+	ProjectSortKeyDueDate   ProjectSortKey = "DUE_DATE"
+)
+
+var AllProjectSortKey = []ProjectSortKey{
+	ProjectSortKeyCreatedAt,
+	ProjectSortKeyUpdatedAt,
+	ProjectSortKeyName,
+	ProjectSortKeyDueDate,
+}
+
+func (e ProjectSortKey) IsValid() bool {
+	switch e {
+	case ProjectSortKeyCreatedAt, ProjectSortKeyUpdatedAt, ProjectSortKeyName, ProjectSortKeyDueDate:
+		return true
+	}
+	return false
+}
+
+func (e ProjectSortKey) String() string {
+	return string(e)
+}
+
+func (e *ProjectSortKey) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProjectSortKey(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProjectSortKey", str)
+	}
+	return nil
+}
+
+func (e ProjectSortKey) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ProjectSortKey) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ProjectSortKey) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ProjectStatus string
+
+const (
+	ProjectStatusPlanning   ProjectStatus = "PLANNING"
+	ProjectStatusInProgress ProjectStatus = "IN_PROGRESS"
+	ProjectStatusOnHold     ProjectStatus = "ON_HOLD"
+	ProjectStatusCompleted  ProjectStatus = "COMPLETED"
+	ProjectStatusCancelled  ProjectStatus = "CANCELLED"
+)
+
+var AllProjectStatus = []ProjectStatus{
+	ProjectStatusPlanning,
+	ProjectStatusInProgress,
+	ProjectStatusOnHold,
+	ProjectStatusCompleted,
+	ProjectStatusCancelled,
+}
+
+func (e ProjectStatus) IsValid() bool {
+	switch e {
+	case ProjectStatusPlanning, ProjectStatusInProgress, ProjectStatusOnHold, ProjectStatusCompleted, ProjectStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (e ProjectStatus) String() string {
+	return string(e)
+}
+
+func (e *ProjectStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProjectStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProjectStatus", str)
+	}
+	return nil
+}
+
+func (e ProjectStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ProjectStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ProjectStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ResourceKind string
+
+const (
+	ResourceKindProject    ResourceKind = "PROJECT"
+	ResourceKindTask       ResourceKind = "TASK"
+	ResourceKindSprint     ResourceKind = "SPRINT"
+	ResourceKindComment    ResourceKind = "COMMENT"
+	ResourceKindAttachment ResourceKind = "ATTACHMENT"
+)
+
+var AllResourceKind = []ResourceKind{
+	ResourceKindProject,
+	ResourceKindTask,
+	ResourceKindSprint,
+	ResourceKindComment,
+	ResourceKindAttachment,
+}
+
+func (e ResourceKind) IsValid() bool {
+	switch e {
+	case ResourceKindProject, ResourceKindTask, ResourceKindSprint, ResourceKindComment, ResourceKindAttachment:
+		return true
+	}
+	return false
+}
+
+func (e ResourceKind) String() string {
+	return string(e)
+}
+
+func (e *ResourceKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ResourceKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ResourceKind", str)
+	}
+	return nil
+}
+
+func (e ResourceKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ResourceKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ResourceKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SessionType string
+
+const (
+	SessionTypeWork       SessionType = "WORK"
+	SessionTypeShortBreak SessionType = "SHORT_BREAK"
+	SessionTypeLongBreak  SessionType = "LONG_BREAK"
+)
+
+var AllSessionType = []SessionType{
+	SessionTypeWork,
+	SessionTypeShortBreak,
+	SessionTypeLongBreak,
+}
+
+func (e SessionType) IsValid() bool {
+	switch e {
+	case SessionTypeWork, SessionTypeShortBreak, SessionTypeLongBreak:
+		return true
+	}
+	return false
+}
+
+func (e SessionType) String() string {
+	return string(e)
+}
+
+func (e *SessionType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SessionType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SessionType", str)
+	}
+	return nil
+}
+
+func (e SessionType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SessionType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SessionType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SkillCategory string
+
+const (
+	SkillCategoryProductivity SkillCategory = "PRODUCTIVITY"
+	SkillCategoryHealth       SkillCategory = "HEALTH"
+	SkillCategoryLearning     SkillCategory = "LEARNING"
+	SkillCategoryCreativity   SkillCategory = "CREATIVITY"
+	SkillCategorySocial       SkillCategory = "SOCIAL"
+	SkillCategoryFinance      SkillCategory = "FINANCE"
+	SkillCategoryPersonal     SkillCategory = "PERSONAL"
+)
+
+var AllSkillCategory = []SkillCategory{
+	SkillCategoryProductivity,
+	SkillCategoryHealth,
+	SkillCategoryLearning,
+	SkillCategoryCreativity,
+	SkillCategorySocial,
+	SkillCategoryFinance,
+	SkillCategoryPersonal,
+}
+
+func (e SkillCategory) IsValid() bool {
+	switch e {
+	case SkillCategoryProductivity, SkillCategoryHealth, SkillCategoryLearning, SkillCategoryCreativity, SkillCategorySocial, SkillCategoryFinance, SkillCategoryPersonal:
+		return true
+	}
+	return false
+}
+
+func (e SkillCategory) String() string {
+	return string(e)
+}
+
+func (e *SkillCategory) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SkillCategory(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SkillCategory", str)
+	}
+	return nil
+}
+
+func (e SkillCategory) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SkillCategory) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SkillCategory) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SprintSortKey string
+
+const (
+	SprintSortKeyStartDate SprintSortKey = "START_DATE"
+	SprintSortKeyCreatedAt SprintSortKey = "CREATED_AT"
+	SprintSortKeyName      SprintSortKey = "NAME"
+)
+
+var AllSprintSortKey = []SprintSortKey{
+	SprintSortKeyStartDate,
+	SprintSortKeyCreatedAt,
+	SprintSortKeyName,
+}
+
+func (e SprintSortKey) IsValid() bool {
+	switch e {
+	case SprintSortKeyStartDate, SprintSortKeyCreatedAt, SprintSortKeyName:
+		return true
+	}
+	return false
+}
+
+func (e SprintSortKey) String() string {
+	return string(e)
+}
+
+func (e *SprintSortKey) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SprintSortKey(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SprintSortKey", str)
+	}
+	return nil
+}
+
+func (e SprintSortKey) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SprintSortKey) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SprintSortKey) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SprintStatus string
+
+const (
+	SprintStatusPlanning  SprintStatus = "PLANNING"
+	SprintStatusActive    SprintStatus = "ACTIVE"
+	SprintStatusCompleted SprintStatus = "COMPLETED"
+	SprintStatusCancelled SprintStatus = "CANCELLED"
+)
+
+var AllSprintStatus = []SprintStatus{
+	SprintStatusPlanning,
+	SprintStatusActive,
+	SprintStatusCompleted,
+	SprintStatusCancelled,
+}
+
+func (e SprintStatus) IsValid() bool {
+	switch e {
+	case SprintStatusPlanning, SprintStatusActive, SprintStatusCompleted, SprintStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (e SprintStatus) String() string {
+	return string(e)
+}
+
+func (e *SprintStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SprintStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SprintStatus", str)
+	}
+	return nil
+}
+
+func (e SprintStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SprintStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SprintStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TaskSort string
+
+const (
+	TaskSortRelevance TaskSort = "RELEVANCE"
+	TaskSortUpdated   TaskSort = "UPDATED"
+	TaskSortCreated   TaskSort = "CREATED"
+	TaskSortDue       TaskSort = "DUE"
+	TaskSortPriority  TaskSort = "PRIORITY"
+	TaskSortTitle     TaskSort = "TITLE"
+)
+
+var AllTaskSort = []TaskSort{
+	TaskSortRelevance,
+	TaskSortUpdated,
+	TaskSortCreated,
+	TaskSortDue,
+	TaskSortPriority,
+	TaskSortTitle,
+}
+
+func (e TaskSort) IsValid() bool {
+	switch e {
+	case TaskSortRelevance, TaskSortUpdated, TaskSortCreated, TaskSortDue, TaskSortPriority, TaskSortTitle:
+		return true
+	}
+	return false
+}
+
+func (e TaskSort) String() string {
+	return string(e)
+}
+
+func (e *TaskSort) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskSort", str)
+	}
+	return nil
+}
+
+func (e TaskSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TaskSort) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TaskSort) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TaskSortKey string
+
+const (
+	TaskSortKeyCreatedAt   TaskSortKey = "CREATED_AT"
+	TaskSortKeyUpdatedAt   TaskSortKey = "UPDATED_AT"
+	TaskSortKeyDueDate     TaskSortKey = "DUE_DATE"
+	TaskSortKeyTitle       TaskSortKey = "TITLE"
+	TaskSortKeyTopological TaskSortKey = "TOPOLOGICAL"
+)
+
+var AllTaskSortKey = []TaskSortKey{
+	TaskSortKeyCreatedAt,
+	TaskSortKeyUpdatedAt,
+	TaskSortKeyDueDate,
+	TaskSortKeyTitle,
+	TaskSortKeyTopological,
+}
+
+func (e TaskSortKey) IsValid() bool {
+	switch e {
+	case TaskSortKeyCreatedAt, TaskSortKeyUpdatedAt, TaskSortKeyDueDate, TaskSortKeyTitle, TaskSortKeyTopological:
+		return true
+	}
+	return false
+}
+
+func (e TaskSortKey) String() string {
+	return string(e)
+}
+
+func (e *TaskSortKey) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskSortKey(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskSortKey", str)
+	}
+	return nil
+}
+
+func (e TaskSortKey) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TaskSortKey) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TaskSortKey) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TaskStatus string
+
+const (
+	TaskStatusTodo       TaskStatus = "TODO"
+	TaskStatusInProgress TaskStatus = "IN_PROGRESS"
+	TaskStatusInReview   TaskStatus = "IN_REVIEW"
+	TaskStatusCompleted  TaskStatus = "COMPLETED"
+	TaskStatusCancelled  TaskStatus = "CANCELLED"
+)
+
+var AllTaskStatus = []TaskStatus{
+	TaskStatusTodo,
+	TaskStatusInProgress,
+	TaskStatusInReview,
+	TaskStatusCompleted,
 	TaskStatusCancelled,
 }
 
@@ -1025,3 +1995,184 @@ func (e TaskStatus) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+type TrashItemKind string
+
+const (
+	TrashItemKindFolder  TrashItemKind = "FOLDER"
+	TrashItemKindProject TrashItemKind = "PROJECT"
+	TrashItemKindTask    TrashItemKind = "TASK"
+	TrashItemKindSprint  TrashItemKind = "SPRINT"
+	TrashItemKindSession TrashItemKind = "SESSION"
+)
+
+var AllTrashItemKind = []TrashItemKind{
+	TrashItemKindFolder,
+	TrashItemKindProject,
+	TrashItemKindTask,
+	TrashItemKindSprint,
+	TrashItemKindSession,
+}
+
+func (e TrashItemKind) IsValid() bool {
+	switch e {
+	case TrashItemKindFolder, TrashItemKindProject, TrashItemKindTask, TrashItemKindSprint, TrashItemKindSession:
+		return true
+	}
+	return false
+}
+
+func (e TrashItemKind) String() string {
+	return string(e)
+}
+
+func (e *TrashItemKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TrashItemKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TrashItemKind", str)
+	}
+	return nil
+}
+
+func (e TrashItemKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TrashItemKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TrashItemKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ViewGrouping string
+
+const (
+	ViewGroupingNone     ViewGrouping = "NONE"
+	ViewGroupingStatus   ViewGrouping = "STATUS"
+	ViewGroupingPriority ViewGrouping = "PRIORITY"
+	ViewGroupingProject  ViewGrouping = "PROJECT"
+	ViewGroupingDue      ViewGrouping = "DUE"
+	ViewGroupingTag      ViewGrouping = "TAG"
+)
+
+var AllViewGrouping = []ViewGrouping{
+	ViewGroupingNone,
+	ViewGroupingStatus,
+	ViewGroupingPriority,
+	ViewGroupingProject,
+	ViewGroupingDue,
+	ViewGroupingTag,
+}
+
+func (e ViewGrouping) IsValid() bool {
+	switch e {
+	case ViewGroupingNone, ViewGroupingStatus, ViewGroupingPriority, ViewGroupingProject, ViewGroupingDue, ViewGroupingTag:
+		return true
+	}
+	return false
+}
+
+func (e ViewGrouping) String() string {
+	return string(e)
+}
+
+func (e *ViewGrouping) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ViewGrouping(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ViewGrouping", str)
+	}
+	return nil
+}
+
+func (e ViewGrouping) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ViewGrouping) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ViewGrouping) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+var AllWebhookDeliveryStatus = []WebhookDeliveryStatus{
+	WebhookDeliveryStatusPending,
+	WebhookDeliveryStatusSucceeded,
+	WebhookDeliveryStatusFailed,
+}
+
+func (e WebhookDeliveryStatus) IsValid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusSucceeded, WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e WebhookDeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookDeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookDeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e WebhookDeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookDeliveryStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -1,4 +1,3 @@
 package model
-package model
 
-// This file is a placeholder to create the package
\ No newline at end of file
+// This file is a placeholder to create the package
@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"strings"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/pagination"
+)
+
+// pageArgs maps connection arguments onto pagination.Args, turning a sort
+// key enum such as DUE_DATE into its key name, dueDate.
+func pageArgs[K ~string](first *int, after *string, sortBy *K, descending *bool) pagination.Args {
+	args := pagination.Args{Descending: descending}
+	if first != nil {
+		args.First = *first
+	}
+	if after != nil {
+		args.After = *after
+	}
+	if sortBy != nil {
+		words := strings.Split(strings.ToLower(string(*sortBy)), "_")
+		for i := 1; i < len(words); i++ {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+		args.Sort = strings.Join(words, "")
+	}
+	return args
+}
+
+func pageInfoToModel[T any](page *pagination.Page[T]) *model.PageInfo {
+	info := &model.PageInfo{
+		HasNextPage:     page.HasNextPage,
+		HasPreviousPage: page.HasPreviousPage,
+	}
+	if len(page.Cursors) > 0 {
+		start, end := page.StartCursor(), page.EndCursor()
+		info.StartCursor = &start
+		info.EndCursor = &end
+	}
+	return info
+}
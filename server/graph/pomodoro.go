@@ -0,0 +1,27 @@
+package graph
+
+import (
+	"strings"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/db"
+)
+
+func pomodoroSessionToModel(session *db.PomodoroSessionModel) *model.PomodoroSession {
+	result := &model.PomodoroSession{
+		ID:          session.ID,
+		Duration:    session.Duration,
+		Completed:   session.Status == "completed",
+		StartTime:   session.StartTime,
+		UserID:      session.UserID,
+		SessionType: model.SessionType(strings.ToUpper(strings.ReplaceAll(session.Type, "-", "_"))),
+		CreatedAt:   session.CreatedAt,
+	}
+	if endTime, ok := session.EndTime(); ok {
+		result.EndTime = &endTime
+	}
+	if taskID, ok := session.TaskID(); ok {
+		result.TaskID = &taskID
+	}
+	return result
+}
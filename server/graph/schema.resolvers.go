@@ -6,15 +6,59 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"lifequest-server/graph/generated"
 	"lifequest-server/graph/model"
+	"lifequest-server/internal/analytics"
+	"lifequest-server/internal/attachments"
+	"lifequest-server/internal/auth"
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/collaborators"
+	"lifequest-server/internal/comments"
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/folders"
+	"lifequest-server/internal/habits"
+	"lifequest-server/internal/ical"
+	"lifequest-server/internal/importer"
+	"lifequest-server/internal/notifications"
+	"lifequest-server/internal/pagination"
+	"lifequest-server/internal/search"
+	"lifequest-server/internal/sprints"
+	"lifequest-server/internal/tags"
+	"lifequest-server/internal/tasks"
+	"lifequest-server/internal/trash"
+	"lifequest-server/internal/utils"
+	"lifequest-server/internal/views"
+	"lifequest-server/internal/webhooks"
+	"lifequest-server/internal/xp"
+	"strings"
 	"time"
+
+	"github.com/99designs/gqlgen/graphql"
 )
 
 // CreateUser is the resolver for the createUser field.
 func (r *mutationResolver) CreateUser(ctx context.Context, input model.CreateUserInput) (*model.User, error) {
-	panic(fmt.Errorf("not implemented: CreateUser - createUser"))
+	client := database.GetClient()
+
+	// Emails are stored lower-cased, as invitations match them
+	user, err := client.User.CreateOne(
+		db.User.Email.Set(strings.ToLower(strings.TrimSpace(input.Email))),
+		db.User.ID.Set(utils.GenerateUUID()),
+		db.User.FirstName.SetIfPresent(input.FirstName),
+		db.User.LastName.SetIfPresent(input.LastName),
+		db.User.Avatar.SetIfPresent(input.AvatarURL),
+	).Exec(ctx)
+	if err != nil {
+		if _, ok := db.IsErrUniqueConstraint(err); ok {
+			return nil, errors.New("email is already registered")
+		}
+		return nil, err
+	}
+	return userToModel(user), nil
 }
 
 // UpdateUser is the resolver for the updateUser field.
@@ -29,7 +73,42 @@ func (r *mutationResolver) UpdateUserPreferences(ctx context.Context, input mode
 
 // CreateFolder is the resolver for the createFolder field.
 func (r *mutationResolver) CreateFolder(ctx context.Context, input model.CreateFolderInput) (*model.Folder, error) {
-	panic(fmt.Errorf("not implemented: CreateFolder - createFolder"))
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	params := []db.FolderSetParam{
+		db.Folder.Description.SetIfPresent(input.Description),
+	}
+	if input.ParentID != nil && *input.ParentID != "" {
+		forest, err := folders.Load(ctx, client, userID)
+		if err != nil {
+			return nil, err
+		}
+		if err := forest.CheckParent("", *input.ParentID); err != nil {
+			return nil, err
+		}
+		params = append(params, db.Folder.Parent.Link(db.Folder.ID.Equals(*input.ParentID)))
+	}
+	if input.Color != nil {
+		params = append(params, db.Folder.Color.Set(*input.Color))
+	}
+	if input.Icon != nil {
+		params = append(params, db.Folder.Icon.Set(*input.Icon))
+	}
+
+	folder, err := client.Folder.CreateOne(
+		db.Folder.Name.Set(input.Name),
+		db.Folder.User.Link(db.User.ID.Equals(userID)),
+		params...,
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return folderNodeToModel(&folders.Node{FolderModel: *folder, Children: []*folders.Node{}}), nil
 }
 
 // UpdateFolder is the resolver for the updateFolder field.
@@ -38,14 +117,75 @@ func (r *mutationResolver) UpdateFolder(ctx context.Context, id string, input mo
 }
 
 // DeleteFolder is the resolver for the deleteFolder field.
-func (r *mutationResolver) DeleteFolder(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteFolder - deleteFolder"))
+func (r *mutationResolver) DeleteFolder(ctx context.Context, id string, mode *model.FolderDeleteMode, targetFolderID *string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	deleteMode := folders.DeleteRefuse
+	if mode != nil {
+		deleteMode = strings.ToLower(string(*mode))
+	}
+	target := ""
+	if targetFolderID != nil {
+		target = *targetFolderID
+	}
+
+	if err := folders.Delete(ctx, database.GetClient(), userID, id, deleteMode, target); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// MoveFolder is the resolver for the moveFolder field.
+func (r *mutationResolver) MoveFolder(ctx context.Context, id string, parentID *string) (*model.Folder, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	target := ""
+	if parentID != nil {
+		target = *parentID
+	}
+	if _, err := folders.Move(ctx, client, userID, id, target); err != nil {
+		return nil, err
+	}
+
+	forest, err := folders.Load(ctx, client, userID)
+	if err != nil {
+		return nil, err
+	}
+	node, err := forest.Node(id, 0)
+	if err != nil {
+		return nil, err
+	}
+	return folderNodeToModel(node), nil
+}
+
+// MoveProject is the resolver for the moveProject field.
+func (r *mutationResolver) MoveProject(ctx context.Context, id string, folderID string) (*model.Project, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	project, err := folders.MoveProject(ctx, database.GetClient(), userID, id, folderID)
+	if err != nil {
+		return nil, err
+	}
+	return projectToModel(project), nil
 }
 
 // CreateProject is the resolver for the createProject field.
 func (r *mutationResolver) CreateProject(ctx context.Context, input model.CreateProjectInput) (*model.Project, error) {
 	// Get user from context (you'll need to implement auth middleware first)
-	userID := "user_123" // For now, using placeholder
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
 
 	// Create project in database
 	project := &model.Project{
@@ -81,7 +221,10 @@ func (r *mutationResolver) CreateProject(ctx context.Context, input model.Create
 // UpdateProject is the resolver for the updateProject field.
 func (r *mutationResolver) UpdateProject(ctx context.Context, id string, input model.UpdateProjectInput) (*model.Project, error) {
 	// Get user from context
-	userID := "user_123" // Placeholder
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
 
 	// Create updated project (in real implementation, you'd fetch from DB first)
 	project := &model.Project{
@@ -125,15 +268,14 @@ func (r *mutationResolver) UpdateProject(ctx context.Context, id string, input m
 
 // DeleteProject is the resolver for the deleteProject field.
 func (r *mutationResolver) DeleteProject(ctx context.Context, id string) (bool, error) {
-	// Get user from context to verify ownership
-	userID := "user_123" // Placeholder
-
-	// In real implementation, you would:
-	// 1. Verify the project exists and belongs to the user
-	// 2. Handle cascading deletes (tasks, sessions, etc.)
-	// 3. Delete from database
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
 
-	fmt.Printf("Deleting project %s for user %s\n", id, userID)
+	if err := trash.Project(ctx, database.GetClient(), userID, id); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
@@ -149,7 +291,15 @@ func (r *mutationResolver) UpdateTask(ctx context.Context, id string, input mode
 
 // DeleteTask is the resolver for the deleteTask field.
 func (r *mutationResolver) DeleteTask(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteTask - deleteTask"))
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	if err := trash.Task(ctx, database.GetClient(), userID, id); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // ToggleTaskStatus is the resolver for the toggleTaskStatus field.
@@ -157,271 +307,1876 @@ func (r *mutationResolver) ToggleTaskStatus(ctx context.Context, id string) (*mo
 	panic(fmt.Errorf("not implemented: ToggleTaskStatus - toggleTaskStatus"))
 }
 
-// CreateSprint is the resolver for the createSprint field.
-func (r *mutationResolver) CreateSprint(ctx context.Context, input model.CreateSprintInput) (*model.Sprint, error) {
-	panic(fmt.Errorf("not implemented: CreateSprint - createSprint"))
-}
+// CompleteTask is the resolver for the completeTask field.
+func (r *mutationResolver) CompleteTask(ctx context.Context, id string, force *bool) (*model.Task, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
 
-// UpdateSprint is the resolver for the updateSprint field.
-func (r *mutationResolver) UpdateSprint(ctx context.Context, id string, input model.UpdateSprintInput) (*model.Sprint, error) {
-	panic(fmt.Errorf("not implemented: UpdateSprint - updateSprint"))
+	if _, err := tasks.Complete(ctx, client, userID, id, force != nil && *force); err != nil {
+		return nil, err
+	}
+	return loadTaskModel(ctx, client, userID, id)
 }
 
-// DeleteSprint is the resolver for the deleteSprint field.
-func (r *mutationResolver) DeleteSprint(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteSprint - deleteSprint"))
-}
+// AddTaskDependency is the resolver for the addTaskDependency field.
+func (r *mutationResolver) AddTaskDependency(ctx context.Context, taskID string, dependsOnID string) (*model.Task, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
 
-// AddTaskToSprint is the resolver for the addTaskToSprint field.
-func (r *mutationResolver) AddTaskToSprint(ctx context.Context, sprintID string, taskID string, storyPoints int) (*model.SprintTask, error) {
-	panic(fmt.Errorf("not implemented: AddTaskToSprint - addTaskToSprint"))
+	if err := tasks.AddDependency(ctx, client, userID, taskID, dependsOnID); err != nil {
+		return nil, err
+	}
+	return loadTaskModel(ctx, client, userID, taskID)
 }
 
-// RemoveTaskFromSprint is the resolver for the removeTaskFromSprint field.
-func (r *mutationResolver) RemoveTaskFromSprint(ctx context.Context, sprintID string, taskID string) (bool, error) {
-	panic(fmt.Errorf("not implemented: RemoveTaskFromSprint - removeTaskFromSprint"))
-}
+// RemoveTaskDependency is the resolver for the removeTaskDependency field.
+func (r *mutationResolver) RemoveTaskDependency(ctx context.Context, taskID string, dependsOnID string) (*model.Task, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
 
-// StartPomodoroSession is the resolver for the startPomodoroSession field.
-func (r *mutationResolver) StartPomodoroSession(ctx context.Context, input model.CreatePomodoroSessionInput) (*model.PomodoroSession, error) {
-	panic(fmt.Errorf("not implemented: StartPomodoroSession - startPomodoroSession"))
+	if err := tasks.RemoveDependency(ctx, client, userID, taskID, dependsOnID); err != nil {
+		return nil, err
+	}
+	return loadTaskModel(ctx, client, userID, taskID)
 }
 
-// UpdatePomodoroSession is the resolver for the updatePomodoroSession field.
-func (r *mutationResolver) UpdatePomodoroSession(ctx context.Context, id string, input model.UpdatePomodoroSessionInput) (*model.PomodoroSession, error) {
-	panic(fmt.Errorf("not implemented: UpdatePomodoroSession - updatePomodoroSession"))
-}
+// CreateTag is the resolver for the createTag field.
+func (r *mutationResolver) CreateTag(ctx context.Context, name string, color *string) (*model.Tag, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
 
-// CompletePomodoroSession is the resolver for the completePomodoroSession field.
-func (r *mutationResolver) CompletePomodoroSession(ctx context.Context, id string) (*model.PomodoroSession, error) {
-	panic(fmt.Errorf("not implemented: CompletePomodoroSession - completePomodoroSession"))
+	c := ""
+	if color != nil {
+		c = *color
+	}
+	tag, err := tags.Create(ctx, database.GetClient(), userID, name, c)
+	if err != nil {
+		return nil, err
+	}
+	return tagToModel(&tag.TagModel), nil
 }
 
-// MarkNotificationAsRead is the resolver for the markNotificationAsRead field.
-func (r *mutationResolver) MarkNotificationAsRead(ctx context.Context, id string) (*model.Notification, error) {
-	panic(fmt.Errorf("not implemented: MarkNotificationAsRead - markNotificationAsRead"))
-}
+// UpdateTag is the resolver for the updateTag field.
+func (r *mutationResolver) UpdateTag(ctx context.Context, id string, name *string, color *string) (*model.Tag, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
 
-// MarkAllNotificationsAsRead is the resolver for the markAllNotificationsAsRead field.
-func (r *mutationResolver) MarkAllNotificationsAsRead(ctx context.Context) (bool, error) {
-	panic(fmt.Errorf("not implemented: MarkAllNotificationsAsRead - markAllNotificationsAsRead"))
+	tag, err := tags.Update(ctx, database.GetClient(), userID, id, name, color)
+	if err != nil {
+		return nil, err
+	}
+	return tagToModel(&tag.TagModel), nil
 }
 
-// InviteCollaborator is the resolver for the inviteCollaborator field.
-func (r *mutationResolver) InviteCollaborator(ctx context.Context, projectID string, email string, role model.CollaboratorRole) (*model.ProjectCollaborator, error) {
-	panic(fmt.Errorf("not implemented: InviteCollaborator - inviteCollaborator"))
+// DeleteTag is the resolver for the deleteTag field.
+func (r *mutationResolver) DeleteTag(ctx context.Context, id string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	if err := tags.Delete(ctx, database.GetClient(), userID, id); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// UpdateCollaboratorRole is the resolver for the updateCollaboratorRole field.
-func (r *mutationResolver) UpdateCollaboratorRole(ctx context.Context, collaboratorID string, role model.CollaboratorRole) (*model.ProjectCollaborator, error) {
-	panic(fmt.Errorf("not implemented: UpdateCollaboratorRole - updateCollaboratorRole"))
+// MergeTags is the resolver for the mergeTags field.
+func (r *mutationResolver) MergeTags(ctx context.Context, sourceIds []string, targetID string) (*model.Tag, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	tag, err := tags.Merge(ctx, database.GetClient(), userID, sourceIds, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return tagToModel(&tag.TagModel), nil
 }
 
-// RemoveCollaborator is the resolver for the removeCollaborator field.
-func (r *mutationResolver) RemoveCollaborator(ctx context.Context, collaboratorID string) (bool, error) {
-	panic(fmt.Errorf("not implemented: RemoveCollaborator - removeCollaborator"))
+// SetTaskTags is the resolver for the setTaskTags field.
+func (r *mutationResolver) SetTaskTags(ctx context.Context, taskID string, names []string) (*model.Task, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	if _, err := tags.SetTaskTags(ctx, client, userID, taskID, names); err != nil {
+		return nil, err
+	}
+	return loadTaskModel(ctx, client, userID, taskID)
 }
 
-// Me is the resolver for the me field.
-func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
-	panic(fmt.Errorf("not implemented: Me - me"))
+// SetTaskRecurrence is the resolver for the setTaskRecurrence field.
+func (r *mutationResolver) SetTaskRecurrence(ctx context.Context, taskID string, rule string, afterCompletion *bool) (*model.TaskRecurrence, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	series, err := tasks.SetRecurrence(ctx, database.GetClient(), userID, taskID, rule, afterCompletion != nil && *afterCompletion)
+	if err != nil {
+		return nil, err
+	}
+	return recurrenceToModel(series), nil
 }
 
-// User is the resolver for the user field.
-func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
-	panic(fmt.Errorf("not implemented: User - user"))
+// StopTaskRecurrence is the resolver for the stopTaskRecurrence field.
+func (r *mutationResolver) StopTaskRecurrence(ctx context.Context, taskID string) (*model.TaskRecurrence, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	series, err := tasks.StopRecurrence(ctx, database.GetClient(), userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return recurrenceToModel(series), nil
 }
 
-// Folders is the resolver for the folders field.
-func (r *queryResolver) Folders(ctx context.Context) ([]*model.Folder, error) {
-	panic(fmt.Errorf("not implemented: Folders - folders"))
+// AddTaskComment is the resolver for the addTaskComment field.
+func (r *mutationResolver) AddTaskComment(ctx context.Context, taskID string, content string) (*model.TaskComment, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	comment, err := comments.Create(ctx, database.GetClient(), userID, taskID, content)
+	if err != nil {
+		return nil, err
+	}
+	return commentToModel(comment), nil
 }
 
-// Folder is the resolver for the folder field.
-func (r *queryResolver) Folder(ctx context.Context, id string) (*model.Folder, error) {
-	panic(fmt.Errorf("not implemented: Folder - folder"))
+// EditTaskComment is the resolver for the editTaskComment field.
+func (r *mutationResolver) EditTaskComment(ctx context.Context, id string, content string) (*model.TaskComment, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	comment, err := comments.Edit(ctx, database.GetClient(), userID, id, content)
+	if err != nil {
+		return nil, err
+	}
+	return commentToModel(comment), nil
 }
 
-// Projects is the resolver for the projects field.
-func (r *queryResolver) Projects(ctx context.Context) ([]*model.Project, error) {
-	// Get user from context
-	userID := "user_123" // Placeholder
-
-	// Mock data for now
-	projects := []*model.Project{
-		{
-			ID:          "proj_1",
-			Name:        "LifeQuest Development",
-			Description: &[]string{"Building the ultimate productivity platform"}[0],
-			Color:       &[]string{"#3b82f6"}[0],
-			Icon:        &[]string{"🚀"}[0],
-			Status:      model.ProjectStatusInProgress,
-			Priority:    model.PriorityHigh,
-			IsArchived:  false,
-			UserID:      userID,
-			CreatedAt:   time.Now().AddDate(0, -1, 0),
-			UpdatedAt:   time.Now(),
-		},
-		{
-			ID:          "proj_2",
-			Name:        "Learning Go",
-			Description: &[]string{"Master Go programming language"}[0],
-			Color:       &[]string{"#10b981"}[0],
-			Icon:        &[]string{"📚"}[0],
-			Status:      model.ProjectStatusPlanning,
-			Priority:    model.PriorityMedium,
-			IsArchived:  false,
-			UserID:      userID,
-			CreatedAt:   time.Now().AddDate(0, 0, -10),
-			UpdatedAt:   time.Now(),
-		},
-	}
-
-	return projects, nil
+// DeleteTaskComment is the resolver for the deleteTaskComment field.
+func (r *mutationResolver) DeleteTaskComment(ctx context.Context, id string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	if err := comments.Delete(ctx, database.GetClient(), userID, id); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// Project is the resolver for the project field.
-func (r *queryResolver) Project(ctx context.Context, id string) (*model.Project, error) {
-	// Get user from context
-	userID := "user_123" // Placeholder
+// UploadTaskAttachment is the resolver for the uploadTaskAttachment field.
+func (r *mutationResolver) UploadTaskAttachment(ctx context.Context, taskID string, file graphql.Upload) (*model.TaskAttachment, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
 
-	// Mock single project for now
-	project := &model.Project{
-		ID:          id,
-		Name:        "LifeQuest Development",
-		Description: &[]string{"Building the ultimate productivity platform with advanced features"}[0],
-		Color:       &[]string{"#3b82f6"}[0],
-		Icon:        &[]string{"🚀"}[0],
-		Status:      model.ProjectStatusInProgress,
-		Priority:    model.PriorityHigh,
-		IsArchived:  false,
-		UserID:      userID,
-		CreatedAt:   time.Now().AddDate(0, -1, 0),
-		UpdatedAt:   time.Now(),
+	if file.Size > attachments.MaxSize {
+		return nil, attachments.ErrTooLarge
 	}
 
-	return project, nil
+	attachment, err := attachments.Upload(ctx, database.GetClient(), userID, taskID, file.Filename, file.File)
+	if err != nil {
+		return nil, err
+	}
+	return attachmentToModel(attachment), nil
 }
 
-// Tasks is the resolver for the tasks field.
-func (r *queryResolver) Tasks(ctx context.Context, status *model.TaskStatus, projectID *string, sprintID *string) ([]*model.Task, error) {
-	panic(fmt.Errorf("not implemented: Tasks - tasks"))
-}
+// DeleteTaskAttachment is the resolver for the deleteTaskAttachment field.
+func (r *mutationResolver) DeleteTaskAttachment(ctx context.Context, id string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
 
-// Task is the resolver for the task field.
-func (r *queryResolver) Task(ctx context.Context, id string) (*model.Task, error) {
-	panic(fmt.Errorf("not implemented: Task - task"))
+	if err := attachments.Delete(ctx, database.GetClient(), userID, id); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// TasksByDueDate is the resolver for the tasksByDueDate field.
-func (r *queryResolver) TasksByDueDate(ctx context.Context, date time.Time) ([]*model.Task, error) {
-	panic(fmt.Errorf("not implemented: TasksByDueDate - tasksByDueDate"))
-}
+// SetTaskAutoComplete is the resolver for the setTaskAutoComplete field.
+func (r *mutationResolver) SetTaskAutoComplete(ctx context.Context, taskID string, enabled bool) (*model.Task, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
 
-// OverdueTasks is the resolver for the overdueTasks field.
-func (r *queryResolver) OverdueTasks(ctx context.Context) ([]*model.Task, error) {
-	panic(fmt.Errorf("not implemented: OverdueTasks - overdueTasks"))
+	if _, err := tasks.SetAutoComplete(ctx, client, userID, taskID, enabled); err != nil {
+		return nil, err
+	}
+	return loadTaskModel(ctx, client, userID, taskID)
 }
 
-// Sprints is the resolver for the sprints field.
-func (r *queryResolver) Sprints(ctx context.Context, status *model.SprintStatus) ([]*model.Sprint, error) {
-	panic(fmt.Errorf("not implemented: Sprints - sprints"))
-}
+// CreateSubtask is the resolver for the createSubtask field.
+func (r *mutationResolver) CreateSubtask(ctx context.Context, taskID string, title string) (*model.Subtask, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
 
-// Sprint is the resolver for the sprint field.
-func (r *queryResolver) Sprint(ctx context.Context, id string) (*model.Sprint, error) {
-	panic(fmt.Errorf("not implemented: Sprint - sprint"))
+	subtask, err := tasks.CreateSubtask(ctx, database.GetClient(), userID, taskID, title)
+	if err != nil {
+		return nil, err
+	}
+	return subtaskToModel(subtask), nil
 }
 
-// ActiveSprints is the resolver for the activeSprints field.
-func (r *queryResolver) ActiveSprints(ctx context.Context) ([]*model.Sprint, error) {
-	panic(fmt.Errorf("not implemented: ActiveSprints - activeSprints"))
-}
+// UpdateSubtask is the resolver for the updateSubtask field.
+func (r *mutationResolver) UpdateSubtask(ctx context.Context, id string, input model.UpdateSubtaskInput) (*model.Subtask, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
 
-// PomodoroSessions is the resolver for the pomodoroSessions field.
-func (r *queryResolver) PomodoroSessions(ctx context.Context, date *time.Time) ([]*model.PomodoroSession, error) {
-	panic(fmt.Errorf("not implemented: PomodoroSessions - pomodoroSessions"))
+	subtask, err := tasks.UpdateSubtask(ctx, database.GetClient(), userID, id, tasks.SubtaskUpdate{
+		Title:     input.Title,
+		Completed: input.Completed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subtaskToModel(subtask), nil
 }
 
-// PomodoroSession is the resolver for the pomodoroSession field.
-func (r *queryResolver) PomodoroSession(ctx context.Context, id string) (*model.PomodoroSession, error) {
-	panic(fmt.Errorf("not implemented: PomodoroSession - pomodoroSession"))
-}
+// ReorderSubtasks is the resolver for the reorderSubtasks field.
+func (r *mutationResolver) ReorderSubtasks(ctx context.Context, taskID string, subtaskIds []string) ([]*model.Subtask, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
 
-// TodaysSessions is the resolver for the todaysSessions field.
-func (r *queryResolver) TodaysSessions(ctx context.Context) ([]*model.PomodoroSession, error) {
-	panic(fmt.Errorf("not implemented: TodaysSessions - todaysSessions"))
+	subtasks, err := tasks.ReorderSubtasks(ctx, database.GetClient(), userID, taskID, subtaskIds)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.Subtask, len(subtasks))
+	for i := range subtasks {
+		result[i] = subtaskToModel(&subtasks[i])
+	}
+	return result, nil
 }
 
-// UserAnalytics is the resolver for the userAnalytics field.
-func (r *queryResolver) UserAnalytics(ctx context.Context, startDate time.Time, endDate time.Time) (*model.UserAnalytics, error) {
-	panic(fmt.Errorf("not implemented: UserAnalytics - userAnalytics"))
-}
+// DeleteSubtask is the resolver for the deleteSubtask field.
+func (r *mutationResolver) DeleteSubtask(ctx context.Context, id string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
 
-// ProjectAnalytics is the resolver for the projectAnalytics field.
-func (r *queryResolver) ProjectAnalytics(ctx context.Context, projectID string) (*model.ProjectAnalytics, error) {
-	panic(fmt.Errorf("not implemented: ProjectAnalytics - projectAnalytics"))
+	if err := tasks.DeleteSubtask(ctx, database.GetClient(), userID, id); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// SprintAnalytics is the resolver for the sprintAnalytics field.
-func (r *queryResolver) SprintAnalytics(ctx context.Context, sprintID string) (*model.SprintAnalytics, error) {
-	panic(fmt.Errorf("not implemented: SprintAnalytics - sprintAnalytics"))
-}
+// ConvertSubtaskToTask is the resolver for the convertSubtaskToTask field.
+func (r *mutationResolver) ConvertSubtaskToTask(ctx context.Context, id string) (*model.Task, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
 
-// Achievements is the resolver for the achievements field.
-func (r *queryResolver) Achievements(ctx context.Context) ([]*model.Achievement, error) {
-	panic(fmt.Errorf("not implemented: Achievements - achievements"))
+	task, err := tasks.SubtaskToTask(ctx, database.GetClient(), userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return taskToModel(task), nil
 }
 
-// Badges is the resolver for the badges field.
-func (r *queryResolver) Badges(ctx context.Context) ([]*model.Badge, error) {
-	panic(fmt.Errorf("not implemented: Badges - badges"))
-}
+// ConvertTaskToSubtask is the resolver for the convertTaskToSubtask field.
+func (r *mutationResolver) ConvertTaskToSubtask(ctx context.Context, taskID string, parentTaskID string) (*model.Subtask, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
 
-// SkillTrees is the resolver for the skillTrees field.
-func (r *queryResolver) SkillTrees(ctx context.Context) ([]*model.SkillTree, error) {
-	panic(fmt.Errorf("not implemented: SkillTrees - skillTrees"))
+	subtask, err := tasks.TaskToSubtask(ctx, database.GetClient(), userID, taskID, parentTaskID)
+	if err != nil {
+		return nil, err
+	}
+	return subtaskToModel(subtask), nil
 }
 
-// Notifications is the resolver for the notifications field.
-func (r *queryResolver) Notifications(ctx context.Context, unreadOnly *bool) ([]*model.Notification, error) {
-	panic(fmt.Errorf("not implemented: Notifications - notifications"))
+// CreateSprint is the resolver for the createSprint field.
+func (r *mutationResolver) CreateSprint(ctx context.Context, input model.CreateSprintInput) (*model.Sprint, error) {
+	panic(fmt.Errorf("not implemented: CreateSprint - createSprint"))
 }
 
-// UnreadNotificationCount is the resolver for the unreadNotificationCount field.
-func (r *queryResolver) UnreadNotificationCount(ctx context.Context) (int, error) {
-	panic(fmt.Errorf("not implemented: UnreadNotificationCount - unreadNotificationCount"))
+// UpdateSprint is the resolver for the updateSprint field.
+func (r *mutationResolver) UpdateSprint(ctx context.Context, id string, input model.UpdateSprintInput) (*model.Sprint, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	if input.Goal != nil {
+		return nil, errors.New("sprint goals are not stored yet")
+	}
+	changes := sprints.Changes{
+		Name:        input.Name,
+		Description: input.Description,
+		StartDate:   input.StartDate,
+		EndDate:     input.EndDate,
+	}
+	if input.Status != nil {
+		status := sprintStatusValue(*input.Status)
+		changes.Status = &status
+	}
+	sprint, err := sprints.Update(ctx, database.GetClient(), userID, id, changes)
+	if err != nil {
+		return nil, err
+	}
+	return sprintToModel(sprint), nil
 }
 
-// NotificationAdded is the resolver for the notificationAdded field.
-func (r *subscriptionResolver) NotificationAdded(ctx context.Context) (<-chan *model.Notification, error) {
-	panic(fmt.Errorf("not implemented: NotificationAdded - notificationAdded"))
+// DeleteSprint is the resolver for the deleteSprint field.
+func (r *mutationResolver) DeleteSprint(ctx context.Context, id string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	if err := trash.Sprint(ctx, database.GetClient(), userID, id); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// PomodoroSessionUpdated is the resolver for the pomodoroSessionUpdated field.
-func (r *subscriptionResolver) PomodoroSessionUpdated(ctx context.Context, userID string) (<-chan *model.PomodoroSession, error) {
-	panic(fmt.Errorf("not implemented: PomodoroSessionUpdated - pomodoroSessionUpdated"))
+// AddTaskToSprint is the resolver for the addTaskToSprint field.
+func (r *mutationResolver) AddTaskToSprint(ctx context.Context, sprintID string, taskID string, storyPoints int) (*model.SprintTask, error) {
+	panic(fmt.Errorf("not implemented: AddTaskToSprint - addTaskToSprint"))
 }
 
-// TaskUpdated is the resolver for the taskUpdated field.
-func (r *subscriptionResolver) TaskUpdated(ctx context.Context, projectID string) (<-chan *model.Task, error) {
-	panic(fmt.Errorf("not implemented: TaskUpdated - taskUpdated"))
+// RemoveTaskFromSprint is the resolver for the removeTaskFromSprint field.
+func (r *mutationResolver) RemoveTaskFromSprint(ctx context.Context, sprintID string, taskID string) (bool, error) {
+	panic(fmt.Errorf("not implemented: RemoveTaskFromSprint - removeTaskFromSprint"))
 }
 
-// SprintUpdated is the resolver for the sprintUpdated field.
-func (r *subscriptionResolver) SprintUpdated(ctx context.Context, sprintID string) (<-chan *model.Sprint, error) {
-	panic(fmt.Errorf("not implemented: SprintUpdated - sprintUpdated"))
+// StartPomodoroSession is the resolver for the startPomodoroSession field.
+func (r *mutationResolver) StartPomodoroSession(ctx context.Context, input model.CreatePomodoroSessionInput) (*model.PomodoroSession, error) {
+	panic(fmt.Errorf("not implemented: StartPomodoroSession - startPomodoroSession"))
 }
 
-// Mutation returns generated.MutationResolver implementation.
+// UpdatePomodoroSession is the resolver for the updatePomodoroSession field.
+func (r *mutationResolver) UpdatePomodoroSession(ctx context.Context, id string, input model.UpdatePomodoroSessionInput) (*model.PomodoroSession, error) {
+	panic(fmt.Errorf("not implemented: UpdatePomodoroSession - updatePomodoroSession"))
+}
+
+// CompletePomodoroSession is the resolver for the completePomodoroSession field.
+func (r *mutationResolver) CompletePomodoroSession(ctx context.Context, id string) (*model.PomodoroSession, error) {
+	panic(fmt.Errorf("not implemented: CompletePomodoroSession - completePomodoroSession"))
+}
+
+// DeletePomodoroSession is the resolver for the deletePomodoroSession field.
+func (r *mutationResolver) DeletePomodoroSession(ctx context.Context, id string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	if err := trash.Session(ctx, database.GetClient(), userID, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateSavedView is the resolver for the createSavedView field.
+func (r *mutationResolver) CreateSavedView(ctx context.Context, input model.CreateSavedViewInput) (*model.SavedView, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	fields := savedViewFields(&input.Name, input.Query, input.Sort, nil, input.Reverse, input.GroupBy, input.Pinned, input.ProjectID, input.Shared)
+	view, err := views.Create(ctx, database.GetClient(), userID, fields)
+	if err != nil {
+		return nil, err
+	}
+	return savedViewToModel(view), nil
+}
+
+// UpdateSavedView is the resolver for the updateSavedView field.
+func (r *mutationResolver) UpdateSavedView(ctx context.Context, id string, input model.UpdateSavedViewInput) (*model.SavedView, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	fields := savedViewFields(input.Name, input.Query, input.Sort, input.ClearSort, input.Reverse, input.GroupBy, input.Pinned, input.ProjectID, input.Shared)
+	view, err := views.Update(ctx, database.GetClient(), userID, id, fields)
+	if err != nil {
+		return nil, err
+	}
+	return savedViewToModel(view), nil
+}
+
+// DeleteSavedView is the resolver for the deleteSavedView field.
+func (r *mutationResolver) DeleteSavedView(ctx context.Context, id string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	if err := views.Delete(ctx, database.GetClient(), userID, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateHabit is the resolver for the createHabit field.
+func (r *mutationResolver) CreateHabit(ctx context.Context, input model.CreateHabitInput) (*model.Habit, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	fields := habitFields(&input.Name, input.Description, input.Icon, input.Color, input.Category, input.Period, input.TargetCount, input.XpValue, nil)
+	habit, err := habits.Create(ctx, database.GetClient(), userID, fields)
+	if err != nil {
+		return nil, err
+	}
+	return habitToModel(habit), nil
+}
+
+// UpdateHabit is the resolver for the updateHabit field.
+func (r *mutationResolver) UpdateHabit(ctx context.Context, id string, input model.UpdateHabitInput) (*model.Habit, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	fields := habitFields(input.Name, input.Description, input.Icon, input.Color, input.Category, input.Period, input.TargetCount, input.XpValue, input.Archived)
+	habit, err := habits.Update(ctx, database.GetClient(), userID, id, fields)
+	if err != nil {
+		return nil, err
+	}
+	return habitToModel(habit), nil
+}
+
+// DeleteHabit is the resolver for the deleteHabit field.
+func (r *mutationResolver) DeleteHabit(ctx context.Context, id string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	if err := habits.Delete(ctx, database.GetClient(), userID, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CheckInHabit is the resolver for the checkInHabit field.
+func (r *mutationResolver) CheckInHabit(ctx context.Context, habitID string, count *int, date *time.Time, note *string) (*model.Habit, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	n := 1
+	if count != nil {
+		n = *count
+	}
+	var day time.Time
+	if date != nil {
+		day = *date
+	}
+	text := ""
+	if note != nil {
+		text = *note
+	}
+
+	habit, err := habits.CheckIn(ctx, database.GetClient(), userID, habitID, n, day, text)
+	if err != nil {
+		return nil, err
+	}
+	return habitToModel(habit), nil
+}
+
+// UndoHabitCheckIn is the resolver for the undoHabitCheckIn field.
+func (r *mutationResolver) UndoHabitCheckIn(ctx context.Context, id string) (*model.Habit, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	habit, err := habits.UndoCheckIn(ctx, database.GetClient(), userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return habitToModel(habit), nil
+}
+
+// MarkNotificationAsRead is the resolver for the markNotificationAsRead field.
+func (r *mutationResolver) MarkNotificationAsRead(ctx context.Context, id string) (*model.Notification, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	notification, err := notifications.MarkRead(ctx, database.GetClient(), userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return notificationToModel(notification), nil
+}
+
+// MarkAllNotificationsAsRead is the resolver for the markAllNotificationsAsRead field.
+func (r *mutationResolver) MarkAllNotificationsAsRead(ctx context.Context) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	if err := notifications.MarkAllRead(ctx, database.GetClient(), userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// InviteCollaborator is the resolver for the inviteCollaborator field.
+func (r *mutationResolver) InviteCollaborator(ctx context.Context, projectID string, email string, role model.CollaboratorRole) (*model.ProjectInvitation, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	invitation, err := collaborators.Invite(ctx, database.GetClient(), userID, projectID, email, strings.ToLower(string(role)))
+	if err != nil {
+		return nil, err
+	}
+	return invitationToModel(invitation.ProjectInvitationModel, invitation.Token), nil
+}
+
+// RevokeInvitation is the resolver for the revokeInvitation field.
+func (r *mutationResolver) RevokeInvitation(ctx context.Context, id string) (*model.ProjectInvitation, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	invitation, err := collaborators.Revoke(ctx, database.GetClient(), userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return invitationToModel(invitation, ""), nil
+}
+
+// AcceptInvitation is the resolver for the acceptInvitation field.
+func (r *mutationResolver) AcceptInvitation(ctx context.Context, token string) (*model.ProjectCollaborator, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	collaborator, err := collaborators.Accept(ctx, database.GetClient(), userID, token)
+	if err != nil {
+		return nil, err
+	}
+	return collaboratorToModel(collaborator), nil
+}
+
+// DeclineInvitation is the resolver for the declineInvitation field.
+func (r *mutationResolver) DeclineInvitation(ctx context.Context, token string) (*model.ProjectInvitation, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	invitation, err := collaborators.Decline(ctx, database.GetClient(), userID, token)
+	if err != nil {
+		return nil, err
+	}
+	return invitationToModel(invitation, ""), nil
+}
+
+// UpdateCollaboratorRole is the resolver for the updateCollaboratorRole field.
+func (r *mutationResolver) UpdateCollaboratorRole(ctx context.Context, collaboratorID string, role model.CollaboratorRole) (*model.ProjectCollaborator, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	collaborator, err := collaborators.UpdateRole(ctx, database.GetClient(), userID, collaboratorID, strings.ToLower(string(role)))
+	if err != nil {
+		return nil, err
+	}
+	return collaboratorToModel(collaborator), nil
+}
+
+// RemoveCollaborator is the resolver for the removeCollaborator field.
+func (r *mutationResolver) RemoveCollaborator(ctx context.Context, collaboratorID string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	if err := collaborators.Remove(ctx, database.GetClient(), userID, collaboratorID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TransferProjectOwnership is the resolver for the transferProjectOwnership field.
+func (r *mutationResolver) TransferProjectOwnership(ctx context.Context, projectID string, newOwnerID string) (*model.Project, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	project, err := collaborators.TransferOwnership(ctx, database.GetClient(), userID, projectID, newOwnerID)
+	if err != nil {
+		return nil, err
+	}
+	return projectToModel(project), nil
+}
+
+// CreateWebhook is the resolver for the createWebhook field.
+func (r *mutationResolver) CreateWebhook(ctx context.Context, input model.CreateWebhookInput) (*model.Webhook, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	if err := webhooks.Validate(ctx, input.URL, input.Events); err != nil {
+		return nil, err
+	}
+
+	secret := ""
+	if input.Secret != nil && *input.Secret != "" {
+		secret = *input.Secret
+	} else {
+		generated, err := webhooks.GenerateSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	params := []db.WebhookSetParam{
+		db.Webhook.Events.Set(input.Events),
+	}
+	if input.Description != nil {
+		params = append(params, db.Webhook.Description.SetOptional(input.Description))
+	}
+
+	hook, err := database.GetClient().Webhook.CreateOne(
+		db.Webhook.URL.Set(input.URL),
+		db.Webhook.Secret.Set(secret),
+		db.Webhook.User.Link(db.User.ID.Equals(userID)),
+		params...,
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhookToModel(hook), nil
+}
+
+// UpdateWebhook is the resolver for the updateWebhook field.
+func (r *mutationResolver) UpdateWebhook(ctx context.Context, id string, input model.UpdateWebhookInput) (*model.Webhook, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	hook, err := client.Webhook.FindFirst(
+		db.Webhook.ID.Equals(id),
+		db.Webhook.UserID.Equals(userID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hookURL := hook.URL
+	if input.URL != nil {
+		hookURL = *input.URL
+	}
+	events := hook.Events
+	if input.Events != nil {
+		events = input.Events
+	}
+	if err := webhooks.Validate(ctx, hookURL, events); err != nil {
+		return nil, err
+	}
+
+	params := []db.WebhookSetParam{
+		db.Webhook.UpdatedAt.Set(time.Now()),
+	}
+	if input.URL != nil {
+		params = append(params, db.Webhook.URL.Set(*input.URL))
+	}
+	if input.Events != nil {
+		params = append(params, db.Webhook.Events.Set(input.Events))
+	}
+	if input.Description != nil {
+		params = append(params, db.Webhook.Description.SetOptional(input.Description))
+	}
+	if input.Active != nil {
+		params = append(params, db.Webhook.Active.Set(*input.Active))
+	}
+
+	updated, err := client.Webhook.FindUnique(
+		db.Webhook.ID.Equals(hook.ID),
+	).Update(params...).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhookToModel(updated), nil
+}
+
+// DeleteWebhook is the resolver for the deleteWebhook field.
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, id string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	result, err := database.GetClient().Webhook.FindMany(
+		db.Webhook.ID.Equals(id),
+		db.Webhook.UserID.Equals(userID),
+	).Delete().Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return result.Count > 0, nil
+}
+
+// SendTestWebhookEvent is the resolver for the sendTestWebhookEvent field.
+func (r *mutationResolver) SendTestWebhookEvent(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	dispatcher := webhooks.Default()
+	if dispatcher == nil {
+		return nil, fmt.Errorf("webhook delivery is not running")
+	}
+
+	hook, err := database.GetClient().Webhook.FindFirst(
+		db.Webhook.ID.Equals(id),
+		db.Webhook.UserID.Equals(userID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := dispatcher.SendTest(ctx, hook)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhookDeliveryToModel(delivery), nil
+}
+
+// RedeliverWebhookDelivery is the resolver for the redeliverWebhookDelivery field.
+func (r *mutationResolver) RedeliverWebhookDelivery(ctx context.Context, deliveryID string) (*model.WebhookDelivery, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	dispatcher := webhooks.Default()
+	if dispatcher == nil {
+		return nil, fmt.Errorf("webhook delivery is not running")
+	}
+
+	delivery, err := database.GetClient().WebhookDelivery.FindFirst(
+		db.WebhookDelivery.ID.Equals(deliveryID),
+		db.WebhookDelivery.Webhook.Where(
+			db.Webhook.UserID.Equals(userID),
+		),
+	).With(
+		db.WebhookDelivery.Webhook.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	redelivered, err := dispatcher.Redeliver(ctx, delivery.Webhook(), delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhookDeliveryToModel(redelivered), nil
+}
+
+// ImportCalendar is the resolver for the importCalendar field.
+func (r *mutationResolver) ImportCalendar(ctx context.Context, projectID string, file graphql.Upload, dryRun *bool) (*model.ImportReport, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	if file.Size > maxImportUploadSize {
+		return nil, fmt.Errorf("file exceeds %d bytes", maxImportUploadSize)
+	}
+
+	cal, err := ical.Parse(io.LimitReader(file.File, maxImportUploadSize))
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := importer.Calendar(ctx, database.GetClient(), userID, projectID, cal, dryRun == nil || *dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return importReportToModel(report), nil
+}
+
+// ImportData is the resolver for the importData field.
+func (r *mutationResolver) ImportData(ctx context.Context, format model.ImportFormat, file graphql.Upload, dryRun *bool) (*model.ImportReport, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	if file.Size > maxImportUploadSize {
+		return nil, fmt.Errorf("file exceeds %d bytes", maxImportUploadSize)
+	}
+
+	plan, err := importer.Parse(importFormat(format), file.Filename, io.LimitReader(file.File, maxImportUploadSize))
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := importer.Apply(ctx, database.GetClient(), userID, plan, dryRun == nil || *dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return importReportToModel(report), nil
+}
+
+// RestoreFromTrash is the resolver for the restoreFromTrash field.
+func (r *mutationResolver) RestoreFromTrash(ctx context.Context, kind model.TrashItemKind, id string, targetID *string) (bool, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return false, auth.ErrUnauthenticated
+	}
+
+	target := ""
+	if targetID != nil {
+		target = *targetID
+	}
+	if err := trash.Restore(ctx, database.GetClient(), userID, strings.ToLower(string(kind)), id, target); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Tasks is the resolver for the tasks field.
+func (r *projectResolver) Tasks(ctx context.Context, obj *model.Project, first *int) ([]*model.Task, error) {
+	n := pagination.DefaultLimit
+	if first != nil {
+		n = *first
+	}
+	if n < 1 || n > pagination.MaxLimit {
+		return nil, pagination.ErrInvalidLimit
+	}
+	list, err := loadersFor(ctx).TasksByProject.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	list = list[:min(n, len(list))]
+	result := make([]*model.Task, len(list))
+	for i := range list {
+		result[i] = taskToModel(&list[i])
+	}
+	return result, nil
+}
+
+// Collaborators is the resolver for the collaborators field.
+func (r *projectResolver) Collaborators(ctx context.Context, obj *model.Project) ([]*model.ProjectCollaborator, error) {
+	list, err := loadersFor(ctx).CollaboratorsByProject.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.ProjectCollaborator, len(list))
+	for i := range list {
+		result[i] = collaboratorToModel(&list[i])
+	}
+	return result, nil
+}
+
+// User is the resolver for the user field.
+func (r *projectCollaboratorResolver) User(ctx context.Context, obj *model.ProjectCollaborator) (*model.User, error) {
+	user, err := loadersFor(ctx).Users.Load(ctx, obj.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user %s not found", obj.UserID)
+	}
+	return userToModel(user), nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+	panic(fmt.Errorf("not implemented: Me - me"))
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
+	panic(fmt.Errorf("not implemented: User - user"))
+}
+
+// Folders is the resolver for the folders field.
+func (r *queryResolver) Folders(ctx context.Context, parentID *string, depth *int) ([]*model.Folder, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	forest, err := folders.Load(ctx, database.GetClient(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := ""
+	if parentID != nil {
+		parent = *parentID
+	}
+	nodes, err := forest.Tree(parent, fetchDepth(depth))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Folder, len(nodes))
+	for i, node := range nodes {
+		result[i] = folderNodeToModel(node)
+	}
+	return result, nil
+}
+
+// Folder is the resolver for the folder field.
+func (r *queryResolver) Folder(ctx context.Context, id string, depth *int) (*model.Folder, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	forest, err := folders.Load(ctx, database.GetClient(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := forest.Node(id, fetchDepth(depth))
+	if errors.Is(err, folders.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return folderNodeToModel(node), nil
+}
+
+// FolderPath is the resolver for the folderPath field.
+func (r *queryResolver) FolderPath(ctx context.Context, id string) ([]*model.Folder, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	forest, err := folders.Load(ctx, database.GetClient(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := forest.Path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Folder, len(path))
+	for i, folder := range path {
+		node, err := forest.Node(folder.ID, 0)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = folderNodeToModel(node)
+	}
+	return result, nil
+}
+
+// Projects is the resolver for the projects field.
+func (r *queryResolver) Projects(ctx context.Context, first *int, after *string, sortBy *model.ProjectSortKey, descending *bool) (*model.ProjectConnection, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	page, err := pagination.Fetch(pagination.Projects, pageArgs(first, after, sortBy, descending), func(where []db.ProjectWhereParam, order []db.ProjectOrderByParam, take int) ([]db.ProjectModel, error) {
+		return client.Project.FindMany(append([]db.ProjectWhereParam{
+			authz.MemberOf(userID),
+			db.Project.DeletedAt.IsNull(),
+		}, where...)...).OrderBy(order...).Take(take).Exec(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*model.ProjectEdge, len(page.Rows))
+	for i := range page.Rows {
+		edges[i] = &model.ProjectEdge{Cursor: page.Cursors[i], Node: projectToModel(&page.Rows[i])}
+	}
+	return &model.ProjectConnection{Edges: edges, PageInfo: pageInfoToModel(page)}, nil
+}
+
+// Project is the resolver for the project field.
+func (r *queryResolver) Project(ctx context.Context, id string) (*model.Project, error) {
+	// Get user from context
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	// Mock single project for now
+	project := &model.Project{
+		ID:          id,
+		Name:        "LifeQuest Development",
+		Description: &[]string{"Building the ultimate productivity platform with advanced features"}[0],
+		Color:       &[]string{"#3b82f6"}[0],
+		Icon:        &[]string{"🚀"}[0],
+		Status:      model.ProjectStatusInProgress,
+		Priority:    model.PriorityHigh,
+		IsArchived:  false,
+		UserID:      userID,
+		CreatedAt:   time.Now().AddDate(0, -1, 0),
+		UpdatedAt:   time.Now(),
+	}
+
+	return project, nil
+}
+
+// Tasks is the resolver for the tasks field.
+func (r *queryResolver) Tasks(ctx context.Context, status *model.TaskStatus, projectID *string, sprintID *string, tagIds []string, first *int, after *string, sortBy *model.TaskSortKey, descending *bool) (*model.TaskConnection, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	filters := []db.TaskWhereParam{
+		db.Task.UserID.Equals(userID),
+		db.Task.DeletedAt.IsNull(),
+	}
+	if status != nil {
+		filters = append(filters, db.Task.Status.Equals(taskStatusValue(*status)))
+	}
+	if projectID != nil {
+		filters = append(filters, db.Task.ProjectID.Equals(*projectID))
+	}
+	if sprintID != nil {
+		filters = append(filters, db.Task.SprintTasks.Some(db.SprintTask.SprintID.Equals(*sprintID)))
+	}
+	for _, tagID := range tagIds {
+		filters = append(filters, db.Task.Tags.Some(db.TaskTag.TagID.Equals(tagID)))
+	}
+
+	args := pageArgs(first, after, sortBy, descending)
+	var page *pagination.Page[db.TaskModel]
+	var err error
+	if sortBy != nil && *sortBy == model.TaskSortKeyTopological {
+		// Dependency order needs the whole graph, so it is paged in memory.
+		graph, err := tasks.LoadGraph(ctx, client, userID)
+		if err != nil {
+			return nil, err
+		}
+		list, err := client.Task.FindMany(filters...).With(taskRelations()...).OrderBy(
+			db.Task.CreatedAt.Order(db.SortOrderAsc),
+		).Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		page, err = pagination.Slice(graph.Sort(list), func(task *db.TaskModel) string { return task.ID }, args.Sort, args)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		page, err = pagination.Fetch(pagination.Tasks, args, func(where []db.TaskWhereParam, order []db.TaskOrderByParam, take int) ([]db.TaskModel, error) {
+			return client.Task.FindMany(append(filters, where...)...).With(taskRelations()...).OrderBy(order...).Take(take).Exec(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	edges := make([]*model.TaskEdge, len(page.Rows))
+	for i := range page.Rows {
+		edges[i] = &model.TaskEdge{Cursor: page.Cursors[i], Node: taskToModel(&page.Rows[i])}
+	}
+	return &model.TaskConnection{Edges: edges, PageInfo: pageInfoToModel(page)}, nil
+}
+
+// Task is the resolver for the task field.
+func (r *queryResolver) Task(ctx context.Context, id string) (*model.Task, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	task, err := loadTaskModel(ctx, database.GetClient(), userID, id)
+	if db.IsErrNotFound(err) || errors.Is(err, authz.ErrNotFound) {
+		return nil, nil
+	}
+	return task, err
+}
+
+// SearchTasks is the resolver for the searchTasks field.
+func (r *queryResolver) SearchTasks(ctx context.Context, query string, sort *model.TaskSort, reverse *bool, limit *int) ([]*model.TaskSearchHit, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	q, err := search.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	opts := search.Options{Sort: taskSortValue(sort)}
+	if reverse != nil {
+		opts.Reverse = *reverse
+	}
+	if limit != nil {
+		opts.Limit = *limit
+	}
+	hits, err := search.Search(ctx, client, search.Scope{UserID: userID}, q, opts, taskRelations()...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.TaskSearchHit, len(hits))
+	for i := range hits {
+		result[i] = searchHitToModel(&hits[i])
+	}
+	return result, nil
+}
+
+// CriticalPath is the resolver for the criticalPath field.
+func (r *queryResolver) CriticalPath(ctx context.Context, projectID string) (*model.CriticalPath, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	project, err := client.Project.FindFirst(
+		db.Project.ID.Equals(projectID),
+		db.Project.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := authz.Authorize(ctx, client, userID, authz.ActionRead, authz.ProjectResource(project)); err != nil {
+		return nil, err
+	}
+
+	graph, err := tasks.LoadGraph(ctx, client, project.UserID)
+	if err != nil {
+		return nil, err
+	}
+	path := graph.CriticalPath(project.ID)
+
+	result := &model.CriticalPath{
+		Tasks:              make([]*model.Task, len(path.Tasks)),
+		EstimatedPomodoros: path.EstimatedPomodoros,
+	}
+	for i, task := range path.Tasks {
+		result.Tasks[i] = taskToModel(task)
+	}
+	return result, nil
+}
+
+// TasksByDueDate is the resolver for the tasksByDueDate field.
+func (r *queryResolver) TasksByDueDate(ctx context.Context, date time.Time) ([]*model.Task, error) {
+	panic(fmt.Errorf("not implemented: TasksByDueDate - tasksByDueDate"))
+}
+
+// OverdueTasks is the resolver for the overdueTasks field.
+func (r *queryResolver) OverdueTasks(ctx context.Context) ([]*model.Task, error) {
+	panic(fmt.Errorf("not implemented: OverdueTasks - overdueTasks"))
+}
+
+// Sprints is the resolver for the sprints field.
+func (r *queryResolver) Sprints(ctx context.Context, status *model.SprintStatus, first *int, after *string, sortBy *model.SprintSortKey, descending *bool) (*model.SprintConnection, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	filters := []db.SprintWhereParam{
+		db.Sprint.UserID.Equals(userID),
+		db.Sprint.DeletedAt.IsNull(),
+	}
+	if status != nil {
+		filters = append(filters, db.Sprint.Status.Equals(sprintStatusValue(*status)))
+	}
+
+	page, err := pagination.Fetch(pagination.Sprints, pageArgs(first, after, sortBy, descending), func(where []db.SprintWhereParam, order []db.SprintOrderByParam, take int) ([]db.SprintModel, error) {
+		return client.Sprint.FindMany(append(filters, where...)...).OrderBy(order...).Take(take).Exec(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*model.SprintEdge, len(page.Rows))
+	for i := range page.Rows {
+		edges[i] = &model.SprintEdge{Cursor: page.Cursors[i], Node: sprintToModel(&page.Rows[i])}
+	}
+	return &model.SprintConnection{Edges: edges, PageInfo: pageInfoToModel(page)}, nil
+}
+
+// Sprint is the resolver for the sprint field.
+func (r *queryResolver) Sprint(ctx context.Context, id string) (*model.Sprint, error) {
+	panic(fmt.Errorf("not implemented: Sprint - sprint"))
+}
+
+// ActiveSprints is the resolver for the activeSprints field.
+func (r *queryResolver) ActiveSprints(ctx context.Context) ([]*model.Sprint, error) {
+	panic(fmt.Errorf("not implemented: ActiveSprints - activeSprints"))
+}
+
+// PomodoroSessions is the resolver for the pomodoroSessions field.
+func (r *queryResolver) PomodoroSessions(ctx context.Context, date *time.Time, first *int, after *string, sortBy *model.PomodoroSessionSortKey, descending *bool) (*model.PomodoroSessionConnection, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	filters := []db.PomodoroSessionWhereParam{
+		db.PomodoroSession.UserID.Equals(userID),
+		db.PomodoroSession.DeletedAt.IsNull(),
+	}
+	if date != nil {
+		y, m, d := date.UTC().Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		filters = append(filters,
+			db.PomodoroSession.StartTime.Gte(day),
+			db.PomodoroSession.StartTime.Lt(day.AddDate(0, 0, 1)),
+		)
+	}
+
+	page, err := pagination.Fetch(pagination.PomodoroSessions, pageArgs(first, after, sortBy, descending), func(where []db.PomodoroSessionWhereParam, order []db.PomodoroSessionOrderByParam, take int) ([]db.PomodoroSessionModel, error) {
+		return client.PomodoroSession.FindMany(append(filters, where...)...).OrderBy(order...).Take(take).Exec(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*model.PomodoroSessionEdge, len(page.Rows))
+	for i := range page.Rows {
+		edges[i] = &model.PomodoroSessionEdge{Cursor: page.Cursors[i], Node: pomodoroSessionToModel(&page.Rows[i])}
+	}
+	return &model.PomodoroSessionConnection{Edges: edges, PageInfo: pageInfoToModel(page)}, nil
+}
+
+// PomodoroSession is the resolver for the pomodoroSession field.
+func (r *queryResolver) PomodoroSession(ctx context.Context, id string) (*model.PomodoroSession, error) {
+	panic(fmt.Errorf("not implemented: PomodoroSession - pomodoroSession"))
+}
+
+// TodaysSessions is the resolver for the todaysSessions field.
+func (r *queryResolver) TodaysSessions(ctx context.Context) ([]*model.PomodoroSession, error) {
+	panic(fmt.Errorf("not implemented: TodaysSessions - todaysSessions"))
+}
+
+// Tags is the resolver for the tags field.
+func (r *queryResolver) Tags(ctx context.Context) ([]*model.TagUsage, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	list, err := tags.List(ctx, database.GetClient(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.TagUsage, len(list))
+	for i := range list {
+		result[i] = tagUsageToModel(&list[i])
+	}
+	return result, nil
+}
+
+// Tag is the resolver for the tag field.
+func (r *queryResolver) Tag(ctx context.Context, id string) (*model.TagUsage, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	tag, err := tags.Get(ctx, database.GetClient(), userID, id)
+	if err != nil {
+		if errors.Is(err, tags.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return tagUsageToModel(tag), nil
+}
+
+// TagAnalytics is the resolver for the tagAnalytics field.
+func (r *queryResolver) TagAnalytics(ctx context.Context, startDate time.Time, endDate time.Time) ([]*model.TagStat, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	stats, err := analytics.ByTag(ctx, database.GetClient(), userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.TagStat, len(stats))
+	for i := range stats {
+		result[i] = tagStatToModel(&stats[i])
+	}
+	return result, nil
+}
+
+// SavedViews is the resolver for the savedViews field.
+func (r *queryResolver) SavedViews(ctx context.Context) ([]*model.SavedView, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	list, err := views.List(ctx, database.GetClient(), userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.SavedView, len(list))
+	for i := range list {
+		result[i] = savedViewToModel(&list[i])
+	}
+	return result, nil
+}
+
+// SavedView is the resolver for the savedView field.
+func (r *queryResolver) SavedView(ctx context.Context, id string) (*model.SavedView, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	view, err := views.Get(ctx, database.GetClient(), userID, id)
+	if errors.Is(err, views.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return savedViewToModel(view), nil
+}
+
+// RunSavedView is the resolver for the runSavedView field.
+func (r *queryResolver) RunSavedView(ctx context.Context, id string, limit *int) (*model.SavedViewResult, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	n := 0
+	if limit != nil {
+		n = *limit
+	}
+	result, err := views.Run(ctx, client, userID, id, n, taskRelations()...)
+	if errors.Is(err, views.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return savedViewResultToModel(result), nil
+}
+
+// Habits is the resolver for the habits field.
+func (r *queryResolver) Habits(ctx context.Context, includeArchived *bool) ([]*model.Habit, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	list, err := habits.List(ctx, database.GetClient(), userID, includeArchived != nil && *includeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Habit, len(list))
+	for i := range list {
+		result[i] = habitToModel(&list[i])
+	}
+	return result, nil
+}
+
+// Habit is the resolver for the habit field.
+func (r *queryResolver) Habit(ctx context.Context, id string) (*model.Habit, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	habit, err := habits.Get(ctx, database.GetClient(), userID, id)
+	if err != nil {
+		if errors.Is(err, habits.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return habitToModel(habit), nil
+}
+
+// HabitHeatmap is the resolver for the habitHeatmap field.
+func (r *queryResolver) HabitHeatmap(ctx context.Context, startDate time.Time, endDate time.Time, habitID *string) ([]*model.HeatmapDay, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	id := ""
+	if habitID != nil {
+		id = *habitID
+	}
+	days, err := habits.Heatmap(ctx, database.GetClient(), userID, id, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.HeatmapDay, len(days))
+	for i, day := range days {
+		result[i] = &model.HeatmapDay{Date: day.Date, Count: day.Count}
+	}
+	return result, nil
+}
+
+// HabitCheckIns is the resolver for the habitCheckIns field.
+func (r *queryResolver) HabitCheckIns(ctx context.Context, habitID string, startDate time.Time, endDate time.Time) ([]*model.HabitCheckIn, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	checkIns, err := habits.CheckIns(ctx, database.GetClient(), userID, habitID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.HabitCheckIn, len(checkIns))
+	for i := range checkIns {
+		result[i] = habitCheckInToModel(&checkIns[i])
+	}
+	return result, nil
+}
+
+// UserAnalytics is the resolver for the userAnalytics field.
+func (r *queryResolver) UserAnalytics(ctx context.Context, startDate time.Time, endDate time.Time) (*model.UserAnalytics, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	report, err := analytics.Load(ctx, database.GetClient(), userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return analyticsToModel(userID, report), nil
+}
+
+// ProjectAnalytics is the resolver for the projectAnalytics field.
+func (r *queryResolver) ProjectAnalytics(ctx context.Context, projectID string) (*model.ProjectAnalytics, error) {
+	panic(fmt.Errorf("not implemented: ProjectAnalytics - projectAnalytics"))
+}
+
+// SprintAnalytics is the resolver for the sprintAnalytics field.
+func (r *queryResolver) SprintAnalytics(ctx context.Context, sprintID string) (*model.SprintAnalytics, error) {
+	panic(fmt.Errorf("not implemented: SprintAnalytics - sprintAnalytics"))
+}
+
+// Achievements is the resolver for the achievements field.
+func (r *queryResolver) Achievements(ctx context.Context) ([]*model.Achievement, error) {
+	panic(fmt.Errorf("not implemented: Achievements - achievements"))
+}
+
+// Badges is the resolver for the badges field.
+func (r *queryResolver) Badges(ctx context.Context) ([]*model.Badge, error) {
+	panic(fmt.Errorf("not implemented: Badges - badges"))
+}
+
+// SkillTrees is the resolver for the skillTrees field.
+func (r *queryResolver) SkillTrees(ctx context.Context) ([]*model.SkillTree, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	totals, err := xp.ByCategory(ctx, database.GetClient(), userID)
+	if err != nil {
+		return nil, err
+	}
+	return skillTreesToModels(userID, totals), nil
+}
+
+// Notifications is the resolver for the notifications field.
+func (r *queryResolver) Notifications(ctx context.Context, unreadOnly *bool, first *int, after *string) (*model.NotificationConnection, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	page, err := notifications.List(ctx, database.GetClient(), userID, unreadOnly != nil && *unreadOnly, pageArgs[string](first, after, nil, nil))
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]*model.NotificationEdge, len(page.Rows))
+	for i := range page.Rows {
+		edges[i] = &model.NotificationEdge{Cursor: page.Cursors[i], Node: notificationToModel(&page.Rows[i])}
+	}
+	return &model.NotificationConnection{Edges: edges, PageInfo: pageInfoToModel(page)}, nil
+}
+
+// UnreadNotificationCount is the resolver for the unreadNotificationCount field.
+func (r *queryResolver) UnreadNotificationCount(ctx context.Context) (int, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return 0, auth.ErrUnauthenticated
+	}
+
+	return notifications.UnreadCount(ctx, database.GetClient(), userID)
+}
+
+// ProjectInvitations is the resolver for the projectInvitations field.
+func (r *queryResolver) ProjectInvitations(ctx context.Context, projectID string) ([]*model.ProjectInvitation, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	list, err := collaborators.ListForProject(ctx, database.GetClient(), userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.ProjectInvitation, len(list))
+	for i := range list {
+		result[i] = invitationToModel(&list[i], "")
+	}
+	return result, nil
+}
+
+// MyInvitations is the resolver for the myInvitations field.
+func (r *queryResolver) MyInvitations(ctx context.Context) ([]*model.ProjectInvitation, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	list, err := collaborators.ListForUser(ctx, database.GetClient(), userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.ProjectInvitation, len(list))
+	for i := range list {
+		result[i] = invitationToModel(&list[i], "")
+	}
+	return result, nil
+}
+
+// Webhooks is the resolver for the webhooks field.
+func (r *queryResolver) Webhooks(ctx context.Context) ([]*model.Webhook, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	hooks, err := database.GetClient().Webhook.FindMany(
+		db.Webhook.UserID.Equals(userID),
+	).OrderBy(
+		db.Webhook.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Webhook, 0, len(hooks))
+	for i := range hooks {
+		result = append(result, webhookToModel(&hooks[i]))
+	}
+	return result, nil
+}
+
+// Webhook is the resolver for the webhook field.
+func (r *queryResolver) Webhook(ctx context.Context, id string) (*model.Webhook, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	hook, err := database.GetClient().Webhook.FindFirst(
+		db.Webhook.ID.Equals(id),
+		db.Webhook.UserID.Equals(userID),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return webhookToModel(hook), nil
+}
+
+// WebhookDeliveries is the resolver for the webhookDeliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, webhookID string, limit *int) ([]*model.WebhookDelivery, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	take := 50
+	if limit != nil {
+		if *limit < 1 || *limit > 200 {
+			return nil, fmt.Errorf("limit must be between 1 and 200")
+		}
+		take = *limit
+	}
+
+	deliveries, err := database.GetClient().WebhookDelivery.FindMany(
+		db.WebhookDelivery.WebhookID.Equals(webhookID),
+		db.WebhookDelivery.Webhook.Where(
+			db.Webhook.UserID.Equals(userID),
+		),
+	).OrderBy(
+		db.WebhookDelivery.CreatedAt.Order(db.SortOrderDesc),
+	).Take(take).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.WebhookDelivery, 0, len(deliveries))
+	for i := range deliveries {
+		result = append(result, webhookDeliveryToModel(&deliveries[i]))
+	}
+	return result, nil
+}
+
+// WebhookEvents is the resolver for the webhookEvents field.
+func (r *queryResolver) WebhookEvents(ctx context.Context) ([]string, error) {
+	return append([]string{webhooks.EventAll}, webhooks.Events...), nil
+}
+
+// Trash is the resolver for the trash field.
+func (r *queryResolver) Trash(ctx context.Context) ([]*model.TrashItem, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	entries, err := trash.List(ctx, database.GetClient(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.TrashItem, len(entries))
+	for i := range entries {
+		result[i] = trashEntryToModel(&entries[i])
+	}
+	return result, nil
+}
+
+// Tasks is the resolver for the tasks field.
+func (r *sprintResolver) Tasks(ctx context.Context, obj *model.Sprint) ([]*model.SprintTask, error) {
+	links, err := loadersFor(ctx).SprintTasksBySprint.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.SprintTask, len(links))
+	for i := range links {
+		result[i] = sprintTaskToModel(&links[i])
+	}
+	return result, nil
+}
+
+// Task is the resolver for the task field.
+func (r *sprintTaskResolver) Task(ctx context.Context, obj *model.SprintTask) (*model.Task, error) {
+	task, err := loadersFor(ctx).Tasks.Load(ctx, obj.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("task %s not found", obj.TaskID)
+	}
+	return taskToModel(task), nil
+}
+
+// NotificationAdded is the resolver for the notificationAdded field.
+func (r *subscriptionResolver) NotificationAdded(ctx context.Context) (<-chan *model.Notification, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+
+	return notificationUpdates(ctx, userID), nil
+}
+
+// PomodoroSessionUpdated is the resolver for the pomodoroSessionUpdated field.
+func (r *subscriptionResolver) PomodoroSessionUpdated(ctx context.Context, userID string) (<-chan *model.PomodoroSession, error) {
+	panic(fmt.Errorf("not implemented: PomodoroSessionUpdated - pomodoroSessionUpdated"))
+}
+
+// TaskUpdated is the resolver for the taskUpdated field.
+func (r *subscriptionResolver) TaskUpdated(ctx context.Context, projectID string) (<-chan *model.Task, error) {
+	userID := auth.FromContext(ctx)
+	if userID == "" {
+		return nil, auth.ErrUnauthenticated
+	}
+	client := database.GetClient()
+
+	project, err := client.Project.FindFirst(
+		db.Project.ID.Equals(projectID),
+		db.Project.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	members, err := comments.Members(ctx, client, project)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if member.ID == userID {
+			return taskUpdates(ctx, project.ID), nil
+		}
+	}
+	return nil, errors.New("project not found")
+}
+
+// SprintUpdated is the resolver for the sprintUpdated field.
+func (r *subscriptionResolver) SprintUpdated(ctx context.Context, sprintID string) (<-chan *model.Sprint, error) {
+	panic(fmt.Errorf("not implemented: SprintUpdated - sprintUpdated"))
+}
+
+// Project is the resolver for the project field.
+func (r *taskResolver) Project(ctx context.Context, obj *model.Task) (*model.Project, error) {
+	if obj.ProjectID == nil {
+		return nil, nil
+	}
+	project, err := loadersFor(ctx).Projects.Load(ctx, *obj.ProjectID)
+	if err != nil || project == nil {
+		return nil, err
+	}
+	return projectToModel(project), nil
+}
+
+// Assignee is the resolver for the assignee field.
+func (r *taskResolver) Assignee(ctx context.Context, obj *model.Task) (*model.User, error) {
+	if obj.AssigneeID == nil {
+		return nil, nil
+	}
+	user, err := loadersFor(ctx).Users.Load(ctx, *obj.AssigneeID)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return userToModel(user), nil
+}
+
+// Dependencies is the resolver for the dependencies field.
+func (r *taskResolver) Dependencies(ctx context.Context, obj *model.Task) ([]*model.Task, error) {
+	dependencies, err := loadersFor(ctx).Dependencies.Load(ctx, obj.ID)
+	if err != nil || dependencies == nil {
+		return []*model.Task{}, err
+	}
+	return tasksToModels(dependencies.Blockers), nil
+}
+
+// Dependents is the resolver for the dependents field.
+func (r *taskResolver) Dependents(ctx context.Context, obj *model.Task) ([]*model.Task, error) {
+	dependencies, err := loadersFor(ctx).Dependencies.Load(ctx, obj.ID)
+	if err != nil || dependencies == nil {
+		return []*model.Task{}, err
+	}
+	return tasksToModels(dependencies.Dependents), nil
+}
+
+// IsBlocked is the resolver for the isBlocked field.
+func (r *taskResolver) IsBlocked(ctx context.Context, obj *model.Task) (bool, error) {
+	dependencies, err := loadersFor(ctx).Dependencies.Load(ctx, obj.ID)
+	if err != nil || dependencies == nil {
+		return false, err
+	}
+	return dependencies.IsBlocked(), nil
+}
+
+// Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// Project returns generated.ProjectResolver implementation.
+func (r *Resolver) Project() generated.ProjectResolver { return &projectResolver{r} }
+
+// ProjectCollaborator returns generated.ProjectCollaboratorResolver implementation.
+func (r *Resolver) ProjectCollaborator() generated.ProjectCollaboratorResolver {
+	return &projectCollaboratorResolver{r}
+}
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// Sprint returns generated.SprintResolver implementation.
+func (r *Resolver) Sprint() generated.SprintResolver { return &sprintResolver{r} }
+
+// SprintTask returns generated.SprintTaskResolver implementation.
+func (r *Resolver) SprintTask() generated.SprintTaskResolver { return &sprintTaskResolver{r} }
+
 // Subscription returns generated.SubscriptionResolver implementation.
 func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
 
+// Task returns generated.TaskResolver implementation.
+func (r *Resolver) Task() generated.TaskResolver { return &taskResolver{r} }
+
 type mutationResolver struct{ *Resolver }
+type projectResolver struct{ *Resolver }
+type projectCollaboratorResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type sprintResolver struct{ *Resolver }
+type sprintTaskResolver struct{ *Resolver }
 type subscriptionResolver struct{ *Resolver }
+type taskResolver struct{ *Resolver }
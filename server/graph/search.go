@@ -0,0 +1,32 @@
+package graph
+
+import (
+	"strings"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/search"
+)
+
+func searchHitToModel(hit *search.Hit) *model.TaskSearchHit {
+	highlights := make([]*model.SearchHighlight, len(hit.Highlights))
+	for i, highlight := range hit.Highlights {
+		highlights[i] = &model.SearchHighlight{
+			Field:   highlight.Field,
+			Snippet: highlight.Snippet,
+		}
+	}
+	return &model.TaskSearchHit{
+		Task:       taskToModel(&hit.Task),
+		Rank:       hit.Rank,
+		Highlights: highlights,
+	}
+}
+
+// taskSortValue lowercases a sort to the form search takes; nil is the
+// default order.
+func taskSortValue(sort *model.TaskSort) string {
+	if sort == nil {
+		return ""
+	}
+	return strings.ToLower(string(*sort))
+}
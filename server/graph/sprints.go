@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/db"
+)
+
+var sprintStatuses = map[string]model.SprintStatus{
+	"planned":   model.SprintStatusPlanning,
+	"active":    model.SprintStatusActive,
+	"completed": model.SprintStatusCompleted,
+	"cancelled": model.SprintStatusCancelled,
+}
+
+func sprintStatusValue(status model.SprintStatus) string {
+	for value, candidate := range sprintStatuses {
+		if candidate == status {
+			return value
+		}
+	}
+	return ""
+}
+
+func sprintToModel(sprint *db.SprintModel) *model.Sprint {
+	status, ok := sprintStatuses[sprint.Status]
+	if !ok {
+		status = model.SprintStatusPlanning
+	}
+	result := &model.Sprint{
+		ID:        sprint.ID,
+		Name:      sprint.Name,
+		Status:    status,
+		StartDate: sprint.StartDate,
+		EndDate:   sprint.EndDate,
+		Velocity:  sprint.EarnedXp, // XP earned so far
+		UserID:    sprint.UserID,
+		Analytics: &model.SprintAnalytics{
+			BurndownData: []*model.BurndownPoint{},
+		},
+		CreatedAt: sprint.CreatedAt,
+		UpdatedAt: sprint.UpdatedAt,
+	}
+	if description, ok := sprint.Description(); ok {
+		result.Description = &description
+	}
+	return result
+}
+
+// sprintTaskToModel converts a sprint's link to a task; its task is resolved
+// separately. Story points and assignment times aren't stored yet.
+func sprintTaskToModel(link *db.SprintTaskModel) *model.SprintTask {
+	return &model.SprintTask{
+		ID:       link.ID,
+		SprintID: link.SprintID,
+		TaskID:   link.TaskID,
+	}
+}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"sort"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/analytics"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/tags"
+)
+
+func tagToModel(tag *db.TagModel) *model.Tag {
+	result := &model.Tag{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		UserID:    tag.UserID,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+	if color, ok := tag.Color(); ok {
+		result.Color = &color
+	}
+	return result
+}
+
+func tagUsageToModel(tag *tags.Tag) *model.TagUsage {
+	return &model.TagUsage{
+		Tag:       tagToModel(&tag.TagModel),
+		TaskCount: tag.TaskCount,
+	}
+}
+
+// taskTagsToModels converts a task's fetched tag links, sorted by name.
+func taskTagsToModels(links []db.TaskTagModel) []*model.Tag {
+	result := make([]*model.Tag, 0, len(links))
+	for i := range links {
+		if links[i].RelationsTaskTag.Tag != nil {
+			result = append(result, tagToModel(links[i].RelationsTaskTag.Tag))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return tags.Key(result[i].Name) < tags.Key(result[j].Name) })
+	return result
+}
+
+func tagStatToModel(stat *analytics.TagStat) *model.TagStat {
+	return &model.TagStat{
+		Tag:            tagToModel(&stat.Tag),
+		TasksCompleted: stat.TasksCompleted,
+		FocusTime:      stat.FocusMinutes,
+		XpEarned:       stat.XpEarned,
+	}
+}
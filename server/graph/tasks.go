@@ -0,0 +1,176 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/tasks"
+)
+
+var taskStatuses = map[string]model.TaskStatus{
+	"todo":        model.TaskStatusTodo,
+	"in-progress": model.TaskStatusInProgress,
+	"in-review":   model.TaskStatusInReview,
+	"completed":   model.TaskStatusCompleted,
+}
+
+// taskRelations fetches a task's subtasks in order, its comments with
+// their authors and history, its attachments, and the series it recurs in
+// with every occurrence.
+func taskRelations() []db.TaskRelationWith {
+	return []db.TaskRelationWith{
+		db.Task.Subtasks.Fetch().OrderBy(
+			db.Subtask.Position.Order(db.SortOrderAsc),
+		),
+		db.Task.Comments.Fetch().With(
+			db.TaskComment.User.Fetch(),
+			db.TaskComment.Revisions.Fetch().OrderBy(
+				db.TaskCommentRevision.CreatedAt.Order(db.SortOrderAsc),
+			),
+		).OrderBy(
+			db.TaskComment.CreatedAt.Order(db.SortOrderAsc),
+		),
+		db.Task.Attachments.Fetch().OrderBy(
+			db.TaskAttachment.CreatedAt.Order(db.SortOrderAsc),
+		),
+		db.Task.Tags.Fetch().With(
+			db.TaskTag.Tag.Fetch(),
+		),
+		db.Task.Recurrence.Fetch().With(
+			db.TaskRecurrence.Tasks.Fetch(
+				db.Task.DeletedAt.IsNull(),
+			).OrderBy(
+				db.Task.DueDate.Order(db.SortOrderDesc),
+			),
+		),
+	}
+}
+
+// loadTask fetches a live task the user may read, with its relations.
+func loadTask(ctx context.Context, client *db.PrismaClient, userID, id string) (*db.TaskModel, error) {
+	task, err := client.Task.FindFirst(
+		db.Task.ID.Equals(id),
+		db.Task.DeletedAt.IsNull(),
+	).With(taskRelations()...).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := authz.Authorize(ctx, client, userID, authz.ActionRead, authz.TaskResource(task)); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// taskStatusValue maps a TaskStatus back to the stored value, or "" for
+// statuses tasks are never stored with.
+func taskStatusValue(status model.TaskStatus) string {
+	for value, candidate := range taskStatuses {
+		if candidate == status {
+			return value
+		}
+	}
+	return ""
+}
+
+// loadTaskModel converts a live task with its subtasks.
+func loadTaskModel(ctx context.Context, client *db.PrismaClient, userID, id string) (*model.Task, error) {
+	task, err := loadTask(ctx, client, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return taskToModel(task), nil
+}
+
+func tasksToModels(list []*db.TaskModel) []*model.Task {
+	result := make([]*model.Task, len(list))
+	for i, task := range list {
+		result[i] = taskToModel(task)
+	}
+	return result
+}
+
+// taskToModel converts a task, with its subtasks and comments when they were
+// fetched.
+func taskToModel(task *db.TaskModel) *model.Task {
+	status, ok := taskStatuses[task.Status]
+	if !ok {
+		status = model.TaskStatusTodo
+	}
+
+	result := &model.Task{
+		ID:               task.ID,
+		Title:            task.Title,
+		Status:           status,
+		Priority:         model.Priority(strings.ToUpper(task.Priority)),
+		XpValue:          task.XpValue,
+		Tags:             taskTagsToModels(task.RelationsTask.Tags),
+		UserID:           task.UserID,
+		ProjectID:        &task.ProjectID,
+		PomodoroSessions: []*model.PomodoroSession{},
+		Subtasks:         make([]*model.Subtask, len(task.RelationsTask.Subtasks)),
+		Progress:         tasks.Progress(task),
+		AutoComplete:     task.AutoComplete,
+		Comments:         make([]*model.TaskComment, len(task.RelationsTask.Comments)),
+		Attachments:      attachmentsToModels(task.RelationsTask.Attachments),
+		CreatedAt:        task.CreatedAt,
+		UpdatedAt:        task.UpdatedAt,
+	}
+	if description, ok := task.Description(); ok {
+		result.Description = &description
+	}
+	if dueDate, ok := task.DueDate(); ok {
+		result.DueDate = &dueDate
+	}
+	if completedAt, ok := task.CompletedAt(); ok {
+		result.CompletedAt = &completedAt
+	}
+	for i := range task.RelationsTask.Subtasks {
+		result.Subtasks[i] = subtaskToModel(&task.RelationsTask.Subtasks[i])
+	}
+	for i := range task.RelationsTask.Comments {
+		result.Comments[i] = commentToModel(&task.RelationsTask.Comments[i])
+	}
+	if series := task.RelationsTask.Recurrence; series != nil {
+		result.Recurrence = recurrenceToModel(series)
+	}
+	return result
+}
+
+// recurrenceToModel converts a series, with its occurrences when they were
+// fetched.
+func recurrenceToModel(series *db.TaskRecurrenceModel) *model.TaskRecurrence {
+	result := &model.TaskRecurrence{
+		ID:              series.ID,
+		Rule:            series.Rule,
+		AfterCompletion: series.AfterCompletion,
+		StartsAt:        series.StartsAt,
+		CurrentStreak:   series.CurrentStreak,
+		LongestStreak:   series.LongestStreak,
+		Occurrences:     make([]*model.Task, len(series.RelationsTaskRecurrence.Tasks)),
+	}
+	if endedAt, ok := series.EndedAt(); ok {
+		result.EndedAt = &endedAt
+	}
+	for i := range series.RelationsTaskRecurrence.Tasks {
+		result.Occurrences[i] = taskToModel(&series.RelationsTaskRecurrence.Tasks[i])
+	}
+	return result
+}
+
+func subtaskToModel(subtask *db.SubtaskModel) *model.Subtask {
+	result := &model.Subtask{
+		ID:        subtask.ID,
+		Title:     subtask.Title,
+		Completed: subtask.Completed,
+		Position:  subtask.Position,
+		TaskID:    subtask.TaskID,
+		CreatedAt: subtask.CreatedAt,
+	}
+	if completedAt, ok := subtask.CompletedAt(); ok {
+		result.CompletedAt = &completedAt
+	}
+	return result
+}
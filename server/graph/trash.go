@@ -0,0 +1,18 @@
+package graph
+
+import (
+	"strings"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/trash"
+)
+
+func trashEntryToModel(entry *trash.Entry) *model.TrashItem {
+	return &model.TrashItem{
+		Kind:      model.TrashItemKind(strings.ToUpper(entry.Kind)),
+		ID:        entry.ID,
+		Name:      entry.Name,
+		DeletedAt: entry.DeletedAt,
+		ExpiresAt: entry.ExpiresAt,
+	}
+}
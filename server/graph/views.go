@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"strings"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/views"
+)
+
+func savedViewToModel(view *db.SavedViewModel) *model.SavedView {
+	result := &model.SavedView{
+		ID:        view.ID,
+		Name:      view.Name,
+		Query:     view.Query,
+		Reverse:   view.Reverse,
+		GroupBy:   model.ViewGrouping(strings.ToUpper(view.GroupBy)),
+		Pinned:    view.Pinned,
+		Shared:    view.Shared,
+		UserID:    view.UserID,
+		CreatedAt: view.CreatedAt,
+		UpdatedAt: view.UpdatedAt,
+	}
+	if sort, ok := view.Sort(); ok {
+		value := model.TaskSort(strings.ToUpper(sort))
+		result.Sort = &value
+	}
+	if projectID, ok := view.ProjectID(); ok {
+		result.ProjectID = &projectID
+	}
+	return result
+}
+
+func savedViewResultToModel(result *views.Result) *model.SavedViewResult {
+	groups := make([]*model.TaskGroup, len(result.Groups))
+	for i, group := range result.Groups {
+		hits := make([]*model.TaskSearchHit, len(group.Hits))
+		for j := range group.Hits {
+			hits[j] = searchHitToModel(&group.Hits[j])
+		}
+		groups[i] = &model.TaskGroup{
+			Key:   group.Key,
+			Label: group.Label,
+			Tasks: hits,
+		}
+	}
+	return &model.SavedViewResult{
+		View:   savedViewToModel(&result.View),
+		Total:  result.Total,
+		Groups: groups,
+	}
+}
+
+// savedViewFields maps GraphQL input onto views.Fields, lowercasing enums
+// to their stored form.
+func savedViewFields(name, query *string, sort *model.TaskSort, clearSort, reverse *bool, groupBy *model.ViewGrouping, pinned *bool, projectID *string, shared *bool) views.Fields {
+	fields := views.Fields{
+		Name:      name,
+		Query:     query,
+		Reverse:   reverse,
+		Pinned:    pinned,
+		ProjectID: projectID,
+		Shared:    shared,
+	}
+	if sort != nil || (clearSort != nil && *clearSort) {
+		value := taskSortValue(sort)
+		fields.Sort = &value
+	}
+	if groupBy != nil {
+		value := strings.ToLower(string(*groupBy))
+		fields.GroupBy = &value
+	}
+	return fields
+}
@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"strings"
+
+	"lifequest-server/graph/model"
+	"lifequest-server/internal/db"
+)
+
+func webhookToModel(hook *db.WebhookModel) *model.Webhook {
+	result := &model.Webhook{
+		ID:        hook.ID,
+		URL:       hook.URL,
+		Events:    hook.Events,
+		Active:    hook.Active,
+		Secret:    hook.Secret,
+		UserID:    hook.UserID,
+		CreatedAt: hook.CreatedAt,
+		UpdatedAt: hook.UpdatedAt,
+	}
+	if description, ok := hook.Description(); ok {
+		result.Description = &description
+	}
+	if result.Events == nil {
+		result.Events = []string{}
+	}
+	return result
+}
+
+func webhookDeliveryToModel(delivery *db.WebhookDeliveryModel) *model.WebhookDelivery {
+	result := &model.WebhookDelivery{
+		ID:        delivery.ID,
+		WebhookID: delivery.WebhookID,
+		Event:     delivery.Event,
+		Payload:   delivery.Payload,
+		Status:    model.WebhookDeliveryStatus(strings.ToUpper(delivery.Status)),
+		Attempts:  delivery.Attempts,
+		CreatedAt: delivery.CreatedAt,
+	}
+	if code, ok := delivery.ResponseCode(); ok {
+		result.ResponseCode = &code
+	}
+	if body, ok := delivery.ResponseBody(); ok {
+		result.ResponseBody = &body
+	}
+	if message, ok := delivery.Error(); ok {
+		result.Error = &message
+	}
+	if next, ok := delivery.NextAttemptAt(); ok {
+		result.NextAttemptAt = &next
+	}
+	if delivered, ok := delivery.DeliveredAt(); ok {
+		result.DeliveredAt = &delivered
+	}
+	return result
+}
@@ -0,0 +1,485 @@
+// Package analytics aggregates a user's completed tasks, pomodoro sessions,
+// habit check-ins and XP ledger into daily, weekly and monthly statistics,
+// and breaks them down by tag. Days are UTC days and weeks start on Monday,
+// as for habits.
+package analytics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/habits"
+	"lifequest-server/internal/tasks"
+	"lifequest-server/internal/xp"
+)
+
+// MaxDays is the longest range a report covers.
+const MaxDays = 366
+
+const (
+	sessionTypeWork        = "work"
+	sessionStatusCompleted = "completed"
+)
+
+var ErrInvalidRange = errors.New("range must end after it starts and cover at most 366 days")
+
+// Day is one day of activity.
+type Day struct {
+	Date             time.Time `json:"date"`
+	TasksCompleted   int       `json:"tasksCompleted"`
+	XpEarned         int       `json:"xpEarned"`
+	PomodoroSessions int       `json:"pomodoroSessions"` // completed work sessions
+	FocusMinutes     int       `json:"focusMinutes"`
+	ProjectsWorkedOn int       `json:"projectsWorkedOn"`
+	HabitCheckIns    int       `json:"habitCheckIns"`
+	HabitGoalsMet    int       `json:"habitGoalsMet"` // habit targets reached
+}
+
+// Week sums the days of a week that fall in the report.
+type Week struct {
+	WeekStart          time.Time `json:"weekStart"`
+	TasksCompleted     int       `json:"tasksCompleted"`
+	XpEarned           int       `json:"xpEarned"`
+	PomodoroSessions   int       `json:"pomodoroSessions"`
+	FocusMinutes       int       `json:"focusMinutes"`
+	HabitCheckIns      int       `json:"habitCheckIns"`
+	AverageTasksPerDay float64   `json:"averageTasksPerDay"`
+}
+
+// Month sums the days of a month that fall in the report.
+type Month struct {
+	Year             int `json:"year"`
+	Month            int `json:"month"`
+	TasksCompleted   int `json:"tasksCompleted"`
+	XpEarned         int `json:"xpEarned"`
+	PomodoroSessions int `json:"pomodoroSessions"`
+	FocusMinutes     int `json:"focusMinutes"`
+	HabitCheckIns    int `json:"habitCheckIns"`
+	GoalsAchieved    int `json:"goalsAchieved"` // habit targets reached
+}
+
+// Productivity describes when and how much work gets done.
+type Productivity struct {
+	AverageTasksPerDay float64 `json:"averageTasksPerDay"`
+	PeakHour           int     `json:"peakHour"`          // UTC hour with the most completed tasks
+	MostProductiveDay  string  `json:"mostProductiveDay"` // weekday with the most completed tasks, empty without any
+	CompletionRate     float64 `json:"completionRate"`    // share of the tasks created in the range that are completed
+	AverageTaskMinutes float64 `json:"averageTaskMinutes"`
+}
+
+// Focus describes pomodoro work sessions.
+type Focus struct {
+	AverageSessionMinutes float64 `json:"averageSessionMinutes"`
+	TotalMinutes          int     `json:"totalMinutes"`
+	StreakDays            int     `json:"streakDays"`    // days in a row with focus time, up to the end of the range
+	PreferredTime         string  `json:"preferredTime"` // morning, afternoon, evening or night; empty without sessions
+	Efficiency            float64 `json:"efficiency"`    // share of finished work sessions that were completed
+}
+
+// Report is a user's activity over a range of days.
+type Report struct {
+	From         time.Time    `json:"from"`
+	To           time.Time    `json:"to"`
+	Days         []Day        `json:"days"`
+	Weeks        []Week       `json:"weeks"`
+	Months       []Month      `json:"months"`
+	Productivity Productivity `json:"productivity"`
+	Focus        Focus        `json:"focus"`
+}
+
+// Load builds the report for the days from from to to, inclusive.
+func Load(ctx context.Context, client *db.PrismaClient, userID string, from, to time.Time) (*Report, error) {
+	from, to, days, err := span(from, to)
+	if err != nil {
+		return nil, err
+	}
+	end := to.AddDate(0, 0, 1)
+
+	completed, err := client.Task.FindMany(
+		db.Task.UserID.Equals(userID),
+		db.Task.DeletedAt.IsNull(),
+		db.Task.Status.Equals(tasks.StatusCompleted),
+		db.Task.CompletedAt.Gte(from),
+		db.Task.CompletedAt.Lt(end),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := client.Task.FindMany(
+		db.Task.UserID.Equals(userID),
+		db.Task.DeletedAt.IsNull(),
+		db.Task.CreatedAt.Gte(from),
+		db.Task.CreatedAt.Lt(end),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := client.PomodoroSession.FindMany(
+		db.PomodoroSession.UserID.Equals(userID),
+		db.PomodoroSession.DeletedAt.IsNull(),
+		db.PomodoroSession.Type.Equals(sessionTypeWork),
+		db.PomodoroSession.StartTime.Gte(from),
+		db.PomodoroSession.StartTime.Lt(end),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger, err := client.XpEntry.FindMany(
+		db.XpEntry.UserID.Equals(userID),
+		db.XpEntry.CreatedAt.Gte(from),
+		db.XpEntry.CreatedAt.Lt(end),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	checkIns, err := client.HabitCheckIn.FindMany(
+		db.HabitCheckIn.UserID.Equals(userID),
+		db.HabitCheckIn.Date.Gte(from),
+		db.HabitCheckIn.Date.Lt(end),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{From: from, To: to, Days: make([]Day, days)}
+	projects := make([]map[string]bool, days)
+	for i := range report.Days {
+		report.Days[i].Date = from.AddDate(0, 0, i)
+		projects[i] = map[string]bool{}
+	}
+	index := func(t time.Time) int {
+		return int(habits.PeriodStart(habits.PeriodDaily, t).Sub(from).Hours() / 24)
+	}
+
+	var hours [24]int
+	var weekdays [7]int
+	completedIDs := map[string]bool{}
+	for _, task := range completed {
+		completedAt, _ := task.CompletedAt()
+		i := index(completedAt)
+		report.Days[i].TasksCompleted++
+		projects[i][task.ProjectID] = true
+		hours[completedAt.UTC().Hour()]++
+		weekdays[completedAt.UTC().Weekday()]++
+		completedIDs[task.ID] = true
+	}
+
+	var finished, completedSessions, taskMinutes int
+	var periods = map[string]int{}
+	for _, session := range sessions {
+		if session.Status != sessionStatusCompleted {
+			if _, ended := session.EndTime(); ended || session.Status == "cancelled" {
+				finished++
+			}
+			continue
+		}
+		finished++
+		completedSessions++
+		i := index(session.StartTime)
+		report.Days[i].PomodoroSessions++
+		report.Days[i].FocusMinutes += session.Duration
+		if projectID, ok := session.ProjectID(); ok {
+			projects[i][projectID] = true
+		}
+		if taskID, ok := session.TaskID(); ok && completedIDs[taskID] {
+			taskMinutes += session.Duration
+		}
+		periods[timeOfDay(session.StartTime.UTC().Hour())] += session.Duration
+	}
+
+	for _, entry := range ledger {
+		i := index(entry.CreatedAt)
+		switch entry.Reason {
+		case xp.ReasonTrashed, xp.ReasonRestored:
+			continue
+		case xp.ReasonHabitCompleted:
+			report.Days[i].HabitGoalsMet++
+		case xp.ReasonHabitReopened:
+			report.Days[i].HabitGoalsMet--
+		}
+		report.Days[i].XpEarned += entry.Amount
+	}
+
+	for _, checkIn := range checkIns {
+		report.Days[index(checkIn.Date)].HabitCheckIns += checkIn.Count
+	}
+
+	for i := range report.Days {
+		report.Days[i].ProjectsWorkedOn = len(projects[i])
+	}
+
+	report.Weeks = weeks(report.Days)
+	report.Months = months(report.Days)
+	report.Productivity = productivity(report.Days, hours, weekdays, completed, created, taskMinutes)
+	report.Focus = focus(report.Days, completedSessions, finished, periods)
+	return report, nil
+}
+
+// TagStat is what the tasks carrying a tag achieved over a range of days. A
+// task with several tags counts towards each of them.
+type TagStat struct {
+	Tag            db.TagModel `json:"tag"`
+	TasksCompleted int         `json:"tasksCompleted"`
+	FocusMinutes   int         `json:"focusMinutes"` // completed work sessions on the tasks
+	XpEarned       int         `json:"xpEarned"`     // XP from the tasks and their subtasks
+}
+
+// ByTag breaks the days from from to to, inclusive, down by tag. Every tag
+// is listed, by name, including ones without activity.
+func ByTag(ctx context.Context, client *db.PrismaClient, userID string, from, to time.Time) ([]TagStat, error) {
+	from, to, _, err := span(from, to)
+	if err != nil {
+		return nil, err
+	}
+	end := to.AddDate(0, 0, 1)
+
+	list, err := client.Tag.FindMany(
+		db.Tag.UserID.Equals(userID),
+	).OrderBy(
+		db.Tag.Key.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := client.TaskTag.FindMany(
+		db.TaskTag.Task.Where(
+			db.Task.UserID.Equals(userID),
+			db.Task.DeletedAt.IsNull(),
+		),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tagged := map[string][]string{}
+	var taskIDs []string
+	for _, link := range links {
+		if _, ok := tagged[link.TaskID]; !ok {
+			taskIDs = append(taskIDs, link.TaskID)
+		}
+		tagged[link.TaskID] = append(tagged[link.TaskID], link.TagID)
+	}
+
+	stats := map[string]*TagStat{}
+	result := make([]TagStat, len(list))
+	for i, tag := range list {
+		result[i].Tag = tag
+		stats[tag.ID] = &result[i]
+	}
+	if len(taskIDs) == 0 {
+		return result, nil
+	}
+
+	completed, err := client.Task.FindMany(
+		db.Task.ID.In(taskIDs),
+		db.Task.Status.Equals(tasks.StatusCompleted),
+		db.Task.CompletedAt.Gte(from),
+		db.Task.CompletedAt.Lt(end),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range completed {
+		for _, tagID := range tagged[task.ID] {
+			stats[tagID].TasksCompleted++
+		}
+	}
+
+	sessions, err := client.PomodoroSession.FindMany(
+		db.PomodoroSession.TaskID.In(taskIDs),
+		db.PomodoroSession.DeletedAt.IsNull(),
+		db.PomodoroSession.Type.Equals(sessionTypeWork),
+		db.PomodoroSession.Status.Equals(sessionStatusCompleted),
+		db.PomodoroSession.StartTime.Gte(from),
+		db.PomodoroSession.StartTime.Lt(end),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		taskID, _ := session.TaskID()
+		for _, tagID := range tagged[taskID] {
+			stats[tagID].FocusMinutes += session.Duration
+		}
+	}
+
+	// Subtask XP is recorded against the subtask; credit it to its task.
+	subtasks, err := client.Subtask.FindMany(
+		db.Subtask.TaskID.In(taskIDs),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]string, len(taskIDs)+len(subtasks))
+	for _, id := range taskIDs {
+		sources[id] = id
+	}
+	for _, subtask := range subtasks {
+		sources[subtask.ID] = subtask.TaskID
+	}
+	sourceIDs := make([]string, 0, len(sources))
+	for id := range sources {
+		sourceIDs = append(sourceIDs, id)
+	}
+
+	ledger, err := client.XpEntry.FindMany(
+		db.XpEntry.UserID.Equals(userID),
+		db.XpEntry.SourceID.In(sourceIDs),
+		db.XpEntry.CreatedAt.Gte(from),
+		db.XpEntry.CreatedAt.Lt(end),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range ledger {
+		if entry.Reason == xp.ReasonTrashed || entry.Reason == xp.ReasonRestored {
+			continue
+		}
+		sourceID, _ := entry.SourceID()
+		for _, tagID := range tagged[sources[sourceID]] {
+			stats[tagID].XpEarned += entry.Amount
+		}
+	}
+	return result, nil
+}
+
+// span truncates a range to whole days and checks its length.
+func span(from, to time.Time) (time.Time, time.Time, int, error) {
+	from, to = habits.PeriodStart(habits.PeriodDaily, from), habits.PeriodStart(habits.PeriodDaily, to)
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days < 1 || days > MaxDays {
+		return time.Time{}, time.Time{}, 0, ErrInvalidRange
+	}
+	return from, to, days, nil
+}
+
+func weeks(days []Day) []Week {
+	var result []Week
+	counted := 0
+	for _, day := range days {
+		start := habits.PeriodStart(habits.PeriodWeekly, day.Date)
+		if len(result) == 0 || !result[len(result)-1].WeekStart.Equal(start) {
+			if len(result) > 0 {
+				result[len(result)-1].AverageTasksPerDay = perDay(result[len(result)-1].TasksCompleted, counted)
+			}
+			result = append(result, Week{WeekStart: start})
+			counted = 0
+		}
+		week := &result[len(result)-1]
+		week.TasksCompleted += day.TasksCompleted
+		week.XpEarned += day.XpEarned
+		week.PomodoroSessions += day.PomodoroSessions
+		week.FocusMinutes += day.FocusMinutes
+		week.HabitCheckIns += day.HabitCheckIns
+		counted++
+	}
+	if len(result) > 0 {
+		result[len(result)-1].AverageTasksPerDay = perDay(result[len(result)-1].TasksCompleted, counted)
+	}
+	return result
+}
+
+func months(days []Day) []Month {
+	var result []Month
+	for _, day := range days {
+		year, month := day.Date.Year(), int(day.Date.Month())
+		if len(result) == 0 || result[len(result)-1].Year != year || result[len(result)-1].Month != month {
+			result = append(result, Month{Year: year, Month: month})
+		}
+		m := &result[len(result)-1]
+		m.TasksCompleted += day.TasksCompleted
+		m.XpEarned += day.XpEarned
+		m.PomodoroSessions += day.PomodoroSessions
+		m.FocusMinutes += day.FocusMinutes
+		m.HabitCheckIns += day.HabitCheckIns
+		m.GoalsAchieved += day.HabitGoalsMet
+	}
+	return result
+}
+
+func productivity(days []Day, hours [24]int, weekdays [7]int, completed, created []db.TaskModel, taskMinutes int) Productivity {
+	result := Productivity{AverageTasksPerDay: perDay(len(completed), len(days))}
+	if len(completed) > 0 {
+		result.PeakHour = busiest(hours[:])
+		result.MostProductiveDay = time.Weekday(busiest(weekdays[:])).String()
+		result.AverageTaskMinutes = float64(taskMinutes) / float64(len(completed))
+	}
+	if len(created) > 0 {
+		done := 0
+		for _, task := range created {
+			if task.Status == tasks.StatusCompleted {
+				done++
+			}
+		}
+		result.CompletionRate = float64(done) / float64(len(created))
+	}
+	return result
+}
+
+func focus(days []Day, completedSessions, finished int, periods map[string]int) Focus {
+	var result Focus
+	for _, day := range days {
+		result.TotalMinutes += day.FocusMinutes
+	}
+	if completedSessions > 0 {
+		result.AverageSessionMinutes = float64(result.TotalMinutes) / float64(completedSessions)
+	}
+	if finished > 0 {
+		result.Efficiency = float64(completedSessions) / float64(finished)
+	}
+
+	// Like habit streaks, a last day without focus time yet doesn't break
+	// the streak.
+	i := len(days) - 1
+	if i >= 0 && days[i].FocusMinutes == 0 {
+		i--
+	}
+	for ; i >= 0 && days[i].FocusMinutes > 0; i-- {
+		result.StreakDays++
+	}
+
+	best := 0
+	for _, period := range []string{"morning", "afternoon", "evening", "night"} {
+		if periods[period] > best {
+			result.PreferredTime, best = period, periods[period]
+		}
+	}
+	return result
+}
+
+// timeOfDay names the part of the day an hour falls in.
+func timeOfDay(hour int) string {
+	switch {
+	case hour >= 5 && hour < 12:
+		return "morning"
+	case hour >= 12 && hour < 17:
+		return "afternoon"
+	case hour >= 17 && hour < 22:
+		return "evening"
+	}
+	return "night"
+}
+
+// busiest returns the index of the largest count, the first on ties.
+func busiest(counts []int) int {
+	best := 0
+	for i, count := range counts {
+		if count > counts[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+func perDay(count, days int) float64 {
+	if days == 0 {
+		return 0
+	}
+	return float64(count) / float64(days)
+}
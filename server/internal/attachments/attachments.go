@@ -0,0 +1,368 @@
+// Package attachments stores files attached to tasks in the configured blob
+// store. Uploads are size-limited, counted against a per-user quota and
+// typed by sniffing their content rather than trusting the client. Blobs of
+// attachments whose task has been deleted are removed in the background.
+package attachments
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/blob"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/live"
+	"lifequest-server/internal/utils"
+)
+
+const (
+	// MaxSize is the largest file accepted, in bytes.
+	MaxSize = 25 << 20
+
+	// LinkTTL is how long a download URL stays valid.
+	LinkTTL = 15 * time.Minute
+
+	defaultQuota = 250 << 20
+
+	sweepInterval = time.Hour
+)
+
+var (
+	ErrTaskNotFound  = errors.New("task not found")
+	ErrNotFound      = errors.New("attachment not found")
+	ErrEmpty         = errors.New("file is empty")
+	ErrTooLarge      = errors.New("file is larger than 25 MB")
+	ErrQuotaExceeded = errors.New("attachment storage quota exceeded")
+	ErrForbidden     = errors.New("your role on the project does not allow this")
+)
+
+// Attachment is an attachment with a signed URL to download it.
+type Attachment struct {
+	db.TaskAttachmentModel
+	URL          string    `json:"url"`
+	URLExpiresAt time.Time `json:"urlExpiresAt"`
+}
+
+// Quota returns how many bytes of attachments a user can store:
+// ATTACHMENT_QUOTA_BYTES, or 250 MB.
+func Quota() int64 {
+	if quota, err := strconv.ParseInt(os.Getenv("ATTACHMENT_QUOTA_BYTES"), 10, 64); err == nil && quota > 0 {
+		return quota
+	}
+	return defaultQuota
+}
+
+// Usage returns the total size of the user's attachments in bytes.
+func Usage(ctx context.Context, client *db.PrismaClient, userID string) (int64, error) {
+	stored, err := client.TaskAttachment.FindMany(
+		db.TaskAttachment.UserID.Equals(userID),
+	).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, attachment := range stored {
+		total += int64(attachment.Size)
+	}
+	return total, nil
+}
+
+// List returns the task's attachments, oldest first.
+func List(ctx context.Context, client *db.PrismaClient, userID, taskID string) ([]Attachment, error) {
+	if _, err := findTask(ctx, client, userID, taskID, authz.ActionRead); err != nil {
+		return nil, err
+	}
+	stored, err := client.TaskAttachment.FindMany(
+		db.TaskAttachment.TaskID.Equals(taskID),
+	).OrderBy(
+		db.TaskAttachment.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Attachment, 0, len(stored))
+	for _, attachment := range stored {
+		signed, err := Sign(&attachment)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *signed)
+	}
+	return list, nil
+}
+
+// Upload reads a file from r and attaches it to the task. The file is
+// buffered on disk first so its size is known before anything is stored.
+func Upload(ctx context.Context, client *db.PrismaClient, userID, taskID, filename string, r io.Reader) (*Attachment, error) {
+	task, err := findTask(ctx, client, userID, taskID, authz.ActionCreate)
+	if err != nil {
+		return nil, err
+	}
+	store, err := blob.Default()
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "lifequest-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case size == 0:
+		return nil, ErrEmpty
+	case size > MaxSize:
+		return nil, ErrTooLarge
+	}
+
+	used, err := Usage(ctx, client, userID)
+	if err != nil {
+		return nil, err
+	}
+	if used+size > Quota() {
+		return nil, ErrQuotaExceeded
+	}
+
+	mimeType, err := sniff(tmp)
+	if err != nil {
+		return nil, err
+	}
+
+	id := utils.GenerateUUID()
+	key := "attachments/" + userID + "/" + id
+	if err := store.Put(ctx, key, tmp, size, mimeType); err != nil {
+		return nil, err
+	}
+
+	attachment, err := client.TaskAttachment.CreateOne(
+		db.TaskAttachment.Filename.Set(cleanFilename(filename)),
+		db.TaskAttachment.MimeType.Set(mimeType),
+		db.TaskAttachment.Size.Set(int(size)),
+		db.TaskAttachment.StorageKey.Set(key),
+		db.TaskAttachment.User.Link(db.User.ID.Equals(userID)),
+		db.TaskAttachment.ID.Set(id),
+		db.TaskAttachment.Task.Link(db.Task.ID.Equals(task.ID)),
+	).Exec(ctx)
+	if err != nil {
+		if deleteErr := store.Delete(ctx, key); deleteErr != nil {
+			log.Printf("attachments: failed to remove blob %s: %v", key, deleteErr)
+		}
+		return nil, err
+	}
+
+	publish(task, live.ActionAttachmentAdded)
+	return Sign(attachment)
+}
+
+// Get returns an attachment on a task that is not in the trash, from a
+// project the user can see.
+func Get(ctx context.Context, client *db.PrismaClient, userID, id string) (*Attachment, error) {
+	attachment, _, err := find(ctx, client, userID, id, authz.ActionRead)
+	if err != nil {
+		return nil, err
+	}
+	return Sign(attachment)
+}
+
+// Delete removes the attachment and its blob. Attachments can be deleted by
+// their uploader and by the project's admins.
+func Delete(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	attachment, task, err := find(ctx, client, userID, id, authz.ActionDelete)
+	if err != nil {
+		return err
+	}
+	if err := remove(ctx, client, attachment); err != nil {
+		return err
+	}
+	publish(task, live.ActionAttachmentDeleted)
+	return nil
+}
+
+// Sign adds a download URL valid for LinkTTL to the attachment.
+func Sign(attachment *db.TaskAttachmentModel) (*Attachment, error) {
+	store, err := blob.Default()
+	if err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(LinkTTL)
+	url, err := store.SignedURL(attachment.StorageKey, attachment.Filename, expires)
+	if err != nil {
+		return nil, err
+	}
+	return &Attachment{TaskAttachmentModel: *attachment, URL: url, URLExpiresAt: expires}, nil
+}
+
+// Start removes the blobs of attachments whose task was deleted every
+// sweepInterval until ctx is cancelled.
+func Start(ctx context.Context, client *db.PrismaClient) {
+	go func() {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sweep(ctx, client)
+			}
+		}
+	}()
+}
+
+func sweep(ctx context.Context, client *db.PrismaClient) {
+	orphans, err := client.TaskAttachment.FindMany(
+		db.TaskAttachment.TaskID.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		log.Printf("attachments: failed to load orphaned attachments: %v", err)
+		return
+	}
+
+	for _, attachment := range orphans {
+		if err := remove(ctx, client, &attachment); err != nil {
+			log.Printf("attachments: failed to remove attachment %s: %v", attachment.ID, err)
+		}
+	}
+}
+
+// remove deletes the blob before the row, so a failure leaves the row to
+// retry from rather than an untracked blob.
+func remove(ctx context.Context, client *db.PrismaClient, attachment *db.TaskAttachmentModel) error {
+	store, err := blob.Default()
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(ctx, attachment.StorageKey); err != nil {
+		return err
+	}
+	_, err = client.TaskAttachment.FindUnique(
+		db.TaskAttachment.ID.Equals(attachment.ID),
+	).Delete().Exec(ctx)
+	if db.IsErrNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// sniff detects the MIME type from the first 512 bytes of file and rewinds
+// it.
+func sniff(file *os.File) (string, error) {
+	head := make([]byte, 512)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// cleanFilename keeps the base name of a client-supplied filename without
+// control characters, capped at 255 bytes.
+func cleanFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, strings.ToValidUTF8(name, ""))
+	name = strings.TrimSpace(name)
+	for len(name) > 255 {
+		name = strings.ToValidUTF8(name[:255], "")
+	}
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
+
+// findTask returns the live task when userID may take action on its
+// attachments.
+func findTask(ctx context.Context, client *db.PrismaClient, userID, id string, action authz.Action) (*db.TaskModel, error) {
+	task, err := authz.Task(ctx, client, id)
+	if errors.Is(err, authz.ErrNotFound) {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = authz.Authorize(ctx, client, userID, action, authz.In(authz.KindAttachment, task.ProjectID))
+	if err != nil {
+		return nil, denied(err, ErrTaskNotFound)
+	}
+	return task, nil
+}
+
+// find returns the attachment and its live task when userID may take action
+// on it.
+func find(ctx context.Context, client *db.PrismaClient, userID, id string, action authz.Action) (*db.TaskAttachmentModel, *db.TaskModel, error) {
+	attachment, err := client.TaskAttachment.FindFirst(
+		db.TaskAttachment.ID.Equals(id),
+		db.TaskAttachment.Task.Where(
+			db.Task.DeletedAt.IsNull(),
+		),
+	).With(
+		db.TaskAttachment.Task.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	task, ok := attachment.Task()
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	err = authz.Authorize(ctx, client, userID, action, authz.Resource{
+		Kind:      authz.KindAttachment,
+		ID:        attachment.ID,
+		ProjectID: task.ProjectID,
+		AuthorID:  attachment.UserID,
+	})
+	if err != nil {
+		return nil, nil, denied(err, ErrNotFound)
+	}
+	return attachment, task, nil
+}
+
+// denied maps authorization failures to notFound or ErrForbidden.
+func denied(err, notFound error) error {
+	switch {
+	case errors.Is(err, authz.ErrNotFound):
+		return notFound
+	case errors.Is(err, authz.ErrForbidden):
+		return ErrForbidden
+	}
+	return err
+}
+
+func publish(task *db.TaskModel, action string) {
+	live.Publish(live.ProjectTopic(task.ProjectID), live.TaskEvent{
+		Action: action,
+		TaskID: task.ID,
+		UserID: task.UserID,
+	})
+}
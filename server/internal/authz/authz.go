@@ -0,0 +1,171 @@
+// Package authz decides what a user may do with the projects shared with
+// them, and everything inside those projects. A user's role on a project
+// comes from owning it (Project.userId) or from a joined collaborator row;
+// the matrix below maps each role to the actions it allows on each kind of
+// resource. Services call Authorize before acting, so the REST and GraphQL
+// APIs enforce the same rules.
+package authz
+
+import (
+	"errors"
+	"strings"
+)
+
+// Role is a user's standing on a project, ordered so that each role can do
+// everything the roles below it can.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleViewer
+	RoleMember
+	RoleAdmin
+	RoleOwner
+)
+
+// nobody is above every role, for actions only authors may take.
+const nobody = RoleOwner + 1
+
+var roleNames = map[Role]string{
+	RoleNone:   "none",
+	RoleViewer: "viewer",
+	RoleMember: "member",
+	RoleAdmin:  "admin",
+	RoleOwner:  "owner",
+}
+
+// ParseRole returns the role stored as name on ProjectCollaborator.role, or
+// RoleNone for unknown names.
+func ParseRole(name string) Role {
+	for role, candidate := range roleNames {
+		if candidate == strings.ToLower(name) {
+			return role
+		}
+	}
+	return RoleNone
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return "nobody"
+}
+
+// Kind is a kind of resource.
+type Kind string
+
+const (
+	KindProject    Kind = "project"
+	KindTask       Kind = "task"
+	KindSprint     Kind = "sprint"
+	KindComment    Kind = "comment"
+	KindAttachment Kind = "attachment"
+)
+
+// Action is something done to a resource. Creating is authorized against
+// the project the resource is created in.
+type Action string
+
+const (
+	ActionRead     Action = "read"
+	ActionCreate   Action = "create"
+	ActionUpdate   Action = "update"
+	ActionDelete   Action = "delete"
+	ActionManage   Action = "manage"   // invite, change and remove collaborators
+	ActionTransfer Action = "transfer" // hand the project to another member
+)
+
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrForbidden = errors.New("not allowed for your role on this project")
+)
+
+// Rule is the least role allowed to take an action: Role on any resource of
+// the kind, AuthorRole on resources the user created. An AuthorRole of
+// RoleNone gives authors no extra rights.
+type Rule struct {
+	Role       Role
+	AuthorRole Role
+}
+
+// matrix holds the rule for every action allowed on every kind. Actions
+// missing from it are allowed to nobody.
+//
+// Tasks are deleted only by their creator, since they go to the creator's
+// trash with the XP they earned. Sprints belong to no project: their creator
+// is their owner and nobody else sees them.
+var matrix = map[Kind]map[Action]Rule{
+	KindProject: {
+		ActionRead:     {Role: RoleViewer},
+		ActionUpdate:   {Role: RoleAdmin},
+		ActionDelete:   {Role: RoleOwner},
+		ActionManage:   {Role: RoleAdmin},
+		ActionTransfer: {Role: RoleOwner},
+	},
+	KindTask: {
+		ActionRead:   {Role: RoleViewer},
+		ActionCreate: {Role: RoleMember},
+		ActionUpdate: {Role: RoleMember},
+		ActionDelete: {Role: nobody, AuthorRole: RoleMember},
+	},
+	KindSprint: {
+		ActionRead:   {Role: RoleOwner},
+		ActionCreate: {Role: RoleOwner},
+		ActionUpdate: {Role: RoleOwner},
+		ActionDelete: {Role: RoleOwner},
+	},
+	KindComment: {
+		ActionRead:   {Role: RoleViewer},
+		ActionCreate: {Role: RoleMember},
+		ActionUpdate: {Role: nobody, AuthorRole: RoleMember},
+		ActionDelete: {Role: RoleAdmin, AuthorRole: RoleMember},
+	},
+	KindAttachment: {
+		ActionRead:   {Role: RoleViewer},
+		ActionCreate: {Role: RoleMember},
+		ActionDelete: {Role: RoleAdmin, AuthorRole: RoleMember},
+	},
+}
+
+// Viewer is a user acting on a resource, with their role on its project.
+type Viewer struct {
+	UserID string
+	Role   Role
+}
+
+// Resource identifies what an action is taken on. AuthorID is the user who
+// created it: the creator of a task or sprint, the author of a comment, the
+// uploader of an attachment. ProjectID is empty for sprints.
+type Resource struct {
+	Kind      Kind
+	ID        string
+	ProjectID string
+	AuthorID  string
+}
+
+// Can reports whether viewer may take action on resource.
+func Can(viewer Viewer, action Action, resource Resource) bool {
+	rule, ok := matrix[resource.Kind][action]
+	if !ok || viewer.Role == RoleNone {
+		return false
+	}
+	if viewer.Role >= rule.Role {
+		return true
+	}
+	return rule.AuthorRole != RoleNone &&
+		resource.AuthorID != "" && resource.AuthorID == viewer.UserID &&
+		viewer.Role >= rule.AuthorRole
+}
+
+// Check is Can as an error: nil when viewer may take action on resource,
+// ErrNotFound when they have no role on it, ErrForbidden otherwise.
+func Check(viewer Viewer, action Action, resource Resource) error {
+	if Can(viewer, action, resource) {
+		return nil
+	}
+	if viewer.Role == RoleNone {
+		return ErrNotFound
+	}
+	return ErrForbidden
+}
@@ -0,0 +1,219 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var (
+	roles   = []Role{RoleNone, RoleViewer, RoleMember, RoleAdmin, RoleOwner}
+	kinds   = []Kind{KindProject, KindTask, KindSprint, KindComment, KindAttachment}
+	actions = []Action{ActionRead, ActionCreate, ActionUpdate, ActionDelete, ActionManage, ActionTransfer}
+)
+
+// allowed is the set of roles allowed an action, indexed by Role.
+type allowed [RoleOwner + 1]bool
+
+// from allows role and every role above it.
+func from(role Role) allowed {
+	var a allowed
+	for r := role; r <= RoleOwner; r++ {
+		a[r] = true
+	}
+	return a
+}
+
+var never allowed
+
+// TestMatrix checks every role on every action on every kind of resource,
+// both for a resource the user created and for someone else's.
+func TestMatrix(t *testing.T) {
+	tests := []struct {
+		kind   Kind
+		action Action
+		others allowed // on resources someone else created
+		own    allowed // on resources the user created
+	}{
+		{KindProject, ActionRead, from(RoleViewer), from(RoleViewer)},
+		{KindProject, ActionCreate, never, never},
+		{KindProject, ActionUpdate, from(RoleAdmin), from(RoleAdmin)},
+		{KindProject, ActionDelete, from(RoleOwner), from(RoleOwner)},
+		{KindProject, ActionManage, from(RoleAdmin), from(RoleAdmin)},
+		{KindProject, ActionTransfer, from(RoleOwner), from(RoleOwner)},
+
+		{KindTask, ActionRead, from(RoleViewer), from(RoleViewer)},
+		{KindTask, ActionCreate, from(RoleMember), from(RoleMember)},
+		{KindTask, ActionUpdate, from(RoleMember), from(RoleMember)},
+		{KindTask, ActionDelete, never, from(RoleMember)},
+		{KindTask, ActionManage, never, never},
+		{KindTask, ActionTransfer, never, never},
+
+		{KindSprint, ActionRead, from(RoleOwner), from(RoleOwner)},
+		{KindSprint, ActionCreate, from(RoleOwner), from(RoleOwner)},
+		{KindSprint, ActionUpdate, from(RoleOwner), from(RoleOwner)},
+		{KindSprint, ActionDelete, from(RoleOwner), from(RoleOwner)},
+		{KindSprint, ActionManage, never, never},
+		{KindSprint, ActionTransfer, never, never},
+
+		{KindComment, ActionRead, from(RoleViewer), from(RoleViewer)},
+		{KindComment, ActionCreate, from(RoleMember), from(RoleMember)},
+		{KindComment, ActionUpdate, never, from(RoleMember)},
+		{KindComment, ActionDelete, from(RoleAdmin), from(RoleMember)},
+		{KindComment, ActionManage, never, never},
+		{KindComment, ActionTransfer, never, never},
+
+		{KindAttachment, ActionRead, from(RoleViewer), from(RoleViewer)},
+		{KindAttachment, ActionCreate, from(RoleMember), from(RoleMember)},
+		{KindAttachment, ActionUpdate, never, never},
+		{KindAttachment, ActionDelete, from(RoleAdmin), from(RoleMember)},
+		{KindAttachment, ActionManage, never, never},
+		{KindAttachment, ActionTransfer, never, never},
+	}
+
+	covered := map[Kind]map[Action]bool{}
+	for _, tt := range tests {
+		if covered[tt.kind] == nil {
+			covered[tt.kind] = map[Action]bool{}
+		}
+		covered[tt.kind][tt.action] = true
+
+		for _, role := range roles {
+			for _, own := range []bool{false, true} {
+				resource := Resource{Kind: tt.kind, ID: "r1", ProjectID: "p1", AuthorID: "someone-else"}
+				want := tt.others[role]
+				if own {
+					resource.AuthorID = "me"
+					want = tt.own[role]
+				}
+				viewer := Viewer{UserID: "me", Role: role}
+
+				if got := Can(viewer, tt.action, resource); got != want {
+					t.Errorf("Can(%v, %s, %s, own=%v) = %v, want %v", role, tt.action, tt.kind, own, got, want)
+				}
+
+				var wantErr error
+				switch {
+				case want:
+				case role == RoleNone:
+					wantErr = ErrNotFound
+				default:
+					wantErr = ErrForbidden
+				}
+				if err := Check(viewer, tt.action, resource); !errors.Is(err, wantErr) {
+					t.Errorf("Check(%v, %s, %s, own=%v) = %v, want %v", role, tt.action, tt.kind, own, err, wantErr)
+				}
+			}
+		}
+	}
+
+	for _, kind := range kinds {
+		for _, action := range actions {
+			if !covered[kind][action] {
+				t.Errorf("no case for %s on %s", action, kind)
+			}
+		}
+	}
+}
+
+// TestAuthorRules spells out the rules that depend on who created the
+// resource.
+func TestAuthorRules(t *testing.T) {
+	mine := func(kind Kind) Resource {
+		return Resource{Kind: kind, ID: "r1", ProjectID: "p1", AuthorID: "me"}
+	}
+	theirs := func(kind Kind) Resource {
+		return Resource{Kind: kind, ID: "r1", ProjectID: "p1", AuthorID: "them"}
+	}
+	member := Viewer{UserID: "me", Role: RoleMember}
+	viewer := Viewer{UserID: "me", Role: RoleViewer}
+	admin := Viewer{UserID: "me", Role: RoleAdmin}
+	owner := Viewer{UserID: "me", Role: RoleOwner}
+
+	tests := []struct {
+		name     string
+		viewer   Viewer
+		action   Action
+		resource Resource
+		want     error
+	}{
+		{"member edits own comment", member, ActionUpdate, mine(KindComment), nil},
+		{"member edits someone else's comment", member, ActionUpdate, theirs(KindComment), ErrForbidden},
+		{"owner edits someone else's comment", owner, ActionUpdate, theirs(KindComment), ErrForbidden},
+		{"member deletes own comment", member, ActionDelete, mine(KindComment), nil},
+		{"member deletes someone else's comment", member, ActionDelete, theirs(KindComment), ErrForbidden},
+		{"admin deletes someone else's comment", admin, ActionDelete, theirs(KindComment), nil},
+		{"viewer deletes own comment", viewer, ActionDelete, mine(KindComment), ErrForbidden},
+		{"member deletes own attachment", member, ActionDelete, mine(KindAttachment), nil},
+		{"member deletes someone else's attachment", member, ActionDelete, theirs(KindAttachment), ErrForbidden},
+		{"admin deletes someone else's attachment", admin, ActionDelete, theirs(KindAttachment), nil},
+		{"viewer deletes own attachment", viewer, ActionDelete, mine(KindAttachment), ErrForbidden},
+		{"member deletes own task", member, ActionDelete, mine(KindTask), nil},
+		{"owner deletes someone else's task", owner, ActionDelete, theirs(KindTask), ErrForbidden},
+		// An unknown author never matches an unknown user.
+		{"anonymous author", Viewer{Role: RoleMember}, ActionUpdate, Resource{Kind: KindComment, ProjectID: "p1"}, ErrForbidden},
+		{"non-member on own comment", Viewer{UserID: "me"}, ActionUpdate, mine(KindComment), ErrNotFound},
+		{"non-member reads a project", Viewer{UserID: "me"}, ActionRead, theirs(KindProject), ErrNotFound},
+		{"unknown kind", owner, ActionRead, Resource{Kind: "label", ProjectID: "p1"}, ErrForbidden},
+		{"unknown action", owner, "archive", theirs(KindProject), ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Check(tt.viewer, tt.action, tt.resource); !errors.Is(err, tt.want) {
+				t.Errorf("Check = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// Sprints belong to no project, so ViewerOf decides without the database.
+func TestSprintViewer(t *testing.T) {
+	sprint := Resource{Kind: KindSprint, ID: "s1", AuthorID: "me"}
+	tests := []struct {
+		userID string
+		role   Role
+		want   error
+	}{
+		{"me", RoleOwner, nil},
+		{"them", RoleNone, ErrNotFound},
+	}
+	for _, tt := range tests {
+		viewer, err := ViewerOf(context.Background(), nil, tt.userID, sprint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if viewer.Role != tt.role {
+			t.Errorf("ViewerOf(%s) role = %v, want %v", tt.userID, viewer.Role, tt.role)
+		}
+		for _, action := range []Action{ActionRead, ActionUpdate, ActionDelete} {
+			if err := Check(viewer, action, sprint); !errors.Is(err, tt.want) {
+				t.Errorf("Check(%s, %s sprint) = %v, want %v", tt.userID, action, err, tt.want)
+			}
+		}
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		name string
+		want Role
+	}{
+		{"viewer", RoleViewer},
+		{"member", RoleMember},
+		{"admin", RoleAdmin},
+		{"owner", RoleOwner},
+		{"ADMIN", RoleAdmin},
+		{"", RoleNone},
+		{"superuser", RoleNone},
+	}
+	for _, tt := range tests {
+		if got := ParseRole(tt.name); got != tt.want {
+			t.Errorf("ParseRole(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	for _, role := range roles {
+		if got := ParseRole(role.String()); got != role {
+			t.Errorf("ParseRole(%v.String()) = %v", role, got)
+		}
+	}
+}
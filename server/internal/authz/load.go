@@ -0,0 +1,195 @@
+package authz
+
+import (
+	"context"
+
+	"lifequest-server/internal/db"
+)
+
+// RoleOn returns userID's role on the live project: RoleOwner for its
+// owner, the collaborator role for members who joined it, RoleNone for
+// everyone else.
+func RoleOn(ctx context.Context, client *db.PrismaClient, userID, projectID string) (Role, error) {
+	project, err := client.Project.FindFirst(
+		db.Project.ID.Equals(projectID),
+		db.Project.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return RoleNone, nil
+		}
+		return RoleNone, err
+	}
+	return ProjectRole(ctx, client, userID, project)
+}
+
+// ProjectRole is RoleOn for a project already loaded.
+func ProjectRole(ctx context.Context, client *db.PrismaClient, userID string, project *db.ProjectModel) (Role, error) {
+	if project.UserID == userID {
+		return RoleOwner, nil
+	}
+
+	collaborator, err := client.ProjectCollaborator.FindFirst(
+		db.ProjectCollaborator.ProjectID.Equals(project.ID),
+		db.ProjectCollaborator.UserID.Equals(userID),
+		db.ProjectCollaborator.Not(db.ProjectCollaborator.JoinedAt.IsNull()),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return RoleNone, nil
+		}
+		return RoleNone, err
+	}
+	return ParseRole(collaborator.Role), nil
+}
+
+// ViewerOf returns userID as a viewer of resource. Sprints, which belong to
+// no project, are owned by their creator.
+func ViewerOf(ctx context.Context, client *db.PrismaClient, userID string, resource Resource) (Viewer, error) {
+	viewer := Viewer{UserID: userID}
+	if resource.ProjectID == "" {
+		if resource.AuthorID == userID {
+			viewer.Role = RoleOwner
+		}
+		return viewer, nil
+	}
+	role, err := RoleOn(ctx, client, userID, resource.ProjectID)
+	if err != nil {
+		return viewer, err
+	}
+	viewer.Role = role
+	return viewer, nil
+}
+
+// Authorize returns nil when userID may take action on resource. Users with
+// no role on the resource get ErrNotFound, so that they cannot tell it
+// exists; members whose role falls short get ErrForbidden.
+func Authorize(ctx context.Context, client *db.PrismaClient, userID string, action Action, resource Resource) error {
+	viewer, err := ViewerOf(ctx, client, userID, resource)
+	if err != nil {
+		return err
+	}
+	return Check(viewer, action, resource)
+}
+
+// Lookup finds the live resource of kind with id, returning ErrNotFound
+// when there is none.
+func Lookup(ctx context.Context, client *db.PrismaClient, kind Kind, id string) (Resource, error) {
+	switch kind {
+	case KindProject:
+		return Project(ctx, client, id)
+	case KindTask:
+		task, err := Task(ctx, client, id)
+		if err != nil {
+			return Resource{}, err
+		}
+		return TaskResource(task), nil
+	case KindSprint:
+		return Sprint(ctx, client, id)
+	case KindComment:
+		return Comment(ctx, client, id)
+	case KindAttachment:
+		return Attachment(ctx, client, id)
+	}
+	return Resource{}, ErrNotFound
+}
+
+// Project returns the live project with id.
+func Project(ctx context.Context, client *db.PrismaClient, id string) (Resource, error) {
+	project, err := client.Project.FindFirst(
+		db.Project.ID.Equals(id),
+		db.Project.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return Resource{}, notFound(err)
+	}
+	return ProjectResource(project), nil
+}
+
+// ProjectResource describes project.
+func ProjectResource(project *db.ProjectModel) Resource {
+	return Resource{Kind: KindProject, ID: project.ID, ProjectID: project.ID, AuthorID: project.UserID}
+}
+
+// Task returns the live task with id. Unlike the other lookups it returns
+// the task itself, which callers usually go on to use.
+func Task(ctx context.Context, client *db.PrismaClient, id string) (*db.TaskModel, error) {
+	task, err := client.Task.FindFirst(
+		db.Task.ID.Equals(id),
+		db.Task.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return task, nil
+}
+
+// TaskResource describes task.
+func TaskResource(task *db.TaskModel) Resource {
+	return Resource{Kind: KindTask, ID: task.ID, ProjectID: task.ProjectID, AuthorID: task.UserID}
+}
+
+// Sprint returns the live sprint with id.
+func Sprint(ctx context.Context, client *db.PrismaClient, id string) (Resource, error) {
+	sprint, err := client.Sprint.FindFirst(
+		db.Sprint.ID.Equals(id),
+		db.Sprint.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return Resource{}, notFound(err)
+	}
+	return Resource{Kind: KindSprint, ID: sprint.ID, AuthorID: sprint.UserID}, nil
+}
+
+// Comment returns the comment with id on a live task.
+func Comment(ctx context.Context, client *db.PrismaClient, id string) (Resource, error) {
+	comment, err := client.TaskComment.FindFirst(
+		db.TaskComment.ID.Equals(id),
+		db.TaskComment.Task.Where(db.Task.DeletedAt.IsNull()),
+	).With(
+		db.TaskComment.Task.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return Resource{}, notFound(err)
+	}
+	return Resource{Kind: KindComment, ID: comment.ID, ProjectID: comment.Task().ProjectID, AuthorID: comment.UserID}, nil
+}
+
+// Attachment returns the attachment with id on a live task.
+func Attachment(ctx context.Context, client *db.PrismaClient, id string) (Resource, error) {
+	attachment, err := client.TaskAttachment.FindFirst(
+		db.TaskAttachment.ID.Equals(id),
+		db.TaskAttachment.Task.Where(db.Task.DeletedAt.IsNull()),
+	).With(
+		db.TaskAttachment.Task.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return Resource{}, notFound(err)
+	}
+	task, _ := attachment.Task()
+	return Resource{Kind: KindAttachment, ID: attachment.ID, ProjectID: task.ProjectID, AuthorID: attachment.UserID}, nil
+}
+
+// In returns a resource of kind to be created in project projectID.
+func In(kind Kind, projectID string) Resource {
+	return Resource{Kind: kind, ProjectID: projectID}
+}
+
+func notFound(err error) error {
+	if db.IsErrNotFound(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// MemberOf matches the projects userID owns or joined as a collaborator,
+// which are the projects they have a role on.
+func MemberOf(userID string) db.ProjectWhereParam {
+	return db.Project.Or(
+		db.Project.UserID.Equals(userID),
+		db.Project.Collaborators.Some(
+			db.ProjectCollaborator.UserID.Equals(userID),
+			db.ProjectCollaborator.Not(db.ProjectCollaborator.JoinedAt.IsNull()),
+		),
+	)
+}
@@ -0,0 +1,142 @@
+// Package blob stores uploaded files behind a small interface with a
+// local-filesystem and an S3-compatible implementation, selected by the
+// BLOB_STORE environment variable.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store keeps blobs under slash-separated keys.
+type Store interface {
+	// Put stores size bytes read from body under key.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Open returns the content stored under key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes key; deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a link that downloads key as filename until
+	// expires, without further authentication.
+	SignedURL(key, filename string, expires time.Time) (string, error)
+}
+
+var (
+	defaultStore Store
+	defaultErr   error
+	defaultOnce  sync.Once
+)
+
+// Default returns the store configured by the environment, created on first
+// use.
+func Default() (Store, error) {
+	defaultOnce.Do(func() {
+		defaultStore, defaultErr = FromEnv()
+	})
+	return defaultStore, defaultErr
+}
+
+// FromEnv creates the store named by BLOB_STORE: "local" (the default)
+// keeps blobs under BLOB_DIR and links to them from BLOB_BASE_URL, signed
+// with BLOB_SIGNING_KEY, "s3" in S3_BUCKET on S3_ENDPOINT.
+func FromEnv() (Store, error) {
+	switch kind := os.Getenv("BLOB_STORE"); kind {
+	case "", "local":
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "lifequest-blobs")
+		}
+		key, err := signingKey()
+		if err != nil {
+			return nil, err
+		}
+		store := NewLocalStore(dir, key)
+		store.BaseURL = os.Getenv("BLOB_BASE_URL")
+		return store, nil
+	case "s3":
+		return s3FromEnv()
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q", kind)
+	}
+}
+
+func s3FromEnv() (Store, error) {
+	region := os.Getenv("S3_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	rawEndpoint := os.Getenv("S3_ENDPOINT")
+	if rawEndpoint == "" {
+		rawEndpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT must be an absolute URL")
+	}
+	pathStyle := false
+	if value := os.Getenv("S3_FORCE_PATH_STYLE"); value != "" {
+		if pathStyle, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("S3_FORCE_PATH_STYLE must be true or false")
+		}
+	}
+
+	store := &S3Store{
+		Endpoint:  endpoint,
+		Region:    region,
+		Bucket:    os.Getenv("S3_BUCKET"),
+		AccessKey: os.Getenv("S3_ACCESS_KEY_ID"),
+		SecretKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		PathStyle: pathStyle,
+	}
+	if store.Bucket == "" || store.AccessKey == "" || store.SecretKey == "" {
+		return nil, fmt.Errorf("S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required")
+	}
+	return store, nil
+}
+
+// signingKey returns BLOB_SIGNING_KEY. Every server that links to or serves
+// local blobs must share it, or links signed by one fail on another.
+func signingKey() ([]byte, error) {
+	value := os.Getenv("BLOB_SIGNING_KEY")
+	if value == "" {
+		return nil, fmt.Errorf("BLOB_SIGNING_KEY is required for the local blob store")
+	}
+	return []byte(value), nil
+}
+
+// checkKey rejects keys that are empty or could step outside the store.
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+// ContentDisposition is the Content-Disposition header that downloads a
+// file as filename.
+func ContentDisposition(filename string) string {
+	if disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename}); disposition != "" {
+		return disposition
+	}
+	return "attachment"
+}
@@ -0,0 +1,260 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3UnsignedBody   = "UNSIGNED-PAYLOAD"
+	s3MaxPresignTime = 7 * 24 * time.Hour
+)
+
+// S3Store keeps blobs in an S3-compatible bucket, signing requests with
+// AWS Signature Version 4. MinIO and other stand-ins usually need
+// PathStyle, which puts the bucket in the path instead of the host name.
+type S3Store struct {
+	Endpoint  *url.URL
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+	// HTTP is the client used for requests; http.DefaultClient when nil.
+	HTTP *http.Client
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, body, size, header)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// SignedURL returns a presigned GET URL that also asks the bucket to send
+// the object as a download named filename. S3 caps the lifetime at seven
+// days.
+func (s *S3Store) SignedURL(key, filename string, expires time.Time) (string, error) {
+	now := time.Now().UTC()
+	lifetime := expires.Sub(now)
+	if lifetime > s3MaxPresignTime {
+		lifetime = s3MaxPresignTime
+	}
+	if lifetime < time.Second {
+		lifetime = time.Second
+	}
+	return s.presign(key, now, lifetime, url.Values{
+		"response-content-disposition": {ContentDisposition(filename)},
+	})
+}
+
+// presign returns a GET URL for key signed at now, valid for lifetime, with
+// the extra query parameters signed along.
+func (s *S3Store) presign(key string, now time.Time, lifetime time.Duration, extra url.Values) (string, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	amzDate, scope := s.scope(now)
+	query := url.Values{}
+	for name, values := range extra {
+		query[name] = values
+	}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(lifetime/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := encodeQuery(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedBody,
+	}, "\n")
+	signature := s.signature(now, amzDate, scope, canonicalRequest)
+
+	return u.Scheme + "://" + u.Host + u.EscapedPath() + "?" + canonicalQuery + "&X-Amz-Signature=" + signature, nil
+}
+
+// do sends a signed request for key and returns the response when it
+// succeeded; any other response is closed and turned into an error.
+func (s *S3Store) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	s.sign(req, time.Now().UTC())
+
+	client := s.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	return nil, fmt.Errorf("s3 %s %s: %s: %s", method, key, resp.Status, strings.TrimSpace(string(detail)))
+}
+
+// sign adds the Authorization header for req. The body is sent unsigned so
+// uploads can be streamed without hashing them first.
+func (s *S3Store) sign(req *http.Request, now time.Time) {
+	amzDate, scope := s.scope(now)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedBody)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + s3UnsignedBody + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		encodeQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		s3UnsignedBody,
+	}, "\n")
+	signature := s.signature(now, amzDate, scope, canonicalRequest)
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.AccessKey, scope, signedHeaders, signature))
+}
+
+// scope returns the request timestamp and credential scope for now.
+func (s *S3Store) scope(now time.Time) (string, string) {
+	return now.Format("20060102T150405Z"), now.Format("20060102") + "/" + s.Region + "/s3/aws4_request"
+}
+
+func (s *S3Store) signature(now time.Time, amzDate, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := s3Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func (s *S3Store) objectURL(key string) (*url.URL, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	u := *s.Endpoint
+	u.RawQuery = ""
+	base := strings.TrimSuffix(u.Path, "/")
+	if s.PathStyle {
+		u.Path = base + "/" + s.Bucket + "/" + key
+	} else {
+		u.Host = s.Bucket + "." + u.Host
+		u.Path = base + "/" + key
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	return &u, nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// encodeQuery is the canonical query string: keys sorted, every reserved
+// character percent-encoded.
+func encodeQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything except the RFC 3986 unreserved
+// characters, and "/" unless encodeSlash is set.
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
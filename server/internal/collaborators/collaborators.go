@@ -0,0 +1,292 @@
+// Package collaborators manages who can work on a project: invitations sent
+// by email with expiring tokens, the roles of the members who accepted them,
+// and transferring the project to another member.
+//
+// A project has exactly one owner, Project.userId. Collaborator rows hold the
+// other roles only, so ownership changes hands by TransferOwnership, never by
+// a role update.
+package collaborators
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/utils"
+)
+
+// Roles of project members. RoleOwner is never stored on a collaborator.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+)
+
+// SharedFolderName is the top-level folder a transferred project is moved
+// into, since the new owner cannot see the folders of the previous one.
+const SharedFolderName = "Shared with me"
+
+var (
+	ErrProjectNotFound = errors.New("project not found")
+	ErrNotFound        = errors.New("collaborator not found")
+	ErrForbidden       = errors.New("only the owner or an admin can manage collaborators")
+	ErrOwnerOnly       = errors.New("only the owner can do this")
+	ErrInvalidRole     = errors.New("role must be admin, member or viewer")
+	ErrOwnerRole       = errors.New("the owner role cannot be granted; transfer ownership instead")
+	ErrNotMember       = errors.New("the new owner must be a member of the project")
+	ErrAlreadyOwner    = errors.New("user already owns the project")
+)
+
+// ValidRole reports whether role can be held by a collaborator.
+func ValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleMember, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// checkRole rejects roles a collaborator cannot hold.
+func checkRole(role string) error {
+	if role == RoleOwner {
+		return ErrOwnerRole
+	}
+	if !ValidRole(role) {
+		return ErrInvalidRole
+	}
+	return nil
+}
+
+// List returns the project's joined collaborators, oldest first, with their
+// users. The owner is not among them.
+func List(ctx context.Context, client *db.PrismaClient, userID, projectID string) ([]db.ProjectCollaboratorModel, error) {
+	if _, _, err := Access(ctx, client, userID, projectID); err != nil {
+		return nil, err
+	}
+	return client.ProjectCollaborator.FindMany(
+		db.ProjectCollaborator.ProjectID.Equals(projectID),
+		db.ProjectCollaborator.Not(db.ProjectCollaborator.JoinedAt.IsNull()),
+	).With(
+		db.ProjectCollaborator.User.Fetch(),
+	).OrderBy(
+		db.ProjectCollaborator.JoinedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+}
+
+// Access returns the live project and userID's role on it, if userID owns
+// it or has joined it.
+func Access(ctx context.Context, client *db.PrismaClient, userID, projectID string) (*db.ProjectModel, authz.Role, error) {
+	project, err := client.Project.FindFirst(
+		db.Project.ID.Equals(projectID),
+		db.Project.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, authz.RoleNone, ErrProjectNotFound
+		}
+		return nil, authz.RoleNone, err
+	}
+	role, err := authz.ProjectRole(ctx, client, userID, project)
+	if err != nil {
+		return nil, authz.RoleNone, err
+	}
+	if role == authz.RoleNone {
+		return nil, authz.RoleNone, ErrProjectNotFound
+	}
+	return project, role, nil
+}
+
+// manage returns the project when userID may manage its collaborators.
+func manage(ctx context.Context, client *db.PrismaClient, userID, projectID string) (*db.ProjectModel, authz.Role, error) {
+	return authorize(ctx, client, userID, projectID, authz.ActionManage, ErrForbidden)
+}
+
+// authorize returns the project when userID may take action on it, or
+// denied when their role falls short.
+func authorize(ctx context.Context, client *db.PrismaClient, userID, projectID string, action authz.Action, denied error) (*db.ProjectModel, authz.Role, error) {
+	project, role, err := Access(ctx, client, userID, projectID)
+	if err != nil {
+		return nil, authz.RoleNone, err
+	}
+	if !authz.Can(authz.Viewer{UserID: userID, Role: role}, action, authz.ProjectResource(project)) {
+		return nil, authz.RoleNone, denied
+	}
+	return project, role, nil
+}
+
+// get returns the collaborator with id on a live project.
+func get(ctx context.Context, client *db.PrismaClient, id string) (*db.ProjectCollaboratorModel, error) {
+	collaborator, err := client.ProjectCollaborator.FindFirst(
+		db.ProjectCollaborator.ID.Equals(id),
+		db.ProjectCollaborator.Project.Where(db.Project.DeletedAt.IsNull()),
+	).With(
+		db.ProjectCollaborator.User.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return collaborator, nil
+}
+
+// UpdateRole changes a collaborator's role. Admins manage members and
+// viewers; only the owner grants or takes away the admin role.
+func UpdateRole(ctx context.Context, client *db.PrismaClient, userID, id, role string) (*db.ProjectCollaboratorModel, error) {
+	if err := checkRole(role); err != nil {
+		return nil, err
+	}
+	collaborator, err := get(ctx, client, id)
+	if err != nil {
+		return nil, err
+	}
+	_, actorRole, err := manage(ctx, client, userID, collaborator.ProjectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if actorRole != authz.RoleOwner && (role == RoleAdmin || collaborator.Role == RoleAdmin) {
+		return nil, ErrOwnerOnly
+	}
+	if collaborator.Role == role {
+		return collaborator, nil
+	}
+
+	_, err = client.ProjectCollaborator.FindUnique(
+		db.ProjectCollaborator.ID.Equals(id),
+	).Update(
+		db.ProjectCollaborator.Role.Set(role),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return get(ctx, client, id)
+}
+
+// Remove takes a collaborator off the project. Members may remove
+// themselves; otherwise the rules of UpdateRole apply.
+func Remove(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	collaborator, err := get(ctx, client, id)
+	if err != nil {
+		return err
+	}
+	if collaborator.UserID != userID {
+		_, actorRole, err := manage(ctx, client, userID, collaborator.ProjectID)
+		if err != nil {
+			if errors.Is(err, ErrProjectNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if actorRole != authz.RoleOwner && collaborator.Role == RoleAdmin {
+			return ErrOwnerOnly
+		}
+	}
+
+	_, err = client.ProjectCollaborator.FindUnique(
+		db.ProjectCollaborator.ID.Equals(id),
+	).Delete().Exec(ctx)
+	return err
+}
+
+// TransferOwnership hands the owner's project to newOwnerID, who must have
+// joined it. The new owner's collaborator row goes away, the previous owner
+// stays on as an admin, and the project moves into the new owner's
+// SharedFolderName folder, which is created when missing. All of it happens
+// in one transaction, so the project never has zero or two owners.
+func TransferOwnership(ctx context.Context, client *db.PrismaClient, userID, projectID, newOwnerID string) (*db.ProjectModel, error) {
+	project, _, err := authorize(ctx, client, userID, projectID, authz.ActionTransfer, ErrOwnerOnly)
+	if err != nil {
+		return nil, err
+	}
+	if newOwnerID == userID {
+		return nil, ErrAlreadyOwner
+	}
+
+	collaborator, err := client.ProjectCollaborator.FindFirst(
+		db.ProjectCollaborator.ProjectID.Equals(projectID),
+		db.ProjectCollaborator.UserID.Equals(newOwnerID),
+		db.ProjectCollaborator.Not(db.ProjectCollaborator.JoinedAt.IsNull()),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrNotMember
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	var txs []db.PrismaTransaction
+	folder, err := client.Folder.FindFirst(
+		db.Folder.UserID.Equals(newOwnerID),
+		db.Folder.ParentID.IsNull(),
+		db.Folder.Name.Equals(SharedFolderName),
+		db.Folder.DeletedAt.IsNull(),
+	).Exec(ctx)
+	var folderID string
+	switch {
+	case err == nil:
+		folderID = folder.ID
+		txs = append(txs, client.Folder.FindUnique(
+			db.Folder.ID.Equals(folderID),
+		).Update(
+			db.Folder.ProjectCount.Increment(1),
+			db.Folder.UpdatedAt.Set(now),
+		).Tx())
+	case db.IsErrNotFound(err):
+		folderID = utils.GenerateUUID()
+		txs = append(txs, client.Folder.CreateOne(
+			db.Folder.Name.Set(SharedFolderName),
+			db.Folder.User.Link(db.User.ID.Equals(newOwnerID)),
+			db.Folder.ID.Set(folderID),
+			db.Folder.ProjectCount.Set(1),
+		).Tx())
+	default:
+		return nil, err
+	}
+
+	transfer := client.Project.FindUnique(
+		db.Project.ID.Equals(projectID),
+	).Update(
+		db.Project.User.Link(db.User.ID.Equals(newOwnerID)),
+		db.Project.Folder.Link(db.Folder.ID.Equals(folderID)),
+		db.Project.UpdatedAt.Set(now),
+	).Tx()
+	txs = append(txs,
+		transfer,
+		client.Folder.FindUnique(
+			db.Folder.ID.Equals(project.FolderID),
+		).Update(
+			db.Folder.ProjectCount.Decrement(1),
+			db.Folder.UpdatedAt.Set(now),
+		).Tx(),
+		client.ProjectCollaborator.FindUnique(
+			db.ProjectCollaborator.ID.Equals(collaborator.ID),
+		).Delete().Tx(),
+		client.ProjectCollaborator.UpsertOne(
+			db.ProjectCollaborator.ProjectIDUserID(
+				db.ProjectCollaborator.ProjectID.Equals(projectID),
+				db.ProjectCollaborator.UserID.Equals(userID),
+			),
+		).Create(
+			db.ProjectCollaborator.Project.Link(db.Project.ID.Equals(projectID)),
+			db.ProjectCollaborator.User.Link(db.User.ID.Equals(userID)),
+			db.ProjectCollaborator.ID.Set(utils.GenerateUUID()),
+			db.ProjectCollaborator.Role.Set(RoleAdmin),
+			db.ProjectCollaborator.JoinedAt.Set(now),
+		).Update(
+			db.ProjectCollaborator.Role.Set(RoleAdmin),
+			db.ProjectCollaborator.JoinedAt.Set(now),
+		).Tx(),
+	)
+	if err := client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return nil, err
+	}
+	return transfer.Result(), nil
+}
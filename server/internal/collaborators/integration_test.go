@@ -0,0 +1,333 @@
+package collaborators
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/utils"
+)
+
+// These tests run the invitation and transfer flows against a migrated
+// database named by TEST_DATABASE_URL, and are skipped without one. Every
+// test creates its own users and removes them, and with them everything
+// else it created, when it ends.
+
+func testClient(t *testing.T) *db.PrismaClient {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	client := db.NewClient(db.WithDatasourceURL(url))
+	if err := client.Prisma.Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Prisma.Disconnect()
+	})
+	return client
+}
+
+func createUser(t *testing.T, client *db.PrismaClient, name string) *db.UserModel {
+	t.Helper()
+	user, err := client.User.CreateOne(
+		db.User.Email.Set(name+"-"+utils.GenerateUUID()+"@example.com"),
+		db.User.ID.Set(utils.GenerateUUID()),
+	).Exec(context.Background())
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	t.Cleanup(func() { deleteUser(client, user.ID) })
+	return user
+}
+
+func deleteUser(client *db.PrismaClient, id string) {
+	client.User.FindMany(db.User.ID.Equals(id)).Delete().Exec(context.Background())
+}
+
+func createProject(t *testing.T, client *db.PrismaClient, owner *db.UserModel) *db.ProjectModel {
+	t.Helper()
+	ctx := context.Background()
+	folder, err := client.Folder.CreateOne(
+		db.Folder.Name.Set("Work"),
+		db.Folder.User.Link(db.User.ID.Equals(owner.ID)),
+		db.Folder.ID.Set(utils.GenerateUUID()),
+		db.Folder.ProjectCount.Set(1),
+	).Exec(ctx)
+	if err != nil {
+		t.Fatalf("create folder: %v", err)
+	}
+	project, err := client.Project.CreateOne(
+		db.Project.Name.Set("Launch"),
+		db.Project.User.Link(db.User.ID.Equals(owner.ID)),
+		db.Project.Folder.Link(db.Folder.ID.Equals(folder.ID)),
+		db.Project.ID.Set(utils.GenerateUUID()),
+	).Exec(ctx)
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	return project
+}
+
+// join invites user to project as role and accepts with the emailed token.
+func join(t *testing.T, client *db.PrismaClient, owner *db.UserModel, project *db.ProjectModel, user *db.UserModel, role string) {
+	t.Helper()
+	ctx := context.Background()
+	invitation, err := Invite(ctx, client, owner.ID, project.ID, user.Email, role)
+	if err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if _, err := Accept(ctx, client, user.ID, invitation.Token); err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+}
+
+func TestAcceptAfterExpiry(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+	owner := createUser(t, client, "owner")
+	invitee := createUser(t, client, "invitee")
+	project := createProject(t, client, owner)
+
+	invitation, err := Invite(ctx, client, owner.ID, project.ID, invitee.Email, RoleMember)
+	if err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	_, err = client.ProjectInvitation.FindUnique(
+		db.ProjectInvitation.ID.Equals(invitation.ID),
+	).Update(
+		db.ProjectInvitation.ExpiresAt.Set(time.Now().Add(-time.Minute)),
+	).Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Accept(ctx, client, invitee.ID, invitation.Token); !errors.Is(err, ErrInvitationExpired) {
+		t.Fatalf("Accept after expiry = %v, want ErrInvitationExpired", err)
+	}
+	if role, err := authz.RoleOn(ctx, client, invitee.ID, project.ID); err != nil || role != authz.RoleNone {
+		t.Errorf("invitee role after expired accept = %v, %v; want none", role, err)
+	}
+}
+
+func TestAcceptByDifferentAccount(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+	owner := createUser(t, client, "owner")
+	invitee := createUser(t, client, "invitee")
+	stranger := createUser(t, client, "stranger")
+	project := createProject(t, client, owner)
+
+	invitation, err := Invite(ctx, client, owner.ID, project.ID, invitee.Email, RoleMember)
+	if err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if inviteeID, ok := invitation.InviteeID(); !ok || inviteeID != invitee.ID {
+		t.Fatalf("invitation to an existing account is not linked to it")
+	}
+
+	if _, err := Accept(ctx, client, stranger.ID, invitation.Token); !errors.Is(err, ErrInvitationNotFound) {
+		t.Fatalf("Accept by another account = %v, want ErrInvitationNotFound", err)
+	}
+	if role, _ := authz.RoleOn(ctx, client, stranger.ID, project.ID); role != authz.RoleNone {
+		t.Errorf("stranger role = %v, want none", role)
+	}
+
+	// Holding an account with the invited email is not enough.
+	if _, err := Accept(ctx, client, invitee.ID, ""); !errors.Is(err, ErrTokenRequired) {
+		t.Fatalf("Accept without the token = %v, want ErrTokenRequired", err)
+	}
+	collaborator, err := Accept(ctx, client, invitee.ID, invitation.Token)
+	if err != nil {
+		t.Fatalf("Accept by the invitee: %v", err)
+	}
+	if collaborator.UserID != invitee.ID || collaborator.Role != RoleMember {
+		t.Errorf("collaborator = %s as %s, want %s as member", collaborator.UserID, collaborator.Role, invitee.ID)
+	}
+	if _, err := Accept(ctx, client, invitee.ID, invitation.Token); !errors.Is(err, ErrInvitationClosed) {
+		t.Errorf("second Accept = %v, want ErrInvitationClosed", err)
+	}
+}
+
+func TestReinvitePending(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+	owner := createUser(t, client, "owner")
+	invitee := createUser(t, client, "invitee")
+	project := createProject(t, client, owner)
+
+	first, err := Invite(ctx, client, owner.ID, project.ID, invitee.Email, RoleViewer)
+	if err != nil {
+		t.Fatalf("first Invite: %v", err)
+	}
+	second, err := Invite(ctx, client, owner.ID, project.ID, strings.ToUpper(invitee.Email), RoleMember)
+	if err != nil {
+		t.Fatalf("second Invite: %v", err)
+	}
+
+	if second.ID != first.ID {
+		t.Errorf("re-invite created invitation %s, want %s refreshed", second.ID, first.ID)
+	}
+	if second.Role != RoleMember {
+		t.Errorf("re-invite role = %s, want member", second.Role)
+	}
+	if second.Token == first.Token {
+		t.Error("re-invite kept the old token")
+	}
+	if !second.ExpiresAt.After(first.ExpiresAt) {
+		t.Errorf("re-invite expiry %v is not after %v", second.ExpiresAt, first.ExpiresAt)
+	}
+	pending, err := client.ProjectInvitation.FindMany(
+		db.ProjectInvitation.ProjectID.Equals(project.ID),
+		db.ProjectInvitation.Status.Equals(StatusPending),
+	).Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Errorf("pending invitations = %d, want 1", len(pending))
+	}
+
+	if _, err := Accept(ctx, client, invitee.ID, first.Token); !errors.Is(err, ErrInvitationNotFound) {
+		t.Fatalf("Accept with the replaced token = %v, want ErrInvitationNotFound", err)
+	}
+	collaborator, err := Accept(ctx, client, invitee.ID, second.Token)
+	if err != nil {
+		t.Fatalf("Accept with the new token: %v", err)
+	}
+	if collaborator.Role != RoleMember {
+		t.Errorf("joined as %s, want member", collaborator.Role)
+	}
+}
+
+// Signing up with an invited email doesn't reach the invitation, as the
+// email isn't verified; the emailed token still works.
+func TestInviteBeforeSignup(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+	owner := createUser(t, client, "owner")
+	project := createProject(t, client, owner)
+	email := "newcomer-" + utils.GenerateUUID() + "@example.com"
+
+	invitation, err := Invite(ctx, client, owner.ID, project.ID, email, RoleMember)
+	if err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if _, ok := invitation.InviteeID(); ok {
+		t.Fatal("invitation to an unknown email is linked to an account")
+	}
+
+	user, err := client.User.CreateOne(
+		db.User.Email.Set(email),
+		db.User.ID.Set(utils.GenerateUUID()),
+	).Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { deleteUser(client, user.ID) })
+
+	mine, err := ListForUser(ctx, client, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mine) != 0 {
+		t.Errorf("ListForUser = %d invitations, want none before accepting", len(mine))
+	}
+	if _, err := Accept(ctx, client, user.ID, invitation.Token); err != nil {
+		t.Fatalf("Accept with the token: %v", err)
+	}
+	accepted, err := getInvitation(ctx, client, invitation.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inviteeID, ok := accepted.InviteeID(); !ok || inviteeID != user.ID {
+		t.Errorf("accepted invitation invitee = %v, want %s", accepted.InnerProjectInvitation.InviteeID, user.ID)
+	}
+}
+
+func TestTransferOwnership(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+	owner := createUser(t, client, "owner")
+	admin := createUser(t, client, "admin")
+	viewer := createUser(t, client, "viewer")
+	project := createProject(t, client, owner)
+	join(t, client, owner, project, admin, RoleAdmin)
+	join(t, client, owner, project, viewer, RoleViewer)
+
+	if _, err := TransferOwnership(ctx, client, admin.ID, project.ID, viewer.ID); !errors.Is(err, ErrOwnerOnly) {
+		t.Fatalf("transfer by an admin = %v, want ErrOwnerOnly", err)
+	}
+	stranger := createUser(t, client, "stranger")
+	if _, err := TransferOwnership(ctx, client, owner.ID, project.ID, stranger.ID); !errors.Is(err, ErrNotMember) {
+		t.Fatalf("transfer to a non-member = %v, want ErrNotMember", err)
+	}
+	if _, err := TransferOwnership(ctx, client, owner.ID, project.ID, owner.ID); !errors.Is(err, ErrAlreadyOwner) {
+		t.Fatalf("transfer to the owner = %v, want ErrAlreadyOwner", err)
+	}
+
+	transferred, err := TransferOwnership(ctx, client, owner.ID, project.ID, viewer.ID)
+	if err != nil {
+		t.Fatalf("TransferOwnership: %v", err)
+	}
+	if transferred.UserID != viewer.ID {
+		t.Fatalf("project owner = %s, want %s", transferred.UserID, viewer.ID)
+	}
+
+	// Exactly one owner: Project.userId, with no collaborator row of its own.
+	want := map[string]authz.Role{
+		viewer.ID: authz.RoleOwner,
+		owner.ID:  authz.RoleAdmin,
+		admin.ID:  authz.RoleAdmin,
+	}
+	owners := 0
+	for userID, wantRole := range want {
+		role, err := authz.RoleOn(ctx, client, userID, project.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if role != wantRole {
+			t.Errorf("role of %s = %v, want %v", userID, role, wantRole)
+		}
+		if role == authz.RoleOwner {
+			owners++
+		}
+	}
+	if owners != 1 {
+		t.Errorf("owners = %d, want 1", owners)
+	}
+	rows, err := client.ProjectCollaborator.FindMany(
+		db.ProjectCollaborator.ProjectID.Equals(project.ID),
+	).Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if row.UserID == viewer.ID || row.Role == RoleOwner {
+			t.Errorf("collaborator row %s as %s after transfer", row.UserID, row.Role)
+		}
+	}
+
+	// The project moved into the new owner's shared folder.
+	folder, err := client.Folder.FindUnique(
+		db.Folder.ID.Equals(transferred.FolderID),
+	).Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folder.UserID != viewer.ID || folder.Name != SharedFolderName || folder.ProjectCount != 1 {
+		t.Errorf("folder = %s %q with %d projects, want the new owner's %q with 1",
+			folder.UserID, folder.Name, folder.ProjectCount, SharedFolderName)
+	}
+
+	// The previous owner can no longer transfer it back.
+	if _, err := TransferOwnership(ctx, client, owner.ID, project.ID, owner.ID); !errors.Is(err, ErrOwnerOnly) {
+		t.Errorf("transfer by the previous owner = %v, want ErrOwnerOnly", err)
+	}
+}
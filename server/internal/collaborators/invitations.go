@@ -0,0 +1,408 @@
+package collaborators
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/comments"
+	"lifequest-server/internal/db"
+	mailer "lifequest-server/internal/mail"
+	"lifequest-server/internal/notifications"
+	"lifequest-server/internal/utils"
+)
+
+// Invitation statuses stored on ProjectInvitation.status. A pending
+// invitation past its expiry can no longer be accepted.
+const (
+	StatusPending  = "pending"
+	StatusAccepted = "accepted"
+	StatusDeclined = "declined"
+	StatusRevoked  = "revoked"
+)
+
+// InvitationTTL is how long an invitation can be accepted for.
+const InvitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationExpired  = errors.New("invitation has expired")
+	ErrInvitationClosed   = errors.New("invitation is no longer pending")
+	ErrInvalidEmail       = errors.New("invalid email address")
+	ErrAlreadyMember      = errors.New("user is already a member of the project")
+	ErrTokenRequired      = errors.New("an invitation token is required")
+)
+
+// Invitation is a stored invitation along with its token, which is only
+// known when the invitation has just been sent.
+type Invitation struct {
+	*db.ProjectInvitationModel
+	Token string
+}
+
+// Expired reports whether a pending invitation can no longer be accepted.
+func Expired(invitation *db.ProjectInvitationModel, now time.Time) bool {
+	return invitation.Status == StatusPending && !now.Before(invitation.ExpiresAt)
+}
+
+// AcceptURL is the web client page that accepts the invitation with token.
+func AcceptURL(token string) string {
+	return mailer.AppURL() + "/invitations/accept?token=" + url.QueryEscape(token)
+}
+
+// Invite invites email to the project with role, emailing a link with a new
+// token and notifying the invitee when the email belongs to an account.
+// Inviting an email with a pending invitation sends that one again with the
+// new role, token and expiry. Only the owner invites admins.
+func Invite(ctx context.Context, client *db.PrismaClient, userID, projectID, email, role string) (*Invitation, error) {
+	if err := checkRole(role); err != nil {
+		return nil, err
+	}
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	project, actorRole, err := manage(ctx, client, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if actorRole != authz.RoleOwner && role == RoleAdmin {
+		return nil, ErrOwnerOnly
+	}
+
+	invitee, err := client.User.FindFirst(
+		db.User.Email.Equals(email),
+		db.User.Email.Mode(db.QueryModeInsensitive),
+	).Exec(ctx)
+	if err != nil && !db.IsErrNotFound(err) {
+		return nil, err
+	}
+	if invitee != nil {
+		if invitee.ID == project.UserID {
+			return nil, ErrAlreadyMember
+		}
+		_, err := client.ProjectCollaborator.FindFirst(
+			db.ProjectCollaborator.ProjectID.Equals(projectID),
+			db.ProjectCollaborator.UserID.Equals(invitee.ID),
+			db.ProjectCollaborator.Not(db.ProjectCollaborator.JoinedAt.IsNull()),
+		).Exec(ctx)
+		if err == nil {
+			return nil, ErrAlreadyMember
+		}
+		if !db.IsErrNotFound(err) {
+			return nil, err
+		}
+	}
+
+	token, hash, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(InvitationTTL)
+	params := []db.ProjectInvitationSetParam{
+		db.ProjectInvitation.Role.Set(role),
+	}
+	if invitee != nil {
+		params = append(params, db.ProjectInvitation.Invitee.Link(db.User.ID.Equals(invitee.ID)))
+	}
+
+	var invitation *db.ProjectInvitationModel
+	pending, err := client.ProjectInvitation.FindFirst(
+		db.ProjectInvitation.ProjectID.Equals(projectID),
+		db.ProjectInvitation.Email.Equals(email),
+		db.ProjectInvitation.Status.Equals(StatusPending),
+	).Exec(ctx)
+	switch {
+	case err == nil:
+		invitation, err = client.ProjectInvitation.FindUnique(
+			db.ProjectInvitation.ID.Equals(pending.ID),
+		).Update(append(params,
+			db.ProjectInvitation.TokenHash.Set(hash),
+			db.ProjectInvitation.ExpiresAt.Set(expiresAt),
+			db.ProjectInvitation.InvitedBy.Link(db.User.ID.Equals(userID)),
+		)...).Exec(ctx)
+	case db.IsErrNotFound(err):
+		invitation, err = client.ProjectInvitation.CreateOne(
+			db.ProjectInvitation.Email.Set(email),
+			db.ProjectInvitation.TokenHash.Set(hash),
+			db.ProjectInvitation.ExpiresAt.Set(expiresAt),
+			db.ProjectInvitation.Project.Link(db.Project.ID.Equals(projectID)),
+			db.ProjectInvitation.InvitedBy.Link(db.User.ID.Equals(userID)),
+			append(params, db.ProjectInvitation.ID.Set(utils.GenerateUUID()))...,
+		).Exec(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	inviter, err := client.User.FindUnique(
+		db.User.ID.Equals(userID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	send(project, inviter, invitation, token)
+	if invitee != nil {
+		notify(ctx, client, invitee.ID, project, inviter, invitation)
+	}
+
+	invitation, err = getInvitation(ctx, client, invitation.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Invitation{ProjectInvitationModel: invitation, Token: token}, nil
+}
+
+// Accept makes userID a member of the invitation's project with its role.
+// The invitation is found by its emailed token: emails are not verified, so
+// holding an account with the invited address is not enough.
+func Accept(ctx context.Context, client *db.PrismaClient, userID, token string) (*db.ProjectCollaboratorModel, error) {
+	invitation, err := respondable(ctx, client, userID, token)
+	if err != nil {
+		return nil, err
+	}
+	project, err := client.Project.FindFirst(
+		db.Project.ID.Equals(invitation.ProjectID),
+		db.Project.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	if project.UserID == userID {
+		return nil, ErrAlreadyMember
+	}
+
+	now := time.Now()
+	join := client.ProjectCollaborator.UpsertOne(
+		db.ProjectCollaborator.ProjectIDUserID(
+			db.ProjectCollaborator.ProjectID.Equals(invitation.ProjectID),
+			db.ProjectCollaborator.UserID.Equals(userID),
+		),
+	).Create(
+		db.ProjectCollaborator.Project.Link(db.Project.ID.Equals(invitation.ProjectID)),
+		db.ProjectCollaborator.User.Link(db.User.ID.Equals(userID)),
+		db.ProjectCollaborator.ID.Set(utils.GenerateUUID()),
+		db.ProjectCollaborator.Role.Set(invitation.Role),
+		db.ProjectCollaborator.InvitedAt.Set(invitation.CreatedAt),
+		db.ProjectCollaborator.JoinedAt.Set(now),
+	).Update(
+		db.ProjectCollaborator.Role.Set(invitation.Role),
+		db.ProjectCollaborator.JoinedAt.Set(now),
+	).Tx()
+	err = client.Prisma.Transaction(
+		join,
+		client.ProjectInvitation.FindUnique(
+			db.ProjectInvitation.ID.Equals(invitation.ID),
+		).Update(
+			db.ProjectInvitation.Status.Set(StatusAccepted),
+			db.ProjectInvitation.RespondedAt.Set(now),
+			db.ProjectInvitation.Invitee.Link(db.User.ID.Equals(userID)),
+		).Tx(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return get(ctx, client, join.Result().ID)
+}
+
+// Decline turns the invitation down, found as in Accept.
+func Decline(ctx context.Context, client *db.PrismaClient, userID, token string) (*db.ProjectInvitationModel, error) {
+	invitation, err := respondable(ctx, client, userID, token)
+	if err != nil {
+		return nil, err
+	}
+	_, err = client.ProjectInvitation.FindUnique(
+		db.ProjectInvitation.ID.Equals(invitation.ID),
+	).Update(
+		db.ProjectInvitation.Status.Set(StatusDeclined),
+		db.ProjectInvitation.RespondedAt.Set(time.Now()),
+		db.ProjectInvitation.Invitee.Link(db.User.ID.Equals(userID)),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return getInvitation(ctx, client, invitation.ID)
+}
+
+// Revoke withdraws a pending invitation so its token stops working.
+func Revoke(ctx context.Context, client *db.PrismaClient, userID, id string) (*db.ProjectInvitationModel, error) {
+	invitation, err := getInvitation(ctx, client, id)
+	if err != nil {
+		return nil, err
+	}
+	_, actorRole, err := manage(ctx, client, userID, invitation.ProjectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	if actorRole != authz.RoleOwner && invitation.Role == RoleAdmin {
+		return nil, ErrOwnerOnly
+	}
+	if invitation.Status != StatusPending {
+		return nil, ErrInvitationClosed
+	}
+	_, err = client.ProjectInvitation.FindUnique(
+		db.ProjectInvitation.ID.Equals(id),
+	).Update(
+		db.ProjectInvitation.Status.Set(StatusRevoked),
+		db.ProjectInvitation.RespondedAt.Set(time.Now()),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return getInvitation(ctx, client, id)
+}
+
+// ListForProject returns the project's invitations, newest first, to the
+// members who can manage them.
+func ListForProject(ctx context.Context, client *db.PrismaClient, userID, projectID string) ([]db.ProjectInvitationModel, error) {
+	if _, _, err := manage(ctx, client, userID, projectID); err != nil {
+		return nil, err
+	}
+	return client.ProjectInvitation.FindMany(
+		db.ProjectInvitation.ProjectID.Equals(projectID),
+	).With(
+		db.ProjectInvitation.Project.Fetch(),
+	).OrderBy(
+		db.ProjectInvitation.CreatedAt.Order(db.SortOrderDesc),
+	).Exec(ctx)
+}
+
+// ListForUser returns the pending, unexpired invitations sent to userID's
+// account, newest first. Invitations sent to their email before they signed
+// up are only reached through the emailed link.
+func ListForUser(ctx context.Context, client *db.PrismaClient, userID string) ([]db.ProjectInvitationModel, error) {
+	return client.ProjectInvitation.FindMany(
+		db.ProjectInvitation.Status.Equals(StatusPending),
+		db.ProjectInvitation.ExpiresAt.After(time.Now()),
+		db.ProjectInvitation.Project.Where(db.Project.DeletedAt.IsNull()),
+		db.ProjectInvitation.InviteeID.Equals(userID),
+	).With(
+		db.ProjectInvitation.Project.Fetch(),
+	).OrderBy(
+		db.ProjectInvitation.CreatedAt.Order(db.SortOrderDesc),
+	).Exec(ctx)
+}
+
+// getInvitation returns the invitation with id and its project.
+func getInvitation(ctx context.Context, client *db.PrismaClient, id string) (*db.ProjectInvitationModel, error) {
+	invitation, err := client.ProjectInvitation.FindUnique(
+		db.ProjectInvitation.ID.Equals(id),
+	).With(
+		db.ProjectInvitation.Project.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// respondable returns the pending, unexpired invitation with token that
+// userID may answer.
+func respondable(ctx context.Context, client *db.PrismaClient, userID, token string) (*db.ProjectInvitationModel, error) {
+	if token == "" {
+		return nil, ErrTokenRequired
+	}
+	invitation, err := client.ProjectInvitation.FindUnique(
+		db.ProjectInvitation.TokenHash.Equals(hashToken(token)),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	if err := checkRespondable(invitation, userID, time.Now()); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// checkRespondable rejects answers to an invitation linked to another
+// account, closed or expired at now. An invitation to an email without an
+// account yet is answered by whoever holds its token.
+func checkRespondable(invitation *db.ProjectInvitationModel, userID string, now time.Time) error {
+	if inviteeID, ok := invitation.InviteeID(); ok && inviteeID != userID {
+		return ErrInvitationNotFound
+	}
+	if invitation.Status != StatusPending {
+		return ErrInvitationClosed
+	}
+	if Expired(invitation, now) {
+		return ErrInvitationExpired
+	}
+	return nil
+}
+
+func normalizeEmail(email string) (string, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || address.Name != "" {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(address.Address), nil
+}
+
+// newToken returns a random URL-safe token and the hash stored for it.
+func newToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// send emails the invitation link. Failures are logged: the invitation
+// stands and can be sent again.
+func send(project *db.ProjectModel, inviter *db.UserModel, invitation *db.ProjectInvitationModel, token string) {
+	msg := mailer.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("%s invited you to %s on LifeQuest", comments.DisplayName(inviter), project.Name),
+		Body: fmt.Sprintf(
+			"%s invited you to join the project %q as %s.\n\nAccept the invitation:\n%s\n\nThe link expires on %s.\n",
+			comments.DisplayName(inviter), project.Name, invitation.Role,
+			AcceptURL(token), invitation.ExpiresAt.UTC().Format("January 2, 2006 15:04 MST"),
+		),
+	}
+	if err := mailer.Default().Send(msg); err != nil {
+		log.Printf("collaborators: failed to email invitation %s: %v", invitation.ID, err)
+	}
+}
+
+func notify(ctx context.Context, client *db.PrismaClient, userID string, project *db.ProjectModel, inviter *db.UserModel, invitation *db.ProjectInvitationModel) {
+	_, err := notifications.Create(ctx, client, userID, notifications.TypeCollaborationInvite,
+		"Project invitation",
+		fmt.Sprintf("%s invited you to %q", comments.DisplayName(inviter), project.Name),
+		map[string]string{
+			"projectId":    project.ID,
+			"invitationId": invitation.ID,
+			"role":         invitation.Role,
+		},
+	)
+	if err != nil {
+		log.Printf("collaborators: failed to notify %s of invitation %s: %v", userID, invitation.ID, err)
+	}
+}
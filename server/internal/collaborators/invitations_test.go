@@ -0,0 +1,139 @@
+package collaborators
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+func invitation(status string, inviteeID *string, expiresAt time.Time) *db.ProjectInvitationModel {
+	return &db.ProjectInvitationModel{InnerProjectInvitation: db.InnerProjectInvitation{
+		ID:        "inv",
+		ProjectID: "project",
+		Email:     "invitee@example.com",
+		Role:      RoleMember,
+		Status:    status,
+		InviteeID: inviteeID,
+		ExpiresAt: expiresAt,
+	}}
+}
+
+func TestCheckRespondable(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	invitee, other := "invitee", "other"
+
+	tests := []struct {
+		name       string
+		invitation *db.ProjectInvitationModel
+		userID     string
+		want       error
+	}{
+		{"invitee answers", invitation(StatusPending, &invitee, later), invitee, nil},
+		{"token to an email without an account", invitation(StatusPending, nil, later), other, nil},
+		{"token accepted by a different account", invitation(StatusPending, &invitee, later), other, ErrInvitationNotFound},
+		{"accept after expiry", invitation(StatusPending, &invitee, now.Add(-time.Second)), invitee, ErrInvitationExpired},
+		{"accept at the expiry instant", invitation(StatusPending, &invitee, now), invitee, ErrInvitationExpired},
+		{"unlinked and expired", invitation(StatusPending, nil, now.Add(-time.Hour)), other, ErrInvitationExpired},
+		{"already accepted", invitation(StatusAccepted, &invitee, later), invitee, ErrInvitationClosed},
+		{"declined", invitation(StatusDeclined, &invitee, later), invitee, ErrInvitationClosed},
+		{"revoked", invitation(StatusRevoked, nil, later), invitee, ErrInvitationClosed},
+		// Closed invitations report being closed, not expired, once their
+		// expiry passes.
+		{"revoked and expired", invitation(StatusRevoked, nil, now.Add(-time.Hour)), invitee, ErrInvitationClosed},
+		// Another account learns nothing about an invitation, whatever its state.
+		{"other account, revoked", invitation(StatusRevoked, &invitee, later), other, ErrInvitationNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkRespondable(tt.invitation, tt.userID, now); !errors.Is(err, tt.want) {
+				t.Errorf("checkRespondable = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// Answering needs the emailed token, checked before any lookup.
+func TestRespondWithoutToken(t *testing.T) {
+	if _, err := Accept(context.Background(), nil, "invitee", ""); !errors.Is(err, ErrTokenRequired) {
+		t.Errorf("Accept without a token = %v, want ErrTokenRequired", err)
+	}
+	if _, err := Decline(context.Background(), nil, "invitee", ""); !errors.Is(err, ErrTokenRequired) {
+		t.Errorf("Decline without a token = %v, want ErrTokenRequired", err)
+	}
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if Expired(invitation(StatusPending, nil, now.Add(time.Nanosecond)), now) {
+		t.Error("pending invitation expiring after now is expired")
+	}
+	if !Expired(invitation(StatusPending, nil, now), now) {
+		t.Error("pending invitation expiring now is not expired")
+	}
+	if Expired(invitation(StatusAccepted, nil, now.Add(-time.Hour)), now) {
+		t.Error("accepted invitation past its expiry is expired")
+	}
+}
+
+func TestNewToken(t *testing.T) {
+	token, hash, err := newToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(token) != 43 {
+		t.Errorf("token %q has %d characters, want 43", token, len(token))
+	}
+	if hash != hashToken(token) || hash == token {
+		t.Errorf("newToken hash %q is not hashToken(token)", hash)
+	}
+	again, _, err := newToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == token {
+		t.Error("newToken returned the same token twice")
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+		err   error
+	}{
+		{"Ada@Example.com", "ada@example.com", nil},
+		{"  ada@example.com ", "ada@example.com", nil},
+		{"Ada Lovelace <ada@example.com>", "", ErrInvalidEmail},
+		{"not an email", "", ErrInvalidEmail},
+		{"", "", ErrInvalidEmail},
+	}
+	for _, tt := range tests {
+		got, err := normalizeEmail(tt.email)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("normalizeEmail(%q) = %q, %v; want %q, %v", tt.email, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestCheckRole(t *testing.T) {
+	tests := []struct {
+		role string
+		want error
+	}{
+		{RoleAdmin, nil},
+		{RoleMember, nil},
+		{RoleViewer, nil},
+		{RoleOwner, ErrOwnerRole},
+		{"ADMIN", ErrInvalidRole},
+		{"", ErrInvalidRole},
+	}
+	for _, tt := range tests {
+		if err := checkRole(tt.role); !errors.Is(err, tt.want) {
+			t.Errorf("checkRole(%q) = %v, want %v", tt.role, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+// Package comments implements task comments: sanitized Markdown bodies,
+// @mentions that notify project members, and the edit history of each
+// comment. Changes are announced to live subscribers of the task's project.
+package comments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/live"
+	"lifequest-server/internal/notifications"
+	"lifequest-server/internal/utils"
+)
+
+var (
+	ErrTaskNotFound = errors.New("task not found")
+	ErrNotFound     = errors.New("comment not found")
+	ErrNotAuthor    = errors.New("only the author can edit a comment")
+	ErrForbidden    = errors.New("only the author or a project admin can delete a comment")
+	ErrReadOnly     = errors.New("viewers cannot comment")
+)
+
+// List returns the task's comments, oldest first, with their authors and
+// edit history.
+func List(ctx context.Context, client *db.PrismaClient, userID, taskID string) ([]db.TaskCommentModel, error) {
+	if _, _, _, err := access(ctx, client, userID, taskID); err != nil {
+		return nil, err
+	}
+	return client.TaskComment.FindMany(
+		db.TaskComment.TaskID.Equals(taskID),
+	).With(
+		db.TaskComment.User.Fetch(),
+		db.TaskComment.Revisions.Fetch().OrderBy(
+			db.TaskCommentRevision.CreatedAt.Order(db.SortOrderAsc),
+		),
+	).OrderBy(
+		db.TaskComment.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+}
+
+// Create adds a comment to the task and notifies the members it mentions.
+func Create(ctx context.Context, client *db.PrismaClient, userID, taskID, body string) (*db.TaskCommentModel, error) {
+	task, members, viewer, err := access(ctx, client, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.Can(viewer, authz.ActionCreate, authz.In(authz.KindComment, task.ProjectID)) {
+		return nil, ErrReadOnly
+	}
+	body, err = Sanitize(body)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions := mentioned(body, members)
+	created, err := client.TaskComment.CreateOne(
+		db.TaskComment.Body.Set(body),
+		db.TaskComment.Task.Link(db.Task.ID.Equals(task.ID)),
+		db.TaskComment.User.Link(db.User.ID.Equals(userID)),
+		db.TaskComment.ID.Set(utils.GenerateUUID()),
+		db.TaskComment.Mentions.Set(mentions),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := get(ctx, client, created.ID)
+	if err != nil {
+		return nil, err
+	}
+	notify(ctx, client, task, comment, mentions)
+	publish(task, live.ActionCommentAdded)
+	return comment, nil
+}
+
+// Edit replaces the body of the author's comment, keeping the previous body
+// in its history. Members mentioned for the first time are notified.
+func Edit(ctx context.Context, client *db.PrismaClient, userID, id, body string) (*db.TaskCommentModel, error) {
+	comment, err := get(ctx, client, id)
+	if err != nil {
+		return nil, err
+	}
+	task, members, viewer, err := access(ctx, client, userID, comment.TaskID)
+	if errors.Is(err, ErrTaskNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !authz.Can(viewer, authz.ActionUpdate, resource(task, comment)) {
+		return nil, ErrNotAuthor
+	}
+	body, err = Sanitize(body)
+	if err != nil {
+		return nil, err
+	}
+	if body == comment.Body {
+		return comment, nil
+	}
+
+	mentions := mentioned(body, members)
+	previous := map[string]bool{}
+	for _, mentionID := range comment.Mentions {
+		previous[mentionID] = true
+	}
+	var added []string
+	for _, mentionID := range mentions {
+		if !previous[mentionID] {
+			added = append(added, mentionID)
+		}
+	}
+
+	now := time.Now()
+	err = client.Prisma.Transaction(
+		client.TaskCommentRevision.CreateOne(
+			db.TaskCommentRevision.Body.Set(comment.Body),
+			db.TaskCommentRevision.Comment.Link(db.TaskComment.ID.Equals(comment.ID)),
+			db.TaskCommentRevision.ID.Set(utils.GenerateUUID()),
+			db.TaskCommentRevision.CreatedAt.Set(now),
+		).Tx(),
+		client.TaskComment.FindUnique(
+			db.TaskComment.ID.Equals(comment.ID),
+		).Update(
+			db.TaskComment.Body.Set(body),
+			db.TaskComment.Mentions.Set(mentions),
+			db.TaskComment.EditedAt.Set(now),
+		).Tx(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err = get(ctx, client, comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	notify(ctx, client, task, comment, added)
+	publish(task, live.ActionCommentEdited)
+	return comment, nil
+}
+
+// Delete removes a comment and its history. Comments can be deleted by
+// their author and by the project's admins.
+func Delete(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	comment, err := get(ctx, client, id)
+	if err != nil {
+		return err
+	}
+	task, _, viewer, err := access(ctx, client, userID, comment.TaskID)
+	if errors.Is(err, ErrTaskNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !authz.Can(viewer, authz.ActionDelete, resource(task, comment)) {
+		return ErrForbidden
+	}
+
+	_, err = client.TaskComment.FindUnique(
+		db.TaskComment.ID.Equals(comment.ID),
+	).Delete().Exec(ctx)
+	if err != nil {
+		return err
+	}
+	publish(task, live.ActionCommentDeleted)
+	return nil
+}
+
+// Members returns the users who can see and comment on the project's tasks:
+// its owner and the collaborators who joined it.
+func Members(ctx context.Context, client *db.PrismaClient, project *db.ProjectModel) ([]db.UserModel, error) {
+	owner, err := client.User.FindUnique(
+		db.User.ID.Equals(project.UserID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborators, err := client.ProjectCollaborator.FindMany(
+		db.ProjectCollaborator.ProjectID.Equals(project.ID),
+		db.ProjectCollaborator.Not(db.ProjectCollaborator.JoinedAt.IsNull()),
+	).With(
+		db.ProjectCollaborator.User.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []db.UserModel{*owner}
+	for _, collaborator := range collaborators {
+		if collaborator.UserID != owner.ID {
+			members = append(members, *collaborator.User())
+		}
+	}
+	return members, nil
+}
+
+// access returns the live task, the members of its project and userID as a
+// viewer of it, if userID is one of them.
+func access(ctx context.Context, client *db.PrismaClient, userID, taskID string) (*db.TaskModel, []db.UserModel, authz.Viewer, error) {
+	viewer := authz.Viewer{UserID: userID}
+	task, err := client.Task.FindFirst(
+		db.Task.ID.Equals(taskID),
+		db.Task.DeletedAt.IsNull(),
+	).With(
+		db.Task.Project.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, nil, viewer, ErrTaskNotFound
+		}
+		return nil, nil, viewer, err
+	}
+
+	viewer.Role, err = authz.ProjectRole(ctx, client, userID, task.Project())
+	if err != nil {
+		return nil, nil, viewer, err
+	}
+	if !authz.Can(viewer, authz.ActionRead, authz.In(authz.KindComment, task.ProjectID)) {
+		return nil, nil, viewer, ErrTaskNotFound
+	}
+	members, err := Members(ctx, client, task.Project())
+	if err != nil {
+		return nil, nil, viewer, err
+	}
+	return task, members, viewer, nil
+}
+
+// resource describes comment for authorization.
+func resource(task *db.TaskModel, comment *db.TaskCommentModel) authz.Resource {
+	return authz.Resource{Kind: authz.KindComment, ID: comment.ID, ProjectID: task.ProjectID, AuthorID: comment.UserID}
+}
+
+func get(ctx context.Context, client *db.PrismaClient, id string) (*db.TaskCommentModel, error) {
+	comment, err := client.TaskComment.FindUnique(
+		db.TaskComment.ID.Equals(id),
+	).With(
+		db.TaskComment.User.Fetch(),
+		db.TaskComment.Revisions.Fetch().OrderBy(
+			db.TaskCommentRevision.CreatedAt.Order(db.SortOrderAsc),
+		),
+	).Exec(ctx)
+	if db.IsErrNotFound(err) {
+		return nil, ErrNotFound
+	}
+	return comment, err
+}
+
+// notify sends a mention notification to each of userIDs other than the
+// comment's author. Failures are logged; the comment is saved either way.
+func notify(ctx context.Context, client *db.PrismaClient, task *db.TaskModel, comment *db.TaskCommentModel, userIDs []string) {
+	author := DisplayName(comment.User())
+	for _, userID := range userIDs {
+		if userID == comment.UserID {
+			continue
+		}
+		_, err := notifications.Create(ctx, client, userID, notifications.TypeCommentMention,
+			"You were mentioned",
+			fmt.Sprintf("%s mentioned you on %q", author, task.Title),
+			map[string]string{
+				"projectId": task.ProjectID,
+				"taskId":    task.ID,
+				"commentId": comment.ID,
+			},
+		)
+		if err != nil {
+			log.Printf("comments: failed to notify %s of comment %s: %v", userID, comment.ID, err)
+		}
+	}
+}
+
+func publish(task *db.TaskModel, action string) {
+	live.Publish(live.ProjectTopic(task.ProjectID), live.TaskEvent{
+		Action: action,
+		TaskID: task.ID,
+		UserID: task.UserID,
+	})
+}
+
+// DisplayName is the user's full name, or their email when they have none.
+func DisplayName(user *db.UserModel) string {
+	var parts []string
+	if firstName, ok := user.FirstName(); ok && firstName != "" {
+		parts = append(parts, firstName)
+	}
+	if lastName, ok := user.LastName(); ok && lastName != "" {
+		parts = append(parts, lastName)
+	}
+	if len(parts) == 0 {
+		return user.Email
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,178 @@
+package comments
+
+import (
+	"errors"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLength is the longest comment body accepted, in characters.
+const MaxLength = 10000
+
+var (
+	ErrEmpty   = errors.New("comment body is empty")
+	ErrTooLong = errors.New("comment body is too long")
+)
+
+var (
+	autolinkPattern   = regexp.MustCompile(`^<(?:https?://|mailto:)[^\s<>]*>`)
+	inlineLinkPattern = regexp.MustCompile(`(\]\(\s*)((?:[^()\s]|\([^()\s]*\))*)`)
+	refLinkPattern    = regexp.MustCompile(`(?m)^( {0,3}\[[^\]]+\]:\s*)(\S*)`)
+	percentPattern    = regexp.MustCompile(`%[0-9A-Fa-f]{2}`)
+	escapePattern     = regexp.MustCompile("\\\\([!-/:-@[-`{-~])")
+	schemePattern     = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+)
+
+// Sanitize cleans a Markdown comment body so clients can render it without
+// further checks: raw HTML is escaped, links other than http, https, mailto
+// and relative ones are pointed at "#", and control characters are dropped. Code spans and fenced
+// code blocks are left as they are, since renderers never treat their
+// content as HTML.
+func Sanitize(body string) (string, error) {
+	body = strings.ToValidUTF8(body, "")
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, body)
+	body = strings.TrimSpace(body)
+
+	if body == "" {
+		return "", ErrEmpty
+	}
+	if utf8.RuneCountInString(body) > MaxLength {
+		return "", ErrTooLong
+	}
+
+	body = mapText(body, func(text string) string {
+		text = escapeHTML(text)
+		text = rewriteLinks(inlineLinkPattern, text)
+		return rewriteLinks(refLinkPattern, text)
+	})
+	return body, nil
+}
+
+// rewriteLinks points the link destinations pattern captures second at "#"
+// unless they are safe.
+func rewriteLinks(pattern *regexp.Regexp, text string) string {
+	return pattern.ReplaceAllStringFunc(text, func(link string) string {
+		m := pattern.FindStringSubmatch(link)
+		if safeURL(m[2]) {
+			return link
+		}
+		return m[1] + "#"
+	})
+}
+
+// safeURL reports whether a link destination is relative or has an http,
+// https or mailto scheme once decoded the way renderers and browsers may
+// decode it: entities, percent-encoding and Markdown backslash escapes are
+// resolved until nothing changes, and whitespace and a leading "<" are
+// dropped.
+func safeURL(dest string) bool {
+	for {
+		decoded := html.UnescapeString(dest)
+		decoded = percentPattern.ReplaceAllStringFunc(decoded, func(code string) string {
+			b, _ := strconv.ParseUint(code[1:], 16, 8)
+			return string(rune(b))
+		})
+		decoded = escapePattern.ReplaceAllString(decoded, "$1")
+		if decoded == dest {
+			break
+		}
+		dest = decoded
+	}
+	dest = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, dest)
+	dest = strings.TrimLeft(dest, "<")
+
+	scheme := schemePattern.FindString(dest)
+	switch strings.ToLower(scheme) {
+	case "", "http:", "https:", "mailto:":
+		return true
+	}
+	return false
+}
+
+// escapeHTML escapes every "<" that doesn't start an autolink, which is
+// enough to keep renderers from seeing HTML tags.
+func escapeHTML(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '<' {
+			if link := autolinkPattern.FindString(text[i:]); link != "" {
+				b.WriteString(link)
+				i += len(link) - 1
+				continue
+			}
+			b.WriteString("&lt;")
+			continue
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+// mapText applies fn to the parts of a Markdown body outside code spans and
+// fenced code blocks.
+func mapText(body string, fn func(string) string) string {
+	var out, text strings.Builder
+	flush := func() {
+		out.WriteString(fn(text.String()))
+		text.Reset()
+	}
+
+	fence := ""
+	lines := strings.SplitAfter(body, "\n")
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		switch {
+		case fence != "":
+			out.WriteString(line)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			fence = trimmed[:3]
+			out.WriteString(line)
+			continue
+		}
+
+		for line != "" {
+			start := strings.IndexByte(line, '`')
+			if start < 0 {
+				text.WriteString(line)
+				break
+			}
+			run := start
+			for run < len(line) && line[run] == '`' {
+				run++
+			}
+			ticks := line[start:run]
+			end := strings.Index(line[run:], ticks)
+			if end < 0 {
+				text.WriteString(line[:run])
+				line = line[run:]
+				continue
+			}
+			text.WriteString(line[:start])
+			flush()
+			out.WriteString(line[start : run+end+len(ticks)])
+			line = line[run+end+len(ticks):]
+		}
+	}
+	flush()
+	return out.String()
+}
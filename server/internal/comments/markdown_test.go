@@ -0,0 +1,49 @@
+package comments
+
+import "testing"
+
+func TestSanitizeLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"https", "[docs](https://example.com/a_(b))", "[docs](https://example.com/a_(b))"},
+		{"http", "[docs](http://example.com)", "[docs](http://example.com)"},
+		{"mailto", "[me](mailto:me@example.com)", "[me](mailto:me@example.com)"},
+		{"relative", "[task](/tasks/1?tab=comments#c2)", "[task](/tasks/1?tab=comments#c2)"},
+		{"relative with colon", "[time](/at/10:30)", "[time](/at/10:30)"},
+		{"image", "![x](javascript:alert(1))", "![x](#)"},
+		{"javascript", "[x](javascript:alert(1))", "[x](#)"},
+		{"upper case", "[x](JavaScript:alert(1))", "[x](#)"},
+		{"data", "[x](data:text/html;base64,PHNjcmlwdD4=)", "[x](#)"},
+		{"other scheme", "[x](file:///etc/passwd)", "[x](#)"},
+		{"named entity", "[x](javascript&colon;alert(1))", "[x](#)"},
+		{"decimal entity", "[x](&#106;avascript:alert(1))", "[x](#)"},
+		{"hex entity", "[x](&#x6A;avascript:alert(1))", "[x](#)"},
+		{"entity without semicolon", "[x](&#106avascript:alert(1))", "[x](#)"},
+		{"percent", "[x](javascript%3Aalert(1))", "[x](#)"},
+		{"double encoded", "[x](javascript%253Aalert(1))", "[x](#)"},
+		{"entity and percent", "[x](javascript&#37;3Aalert(1))", "[x](#)"},
+		{"backslash escape", `[x](javascript\:alert(1))`, "[x](#)"},
+		{"angle brackets", "[x](<javascript:alert(1)>)", "[x](#)"},
+		{"leading space", "[x](  javascript:alert(1))", "[x](  #)"},
+		{"reference", "[x]: javascript:alert(1)", "[x]: #"},
+		{"encoded reference", "[x]: &#x6A;avascript:alert(1)", "[x]: #"},
+		{"safe reference", "[x]: https://example.com", "[x]: https://example.com"},
+		{"code span", "`[x](javascript:alert(1))`", "`[x](javascript:alert(1))`"},
+		{"autolink", "<https://example.com>", "<https://example.com>"},
+		{"html", "<script>alert(1)</script>", "&lt;script>alert(1)&lt;/script>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sanitize(tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
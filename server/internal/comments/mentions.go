@@ -0,0 +1,46 @@
+package comments
+
+import (
+	"regexp"
+	"strings"
+
+	"lifequest-server/internal/db"
+)
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([\w.+-]*\w(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+// Mentions returns the lower-cased handles mentioned in a Markdown body, in
+// order of first appearance. A handle is a collaborator's email address or
+// the part of it before the "@"; mentions inside code are ignored.
+func Mentions(body string) []string {
+	var handles []string
+	seen := map[string]bool{}
+	mapText(body, func(text string) string {
+		for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+			handle := strings.ToLower(match[1])
+			if !seen[handle] {
+				seen[handle] = true
+				handles = append(handles, handle)
+			}
+		}
+		return text
+	})
+	return handles
+}
+
+// mentioned returns the IDs of the members the body mentions.
+func mentioned(body string, members []db.UserModel) []string {
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, handle := range Mentions(body) {
+		for _, member := range members {
+			email := strings.ToLower(member.Email)
+			local, _, _ := strings.Cut(email, "@")
+			if (handle == email || handle == local) && !seen[member.ID] {
+				seen[member.ID] = true
+				ids = append(ids, member.ID)
+			}
+		}
+	}
+	return ids
+}
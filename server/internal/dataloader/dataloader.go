@@ -0,0 +1,101 @@
+// Package dataloader batches and caches loads by key. Loads made within a
+// short window are fetched together in one call, and every key is fetched
+// at most once for the life of the loader, so a loader is meant to live as
+// long as one request.
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWait is how long a batch collects keys before it is fetched.
+	DefaultWait = 2 * time.Millisecond
+	// MaxBatch is the most keys fetched in one call; a full batch is
+	// fetched at once.
+	MaxBatch = 500
+)
+
+// Fetch loads the values of keys. Keys missing from the result load as the
+// zero value.
+type Fetch[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader loads values of type V by keys of type K.
+type Loader[K comparable, V any] struct {
+	fetch Fetch[K, V]
+
+	mu      sync.Mutex
+	results map[K]*result[V]
+	batch   *batch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	results []*result[V]
+}
+
+// New returns a loader fetching with fetch.
+func New[K comparable, V any](fetch Fetch[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{fetch: fetch, results: map[K]*result[V]{}}
+}
+
+// Load returns the value of key, waiting for the batch it joins. A failed
+// fetch fails every load in the batch, and later loads of the same keys.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	r, ok := l.results[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.results[key] = r
+		if l.batch == nil {
+			l.batch = &batch[K, V]{}
+			b := l.batch
+			time.AfterFunc(DefaultWait, func() { l.dispatch(ctx, b) })
+		}
+		l.batch.keys = append(l.batch.keys, key)
+		l.batch.results = append(l.batch.results, r)
+		if len(l.batch.keys) >= MaxBatch {
+			b := l.batch
+			l.batch = nil
+			go l.run(ctx, b)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatch fetches b when its wait is over, unless it filled up and was
+// fetched already.
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	l.mu.Lock()
+	if l.batch != b {
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	l.mu.Unlock()
+	l.run(ctx, b)
+}
+
+func (l *Loader[K, V]) run(ctx context.Context, b *batch[K, V]) {
+	values, err := l.fetch(ctx, b.keys)
+	for i, key := range b.keys {
+		b.results[i].value, b.results[i].err = values[key], err
+		close(b.results[i].done)
+	}
+}
@@ -0,0 +1,269 @@
+// Package export builds a zip archive of everything LifeQuest stores for a
+// user, and manages the short-lived download links handed out for it.
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+// FormatVersion is bumped whenever the archive layout changes.
+const FormatVersion = 9
+
+// Manifest is written to manifest.json at the root of every archive.
+type Manifest struct {
+	Version    int       `json:"version"`
+	UserID     string    `json:"userId"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Files      []string  `json:"files"`
+}
+
+// Write streams the user's data to w as a zip archive: one JSON file per
+// entity type plus CSVs of tasks and focus sessions.
+func Write(ctx context.Context, client *db.PrismaClient, userID string, w io.Writer) error {
+	user, err := client.User.FindUnique(
+		db.User.ID.Equals(userID),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	folders, err := client.Folder.FindMany(
+		db.Folder.UserID.Equals(userID),
+	).OrderBy(
+		db.Folder.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	projects, err := client.Project.FindMany(
+		db.Project.UserID.Equals(userID),
+	).OrderBy(
+		db.Project.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := client.Task.FindMany(
+		db.Task.UserID.Equals(userID),
+	).OrderBy(
+		db.Task.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	subtasks, err := client.Subtask.FindMany(
+		db.Subtask.Task.Where(db.Task.UserID.Equals(userID)),
+	).OrderBy(
+		db.Subtask.TaskID.Order(db.SortOrderAsc),
+		db.Subtask.Position.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	dependencies, err := client.TaskDependency.FindMany(
+		db.TaskDependency.Task.Where(db.Task.UserID.Equals(userID)),
+	).OrderBy(
+		db.TaskDependency.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	taskComments, err := client.TaskComment.FindMany(
+		db.TaskComment.Or(
+			db.TaskComment.UserID.Equals(userID),
+			db.TaskComment.Task.Where(db.Task.UserID.Equals(userID)),
+		),
+	).With(
+		db.TaskComment.Revisions.Fetch(),
+	).OrderBy(
+		db.TaskComment.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Attachment metadata only; the files stay in blob storage.
+	taskAttachments, err := client.TaskAttachment.FindMany(
+		db.TaskAttachment.UserID.Equals(userID),
+		db.TaskAttachment.Not(db.TaskAttachment.TaskID.IsNull()),
+	).OrderBy(
+		db.TaskAttachment.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	recurrences, err := client.TaskRecurrence.FindMany(
+		db.TaskRecurrence.UserID.Equals(userID),
+	).OrderBy(
+		db.TaskRecurrence.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	sprints, err := client.Sprint.FindMany(
+		db.Sprint.UserID.Equals(userID),
+	).With(
+		db.Sprint.SprintTasks.Fetch(),
+	).OrderBy(
+		db.Sprint.StartDate.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := client.PomodoroSession.FindMany(
+		db.PomodoroSession.UserID.Equals(userID),
+	).OrderBy(
+		db.PomodoroSession.StartTime.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	tags, err := client.Tag.FindMany(
+		db.Tag.UserID.Equals(userID),
+	).OrderBy(
+		db.Tag.Key.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	taskTags, err := client.TaskTag.FindMany(
+		db.TaskTag.Tag.Where(db.Tag.UserID.Equals(userID)),
+	).OrderBy(
+		db.TaskTag.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	savedViews, err := client.SavedView.FindMany(
+		db.SavedView.UserID.Equals(userID),
+	).OrderBy(
+		db.SavedView.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	habits, err := client.Habit.FindMany(
+		db.Habit.UserID.Equals(userID),
+	).OrderBy(
+		db.Habit.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	checkIns, err := client.HabitCheckIn.FindMany(
+		db.HabitCheckIn.UserID.Equals(userID),
+	).OrderBy(
+		db.HabitCheckIn.Date.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	ledger, err := client.XpEntry.FindMany(
+		db.XpEntry.UserID.Equals(userID),
+	).OrderBy(
+		db.XpEntry.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	notifications, err := client.Notification.FindMany(
+		db.Notification.UserID.Equals(userID),
+	).OrderBy(
+		db.Notification.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectNames := make(map[string]string, len(projects))
+	for _, project := range projects {
+		projectNames[project.ID] = project.Name
+	}
+
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"user.json", jsonFile(user)},
+		{"folders.json", jsonFile(folders)},
+		{"projects.json", jsonFile(projects)},
+		{"tasks.json", jsonFile(tasks)},
+		{"subtasks.json", jsonFile(subtasks)},
+		{"task_dependencies.json", jsonFile(dependencies)},
+		{"task_comments.json", jsonFile(taskComments)},
+		{"task_attachments.json", jsonFile(taskAttachments)},
+		{"task_recurrences.json", jsonFile(recurrences)},
+		{"tags.json", jsonFile(tags)},
+		{"task_tags.json", jsonFile(taskTags)},
+		{"saved_views.json", jsonFile(savedViews)},
+		{"sprints.json", jsonFile(sprints)},
+		{"pomodoro_sessions.json", jsonFile(sessions)},
+		{"habits.json", jsonFile(habits)},
+		{"habit_check_ins.json", jsonFile(checkIns)},
+		{"xp_ledger.json", jsonFile(ledger)},
+		{"notifications.json", jsonFile(notifications)},
+		{"tasks.csv", func(w io.Writer) error { return writeTasksCSV(w, tasks, projectNames) }},
+		{"pomodoro_sessions.csv", func(w io.Writer) error { return writeSessionsCSV(w, sessions) }},
+	}
+
+	manifest := Manifest{
+		Version:    FormatVersion,
+		UserID:     userID,
+		ExportedAt: time.Now().UTC(),
+	}
+	for _, file := range files {
+		manifest.Files = append(manifest.Files, file.name)
+	}
+
+	archive := zip.NewWriter(w)
+	if err := addFile(archive, "manifest.json", manifest.ExportedAt, jsonFile(manifest)); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := addFile(archive, file.name, manifest.ExportedAt, file.write); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func addFile(archive *zip.Writer, name string, modified time.Time, write func(io.Writer) error) error {
+	w, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+	return write(w)
+}
+
+func jsonFile(v interface{}) func(io.Writer) error {
+	return func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+func writeTasksCSV(w io.Writer, tasks []db.TaskModel, projectNames map[string]string) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{
+		"id", "project_id", "project", "title", "description", "status", "priority",
+		"xp_value", "estimated_pomodoros", "actual_pomodoros",
+		"due_date", "completed_at", "created_at",
+	})
+
+	for _, task := range tasks {
+		description, _ := task.Description()
+		dueDate, hasDueDate := task.DueDate()
+		completedAt, hasCompletedAt := task.CompletedAt()
+
+		out.Write([]string{
+			task.ID,
+			task.ProjectID,
+			projectNames[task.ProjectID],
+			task.Title,
+			description,
+			task.Status,
+			task.Priority,
+			strconv.Itoa(task.XpValue),
+			strconv.Itoa(task.EstimatedPomodoros),
+			strconv.Itoa(task.ActualPomodoros),
+			formatTime(dueDate, hasDueDate),
+			formatTime(completedAt, hasCompletedAt),
+			formatTime(task.CreatedAt, true),
+		})
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func writeSessionsCSV(w io.Writer, sessions []db.PomodoroSessionModel) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{
+		"id", "type", "status", "duration_minutes", "task_id", "project_id",
+		"start_time", "end_time", "xp_earned",
+	})
+
+	for _, session := range sessions {
+		taskID, _ := session.TaskID()
+		projectID, _ := session.ProjectID()
+		endTime, hasEndTime := session.EndTime()
+
+		out.Write([]string{
+			session.ID,
+			session.Type,
+			session.Status,
+			strconv.Itoa(session.Duration),
+			taskID,
+			projectID,
+			formatTime(session.StartTime, true),
+			formatTime(endTime, hasEndTime),
+			strconv.Itoa(session.XpEarned),
+		})
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// formatTime renders t as RFC 3339 in UTC, or an empty cell when unset.
+func formatTime(t time.Time, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
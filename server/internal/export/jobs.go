@@ -0,0 +1,165 @@
+package export
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+// Export statuses stored on DataExport.status.
+const (
+	StatusPending = "pending"
+	StatusReady   = "ready"
+	StatusFailed  = "failed"
+	StatusExpired = "expired"
+)
+
+const (
+	// Retention is how long a finished archive is kept on disk.
+	Retention = 24 * time.Hour
+
+	// Timeout is how long an export may stay pending. Jobs run in the
+	// process that accepted them, so one still pending after this was lost
+	// to a crash or restart and is marked failed.
+	Timeout = 30 * time.Minute
+
+	cleanupInterval = time.Hour
+)
+
+// Dir returns the directory archives are written to, EXPORT_DIR or a
+// lifequest-exports directory under the system temp dir.
+func Dir() string {
+	if dir := os.Getenv("EXPORT_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "lifequest-exports")
+}
+
+// Path returns the archive location for exportID.
+func Path(exportID string) string {
+	return filepath.Join(Dir(), exportID+".zip")
+}
+
+// Run builds the archive for a pending DataExport and records the outcome.
+func Run(ctx context.Context, client *db.PrismaClient, export *db.DataExportModel) {
+	size, err := writeArchive(ctx, client, export)
+	now := time.Now()
+
+	params := []db.DataExportSetParam{
+		db.DataExport.CompletedAt.Set(now),
+	}
+	if err != nil {
+		log.Printf("export: failed to build export %s: %v", export.ID, err)
+		params = append(params,
+			db.DataExport.Status.Set(StatusFailed),
+			db.DataExport.Error.Set(err.Error()),
+		)
+	} else {
+		params = append(params,
+			db.DataExport.Status.Set(StatusReady),
+			db.DataExport.Size.Set(int(size)),
+			db.DataExport.ExpiresAt.Set(now.Add(Retention)),
+		)
+	}
+
+	_, err = client.DataExport.FindUnique(
+		db.DataExport.ID.Equals(export.ID),
+	).Update(params...).Exec(ctx)
+	if err != nil {
+		log.Printf("export: failed to record export %s: %v", export.ID, err)
+	}
+}
+
+func writeArchive(ctx context.Context, client *db.PrismaClient, export *db.DataExportModel) (int64, error) {
+	if err := os.MkdirAll(Dir(), 0o700); err != nil {
+		return 0, err
+	}
+
+	// Write to a temporary name so a half-written archive is never served.
+	tmp, err := os.CreateTemp(Dir(), export.ID+"-*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := Write(ctx, client, export.UserID, tmp); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	return info.Size(), os.Rename(tmp.Name(), Path(export.ID))
+}
+
+// Start fails stale exports and removes expired archives right away, then
+// every cleanupInterval until ctx is cancelled.
+func Start(ctx context.Context, client *db.PrismaClient) {
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			failStale(ctx, client)
+			removeExpired(ctx, client)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// failStale marks exports pending for longer than Timeout as failed, so
+// their users can start a new one.
+func failStale(ctx context.Context, client *db.PrismaClient) {
+	now := time.Now()
+	_, err := client.DataExport.FindMany(
+		db.DataExport.Status.Equals(StatusPending),
+		db.DataExport.CreatedAt.Lte(now.Add(-Timeout)),
+	).Update(
+		db.DataExport.Status.Set(StatusFailed),
+		db.DataExport.Error.Set("export was interrupted"),
+		db.DataExport.CompletedAt.Set(now),
+	).Exec(ctx)
+	if err != nil {
+		log.Printf("export: failed to mark stale exports failed: %v", err)
+	}
+}
+
+func removeExpired(ctx context.Context, client *db.PrismaClient) {
+	expired, err := client.DataExport.FindMany(
+		db.DataExport.Status.Equals(StatusReady),
+		db.DataExport.ExpiresAt.Lte(time.Now()),
+	).Exec(ctx)
+	if err != nil {
+		log.Printf("export: failed to load expired exports: %v", err)
+		return
+	}
+
+	for _, export := range expired {
+		if err := os.Remove(Path(export.ID)); err != nil && !os.IsNotExist(err) {
+			log.Printf("export: failed to remove archive %s: %v", export.ID, err)
+			continue
+		}
+		_, err := client.DataExport.FindUnique(
+			db.DataExport.ID.Equals(export.ID),
+		).Update(
+			db.DataExport.Status.Set(StatusExpired),
+		).Exec(ctx)
+		if err != nil {
+			log.Printf("export: failed to expire export %s: %v", export.ID, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LinkTTL is how long a signed download link stays valid.
+const LinkTTL = 15 * time.Minute
+
+var (
+	signingKey     []byte
+	signingKeyOnce sync.Once
+)
+
+// key returns EXPORT_SIGNING_KEY, or a random per-process key when it is not
+// set, in which case links stop working when the server restarts.
+func key() []byte {
+	signingKeyOnce.Do(func() {
+		if value := os.Getenv("EXPORT_SIGNING_KEY"); value != "" {
+			signingKey = []byte(value)
+			return
+		}
+		log.Println("Warning: EXPORT_SIGNING_KEY not set, export links will not survive a restart")
+		signingKey = make([]byte, 32)
+		if _, err := rand.Read(signingKey); err != nil {
+			log.Fatal("Failed to generate export signing key:", err)
+		}
+	})
+	return signingKey
+}
+
+// SignedPath returns the download path for exportID with an expiry and
+// signature in the query string.
+func SignedPath(exportID string, now time.Time) (string, time.Time) {
+	expires := now.Add(LinkTTL).Truncate(time.Second)
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", sign(exportID, expires.Unix()))
+	return "/api/exports/" + url.PathEscape(exportID) + "/download?" + query.Encode(), expires
+}
+
+// VerifyLink checks the expires and signature query values of a download
+// link for exportID.
+func VerifyLink(exportID, expires, signature string, now time.Time) bool {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > unix {
+		return false
+	}
+	return hmac.Equal([]byte(sign(exportID, unix)), []byte(signature))
+}
+
+func sign(exportID string, expires int64) string {
+	mac := hmac.New(sha256.New, key())
+	mac.Write([]byte(exportID + "." + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,118 @@
+package folders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/trash"
+)
+
+// Ways of deleting a folder.
+const (
+	// DeleteRefuse trashes the folder only if it has no subfolders or
+	// projects.
+	DeleteRefuse = "refuse"
+	// DeleteMove moves the subfolders and projects into another folder, then
+	// trashes the empty folder.
+	DeleteMove = "move"
+	// DeleteArchive keeps the folder and its contents and marks it archived.
+	DeleteArchive = "archive"
+	// DeleteTrash moves the folder and everything inside it to the trash.
+	DeleteTrash = "trash"
+)
+
+var (
+	ErrNotEmpty       = errors.New("folder is not empty")
+	ErrUnknownMode    = errors.New("unknown delete mode")
+	ErrTargetRequired = errors.New("a target folder is required to move the contents")
+)
+
+// Delete removes folder id as described by mode. targetID is the folder that
+// receives the contents in DeleteMove mode and is ignored otherwise.
+func Delete(ctx context.Context, client *db.PrismaClient, userID, id, mode, targetID string) error {
+	forest, err := Load(ctx, client, userID)
+	if err != nil {
+		return err
+	}
+	if _, ok := forest.Get(id); !ok {
+		return ErrNotFound
+	}
+
+	switch mode {
+	case DeleteRefuse:
+		if subfolders, projects := len(forest.children[id]), forest.projectCount[id]; subfolders > 0 || projects > 0 {
+			return fmt.Errorf("%w: it has %d subfolders and %d projects", ErrNotEmpty, subfolders, projects)
+		}
+	case DeleteMove:
+		if err := moveContents(ctx, client, userID, forest, id, targetID); err != nil {
+			return err
+		}
+	case DeleteArchive:
+		now := time.Now()
+		_, err := client.Folder.FindUnique(
+			db.Folder.ID.Equals(id),
+		).Update(
+			db.Folder.ArchivedAt.Set(now),
+			db.Folder.UpdatedAt.Set(now),
+		).Exec(ctx)
+		return err
+	case DeleteTrash:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownMode, mode)
+	}
+
+	return trash.Folder(ctx, client, userID, id)
+}
+
+// moveContents moves the direct subfolders and projects of id into targetID.
+func moveContents(ctx context.Context, client *db.PrismaClient, userID string, forest *Forest, id, targetID string) error {
+	if targetID == "" {
+		return ErrTargetRequired
+	}
+	if _, ok := forest.Get(targetID); !ok {
+		return ErrParentMissing
+	}
+	if targetID == id {
+		return ErrCycle
+	}
+	for _, childID := range forest.children[id] {
+		if err := forest.CheckParent(childID, targetID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	moved := forest.projectCount[id]
+
+	return client.Prisma.Transaction(
+		client.Folder.FindMany(
+			db.Folder.ID.In(forest.children[id]),
+		).Update(
+			db.Folder.ParentID.Set(targetID),
+			db.Folder.UpdatedAt.Set(now),
+		).Tx(),
+		client.Project.FindMany(
+			db.Project.UserID.Equals(userID),
+			db.Project.FolderID.Equals(id),
+			db.Project.DeletedAt.IsNull(),
+		).Update(
+			db.Project.FolderID.Set(targetID),
+			db.Project.UpdatedAt.Set(now),
+		).Tx(),
+		client.Folder.FindUnique(
+			db.Folder.ID.Equals(id),
+		).Update(
+			db.Folder.ProjectCount.Decrement(moved),
+			db.Folder.UpdatedAt.Set(now),
+		).Tx(),
+		client.Folder.FindUnique(
+			db.Folder.ID.Equals(targetID),
+		).Update(
+			db.Folder.ProjectCount.Increment(moved),
+			db.Folder.UpdatedAt.Set(now),
+		).Tx(),
+	).Exec(ctx)
+}
@@ -0,0 +1,102 @@
+package folders
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+// ErrProjectNotFound is returned by MoveProject for projects the user does
+// not own.
+var ErrProjectNotFound = errors.New("project not found")
+
+// Move re-parents folder id under parentID, or to the top level when
+// parentID is "".
+func Move(ctx context.Context, client *db.PrismaClient, userID, id, parentID string) (*db.FolderModel, error) {
+	forest, err := Load(ctx, client, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := forest.Get(id); !ok {
+		return nil, ErrNotFound
+	}
+	if err := forest.CheckParent(id, parentID); err != nil {
+		return nil, err
+	}
+
+	params := []db.FolderSetParam{
+		db.Folder.UpdatedAt.Set(time.Now()),
+	}
+	if parentID == "" {
+		params = append(params, db.Folder.ParentID.SetOptional(nil))
+	} else {
+		params = append(params, db.Folder.ParentID.Set(parentID))
+	}
+
+	return client.Folder.FindUnique(
+		db.Folder.ID.Equals(id),
+	).Update(params...).Exec(ctx)
+}
+
+// MoveProject moves project id into folderID and keeps both folders' direct
+// project counts in step.
+func MoveProject(ctx context.Context, client *db.PrismaClient, userID, id, folderID string) (*db.ProjectModel, error) {
+	project, err := client.Project.FindFirst(
+		db.Project.ID.Equals(id),
+		db.Project.UserID.Equals(userID),
+		db.Project.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	_, err = client.Folder.FindFirst(
+		db.Folder.ID.Equals(folderID),
+		db.Folder.UserID.Equals(userID),
+		db.Folder.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrParentMissing
+		}
+		return nil, err
+	}
+
+	if project.FolderID == folderID {
+		return project, nil
+	}
+
+	now := time.Now()
+	move := client.Project.FindUnique(
+		db.Project.ID.Equals(project.ID),
+	).Update(
+		db.Project.Folder.Link(db.Folder.ID.Equals(folderID)),
+		db.Project.UpdatedAt.Set(now),
+	).Tx()
+
+	err = client.Prisma.Transaction(
+		move,
+		client.Folder.FindUnique(
+			db.Folder.ID.Equals(project.FolderID),
+		).Update(
+			db.Folder.ProjectCount.Decrement(1),
+			db.Folder.UpdatedAt.Set(now),
+		).Tx(),
+		client.Folder.FindUnique(
+			db.Folder.ID.Equals(folderID),
+		).Update(
+			db.Folder.ProjectCount.Increment(1),
+			db.Folder.UpdatedAt.Set(now),
+		).Tx(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return move.Result(), nil
+}
@@ -0,0 +1,216 @@
+// Package folders loads a user's folder hierarchy and answers questions about
+// it: subtrees with recursive aggregates, breadcrumb paths, and whether a
+// move would create a cycle.
+package folders
+
+import (
+	"context"
+	"errors"
+
+	"lifequest-server/internal/db"
+)
+
+const (
+	// MaxDepth is the deepest a folder may be nested; top-level folders are
+	// at depth 1.
+	MaxDepth = 10
+
+	// DefaultFetchDepth is how many levels a tree fetch returns when the
+	// caller does not ask for a depth.
+	DefaultFetchDepth = 3
+)
+
+var (
+	ErrNotFound      = errors.New("folder not found")
+	ErrCycle         = errors.New("a folder cannot be moved into itself or one of its subfolders")
+	ErrTooDeep       = errors.New("folders cannot be nested more than 10 levels deep")
+	ErrInvalidDepth  = errors.New("depth must be between 1 and 10")
+	ErrParentMissing = errors.New("parent folder not found")
+)
+
+// Node is a folder with its subfolders and aggregates over its whole
+// subtree. Children is nil below the requested depth; the aggregates always
+// cover every descendant.
+type Node struct {
+	db.FolderModel
+	Children      []*Node `json:"children"`
+	ProjectCount  int     `json:"projectCount"`
+	OpenTaskCount int     `json:"openTaskCount"`
+}
+
+// Forest is a snapshot of all of a user's folders.
+type Forest struct {
+	folders  map[string]*db.FolderModel
+	children map[string][]string // parent ID ("" for roots) to child IDs, in creation order
+
+	projectCount  map[string]int // direct projects per folder
+	openTaskCount map[string]int // open tasks in direct projects per folder
+}
+
+// Load reads the user's folders, projects and open tasks, leaving out
+// anything in the trash.
+func Load(ctx context.Context, client *db.PrismaClient, userID string) (*Forest, error) {
+	folders, err := client.Folder.FindMany(
+		db.Folder.UserID.Equals(userID),
+		db.Folder.DeletedAt.IsNull(),
+	).OrderBy(
+		db.Folder.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := client.Project.FindMany(
+		db.Project.UserID.Equals(userID),
+		db.Project.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	openTasks, err := client.Task.FindMany(
+		db.Task.UserID.Equals(userID),
+		db.Task.Status.Not("completed"),
+		db.Task.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Forest{
+		folders:       make(map[string]*db.FolderModel, len(folders)),
+		children:      map[string][]string{},
+		projectCount:  map[string]int{},
+		openTaskCount: map[string]int{},
+	}
+	for i := range folders {
+		folder := &folders[i]
+		parentID, _ := folder.ParentID()
+		f.folders[folder.ID] = folder
+		f.children[parentID] = append(f.children[parentID], folder.ID)
+	}
+
+	projectFolder := make(map[string]string, len(projects))
+	for _, project := range projects {
+		projectFolder[project.ID] = project.FolderID
+		f.projectCount[project.FolderID]++
+	}
+	for _, task := range openTasks {
+		f.openTaskCount[projectFolder[task.ProjectID]]++
+	}
+
+	return f, nil
+}
+
+// Get returns the folder with id.
+func (f *Forest) Get(id string) (*db.FolderModel, bool) {
+	folder, ok := f.folders[id]
+	return folder, ok
+}
+
+// Tree returns the subfolders of parentID ("" for the top level) as nodes
+// with depth levels of children.
+func (f *Forest) Tree(parentID string, depth int) ([]*Node, error) {
+	if depth < 1 || depth > MaxDepth {
+		return nil, ErrInvalidDepth
+	}
+	if parentID != "" {
+		if _, ok := f.folders[parentID]; !ok {
+			return nil, ErrNotFound
+		}
+	}
+
+	nodes := []*Node{}
+	for _, id := range f.children[parentID] {
+		nodes = append(nodes, f.node(id, depth))
+	}
+	return nodes, nil
+}
+
+// Node returns the folder id as a node with depth levels of children.
+func (f *Forest) Node(id string, depth int) (*Node, error) {
+	if depth < 0 || depth > MaxDepth {
+		return nil, ErrInvalidDepth
+	}
+	if _, ok := f.folders[id]; !ok {
+		return nil, ErrNotFound
+	}
+	return f.node(id, depth), nil
+}
+
+func (f *Forest) node(id string, depth int) *Node {
+	n := &Node{
+		FolderModel:   *f.folders[id],
+		ProjectCount:  f.projectCount[id],
+		OpenTaskCount: f.openTaskCount[id],
+	}
+	if depth > 0 {
+		n.Children = []*Node{}
+	}
+	for _, childID := range f.children[id] {
+		child := f.node(childID, max(depth-1, 0))
+		n.ProjectCount += child.ProjectCount
+		n.OpenTaskCount += child.OpenTaskCount
+		if depth > 0 {
+			n.Children = append(n.Children, child)
+		}
+	}
+	return n
+}
+
+// Path returns the folders from the top level down to id, inclusive.
+func (f *Forest) Path(id string) ([]db.FolderModel, error) {
+	var path []db.FolderModel
+	for id != "" {
+		folder, ok := f.folders[id]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		if len(path) > MaxDepth {
+			// Only reachable if the stored data already has a cycle.
+			return nil, ErrCycle
+		}
+		path = append([]db.FolderModel{*folder}, path...)
+		id, _ = folder.ParentID()
+	}
+	return path, nil
+}
+
+// CheckParent validates placing a folder under parentID ("" for the top
+// level). id is the folder being moved, or "" for a new folder.
+func (f *Forest) CheckParent(id, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+	if _, ok := f.folders[parentID]; !ok {
+		return ErrParentMissing
+	}
+
+	path, err := f.Path(parentID)
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range path {
+		if ancestor.ID == id {
+			return ErrCycle
+		}
+	}
+
+	height := 1
+	if id != "" {
+		height = f.height(id)
+	}
+	if len(path)+height > MaxDepth {
+		return ErrTooDeep
+	}
+	return nil
+}
+
+// height is the number of levels in the subtree rooted at id.
+func (f *Forest) height(id string) int {
+	tallest := 0
+	for _, childID := range f.children[id] {
+		tallest = max(tallest, f.height(childID))
+	}
+	return tallest + 1
+}
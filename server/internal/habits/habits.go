@@ -0,0 +1,612 @@
+// Package habits implements habit tracking next to tasks: habits with a
+// target number of check-ins per day, week or month, streaks counted in
+// periods, and XP towards the habit's skill category each period the target
+// is reached. Periods follow UTC days, Monday-based weeks and calendar
+// months.
+package habits
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/utils"
+	"lifequest-server/internal/xp"
+)
+
+// Habit periods stored on Habit.period.
+const (
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+)
+
+// Categories are the skill categories habit XP can count towards.
+var Categories = []string{"productivity", "health", "learning", "creativity", "social", "finance", "personal"}
+
+const (
+	// MaxHeatmapDays is the longest range Heatmap covers.
+	MaxHeatmapDays = 366
+
+	maxTarget  = 1000
+	maxXpValue = 1000
+)
+
+var (
+	ErrNotFound        = errors.New("habit not found")
+	ErrCheckInNotFound = errors.New("check-in not found")
+	ErrNameRequired    = errors.New("habit name is required")
+	ErrInvalidPeriod   = errors.New("period must be daily, weekly or monthly")
+	ErrInvalidCategory = errors.New("unknown skill category")
+	ErrInvalidTarget   = errors.New("target count must be between 1 and 1000")
+	ErrInvalidXp       = errors.New("XP value must be between 0 and 1000")
+	ErrInvalidCount    = errors.New("check-in count must be at least 1")
+	ErrFutureDate      = errors.New("cannot check in for a future day")
+	ErrArchived        = errors.New("habit is archived")
+	ErrInvalidRange    = errors.New("range must end after it starts and cover at most 366 days")
+)
+
+// Fields are the editable fields of a habit; nil fields are left unchanged
+// on update and defaulted on create.
+type Fields struct {
+	Name        *string
+	Description *string
+	Icon        *string
+	Color       *string
+	Category    *string
+	Period      *string
+	TargetCount *int
+	XpValue     *int
+	Archived    *bool
+}
+
+// Status is a habit with its progress in the current period.
+type Status struct {
+	db.HabitModel
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodCount int       `json:"periodCount"` // check-ins so far in the current period
+	Completed   bool      `json:"completed"`   // the target is reached for the current period
+}
+
+// Day is one day of a check-in heatmap.
+type Day struct {
+	Date  time.Time `json:"date"`
+	Count int       `json:"count"`
+}
+
+// List returns the user's habits, oldest first, with their progress in the
+// current period.
+func List(ctx context.Context, client *db.PrismaClient, userID string, includeArchived bool) ([]Status, error) {
+	filters := []db.HabitWhereParam{db.Habit.UserID.Equals(userID)}
+	if !includeArchived {
+		filters = append(filters, db.Habit.ArchivedAt.IsNull())
+	}
+	stored, err := client.Habit.FindMany(filters...).OrderBy(
+		db.Habit.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	// The earliest current period is this week's or this month's.
+	since := PeriodStart(PeriodWeekly, now)
+	if month := PeriodStart(PeriodMonthly, now); month.Before(since) {
+		since = month
+	}
+	checkIns, err := client.HabitCheckIn.FindMany(
+		db.HabitCheckIn.UserID.Equals(userID),
+		db.HabitCheckIn.Date.Gte(since),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Status, len(stored))
+	for i, habit := range stored {
+		list[i] = status(habit, checkIns, now)
+	}
+	return list, nil
+}
+
+// Get returns one of the user's habits with its progress in the current
+// period.
+func Get(ctx context.Context, client *db.PrismaClient, userID, id string) (*Status, error) {
+	habit, err := find(ctx, client, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return current(ctx, client, habit)
+}
+
+// Create adds a habit. Name is required; the rest defaults to one check-in
+// a day in the personal category.
+func Create(ctx context.Context, client *db.PrismaClient, userID string, fields Fields) (*Status, error) {
+	if fields.Name == nil {
+		return nil, ErrNameRequired
+	}
+	params, err := setParams(fields)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(*fields.Name)
+
+	habit, err := client.Habit.CreateOne(
+		db.Habit.Name.Set(name),
+		db.Habit.User.Link(db.User.ID.Equals(userID)),
+		append([]db.HabitSetParam{db.Habit.ID.Set(utils.GenerateUUID())}, params...)...,
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return current(ctx, client, habit)
+}
+
+// Update changes the habit's fields. Changing the period or target only
+// affects streaks and XP from the next check-in on.
+func Update(ctx context.Context, client *db.PrismaClient, userID, id string, fields Fields) (*Status, error) {
+	if _, err := find(ctx, client, userID, id); err != nil {
+		return nil, err
+	}
+	params, err := setParams(fields)
+	if err != nil {
+		return nil, err
+	}
+	if fields.Name != nil {
+		params = append(params, db.Habit.Name.Set(strings.TrimSpace(*fields.Name)))
+	}
+
+	habit, err := client.Habit.FindUnique(
+		db.Habit.ID.Equals(id),
+	).Update(params...).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return current(ctx, client, habit)
+}
+
+// Delete removes the habit and its check-ins. XP it earned is kept.
+func Delete(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	if _, err := find(ctx, client, userID, id); err != nil {
+		return err
+	}
+	_, err := client.Habit.FindUnique(
+		db.Habit.ID.Equals(id),
+	).Delete().Exec(ctx)
+	return err
+}
+
+// utc turns a timestamp parameter into a timestamp column value; Prisma
+// stores timestamps in UTC.
+const utc = `::timestamptz AT TIME ZONE 'UTC'`
+
+// checkInSQL records check-in $3 of $5 on habit $1 for user $2 on day $4
+// with note $6, counting what the period from $7 to $8 had before it, and
+// awards the habit's XP through ledger entry $9 with reason $10 when it
+// reaches the target. Returns whether XP was awarded.
+const checkInSQL = `
+WITH counted AS (
+	SELECT coalesce(sum(count), 0)::int AS before FROM habit_check_ins
+	WHERE habit_id = $1 AND "date" >= $7` + utc + ` AND "date" < $8` + utc + `
+), checked AS (
+	INSERT INTO habit_check_ins (id, habit_id, user_id, "date", count, note, created_at)
+	VALUES ($3, $1, $2, $4` + utc + `, $5::int, NULLIF($6, ''), now() AT TIME ZONE 'UTC')
+), reached AS (
+	SELECT h.xp_value, h.category FROM habits h, counted
+	WHERE h.id = $1 AND h.xp_value > 0 AND before < h.target_count AND before + $5::int >= h.target_count
+), awarded AS (
+	UPDATE users
+	SET xp = xp + r.xp_value, total_xp = total_xp + r.xp_value, updated_at = now() AT TIME ZONE 'UTC'
+	FROM reached r
+	WHERE id = $2
+), recorded AS (
+	INSERT INTO xp_ledger (id, user_id, amount, reason, source_id, category, created_at)
+	SELECT $9, $2, xp_value, $10, $3, category, now() AT TIME ZONE 'UTC' FROM reached
+)
+SELECT count(*)::int AS "awarded" FROM reached`
+
+// undoCheckInSQL deletes user $2's check-in $1 on habit $3, and takes back
+// the habit's XP through ledger entry $6 with reason $7 when the period from
+// $4 to $5 drops below the target. The count sees the period as it was
+// before the delete. Returns whether the check-in was deleted and XP taken
+// back.
+const undoCheckInSQL = `
+WITH removed AS (
+	DELETE FROM habit_check_ins WHERE id = $1 AND user_id = $2
+	RETURNING count
+), counted AS (
+	SELECT coalesce(sum(count), 0)::int AS before FROM habit_check_ins
+	WHERE habit_id = $3 AND "date" >= $4` + utc + ` AND "date" < $5` + utc + `
+), reopened AS (
+	SELECT h.xp_value, h.category FROM habits h, counted, removed
+	WHERE h.id = $3 AND h.xp_value > 0 AND before >= h.target_count AND before - removed.count < h.target_count
+), awarded AS (
+	UPDATE users
+	SET xp = xp - r.xp_value, total_xp = total_xp - r.xp_value, updated_at = now() AT TIME ZONE 'UTC'
+	FROM reopened r
+	WHERE id = $2
+), recorded AS (
+	INSERT INTO xp_ledger (id, user_id, amount, reason, source_id, category, created_at)
+	SELECT $6, $2, -xp_value, $7, $1, category, now() AT TIME ZONE 'UTC' FROM reopened
+)
+SELECT (SELECT count(*) FROM removed)::int AS "removed", (SELECT count(*) FROM reopened)::int AS "awarded"`
+
+// lockHabit locks the habit's row for the rest of the transaction, so
+// check-ins on it are counted one at a time. Each statement after it sees
+// the check-ins committed while it waited.
+func lockHabit(client *db.PrismaClient, habitID string) db.PrismaTransaction {
+	return client.Prisma.QueryRaw(`SELECT id FROM habits WHERE id = $1 FOR UPDATE`, habitID).Tx()
+}
+
+// CheckIn records count check-ins on the habit for date, today when date is
+// zero. Reaching the period's target credits the habit's XP to its skill
+// category. Check-ins on a habit are serialized on its row, and each is
+// counted and awarded in the same statement that records it, so concurrent
+// check-ins award the period once.
+func CheckIn(ctx context.Context, client *db.PrismaClient, userID, habitID string, count int, date time.Time, note string) (*Status, error) {
+	habit, err := find(ctx, client, userID, habitID)
+	if err != nil {
+		return nil, err
+	}
+	if _, archived := habit.ArchivedAt(); archived {
+		return nil, ErrArchived
+	}
+	if count < 1 {
+		return nil, ErrInvalidCount
+	}
+	now := time.Now()
+	if date.IsZero() {
+		date = now
+	}
+	day := PeriodStart(PeriodDaily, date)
+	if day.After(now) {
+		return nil, ErrFutureDate
+	}
+
+	start := PeriodStart(habit.Period, day)
+	var rows []struct {
+		Awarded int `json:"awarded"`
+	}
+	checkInID := utils.GenerateUUID()
+	result := client.Prisma.QueryRaw(checkInSQL,
+		habit.ID, userID, checkInID, day, count, strings.TrimSpace(note),
+		start, nextPeriod(habit.Period, start), utils.GenerateUUID(), xp.ReasonHabitCompleted,
+	).Tx()
+	if err := client.Prisma.Transaction(lockHabit(client, habit.ID), result).Exec(ctx); err != nil {
+		return nil, err
+	}
+	if err := result.Into(&rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 1 && rows[0].Awarded > 0 {
+		if _, err := xp.SyncLevel(ctx, client, userID); err != nil {
+			return nil, err
+		}
+	}
+	return refresh(ctx, client, habit)
+}
+
+// UndoCheckIn removes a check-in. Dropping below the period's target takes
+// back the XP the period earned. Like CheckIn, the count, delete and
+// reversal happen together under the habit's row lock.
+func UndoCheckIn(ctx context.Context, client *db.PrismaClient, userID, checkInID string) (*Status, error) {
+	checkIn, err := client.HabitCheckIn.FindFirst(
+		db.HabitCheckIn.ID.Equals(checkInID),
+		db.HabitCheckIn.UserID.Equals(userID),
+	).With(
+		db.HabitCheckIn.Habit.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrCheckInNotFound
+		}
+		return nil, err
+	}
+	habit := checkIn.Habit()
+
+	start := PeriodStart(habit.Period, checkIn.Date)
+	var rows []struct {
+		Removed int `json:"removed"`
+		Awarded int `json:"awarded"`
+	}
+	result := client.Prisma.QueryRaw(undoCheckInSQL,
+		checkIn.ID, userID, habit.ID, start, nextPeriod(habit.Period, start),
+		utils.GenerateUUID(), xp.ReasonHabitReopened,
+	).Tx()
+	if err := client.Prisma.Transaction(lockHabit(client, habit.ID), result).Exec(ctx); err != nil {
+		return nil, err
+	}
+	if err := result.Into(&rows); err != nil {
+		return nil, err
+	}
+	if len(rows) != 1 || rows[0].Removed == 0 {
+		// Undone concurrently
+		return nil, ErrCheckInNotFound
+	}
+	if rows[0].Awarded > 0 {
+		if _, err := xp.SyncLevel(ctx, client, userID); err != nil {
+			return nil, err
+		}
+	}
+	return refresh(ctx, client, habit)
+}
+
+// CheckIns returns the habit's check-ins from from to to, inclusive, newest
+// first.
+func CheckIns(ctx context.Context, client *db.PrismaClient, userID, habitID string, from, to time.Time) ([]db.HabitCheckInModel, error) {
+	from, to = PeriodStart(PeriodDaily, from), PeriodStart(PeriodDaily, to)
+	if to.Before(from) || int(to.Sub(from).Hours()/24) >= MaxHeatmapDays {
+		return nil, ErrInvalidRange
+	}
+	if _, err := find(ctx, client, userID, habitID); err != nil {
+		return nil, err
+	}
+	return client.HabitCheckIn.FindMany(
+		db.HabitCheckIn.HabitID.Equals(habitID),
+		db.HabitCheckIn.Date.Gte(from),
+		db.HabitCheckIn.Date.Lte(to),
+	).OrderBy(
+		db.HabitCheckIn.Date.Order(db.SortOrderDesc),
+	).Exec(ctx)
+}
+
+// Heatmap returns the check-ins per day from from to to, inclusive, for one
+// habit or, when habitID is empty, all of the user's habits. Days without
+// check-ins are included with a zero count.
+func Heatmap(ctx context.Context, client *db.PrismaClient, userID, habitID string, from, to time.Time) ([]Day, error) {
+	from, to = PeriodStart(PeriodDaily, from), PeriodStart(PeriodDaily, to)
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days < 1 || days > MaxHeatmapDays {
+		return nil, ErrInvalidRange
+	}
+
+	filters := []db.HabitCheckInWhereParam{
+		db.HabitCheckIn.UserID.Equals(userID),
+		db.HabitCheckIn.Date.Gte(from),
+		db.HabitCheckIn.Date.Lte(to),
+	}
+	if habitID != "" {
+		if _, err := find(ctx, client, userID, habitID); err != nil {
+			return nil, err
+		}
+		filters = append(filters, db.HabitCheckIn.HabitID.Equals(habitID))
+	}
+	checkIns, err := client.HabitCheckIn.FindMany(filters...).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := make([]Day, days)
+	for i := range heatmap {
+		heatmap[i].Date = from.AddDate(0, 0, i)
+	}
+	for _, checkIn := range checkIns {
+		if i := int(checkIn.Date.Sub(from).Hours() / 24); i >= 0 && i < days {
+			heatmap[i].Count += checkIn.Count
+		}
+	}
+	return heatmap, nil
+}
+
+// PeriodStart returns the start of the period containing t: midnight UTC
+// for daily habits, Monday for weekly ones and the first of the month for
+// monthly ones.
+func PeriodStart(period string, t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	switch period {
+	case PeriodWeekly:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case PeriodMonthly:
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// previousPeriod returns the start of the period before the one starting
+// at start.
+func previousPeriod(period string, start time.Time) time.Time {
+	switch period {
+	case PeriodWeekly:
+		return start.AddDate(0, 0, -7)
+	case PeriodMonthly:
+		return start.AddDate(0, -1, 0)
+	}
+	return start.AddDate(0, 0, -1)
+}
+
+// nextPeriod returns the start of the period after the one starting at
+// start.
+func nextPeriod(period string, start time.Time) time.Time {
+	switch period {
+	case PeriodWeekly:
+		return start.AddDate(0, 0, 7)
+	case PeriodMonthly:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// refresh recounts the habit's streaks after a change to its check-ins and
+// returns its current status.
+func refresh(ctx context.Context, client *db.PrismaClient, habit *db.HabitModel) (*Status, error) {
+	checkIns, err := client.HabitCheckIn.FindMany(
+		db.HabitCheckIn.HabitID.Equals(habit.ID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	currentStreak, longestStreak := streaks(habit, checkIns, time.Now())
+	updated, err := client.Habit.FindUnique(
+		db.Habit.ID.Equals(habit.ID),
+	).Update(
+		db.Habit.CurrentStreak.Set(currentStreak),
+		db.Habit.LongestStreak.Set(longestStreak),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := status(*updated, checkIns, time.Now())
+	return &result, nil
+}
+
+// streaks counts the periods in a row with the target reached, up to the
+// current period, and the longest such run. The current period doesn't
+// break the streak before it is over.
+func streaks(habit *db.HabitModel, checkIns []db.HabitCheckInModel, now time.Time) (int, int) {
+	totals := map[time.Time]int{}
+	for _, checkIn := range checkIns {
+		totals[PeriodStart(habit.Period, checkIn.Date)] += checkIn.Count
+	}
+
+	start := PeriodStart(habit.Period, now)
+	if totals[start] < habit.TargetCount {
+		start = previousPeriod(habit.Period, start)
+	}
+	current := 0
+	for ; totals[start] >= habit.TargetCount; start = previousPeriod(habit.Period, start) {
+		current++
+	}
+
+	longest := 0
+	for period, total := range totals {
+		// Count each run once, from its last period backwards.
+		if total < habit.TargetCount {
+			continue
+		}
+		if totals[nextPeriod(habit.Period, period)] >= habit.TargetCount {
+			continue
+		}
+		run := 0
+		for p := period; totals[p] >= habit.TargetCount; p = previousPeriod(habit.Period, p) {
+			run++
+		}
+		longest = max(longest, run)
+	}
+	return current, longest
+}
+
+// status adds the habit's progress in the current period, from checkIns,
+// which may include other habits' check-ins.
+func status(habit db.HabitModel, checkIns []db.HabitCheckInModel, now time.Time) Status {
+	start := PeriodStart(habit.Period, now)
+	count := 0
+	for _, checkIn := range checkIns {
+		if checkIn.HabitID == habit.ID && PeriodStart(habit.Period, checkIn.Date).Equal(start) {
+			count += checkIn.Count
+		}
+	}
+	return Status{
+		HabitModel:  habit,
+		PeriodStart: start,
+		PeriodCount: count,
+		Completed:   count >= habit.TargetCount,
+	}
+}
+
+func current(ctx context.Context, client *db.PrismaClient, habit *db.HabitModel) (*Status, error) {
+	now := time.Now()
+	count, err := periodCount(ctx, client, habit, now)
+	if err != nil {
+		return nil, err
+	}
+	return &Status{
+		HabitModel:  *habit,
+		PeriodStart: PeriodStart(habit.Period, now),
+		PeriodCount: count,
+		Completed:   count >= habit.TargetCount,
+	}, nil
+}
+
+// periodCount sums the habit's check-ins in the period containing t.
+func periodCount(ctx context.Context, client *db.PrismaClient, habit *db.HabitModel, t time.Time) (int, error) {
+	start := PeriodStart(habit.Period, t)
+	checkIns, err := client.HabitCheckIn.FindMany(
+		db.HabitCheckIn.HabitID.Equals(habit.ID),
+		db.HabitCheckIn.Date.Gte(start),
+		db.HabitCheckIn.Date.Lt(nextPeriod(habit.Period, start)),
+	).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, checkIn := range checkIns {
+		total += checkIn.Count
+	}
+	return total, nil
+}
+
+// setParams validates fields and turns them into updates, leaving out the
+// name, which create and update handle differently.
+func setParams(fields Fields) ([]db.HabitSetParam, error) {
+	var params []db.HabitSetParam
+	if fields.Name != nil && strings.TrimSpace(*fields.Name) == "" {
+		return nil, ErrNameRequired
+	}
+	if fields.Description != nil {
+		params = append(params, db.Habit.Description.Set(*fields.Description))
+	}
+	if fields.Icon != nil {
+		params = append(params, db.Habit.Icon.Set(*fields.Icon))
+	}
+	if fields.Color != nil {
+		params = append(params, db.Habit.Color.Set(*fields.Color))
+	}
+	if fields.Category != nil {
+		if !validCategory(*fields.Category) {
+			return nil, ErrInvalidCategory
+		}
+		params = append(params, db.Habit.Category.Set(*fields.Category))
+	}
+	if fields.Period != nil {
+		switch *fields.Period {
+		case PeriodDaily, PeriodWeekly, PeriodMonthly:
+		default:
+			return nil, ErrInvalidPeriod
+		}
+		params = append(params, db.Habit.Period.Set(*fields.Period))
+	}
+	if fields.TargetCount != nil {
+		if *fields.TargetCount < 1 || *fields.TargetCount > maxTarget {
+			return nil, ErrInvalidTarget
+		}
+		params = append(params, db.Habit.TargetCount.Set(*fields.TargetCount))
+	}
+	if fields.XpValue != nil {
+		if *fields.XpValue < 0 || *fields.XpValue > maxXpValue {
+			return nil, ErrInvalidXp
+		}
+		params = append(params, db.Habit.XpValue.Set(*fields.XpValue))
+	}
+	if fields.Archived != nil {
+		if *fields.Archived {
+			params = append(params, db.Habit.ArchivedAt.Set(time.Now()))
+		} else {
+			params = append(params, db.Habit.ArchivedAt.SetOptional(nil))
+		}
+	}
+	return params, nil
+}
+
+func validCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+func find(ctx context.Context, client *db.PrismaClient, userID, id string) (*db.HabitModel, error) {
+	habit, err := client.Habit.FindFirst(
+		db.Habit.ID.Equals(id),
+		db.Habit.UserID.Equals(userID),
+	).Exec(ctx)
+	if db.IsErrNotFound(err) {
+		return nil, ErrNotFound
+	}
+	return habit, err
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/attachments"
+	"lifequest-server/internal/blob"
+	"lifequest-server/internal/database"
+)
+
+// Attachment handlers
+func GetTaskAttachments(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	list, err := attachments.List(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(attachmentErrorStatus(err), gin.H{"error": attachmentErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// UploadTaskAttachment attaches the multipart "file" field to the task. The
+// response includes a signed download URL.
+func UploadTaskAttachment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	// Leave room for the multipart framing around the file itself.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, attachments.MaxSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": attachments.ErrTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file is required in the \"file\" field"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	attachment, err := attachments.Upload(ctx, client, userID.(string), c.Param("id"), header.Filename, file)
+	if err != nil {
+		c.JSON(attachmentErrorStatus(err), gin.H{"error": attachmentErrorMessage(err, "Failed to upload attachment")})
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// GetTaskAttachment returns the attachment with a fresh download URL.
+func GetTaskAttachment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	attachment, err := attachments.Get(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(attachmentErrorStatus(err), gin.H{"error": attachmentErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+func DeleteTaskAttachment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := attachments.Delete(ctx, client, userID.(string), c.Param("id")); err != nil {
+		c.JSON(attachmentErrorStatus(err), gin.H{"error": attachmentErrorMessage(err, "Failed to delete attachment")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
+
+// DownloadBlob serves a file from the local blob store to holders of a
+// signed link. Stores that sign their own URLs, such as S3, never link
+// here.
+func DownloadBlob(c *gin.Context) {
+	store, err := blob.Default()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Blob storage is not configured"})
+		return
+	}
+	local, ok := store.(*blob.LocalStore)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	filename := c.Query("filename")
+	if !local.Verify(key, filename, c.Query("expires"), c.Query("signature"), time.Now()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid or expired download link"})
+		return
+	}
+
+	path, err := local.Path(key)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+		return
+	}
+
+	// Always download, never render: the content comes from users.
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Disposition", blob.ContentDisposition(filename))
+	c.File(path)
+}
+
+func attachmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, attachments.ErrTaskNotFound), errors.Is(err, attachments.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, attachments.ErrEmpty):
+		return http.StatusBadRequest
+	case errors.Is(err, attachments.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, attachments.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, attachments.ErrQuotaExceeded):
+		return http.StatusInsufficientStorage
+	}
+	return http.StatusInternalServerError
+}
+
+// attachmentErrorMessage hides database and storage errors behind fallback.
+func attachmentErrorMessage(err error, fallback string) string {
+	if attachmentErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
@@ -9,6 +9,8 @@ import (
 
 	"lifequest-server/internal/database"
 	"lifequest-server/internal/db"
+	"lifequest-server/internal/folders"
+	"lifequest-server/internal/pagination"
 )
 
 // Auth handlers
@@ -92,19 +94,27 @@ func GetFolders(c *gin.Context) {
 		return
 	}
 
+	args, ok := pageArgs(c)
+	if !ok {
+		return
+	}
+
 	ctx := context.Background()
 	client := database.GetClient()
 
-	folders, err := client.Folder.FindMany(
-		db.Folder.UserID.Equals(userID.(string)),
-	).Exec(ctx)
+	page, err := pagination.Fetch(pagination.Folders, args, func(where []db.FolderWhereParam, order []db.FolderOrderByParam, take int) ([]db.FolderModel, error) {
+		return client.Folder.FindMany(append([]db.FolderWhereParam{
+			db.Folder.UserID.Equals(userID.(string)),
+			db.Folder.DeletedAt.IsNull(),
+		}, where...)...).OrderBy(order...).Take(take).Exec(ctx)
+	})
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		c.JSON(pageErrorStatus(err), gin.H{"error": pageErrorMessage(err, "Database error")})
 		return
 	}
 
-	c.JSON(http.StatusOK, folders)
+	c.JSON(http.StatusOK, pageResponse(page))
 }
 
 func CreateFolder(c *gin.Context) {
@@ -119,6 +129,7 @@ func CreateFolder(c *gin.Context) {
 		Description *string `json:"description"`
 		Color       *string `json:"color"`
 		Icon        *string `json:"icon"`
+		ParentID    *string `json:"parentId"`
 	}
 
 	if err := c.ShouldBindJSON(&folderData); err != nil {
@@ -131,6 +142,20 @@ func CreateFolder(c *gin.Context) {
 
 	params := []db.FolderSetParam{}
 
+	if folderData.ParentID != nil && *folderData.ParentID != "" {
+		forest, err := folders.Load(ctx, client, userID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		if err := forest.CheckParent("", *folderData.ParentID); err != nil {
+			c.JSON(folderTreeErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		params = append(params, db.Folder.Parent.Link(
+			db.Folder.ID.Equals(*folderData.ParentID),
+		))
+	}
 	if folderData.Description != nil {
 		params = append(params, db.Folder.Description.SetOptional(folderData.Description))
 	}
@@ -171,8 +196,11 @@ func GetFolder(c *gin.Context) {
 	folder, err := client.Folder.FindFirst(
 		db.Folder.ID.Equals(folderID),
 		db.Folder.UserID.Equals(userID.(string)),
+		db.Folder.DeletedAt.IsNull(),
 	).With(
-		db.Folder.Projects.Fetch(),
+		db.Folder.Projects.Fetch(
+			db.Project.DeletedAt.IsNull(),
+		),
 	).Exec(ctx)
 
 	if err != nil {
@@ -201,6 +229,7 @@ func UpdateFolder(c *gin.Context) {
 		Description *string `json:"description"`
 		Color       *string `json:"color"`
 		Icon        *string `json:"icon"`
+		IsArchived  *bool   `json:"isArchived"`
 	}
 
 	if err := c.ShouldBindJSON(&updateData); err != nil {
@@ -227,10 +256,18 @@ func UpdateFolder(c *gin.Context) {
 	if updateData.Icon != nil {
 		params = append(params, db.Folder.Icon.Set(*updateData.Icon))
 	}
+	if updateData.IsArchived != nil {
+		if *updateData.IsArchived {
+			params = append(params, db.Folder.ArchivedAt.Set(time.Now()))
+		} else {
+			params = append(params, db.Folder.ArchivedAt.SetOptional(nil))
+		}
+	}
 
 	result, err := client.Folder.FindMany(
 		db.Folder.ID.Equals(folderID),
 		db.Folder.UserID.Equals(userID.(string)),
+		db.Folder.DeletedAt.IsNull(),
 	).Update(params...).Exec(ctx)
 
 	if err != nil {
@@ -257,6 +294,9 @@ func UpdateFolder(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedFolder)
 }
 
+// DeleteFolder deletes a folder in one of the folders.Delete* modes, given
+// as ?mode=; the default refuses to delete folders that are not empty. In
+// move mode ?targetFolderId= receives the contents.
 func DeleteFolder(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -265,23 +305,19 @@ func DeleteFolder(c *gin.Context) {
 	}
 
 	folderID := c.Param("id")
+	mode := c.DefaultQuery("mode", folders.DeleteRefuse)
 	ctx := context.Background()
 	client := database.GetClient()
 
-	result, err := client.Folder.FindMany(
-		db.Folder.ID.Equals(folderID),
-		db.Folder.UserID.Equals(userID.(string)),
-	).Delete().Exec(ctx)
-
+	err := folders.Delete(ctx, client, userID.(string), folderID, mode, c.Query("targetFolderId"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete folder"})
+		c.JSON(folderTreeErrorStatus(err), gin.H{"error": folderTreeErrorMessage(err, "Failed to delete folder")})
 		return
 	}
 
-	if result.Count == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
+	if mode == folders.DeleteArchive {
+		c.JSON(http.StatusOK, gin.H{"message": "Folder archived successfully"})
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Folder deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Folder moved to trash"})
 }
\ No newline at end of file
@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/ical"
+	"lifequest-server/internal/webhooks"
+)
+
+const (
+	calendarFeedPath = "/api/calendar/feed/"
+
+	// calendarSessionWindow bounds how far back focus sessions are exported,
+	// so feeds of long-time users stay small enough for calendar clients.
+	calendarSessionWindow = 90 * 24 * time.Hour
+)
+
+// Calendar feed entry kinds selectable with ?include=.
+const (
+	calendarIncludeTasks    = "tasks"
+	calendarIncludeSprints  = "sprints"
+	calendarIncludeSessions = "sessions"
+)
+
+// Calendar feed handlers
+func GetCalendarSubscription(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	user, err := client.User.FindUnique(
+		db.User.ID.Equals(userID.(string)),
+	).Exec(ctx)
+
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	token, ok := user.CalendarToken()
+	if !ok {
+		c.JSON(http.StatusOK, gin.H{"token": nil, "feedPath": nil})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token, "feedPath": calendarFeedPath + token + ".ics"})
+}
+
+// RotateCalendarToken issues a new feed token, revoking the previous URL.
+func RotateCalendarToken(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	token, err := webhooks.GenerateSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	_, err = client.User.FindUnique(
+		db.User.ID.Equals(userID.(string)),
+	).Update(
+		db.User.CalendarToken.Set(token),
+		db.User.UpdatedAt.Set(time.Now()),
+	).Exec(ctx)
+
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token, "feedPath": calendarFeedPath + token + ".ics"})
+}
+
+func RevokeCalendarToken(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	_, err := client.User.FindUnique(
+		db.User.ID.Equals(userID.(string)),
+	).Update(
+		db.User.CalendarToken.SetOptional(nil),
+		db.User.UpdatedAt.Set(time.Now()),
+	).Exec(ctx)
+
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar feed revoked successfully"})
+}
+
+// ServeCalendarFeed renders the user's .ics feed. It is public: the token in
+// the path is the only credential, which is what calendar clients support.
+func ServeCalendarFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	if token == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Calendar not found"})
+		return
+	}
+
+	include := map[string]bool{
+		calendarIncludeTasks:    true,
+		calendarIncludeSprints:  true,
+		calendarIncludeSessions: true,
+	}
+	if value := c.Query("include"); value != "" {
+		include = map[string]bool{}
+		for _, kind := range strings.Split(value, ",") {
+			kind = strings.TrimSpace(kind)
+			switch kind {
+			case calendarIncludeTasks, calendarIncludeSprints, calendarIncludeSessions:
+				include[kind] = true
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "include must list tasks, sprints or sessions"})
+				return
+			}
+		}
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	user, err := client.User.FindUnique(
+		db.User.CalendarToken.Equals(token),
+	).Exec(ctx)
+
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Calendar not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	projectID := c.Query("projectId")
+	folderID := c.Query("folderId")
+	now := time.Now()
+	cal := &ical.Calendar{Name: "LifeQuest"}
+
+	if include[calendarIncludeTasks] {
+		filters := []db.TaskWhereParam{
+			db.Task.UserID.Equals(user.ID),
+			db.Task.Not(db.Task.DueDate.IsNull()),
+			db.Task.DeletedAt.IsNull(),
+		}
+		if projectID != "" {
+			filters = append(filters, db.Task.ProjectID.Equals(projectID))
+		}
+		if folderID != "" {
+			filters = append(filters, db.Task.Project.Where(db.Project.FolderID.Equals(folderID)))
+		}
+
+		tasks, err := client.Task.FindMany(filters...).With(
+			db.Task.Project.Fetch(),
+		).OrderBy(
+			db.Task.DueDate.Order(db.SortOrderAsc),
+		).Exec(ctx)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+
+		for _, task := range tasks {
+			cal.Todos = append(cal.Todos, taskTodo(&task, now))
+		}
+	}
+
+	if include[calendarIncludeSprints] {
+		filters := []db.SprintWhereParam{
+			db.Sprint.UserID.Equals(user.ID),
+			db.Sprint.DeletedAt.IsNull(),
+		}
+		if projectID != "" {
+			filters = append(filters, db.Sprint.SprintTasks.Some(
+				db.SprintTask.Task.Where(db.Task.ProjectID.Equals(projectID)),
+			))
+		}
+		if folderID != "" {
+			filters = append(filters, db.Sprint.SprintTasks.Some(
+				db.SprintTask.Task.Where(db.Task.Project.Where(db.Project.FolderID.Equals(folderID))),
+			))
+		}
+
+		sprints, err := client.Sprint.FindMany(filters...).OrderBy(
+			db.Sprint.StartDate.Order(db.SortOrderAsc),
+		).Exec(ctx)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+
+		for _, sprint := range sprints {
+			cal.Events = append(cal.Events, sprintEvent(&sprint, now))
+		}
+	}
+
+	if include[calendarIncludeSessions] {
+		filters := []db.PomodoroSessionWhereParam{
+			db.PomodoroSession.UserID.Equals(user.ID),
+			db.PomodoroSession.Status.Equals("completed"),
+			db.PomodoroSession.StartTime.Gte(now.Add(-calendarSessionWindow)),
+			db.PomodoroSession.DeletedAt.IsNull(),
+		}
+		if projectID != "" {
+			filters = append(filters, db.PomodoroSession.ProjectID.Equals(projectID))
+		}
+		if folderID != "" {
+			filters = append(filters, db.PomodoroSession.Project.Where(db.Project.FolderID.Equals(folderID)))
+		}
+
+		sessions, err := client.PomodoroSession.FindMany(filters...).With(
+			db.PomodoroSession.Task.Fetch(),
+		).OrderBy(
+			db.PomodoroSession.StartTime.Order(db.SortOrderAsc),
+		).Exec(ctx)
+
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+
+		for _, session := range sessions {
+			cal.Events = append(cal.Events, sessionEvent(&session, now))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := cal.Write(&buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render calendar"})
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="lifequest.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", buf.Bytes())
+}
+
+func taskTodo(task *db.TaskModel, stamp time.Time) ical.Todo {
+	due, _ := task.DueDate()
+	todo := ical.Todo{
+		UID:     "task-" + task.ID + "@lifequest",
+		Summary: task.Title,
+		Due:     due,
+		Status:  ical.TodoNeedsAction,
+		Stamp:   stamp,
+	}
+	if description, ok := task.Description(); ok {
+		todo.Description = description
+	}
+	if project := task.Project(); project != nil {
+		todo.Categories = []string{project.Name}
+	}
+
+	switch task.Status {
+	case taskStatusCompleted:
+		todo.Status = ical.TodoCompleted
+		if completedAt, ok := task.CompletedAt(); ok {
+			todo.Completed = &completedAt
+		}
+	case "in-progress", taskStatusInReview:
+		todo.Status = ical.TodoInProcess
+	}
+
+	switch task.Priority {
+	case "high":
+		todo.Priority = 1
+	case "medium":
+		todo.Priority = 5
+	case "low":
+		todo.Priority = 9
+	}
+
+	return todo
+}
+
+func sprintEvent(sprint *db.SprintModel, stamp time.Time) ical.Event {
+	event := ical.Event{
+		UID:     "sprint-" + sprint.ID + "@lifequest",
+		Summary: "Sprint: " + sprint.Name,
+		// Sprints cover whole days; DTEND is exclusive, so end the day after.
+		Start:      sprint.StartDate,
+		End:        sprint.EndDate.AddDate(0, 0, 1),
+		AllDay:     true,
+		Stamp:      stamp,
+		Categories: []string{"Sprint"},
+	}
+	if description, ok := sprint.Description(); ok {
+		event.Description = description
+	}
+	return event
+}
+
+func sessionEvent(session *db.PomodoroSessionModel, stamp time.Time) ical.Event {
+	end, ok := session.EndTime()
+	if !ok {
+		end = session.StartTime.Add(time.Duration(session.Duration) * time.Minute)
+	}
+
+	summary := "Focus session"
+	if session.Type != "work" {
+		summary = "Break"
+	}
+	if task, ok := session.Task(); ok {
+		summary += ": " + task.Title
+	}
+
+	return ical.Event{
+		UID:        "session-" + session.ID + "@lifequest",
+		Summary:    summary,
+		Start:      session.StartTime,
+		End:        end,
+		Stamp:      stamp,
+		Categories: []string{"Pomodoro"},
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/comments"
+	"lifequest-server/internal/database"
+)
+
+// Comment handlers
+func GetTaskComments(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	list, err := comments.List(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": commentErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// CreateTaskComment adds a Markdown comment to the task. Project members
+// mentioned as @name or @email are notified.
+func CreateTaskComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var commentData struct {
+		Body string `json:"body" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&commentData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	comment, err := comments.Create(ctx, client, userID.(string), c.Param("id"), commentData.Body)
+	if err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": commentErrorMessage(err, "Failed to create comment")})
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// UpdateTaskComment replaces the body of a comment; the previous body is
+// kept in its history.
+func UpdateTaskComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var commentData struct {
+		Body string `json:"body" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&commentData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	comment, err := comments.Edit(ctx, client, userID.(string), c.Param("id"), commentData.Body)
+	if err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": commentErrorMessage(err, "Failed to update comment")})
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+func DeleteTaskComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := comments.Delete(ctx, client, userID.(string), c.Param("id")); err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": commentErrorMessage(err, "Failed to delete comment")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+func commentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, comments.ErrTaskNotFound), errors.Is(err, comments.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, comments.ErrEmpty), errors.Is(err, comments.ErrTooLong):
+		return http.StatusBadRequest
+	case errors.Is(err, comments.ErrNotAuthor), errors.Is(err, comments.ErrForbidden), errors.Is(err, comments.ErrReadOnly):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// commentErrorMessage hides database errors behind fallback.
+func commentErrorMessage(err error, fallback string) string {
+	if commentErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
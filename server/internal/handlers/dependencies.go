@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/tasks"
+)
+
+// Dependency handlers
+
+// GetTaskDependencies lists the tasks the task depends on and the tasks
+// that depend on it.
+func GetTaskDependencies(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	graph, err := tasks.LoadGraph(ctx, client, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	taskID := c.Param("id")
+	if graph.Task(taskID) == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dependencies": graph.Blockers(taskID),
+		"dependents":   graph.Dependents(taskID),
+		"isBlocked":    graph.IsBlocked(taskID),
+	})
+}
+
+func AddTaskDependency(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var dependencyData struct {
+		DependsOnID string `json:"dependsOnId" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&dependencyData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := tasks.AddDependency(ctx, client, userID.(string), c.Param("id"), dependencyData.DependsOnID); err != nil {
+		c.JSON(dependencyErrorStatus(err), gin.H{"error": dependencyErrorMessage(err, "Failed to add dependency")})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Dependency added successfully"})
+}
+
+func RemoveTaskDependency(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := tasks.RemoveDependency(ctx, client, userID.(string), c.Param("id"), c.Param("dependsOnId")); err != nil {
+		c.JSON(dependencyErrorStatus(err), gin.H{"error": dependencyErrorMessage(err, "Failed to remove dependency")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Dependency removed successfully"})
+}
+
+// GetProjectCriticalPath returns the longest chain of open tasks in the
+// project by estimated pomodoros.
+func GetProjectCriticalPath(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	project, err := client.Project.FindFirst(
+		db.Project.ID.Equals(c.Param("id")),
+		db.Project.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err == nil {
+		err = authz.Authorize(ctx, client, userID.(string), authz.ActionRead, authz.ProjectResource(project))
+	}
+
+	if err != nil {
+		if db.IsErrNotFound(err) || errors.Is(err, authz.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	graph, err := tasks.LoadGraph(ctx, client, project.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, graph.CriticalPath(project.ID))
+}
+
+func dependencyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, tasks.ErrTaskNotFound), errors.Is(err, tasks.ErrDependencyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, tasks.ErrSelfDependency):
+		return http.StatusBadRequest
+	case errors.Is(err, tasks.ErrDependencyCycle), errors.Is(err, tasks.ErrDependencyExists):
+		return http.StatusConflict
+	case errors.Is(err, tasks.ErrForbidden):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// dependencyErrorMessage hides database errors behind fallback.
+func dependencyErrorMessage(err error, fallback string) string {
+	if dependencyErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
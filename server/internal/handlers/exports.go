@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/export"
+)
+
+// dataExportResponse adds a signed download link to finished exports.
+type dataExportResponse struct {
+	*db.DataExportModel
+	DownloadURL          *string    `json:"downloadUrl,omitempty"`
+	DownloadURLExpiresAt *time.Time `json:"downloadUrlExpiresAt,omitempty"`
+}
+
+// Data export handlers
+func GetDataExports(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	exports, err := client.DataExport.FindMany(
+		db.DataExport.UserID.Equals(userID.(string)),
+	).OrderBy(
+		db.DataExport.CreatedAt.Order(db.SortOrderDesc),
+	).Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, exports)
+}
+
+// CreateDataExport starts building an archive in the background. Poll
+// GetDataExport until it is ready to get a download link.
+func CreateDataExport(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	// One export at a time per user; a pending export past the timeout was
+	// lost with the process running it, so it no longer counts
+	pending, err := client.DataExport.FindFirst(
+		db.DataExport.UserID.Equals(userID.(string)),
+		db.DataExport.Status.Equals(export.StatusPending),
+		db.DataExport.CreatedAt.Gt(time.Now().Add(-export.Timeout)),
+	).Exec(ctx)
+
+	if err == nil {
+		c.JSON(http.StatusAccepted, pending)
+		return
+	}
+	if !db.IsErrNotFound(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	created, err := client.DataExport.CreateOne(
+		db.DataExport.User.Link(
+			db.User.ID.Equals(userID.(string)),
+		),
+	).Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create export"})
+		return
+	}
+
+	go export.Run(context.Background(), client, created)
+
+	c.JSON(http.StatusAccepted, created)
+}
+
+func GetDataExport(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	found, err := client.DataExport.FindFirst(
+		db.DataExport.ID.Equals(c.Param("id")),
+		db.DataExport.UserID.Equals(userID.(string)),
+	).Exec(ctx)
+
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	response := dataExportResponse{DataExportModel: found}
+	if found.Status == export.StatusReady {
+		path, expiresAt := export.SignedPath(found.ID, time.Now())
+		response.DownloadURL = &path
+		response.DownloadURLExpiresAt = &expiresAt
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DownloadDataExport serves a finished archive. It is public: the signed,
+// short-lived query string issued by GetDataExport is the credential.
+func DownloadDataExport(c *gin.Context) {
+	exportID := c.Param("id")
+	if !export.VerifyLink(exportID, c.Query("expires"), c.Query("signature"), time.Now()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid or expired download link"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	found, err := client.DataExport.FindUnique(
+		db.DataExport.ID.Equals(exportID),
+	).Exec(ctx)
+
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	path := export.Path(found.ID)
+	if found.Status != export.StatusReady {
+		c.JSON(http.StatusGone, gin.H{"error": "Export is no longer available"})
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		c.JSON(http.StatusGone, gin.H{"error": "Export is no longer available"})
+		return
+	}
+
+	c.FileAttachment(path, "lifequest-export-"+found.CreatedAt.Format("2006-01-02")+".zip")
+}
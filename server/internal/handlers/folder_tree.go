@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/folders"
+)
+
+// Folder tree handlers
+func GetFolderTree(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", strconv.Itoa(folders.DefaultFetchDepth)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": folders.ErrInvalidDepth.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	forest, err := folders.Load(ctx, client, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	tree, err := forest.Tree(c.Query("parentId"), depth)
+	if err != nil {
+		c.JSON(folderTreeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+func GetFolderPath(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	forest, err := folders.Load(ctx, client, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	path, err := forest.Path(c.Param("id"))
+	if err != nil {
+		c.JSON(folderTreeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, path)
+}
+
+// MoveFolder re-parents a folder; a null or empty parentId moves it to the
+// top level.
+func MoveFolder(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var moveData struct {
+		ParentID *string `json:"parentId"`
+	}
+
+	if err := c.ShouldBindJSON(&moveData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	parentID := ""
+	if moveData.ParentID != nil {
+		parentID = *moveData.ParentID
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	folder, err := folders.Move(ctx, client, userID.(string), c.Param("id"), parentID)
+	if err != nil {
+		c.JSON(folderTreeErrorStatus(err), gin.H{"error": folderTreeErrorMessage(err, "Failed to move folder")})
+		return
+	}
+
+	c.JSON(http.StatusOK, folder)
+}
+
+func MoveProject(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var moveData struct {
+		FolderID string `json:"folderId" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&moveData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	project, err := folders.MoveProject(ctx, client, userID.(string), c.Param("id"), moveData.FolderID)
+	if err != nil {
+		c.JSON(folderTreeErrorStatus(err), gin.H{"error": folderTreeErrorMessage(err, "Failed to move project")})
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func folderTreeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, folders.ErrNotFound), errors.Is(err, folders.ErrProjectNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, folders.ErrParentMissing), errors.Is(err, folders.ErrInvalidDepth),
+		errors.Is(err, folders.ErrUnknownMode), errors.Is(err, folders.ErrTargetRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, folders.ErrCycle), errors.Is(err, folders.ErrTooDeep), errors.Is(err, folders.ErrNotEmpty):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// folderTreeErrorMessage hides database errors behind fallback.
+func folderTreeErrorMessage(err error, fallback string) string {
+	if folderTreeErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/analytics"
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/habits"
+)
+
+// Habit handlers
+
+// habitFields is the request body for creating and updating habits.
+type habitFields struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Icon        *string `json:"icon"`
+	Color       *string `json:"color"`
+	Category    *string `json:"category"`
+	Period      *string `json:"period"`
+	TargetCount *int    `json:"targetCount"`
+	XpValue     *int    `json:"xpValue"`
+	Archived    *bool   `json:"archived"`
+}
+
+func (f habitFields) fields() habits.Fields {
+	return habits.Fields{
+		Name:        f.Name,
+		Description: f.Description,
+		Icon:        f.Icon,
+		Color:       f.Color,
+		Category:    f.Category,
+		Period:      f.Period,
+		TargetCount: f.TargetCount,
+		XpValue:     f.XpValue,
+		Archived:    f.Archived,
+	}
+}
+
+// GetHabits lists the user's habits with their progress in the current
+// period; ?archived=true includes archived ones.
+func GetHabits(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	list, err := habits.List(ctx, client, userID.(string), c.Query("archived") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+func CreateHabit(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var habitData habitFields
+	if err := c.ShouldBindJSON(&habitData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	habit, err := habits.Create(ctx, client, userID.(string), habitData.fields())
+	if err != nil {
+		c.JSON(habitErrorStatus(err), gin.H{"error": habitErrorMessage(err, "Failed to create habit")})
+		return
+	}
+
+	c.JSON(http.StatusCreated, habit)
+}
+
+func GetHabit(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	habit, err := habits.Get(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(habitErrorStatus(err), gin.H{"error": habitErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, habit)
+}
+
+func UpdateHabit(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var habitData habitFields
+	if err := c.ShouldBindJSON(&habitData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	habit, err := habits.Update(ctx, client, userID.(string), c.Param("id"), habitData.fields())
+	if err != nil {
+		c.JSON(habitErrorStatus(err), gin.H{"error": habitErrorMessage(err, "Failed to update habit")})
+		return
+	}
+
+	c.JSON(http.StatusOK, habit)
+}
+
+// DeleteHabit removes the habit with its check-ins; XP it earned is kept.
+func DeleteHabit(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := habits.Delete(ctx, client, userID.(string), c.Param("id")); err != nil {
+		c.JSON(habitErrorStatus(err), gin.H{"error": habitErrorMessage(err, "Failed to delete habit")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Habit deleted successfully"})
+}
+
+// CheckInHabit records check-ins for today, or for an earlier date given as
+// YYYY-MM-DD.
+func CheckInHabit(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var checkInData struct {
+		Count *int   `json:"count"`
+		Date  string `json:"date"`
+		Note  string `json:"note"`
+	}
+
+	if err := c.ShouldBindJSON(&checkInData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	count := 1
+	if checkInData.Count != nil {
+		count = *checkInData.Count
+	}
+	var date time.Time
+	if checkInData.Date != "" {
+		parsed, err := time.Parse(time.DateOnly, checkInData.Date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+			return
+		}
+		date = parsed
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	habit, err := habits.CheckIn(ctx, client, userID.(string), c.Param("id"), count, date, checkInData.Note)
+	if err != nil {
+		c.JSON(habitErrorStatus(err), gin.H{"error": habitErrorMessage(err, "Failed to check in")})
+		return
+	}
+
+	c.JSON(http.StatusCreated, habit)
+}
+
+// GetHabitCheckIns lists the habit's check-ins between ?from and ?to, the
+// last 30 days by default.
+func GetHabitCheckIns(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	from, to, ok := dateRange(c, 30)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	checkIns, err := habits.CheckIns(ctx, client, userID.(string), c.Param("id"), from, to)
+	if err != nil {
+		c.JSON(habitErrorStatus(err), gin.H{"error": habitErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, checkIns)
+}
+
+// UndoHabitCheckIn removes a check-in and returns its habit.
+func UndoHabitCheckIn(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	habit, err := habits.UndoCheckIn(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(habitErrorStatus(err), gin.H{"error": habitErrorMessage(err, "Failed to undo check-in")})
+		return
+	}
+
+	c.JSON(http.StatusOK, habit)
+}
+
+// GetHabitHeatmap returns check-ins per day between ?from and ?to, the last
+// year by default, for all habits or the one given by ?habitId.
+func GetHabitHeatmap(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	from, to, ok := dateRange(c, habits.MaxHeatmapDays)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	heatmap, err := habits.Heatmap(ctx, client, userID.(string), c.Query("habitId"), from, to)
+	if err != nil {
+		c.JSON(habitErrorStatus(err), gin.H{"error": habitErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, heatmap)
+}
+
+// GetAnalytics returns daily, weekly and monthly statistics between ?from
+// and ?to, the last 30 days by default.
+func GetAnalytics(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	from, to, ok := dateRange(c, 30)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	report, err := analytics.Load(ctx, client, userID.(string), from, to)
+	if err != nil {
+		if errors.Is(err, analytics.ErrInvalidRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// dateRange reads ?from and ?to as YYYY-MM-DD, defaulting to the days days
+// ending today. It responds with 400 and returns false on malformed dates.
+func dateRange(c *gin.Context, days int) (time.Time, time.Time, bool) {
+	to := time.Now().UTC()
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be YYYY-MM-DD"})
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, 1-days)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be YYYY-MM-DD"})
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+	return from, to, true
+}
+
+func habitErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, habits.ErrNotFound), errors.Is(err, habits.ErrCheckInNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, habits.ErrArchived):
+		return http.StatusConflict
+	case errors.Is(err, habits.ErrNameRequired), errors.Is(err, habits.ErrInvalidPeriod),
+		errors.Is(err, habits.ErrInvalidCategory), errors.Is(err, habits.ErrInvalidTarget),
+		errors.Is(err, habits.ErrInvalidXp), errors.Is(err, habits.ErrInvalidCount),
+		errors.Is(err, habits.ErrFutureDate), errors.Is(err, habits.ErrInvalidRange):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// habitErrorMessage hides database errors behind fallback.
+func habitErrorMessage(err error, fallback string) string {
+	if habitErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/notifications"
+)
+
+// Notification handlers
+func GetNotifications(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	unreadOnly, err := strconv.ParseBool(c.DefaultQuery("unreadOnly", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unreadOnly must be true or false"})
+		return
+	}
+	args, ok := pageArgs(c)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	page, err := notifications.List(ctx, client, userID.(string), unreadOnly, args)
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{"error": pageErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, pageResponse(page))
+}
+
+func MarkNotificationRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	notification, err := notifications.MarkRead(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, notifications.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+func MarkAllNotificationsRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := notifications.MarkAllRead(ctx, client, userID.(string)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notifications marked as read"})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/pagination"
+)
+
+// pageArgs reads the ?limit, ?cursor, ?sort and ?order=asc|desc parameters
+// of a list endpoint, answering 400 if they are malformed.
+func pageArgs(c *gin.Context) (pagination.Args, bool) {
+	args := pagination.Args{
+		After: c.Query("cursor"),
+		Sort:  c.Query("sort"),
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": pagination.ErrInvalidLimit.Error()})
+			return args, false
+		}
+		args.First = n
+	}
+	switch order := c.Query("order"); order {
+	case "":
+	case "asc", "desc":
+		descending := order == "desc"
+		args.Descending = &descending
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+		return args, false
+	}
+	return args, true
+}
+
+// pageResponse is the body of a list endpoint: the page's rows and the
+// cursor to pass as ?cursor for the next page.
+func pageResponse[T any](page *pagination.Page[T]) gin.H {
+	pageInfo := gin.H{"hasNextPage": page.HasNextPage}
+	if cursor := page.EndCursor(); cursor != "" {
+		pageInfo["endCursor"] = cursor
+	}
+	return gin.H{"items": page.Rows, "pageInfo": pageInfo}
+}
+
+func pageErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, pagination.ErrInvalidCursor), errors.Is(err, pagination.ErrInvalidLimit),
+		errors.Is(err, pagination.ErrInvalidSort):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// pageErrorMessage hides database errors behind fallback.
+func pageErrorMessage(err error, fallback string) string {
+	if pageErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
@@ -8,10 +8,6 @@ import (
 
 // Placeholder handlers - will be implemented with Prisma later
 
-func GetProjects(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Projects endpoint - coming soon"})
-}
-
 func CreateProject(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Create project endpoint - coming soon"})
 }
@@ -24,14 +20,6 @@ func UpdateProject(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Update project endpoint - coming soon"})
 }
 
-func DeleteProject(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Delete project endpoint - coming soon"})
-}
-
-func GetTasks(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Tasks endpoint - coming soon"})
-}
-
 func CreateTask(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Create task endpoint - coming soon"})
 }
@@ -44,18 +32,6 @@ func UpdateTask(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Update task endpoint - coming soon"})
 }
 
-func DeleteTask(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Delete task endpoint - coming soon"})
-}
-
-func CompleteTask(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Complete task endpoint - coming soon"})
-}
-
-func GetPomodoroSessions(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Pomodoro sessions endpoint - coming soon"})
-}
-
 func CreatePomodoroSession(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Create pomodoro session endpoint - coming soon"})
 }
@@ -64,14 +40,6 @@ func UpdatePomodoroSession(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Update pomodoro session endpoint - coming soon"})
 }
 
-func CompletePomodoroSession(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Complete pomodoro session endpoint - coming soon"})
-}
-
-func GetSprints(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Sprints endpoint - coming soon"})
-}
-
 func CreateSprint(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Create sprint endpoint - coming soon"})
 }
@@ -84,10 +52,6 @@ func UpdateSprint(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Update sprint endpoint - coming soon"})
 }
 
-func DeleteSprint(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"message": "Delete sprint endpoint - coming soon"})
-}
-
 func AddTaskToSprint(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Add task to sprint endpoint - coming soon"})
 }
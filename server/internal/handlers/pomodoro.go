@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/pagination"
+	"lifequest-server/internal/webhooks"
+)
+
+// Pomodoro session values stored on PomodoroSession.type and .status.
+const (
+	sessionTypeWork        = "work"
+	sessionStatusCompleted = "completed"
+)
+
+// Pomodoro session handlers
+
+// GetPomodoroSessions lists a page of the user's sessions, optionally only
+// those started on ?date=YYYY-MM-DD (UTC); see pageArgs for the other
+// parameters.
+func GetPomodoroSessions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	args, ok := pageArgs(c)
+	if !ok {
+		return
+	}
+
+	filters := []db.PomodoroSessionWhereParam{
+		db.PomodoroSession.UserID.Equals(userID.(string)),
+		db.PomodoroSession.DeletedAt.IsNull(),
+	}
+	if date := c.Query("date"); date != "" {
+		day, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+			return
+		}
+		filters = append(filters,
+			db.PomodoroSession.StartTime.Gte(day),
+			db.PomodoroSession.StartTime.Lt(day.AddDate(0, 0, 1)),
+		)
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	page, err := pagination.Fetch(pagination.PomodoroSessions, args, func(where []db.PomodoroSessionWhereParam, order []db.PomodoroSessionOrderByParam, take int) ([]db.PomodoroSessionModel, error) {
+		return client.PomodoroSession.FindMany(append(filters, where...)...).OrderBy(order...).Take(take).Exec(ctx)
+	})
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{"error": pageErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, pageResponse(page))
+}
+
+func CompletePomodoroSession(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	sessionID := c.Param("id")
+	ctx := context.Background()
+	client := database.GetClient()
+
+	session, err := client.PomodoroSession.FindFirst(
+		db.PomodoroSession.ID.Equals(sessionID),
+		db.PomodoroSession.UserID.Equals(userID.(string)),
+		db.PomodoroSession.DeletedAt.IsNull(),
+	).Exec(ctx)
+
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	if session.Status == sessionStatusCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Session already completed"})
+		return
+	}
+
+	updated, err := client.PomodoroSession.FindUnique(
+		db.PomodoroSession.ID.Equals(session.ID),
+	).Update(
+		db.PomodoroSession.Status.Set(sessionStatusCompleted),
+		db.PomodoroSession.EndTime.Set(time.Now()),
+	).Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete session"})
+		return
+	}
+
+	if taskID, ok := updated.TaskID(); ok && updated.Type == sessionTypeWork {
+		_, err = client.Task.FindUnique(
+			db.Task.ID.Equals(taskID),
+		).Update(
+			db.Task.ActualPomodoros.Increment(1),
+		).Exec(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
+			return
+		}
+	}
+
+	webhooks.Publish(userID.(string), webhooks.EventSessionCompleted, updated)
+
+	c.JSON(http.StatusOK, updated)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/pagination"
+)
+
+// Project handlers
+
+// GetProjects lists a page of the projects the user owns or joined; see
+// pageArgs for the parameters.
+func GetProjects(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	args, ok := pageArgs(c)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	page, err := pagination.Fetch(pagination.Projects, args, func(where []db.ProjectWhereParam, order []db.ProjectOrderByParam, take int) ([]db.ProjectModel, error) {
+		return client.Project.FindMany(append([]db.ProjectWhereParam{
+			authz.MemberOf(userID.(string)),
+			db.Project.DeletedAt.IsNull(),
+		}, where...)...).OrderBy(order...).Take(take).Exec(ctx)
+	})
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{"error": pageErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, pageResponse(page))
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/rrule"
+	"lifequest-server/internal/tasks"
+)
+
+// Recurrence handlers
+
+// GetTaskRecurrence returns the series the task belongs to, with every
+// occurrence newest first.
+func GetTaskRecurrence(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	series, err := tasks.Recurrence(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(recurrenceErrorStatus(err), gin.H{"error": recurrenceErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// SetTaskRecurrence makes the task recur by an RRULE, or changes the rule of
+// its series.
+func SetTaskRecurrence(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var recurrenceData struct {
+		Rule            string `json:"rule" binding:"required"`
+		AfterCompletion bool   `json:"afterCompletion"`
+	}
+
+	if err := c.ShouldBindJSON(&recurrenceData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	series, err := tasks.SetRecurrence(ctx, client, userID.(string), c.Param("id"), recurrenceData.Rule, recurrenceData.AfterCompletion)
+	if err != nil {
+		c.JSON(recurrenceErrorStatus(err), gin.H{"error": recurrenceErrorMessage(err, "Failed to update recurrence")})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// StopTaskRecurrence ends the task's series; existing occurrences are kept.
+func StopTaskRecurrence(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	series, err := tasks.StopRecurrence(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(recurrenceErrorStatus(err), gin.H{"error": recurrenceErrorMessage(err, "Failed to stop recurrence")})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+func recurrenceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, tasks.ErrTaskNotFound), errors.Is(err, tasks.ErrNotRecurring):
+		return http.StatusNotFound
+	case errors.Is(err, rrule.ErrInvalid), errors.Is(err, rrule.ErrUnsupported):
+		return http.StatusBadRequest
+	case errors.Is(err, tasks.ErrForbidden):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// recurrenceErrorMessage hides database errors behind fallback.
+func recurrenceErrorMessage(err error, fallback string) string {
+	if recurrenceErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
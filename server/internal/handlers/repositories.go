@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/tasks"
+	"lifequest-server/internal/vcs"
+	"lifequest-server/internal/webhooks"
+)
+
+// maxPushBody bounds the size of an inbound push payload.
+const maxPushBody = 5 << 20
+
+// Repository mapping handlers
+func GetProjectRepositories(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	filters := []db.ProjectRepositoryWhereParam{
+		db.ProjectRepository.UserID.Equals(userID.(string)),
+	}
+	if projectID := c.Query("projectId"); projectID != "" {
+		filters = append(filters, db.ProjectRepository.ProjectID.Equals(projectID))
+	}
+
+	repositories, err := client.ProjectRepository.FindMany(filters...).OrderBy(
+		db.ProjectRepository.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, repositories)
+}
+
+func CreateProjectRepository(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var repositoryData struct {
+		ProjectID  string  `json:"projectId" binding:"required"`
+		Provider   string  `json:"provider" binding:"required"`
+		Repository string  `json:"repository" binding:"required"`
+		Branch     *string `json:"branch"`
+		Transition *string `json:"transition"`
+		Secret     *string `json:"secret"`
+	}
+
+	if err := c.ShouldBindJSON(&repositoryData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !vcs.IsValidProvider(repositoryData.Provider) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "provider must be github or gitlab"})
+		return
+	}
+	if repositoryData.Transition != nil && !isValidRepositoryTransition(*repositoryData.Transition) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "transition must be in-review or completed"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	_, err := client.Project.FindFirst(
+		db.Project.ID.Equals(repositoryData.ProjectID),
+		db.Project.UserID.Equals(userID.(string)),
+		db.Project.DeletedAt.IsNull(),
+	).Exec(ctx)
+
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	secret := ""
+	if repositoryData.Secret != nil && *repositoryData.Secret != "" {
+		secret = *repositoryData.Secret
+	} else {
+		generated, err := webhooks.GenerateSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate secret"})
+			return
+		}
+		secret = generated
+	}
+
+	params := []db.ProjectRepositorySetParam{}
+	if repositoryData.Branch != nil && *repositoryData.Branch != "" {
+		params = append(params, db.ProjectRepository.Branch.Set(*repositoryData.Branch))
+	}
+	if repositoryData.Transition != nil {
+		params = append(params, db.ProjectRepository.Transition.Set(*repositoryData.Transition))
+	}
+
+	repository, err := client.ProjectRepository.CreateOne(
+		db.ProjectRepository.Provider.Set(repositoryData.Provider),
+		db.ProjectRepository.Repository.Set(repositoryData.Repository),
+		db.ProjectRepository.Secret.Set(secret),
+		db.ProjectRepository.User.Link(
+			db.User.ID.Equals(userID.(string)),
+		),
+		db.ProjectRepository.Project.Link(
+			db.Project.ID.Equals(repositoryData.ProjectID),
+		),
+		params...,
+	).Exec(ctx)
+
+	if err != nil {
+		if _, ok := db.IsErrUniqueConstraint(err); ok {
+			c.JSON(http.StatusConflict, gin.H{"error": "Repository is already mapped to this project"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create repository mapping"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"repository": repository,
+		"hookPath":   "/api/hooks/git/" + repository.ID,
+	})
+}
+
+func UpdateProjectRepository(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var updateData struct {
+		Branch     *string `json:"branch"`
+		Transition *string `json:"transition"`
+	}
+
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if updateData.Transition != nil && !isValidRepositoryTransition(*updateData.Transition) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "transition must be in-review or completed"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	// Build update params
+	params := []db.ProjectRepositorySetParam{
+		db.ProjectRepository.UpdatedAt.Set(time.Now()),
+	}
+	if updateData.Branch != nil {
+		if *updateData.Branch == "" {
+			params = append(params, db.ProjectRepository.Branch.SetOptional(nil))
+		} else {
+			params = append(params, db.ProjectRepository.Branch.Set(*updateData.Branch))
+		}
+	}
+	if updateData.Transition != nil {
+		params = append(params, db.ProjectRepository.Transition.Set(*updateData.Transition))
+	}
+
+	result, err := client.ProjectRepository.FindMany(
+		db.ProjectRepository.ID.Equals(c.Param("id")),
+		db.ProjectRepository.UserID.Equals(userID.(string)),
+	).Update(params...).Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update repository mapping"})
+		return
+	}
+
+	if result.Count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Repository mapping not found"})
+		return
+	}
+
+	repository, err := client.ProjectRepository.FindUnique(
+		db.ProjectRepository.ID.Equals(c.Param("id")),
+	).Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch updated repository mapping"})
+		return
+	}
+
+	c.JSON(http.StatusOK, repository)
+}
+
+func DeleteProjectRepository(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	result, err := client.ProjectRepository.FindMany(
+		db.ProjectRepository.ID.Equals(c.Param("id")),
+		db.ProjectRepository.UserID.Equals(userID.(string)),
+	).Delete().Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete repository mapping"})
+		return
+	}
+
+	if result.Count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Repository mapping not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Repository mapping deleted successfully"})
+}
+
+// ReceiveGitPush handles push events from GitHub or GitLab for one repository
+// mapping. It is not behind AuthMiddleware; the mapping's shared secret
+// authenticates the provider instead.
+func ReceiveGitPush(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPushBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read payload"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	mapping, err := client.ProjectRepository.FindUnique(
+		db.ProjectRepository.ID.Equals(c.Param("id")),
+	).Exec(ctx)
+
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Repository mapping not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	var push *vcs.Push
+	switch mapping.Provider {
+	case vcs.ProviderGitHub:
+		if !vcs.VerifyGitHub(mapping.Secret, body, c.GetHeader(vcs.HeaderGitHubSignature)) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+			return
+		}
+		switch c.GetHeader(vcs.HeaderGitHubEvent) {
+		case "ping":
+			c.JSON(http.StatusOK, gin.H{"message": "pong"})
+			return
+		case "push":
+		default:
+			c.JSON(http.StatusAccepted, gin.H{"message": "Event ignored"})
+			return
+		}
+		push, err = vcs.ParseGitHubPush(body)
+	case vcs.ProviderGitLab:
+		if !vcs.VerifyGitLab(mapping.Secret, c.GetHeader(vcs.HeaderGitLabToken)) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+		if c.GetHeader(vcs.HeaderGitLabEvent) != "Push Hook" {
+			c.JSON(http.StatusAccepted, gin.H{"message": "Event ignored"})
+			return
+		}
+		push, err = vcs.ParseGitLabPush(body)
+	}
+
+	if err != nil || push == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid push payload"})
+		return
+	}
+
+	if !strings.EqualFold(push.Repository, mapping.Repository) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Repository does not match mapping"})
+		return
+	}
+
+	if branch, ok := mapping.Branch(); ok && branch != push.Branch {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Branch ignored"})
+		return
+	}
+
+	outcome, err := applyCommitReferences(ctx, client, mapping, push.Commits)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tasks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, outcome)
+}
+
+// pushOutcome reports which referenced tasks a push changed.
+type pushOutcome struct {
+	Completed []string `json:"completed"`
+	InReview  []string `json:"inReview"`
+	Unchanged []string `json:"unchanged"`
+	Blocked   []string `json:"blocked"`
+	NotFound  []string `json:"notFound"`
+}
+
+// applyCommitReferences moves every task referenced by the commits. Closing
+// references ("closes #id", "fixes LQ-id") complete the task and award its
+// XP unless it has unfinished dependencies; bare LQ-<id> mentions apply the
+// mapping's configured transition. Only tasks in the mapped project that
+// belong to the mapping's owner are touched.
+func applyCommitReferences(ctx context.Context, client *db.PrismaClient, mapping *db.ProjectRepositoryModel, commits []vcs.Commit) (*pushOutcome, error) {
+	outcome := &pushOutcome{
+		Completed: []string{},
+		InReview:  []string{},
+		Unchanged: []string{},
+		Blocked:   []string{},
+		NotFound:  []string{},
+	}
+	seen := map[string]bool{}
+
+	for _, commit := range commits {
+		for _, ref := range vcs.ParseReferences(commit.Message) {
+			if seen[ref.TaskID] {
+				continue
+			}
+			seen[ref.TaskID] = true
+
+			task, err := client.Task.FindFirst(
+				db.Task.ID.Equals(ref.TaskID),
+				db.Task.ProjectID.Equals(mapping.ProjectID),
+				db.Task.UserID.Equals(mapping.UserID),
+				db.Task.DeletedAt.IsNull(),
+			).Exec(ctx)
+			if err != nil {
+				if db.IsErrNotFound(err) {
+					outcome.NotFound = append(outcome.NotFound, ref.TaskID)
+					continue
+				}
+				return nil, err
+			}
+
+			if ref.Closing || mapping.Transition == taskStatusCompleted {
+				_, err := tasks.Complete(ctx, client, mapping.UserID, task.ID, false)
+				if errors.Is(err, tasks.ErrAlreadyCompleted) {
+					outcome.Unchanged = append(outcome.Unchanged, task.ID)
+					continue
+				}
+				if errors.Is(err, tasks.ErrBlocked) {
+					outcome.Blocked = append(outcome.Blocked, task.ID)
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				outcome.Completed = append(outcome.Completed, task.ID)
+				continue
+			}
+
+			result, err := client.Task.FindMany(
+				db.Task.ID.Equals(task.ID),
+				db.Task.Status.NotIn([]string{taskStatusCompleted, taskStatusInReview}),
+			).Update(
+				db.Task.Status.Set(taskStatusInReview),
+				db.Task.UpdatedAt.Set(time.Now()),
+			).Exec(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if result.Count == 0 {
+				outcome.Unchanged = append(outcome.Unchanged, task.ID)
+				continue
+			}
+			outcome.InReview = append(outcome.InReview, task.ID)
+		}
+	}
+
+	return outcome, nil
+}
+
+func isValidRepositoryTransition(transition string) bool {
+	return transition == taskStatusInReview || transition == taskStatusCompleted
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/pagination"
+)
+
+// Sprint handlers
+
+// GetSprints lists a page of the user's sprints, optionally only those with
+// ?status; see pageArgs for the other parameters.
+func GetSprints(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	args, ok := pageArgs(c)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	filters := []db.SprintWhereParam{
+		db.Sprint.UserID.Equals(userID.(string)),
+		db.Sprint.DeletedAt.IsNull(),
+	}
+	if status := c.Query("status"); status != "" {
+		filters = append(filters, db.Sprint.Status.Equals(status))
+	}
+
+	page, err := pagination.Fetch(pagination.Sprints, args, func(where []db.SprintWhereParam, order []db.SprintOrderByParam, take int) ([]db.SprintModel, error) {
+		return client.Sprint.FindMany(append(filters, where...)...).OrderBy(order...).Take(take).Exec(ctx)
+	})
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{"error": pageErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, pageResponse(page))
+}
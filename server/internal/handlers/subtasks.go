@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/tasks"
+)
+
+// Subtask handlers
+func GetSubtasks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	subtasks, err := tasks.Subtasks(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(subtaskErrorStatus(err), gin.H{"error": subtaskErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, subtasks)
+}
+
+func CreateSubtask(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var subtaskData struct {
+		Title string `json:"title" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&subtaskData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	subtask, err := tasks.CreateSubtask(ctx, client, userID.(string), c.Param("id"), subtaskData.Title)
+	if err != nil {
+		c.JSON(subtaskErrorStatus(err), gin.H{"error": subtaskErrorMessage(err, "Failed to create subtask")})
+		return
+	}
+
+	c.JSON(http.StatusCreated, subtask)
+}
+
+func UpdateSubtask(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var updateData struct {
+		Title     *string `json:"title"`
+		Completed *bool   `json:"completed"`
+	}
+
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	subtask, err := tasks.UpdateSubtask(ctx, client, userID.(string), c.Param("id"), tasks.SubtaskUpdate{
+		Title:     updateData.Title,
+		Completed: updateData.Completed,
+	})
+	if err != nil {
+		c.JSON(subtaskErrorStatus(err), gin.H{"error": subtaskErrorMessage(err, "Failed to update subtask")})
+		return
+	}
+
+	c.JSON(http.StatusOK, subtask)
+}
+
+// ReorderSubtasks takes the IDs of all of the task's subtasks in their new
+// order.
+func ReorderSubtasks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var orderData struct {
+		SubtaskIDs []string `json:"subtaskIds" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&orderData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	subtasks, err := tasks.ReorderSubtasks(ctx, client, userID.(string), c.Param("id"), orderData.SubtaskIDs)
+	if err != nil {
+		c.JSON(subtaskErrorStatus(err), gin.H{"error": subtaskErrorMessage(err, "Failed to reorder subtasks")})
+		return
+	}
+
+	c.JSON(http.StatusOK, subtasks)
+}
+
+func DeleteSubtask(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := tasks.DeleteSubtask(ctx, client, userID.(string), c.Param("id")); err != nil {
+		c.JSON(subtaskErrorStatus(err), gin.H{"error": subtaskErrorMessage(err, "Failed to delete subtask")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Subtask deleted successfully"})
+}
+
+// ConvertSubtask turns a subtask into a task in its parent's project.
+func ConvertSubtask(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	task, err := tasks.SubtaskToTask(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(subtaskErrorStatus(err), gin.H{"error": subtaskErrorMessage(err, "Failed to convert subtask")})
+		return
+	}
+
+	c.JSON(http.StatusCreated, task)
+}
+
+// ConvertTaskToSubtask turns the task into a subtask of parentTaskId.
+func ConvertTaskToSubtask(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var convertData struct {
+		ParentTaskID string `json:"parentTaskId" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&convertData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	subtask, err := tasks.TaskToSubtask(ctx, client, userID.(string), c.Param("id"), convertData.ParentTaskID)
+	if err != nil {
+		c.JSON(subtaskErrorStatus(err), gin.H{"error": subtaskErrorMessage(err, "Failed to convert task")})
+		return
+	}
+
+	c.JSON(http.StatusCreated, subtask)
+}
+
+func SetTaskAutoComplete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var autoCompleteData struct {
+		Enabled *bool `json:"enabled" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&autoCompleteData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	task, err := tasks.SetAutoComplete(ctx, client, userID.(string), c.Param("id"), *autoCompleteData.Enabled)
+	if err != nil {
+		c.JSON(subtaskErrorStatus(err), gin.H{"error": subtaskErrorMessage(err, "Failed to update task")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"task":     task,
+		"progress": tasks.Progress(task),
+	})
+}
+
+func subtaskErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, tasks.ErrTaskNotFound), errors.Is(err, tasks.ErrSubtaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, tasks.ErrInvalidOrder), errors.Is(err, tasks.ErrSameTask):
+		return http.StatusBadRequest
+	case errors.Is(err, tasks.ErrHasSubtasks):
+		return http.StatusConflict
+	case errors.Is(err, tasks.ErrForbidden):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// subtaskErrorMessage hides database errors behind fallback.
+func subtaskErrorMessage(err error, fallback string) string {
+	if subtaskErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/analytics"
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/tags"
+)
+
+// Tag handlers
+
+// GetTags lists the user's tags by name with the number of tasks carrying
+// each.
+func GetTags(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	list, err := tags.List(ctx, client, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+func CreateTag(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var tagData struct {
+		Name  string `json:"name" binding:"required"`
+		Color string `json:"color"`
+	}
+
+	if err := c.ShouldBindJSON(&tagData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	tag, err := tags.Create(ctx, client, userID.(string), tagData.Name, tagData.Color)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"error": tagErrorMessage(err, "Failed to create tag")})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+func GetTag(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	tag, err := tags.Get(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"error": tagErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// UpdateTag renames the tag on every task or changes its color; an empty
+// color clears it.
+func UpdateTag(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var updateData struct {
+		Name  *string `json:"name"`
+		Color *string `json:"color"`
+	}
+
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	tag, err := tags.Update(ctx, client, userID.(string), c.Param("id"), updateData.Name, updateData.Color)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"error": tagErrorMessage(err, "Failed to update tag")})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag removes the tag from every task and deletes it.
+func DeleteTag(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := tags.Delete(ctx, client, userID.(string), c.Param("id")); err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"error": tagErrorMessage(err, "Failed to delete tag")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+// MergeTags moves the tasks of the source tags onto the target tag and
+// deletes the source tags.
+func MergeTags(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var mergeData struct {
+		SourceIDs []string `json:"sourceIds" binding:"required"`
+		TargetID  string   `json:"targetId" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&mergeData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	tag, err := tags.Merge(ctx, client, userID.(string), mergeData.SourceIDs, mergeData.TargetID)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"error": tagErrorMessage(err, "Failed to merge tags")})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// SetTaskTags replaces the task's tags with the named ones, creating tags
+// that don't exist yet.
+func SetTaskTags(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var tagsData struct {
+		Tags []string `json:"tags" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&tagsData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	list, err := tags.SetTaskTags(ctx, client, userID.(string), c.Param("id"), tagsData.Tags)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"error": tagErrorMessage(err, "Failed to update task tags")})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetTagAnalytics breaks completed tasks, focus time and XP between ?from
+// and ?to, the last 30 days by default, down by tag.
+func GetTagAnalytics(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	from, to, ok := dateRange(c, 30)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	stats, err := analytics.ByTag(ctx, client, userID.(string), from, to)
+	if err != nil {
+		if errors.Is(err, analytics.ErrInvalidRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func tagErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, tags.ErrNotFound), errors.Is(err, tags.ErrTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, tags.ErrNameTaken):
+		return http.StatusConflict
+	case errors.Is(err, tags.ErrNameRequired), errors.Is(err, tags.ErrNameTooLong), errors.Is(err, tags.ErrMergeIntoSelf):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// tagErrorMessage hides database errors behind fallback.
+func tagErrorMessage(err error, fallback string) string {
+	if tagErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/search"
+	"lifequest-server/internal/tasks"
+)
+
+// Task statuses stored on Task.status.
+const (
+	taskStatusInReview  = "in-review"
+	taskStatusCompleted = "completed"
+)
+
+// Task handlers
+
+// GetTasks searches the user's tasks with the ?q query language, such as
+// tag:work priority:high due:<7d text, returning a page of at most ?limit
+// hits in ?sort order, reversed with ?reverse=true, that continues after
+// ?cursor. Without a query it lists the most recently updated tasks.
+func GetTasks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(search.DefaultLimit)))
+	if err != nil || limit < 1 || limit > search.MaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+		return
+	}
+	reverse, err := strconv.ParseBool(c.DefaultQuery("reverse", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reverse must be true or false"})
+		return
+	}
+	opts := search.Options{Sort: c.Query("sort"), Reverse: reverse, Limit: limit, After: c.Query("cursor")}
+	if opts.Sort != "" && !search.ValidSort(opts.Sort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": search.ErrInvalidSort.Error()})
+		return
+	}
+	q, err := search.Parse(c.Query("q"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	page, err := search.Page(ctx, client, search.Scope{UserID: userID.(string)}, q, opts)
+	if err != nil {
+		c.JSON(pageErrorStatus(err), gin.H{"error": pageErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, pageResponse(page))
+}
+
+// CompleteTask refuses tasks with unfinished dependencies and lists them,
+// unless ?force=true.
+func CompleteTask(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	force, err := strconv.ParseBool(c.DefaultQuery("force", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "force must be true or false"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	task, err := tasks.Complete(ctx, client, userID.(string), c.Param("id"), force)
+	if err != nil {
+		if errors.Is(err, tasks.ErrAlreadyCompleted) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Task already completed"})
+			return
+		}
+		if errors.Is(err, tasks.ErrBlocked) {
+			task, err := authz.Task(ctx, client, c.Param("id"))
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete task"})
+				return
+			}
+			graph, err := tasks.LoadGraph(ctx, client, task.UserID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete task"})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{
+				"error":    "Task has unfinished dependencies",
+				"blockers": graph.OpenBlockers(c.Param("id")),
+			})
+			return
+		}
+		if errors.Is(err, tasks.ErrTaskNotFound) || db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			return
+		}
+		if errors.Is(err, tasks.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete task"})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/trash"
+)
+
+// Trash handlers
+func GetTrash(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	entries, err := trash.List(ctx, client, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// RestoreTrashItem restores an item listed by GetTrash, together with
+// everything that was trashed along with it. ?targetId= restores a project
+// into another folder, or a task into another project.
+func RestoreTrashItem(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	err := trash.Restore(ctx, client, userID.(string), c.Param("kind"), c.Param("id"), c.Query("targetId"))
+	if err != nil {
+		c.JSON(trashErrorStatus(err), gin.H{"error": trashErrorMessage(err, "Failed to restore item")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item restored successfully"})
+}
+
+func DeleteProject(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := trash.Project(ctx, client, userID.(string), c.Param("id")); err != nil {
+		if errors.Is(err, trash.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Project moved to trash"})
+}
+
+func DeleteTask(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := trash.Task(ctx, client, userID.(string), c.Param("id")); err != nil {
+		if errors.Is(err, trash.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete task"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task moved to trash"})
+}
+
+func DeleteSprint(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := trash.Sprint(ctx, client, userID.(string), c.Param("id")); err != nil {
+		if errors.Is(err, trash.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sprint not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete sprint"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sprint moved to trash"})
+}
+
+func DeletePomodoroSession(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := trash.Session(ctx, client, userID.(string), c.Param("id")); err != nil {
+		if errors.Is(err, trash.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session moved to trash"})
+}
+
+func trashErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, trash.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, trash.ErrUnknownKind), errors.Is(err, trash.ErrTargetNotFound):
+		return http.StatusBadRequest
+	case errors.Is(err, trash.ErrExpired):
+		return http.StatusGone
+	case errors.Is(err, trash.ErrParentTrashed):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// trashErrorMessage hides database errors behind fallback.
+func trashErrorMessage(err error, fallback string) string {
+	if trashErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/search"
+	"lifequest-server/internal/views"
+)
+
+// Saved view handlers
+
+// viewData is the editable fields of a saved view; omitted fields are left
+// unchanged.
+type viewData struct {
+	Name      *string `json:"name"`
+	Query     *string `json:"query"`
+	Sort      *string `json:"sort"` // empty restores the default order
+	Reverse   *bool   `json:"reverse"`
+	GroupBy   *string `json:"groupBy"`
+	Pinned    *bool   `json:"pinned"`
+	ProjectID *string `json:"projectId"` // empty removes the project limit
+	Shared    *bool   `json:"shared"`
+}
+
+func (d viewData) fields() views.Fields {
+	return views.Fields{
+		Name:      d.Name,
+		Query:     d.Query,
+		Sort:      d.Sort,
+		Reverse:   d.Reverse,
+		GroupBy:   d.GroupBy,
+		Pinned:    d.Pinned,
+		ProjectID: d.ProjectID,
+		Shared:    d.Shared,
+	}
+}
+
+// GetViews lists the user's saved views and the views shared with them,
+// pinned views first.
+func GetViews(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	list, err := views.List(ctx, client, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+func CreateView(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var createData viewData
+	if err := c.ShouldBindJSON(&createData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	view, err := views.Create(ctx, client, userID.(string), createData.fields())
+	if err != nil {
+		c.JSON(viewErrorStatus(err), gin.H{"error": viewErrorMessage(err, "Failed to create view")})
+		return
+	}
+
+	c.JSON(http.StatusCreated, view)
+}
+
+func GetView(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	view, err := views.Get(ctx, client, userID.(string), c.Param("id"))
+	if err != nil {
+		c.JSON(viewErrorStatus(err), gin.H{"error": viewErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// UpdateView changes a view the user owns, including pinning it.
+func UpdateView(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var updateData viewData
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	view, err := views.Update(ctx, client, userID.(string), c.Param("id"), updateData.fields())
+	if err != nil {
+		c.JSON(viewErrorStatus(err), gin.H{"error": viewErrorMessage(err, "Failed to update view")})
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+func DeleteView(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	if err := views.Delete(ctx, client, userID.(string), c.Param("id")); err != nil {
+		c.JSON(viewErrorStatus(err), gin.H{"error": viewErrorMessage(err, "Failed to delete view")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "View deleted successfully"})
+}
+
+// RunView returns the tasks matching the view, grouped as it says, at most
+// ?limit of them.
+func RunView(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(search.DefaultLimit)))
+	if err != nil || limit < 1 || limit > search.MaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	result, err := views.Run(ctx, client, userID.(string), c.Param("id"), limit)
+	if err != nil {
+		c.JSON(viewErrorStatus(err), gin.H{"error": viewErrorMessage(err, "Database error")})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func viewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, views.ErrNotFound), errors.Is(err, views.ErrProjectNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, views.ErrNotOwner):
+		return http.StatusForbidden
+	case errors.Is(err, views.ErrNameTaken):
+		return http.StatusConflict
+	case errors.Is(err, views.ErrNameRequired), errors.Is(err, views.ErrNameTooLong),
+		errors.Is(err, views.ErrInvalidGrouping), errors.Is(err, views.ErrSharedNeedsProject),
+		errors.Is(err, search.ErrInvalid), errors.Is(err, search.ErrInvalidSort):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// viewErrorMessage hides database errors behind fallback.
+func viewErrorMessage(err error, fallback string) string {
+	if viewErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/database"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/webhooks"
+)
+
+// Webhook handlers
+func GetWebhooks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	hooks, err := client.Webhook.FindMany(
+		db.Webhook.UserID.Equals(userID.(string)),
+	).OrderBy(
+		db.Webhook.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, hooks)
+}
+
+func CreateWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var webhookData struct {
+		URL         string   `json:"url" binding:"required"`
+		Events      []string `json:"events" binding:"required"`
+		Description *string  `json:"description"`
+		Secret      *string  `json:"secret"`
+	}
+
+	if err := c.ShouldBindJSON(&webhookData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := webhooks.Validate(c.Request.Context(), webhookData.URL, webhookData.Events); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	secret := ""
+	if webhookData.Secret != nil && *webhookData.Secret != "" {
+		secret = *webhookData.Secret
+	} else {
+		generated, err := webhooks.GenerateSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate secret"})
+			return
+		}
+		secret = generated
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	params := []db.WebhookSetParam{
+		db.Webhook.Events.Set(webhookData.Events),
+	}
+	if webhookData.Description != nil {
+		params = append(params, db.Webhook.Description.SetOptional(webhookData.Description))
+	}
+
+	hook, err := client.Webhook.CreateOne(
+		db.Webhook.URL.Set(webhookData.URL),
+		db.Webhook.Secret.Set(secret),
+		db.Webhook.User.Link(
+			db.User.ID.Equals(userID.(string)),
+		),
+		params...,
+	).Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, hook)
+}
+
+func GetWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	hook, err := findWebhook(context.Background(), userID.(string), c.Param("id"))
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, hook)
+}
+
+func UpdateWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	var updateData struct {
+		URL         *string  `json:"url"`
+		Events      []string `json:"events"`
+		Description *string  `json:"description"`
+		Active      *bool    `json:"active"`
+	}
+
+	if err := c.ShouldBindJSON(&updateData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	hook, err := findWebhook(ctx, userID.(string), c.Param("id"))
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	hookURL := hook.URL
+	if updateData.URL != nil {
+		hookURL = *updateData.URL
+	}
+	events := hook.Events
+	if updateData.Events != nil {
+		events = updateData.Events
+	}
+	if err := webhooks.Validate(ctx, hookURL, events); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Build update params
+	params := []db.WebhookSetParam{
+		db.Webhook.UpdatedAt.Set(time.Now()),
+	}
+	if updateData.URL != nil {
+		params = append(params, db.Webhook.URL.Set(*updateData.URL))
+	}
+	if updateData.Events != nil {
+		params = append(params, db.Webhook.Events.Set(updateData.Events))
+	}
+	if updateData.Description != nil {
+		params = append(params, db.Webhook.Description.SetOptional(updateData.Description))
+	}
+	if updateData.Active != nil {
+		params = append(params, db.Webhook.Active.Set(*updateData.Active))
+	}
+
+	updated, err := client.Webhook.FindUnique(
+		db.Webhook.ID.Equals(hook.ID),
+	).Update(params...).Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func DeleteWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	result, err := client.Webhook.FindMany(
+		db.Webhook.ID.Equals(c.Param("id")),
+		db.Webhook.UserID.Equals(userID.(string)),
+	).Delete().Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+
+	if result.Count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+func GetWebhookDeliveries(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	hook, err := findWebhook(ctx, userID.(string), c.Param("id"))
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	deliveries, err := client.WebhookDelivery.FindMany(
+		db.WebhookDelivery.WebhookID.Equals(hook.ID),
+	).OrderBy(
+		db.WebhookDelivery.CreatedAt.Order(db.SortOrderDesc),
+	).Take(limit).Exec(ctx)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func SendTestWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+
+	hook, err := findWebhook(ctx, userID.(string), c.Param("id"))
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	delivery, err := webhooks.Default().SendTest(ctx, hook)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send test event"})
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func RedeliverWebhookDelivery(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ctx := context.Background()
+	client := database.GetClient()
+
+	hook, err := findWebhook(ctx, userID.(string), c.Param("id"))
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	delivery, err := client.WebhookDelivery.FindFirst(
+		db.WebhookDelivery.ID.Equals(c.Param("deliveryId")),
+		db.WebhookDelivery.WebhookID.Equals(hook.ID),
+	).Exec(ctx)
+
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	redelivered, err := webhooks.Default().Redeliver(ctx, hook, delivery)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to redeliver"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redelivered)
+}
+
+func findWebhook(ctx context.Context, userID, webhookID string) (*db.WebhookModel, error) {
+	return database.GetClient().Webhook.FindFirst(
+		db.Webhook.ID.Equals(webhookID),
+		db.Webhook.UserID.Equals(userID),
+	).Exec(ctx)
+}
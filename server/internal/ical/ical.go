@@ -0,0 +1,181 @@
+// Package ical reads and writes the subset of RFC 5545 iCalendar data
+// LifeQuest exchanges with calendar applications.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateTimeFormat = "20060102T150405Z"
+	dateFormat     = "20060102"
+
+	// maxLineOctets is the RFC 5545 content line limit before folding.
+	maxLineOctets = 75
+)
+
+// VTODO status values.
+const (
+	TodoNeedsAction = "NEEDS-ACTION"
+	TodoInProcess   = "IN-PROCESS"
+	TodoCompleted   = "COMPLETED"
+	TodoCancelled   = "CANCELLED"
+)
+
+// Calendar is a VCALENDAR object holding events and to-dos.
+type Calendar struct {
+	Name   string
+	Events []Event
+	Todos  []Todo
+}
+
+// Event is a VEVENT. All-day events use DATE values and an exclusive End.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Stamp       time.Time
+	Categories  []string
+}
+
+// Todo is a VTODO.
+type Todo struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	Due         time.Time
+	AllDay      bool
+	Completed   *time.Time
+	Status      string
+	// Priority is 1 (highest) to 9 (lowest); 0 leaves it undefined.
+	Priority   int
+	Stamp      time.Time
+	Categories []string
+}
+
+// Write serializes the calendar with CRLF line endings and folded lines.
+func (c *Calendar) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//LifeQuest//Calendar Feed//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", EscapeText(c.Name))
+	}
+
+	for _, event := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", event.UID)
+		line("DTSTAMP", formatDateTime(event.Stamp))
+		if event.AllDay {
+			line("DTSTART;VALUE=DATE", event.Start.Format(dateFormat))
+			line("DTEND;VALUE=DATE", event.End.Format(dateFormat))
+		} else {
+			line("DTSTART", formatDateTime(event.Start))
+			line("DTEND", formatDateTime(event.End))
+		}
+		line("SUMMARY", EscapeText(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", EscapeText(event.Description))
+		}
+		if len(event.Categories) > 0 {
+			line("CATEGORIES", escapeList(event.Categories))
+		}
+		line("END", "VEVENT")
+	}
+
+	for _, todo := range c.Todos {
+		line("BEGIN", "VTODO")
+		line("UID", todo.UID)
+		line("DTSTAMP", formatDateTime(todo.Stamp))
+		if !todo.Start.IsZero() {
+			line("DTSTART", formatDateTime(todo.Start))
+		}
+		switch {
+		case todo.Due.IsZero():
+		case todo.AllDay:
+			line("DUE;VALUE=DATE", todo.Due.Format(dateFormat))
+		default:
+			line("DUE", formatDateTime(todo.Due))
+		}
+		line("SUMMARY", EscapeText(todo.Summary))
+		if todo.Description != "" {
+			line("DESCRIPTION", EscapeText(todo.Description))
+		}
+		if todo.Status != "" {
+			line("STATUS", todo.Status)
+		}
+		if todo.Completed != nil {
+			line("COMPLETED", formatDateTime(*todo.Completed))
+		}
+		if todo.Priority > 0 {
+			line("PRIORITY", fmt.Sprint(todo.Priority))
+		}
+		if len(todo.Categories) > 0 {
+			line("CATEGORIES", escapeList(todo.Categories))
+		}
+		line("END", "VTODO")
+	}
+
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// EscapeText escapes a TEXT property value.
+func EscapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+func escapeList(values []string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = EscapeText(value)
+	}
+	return strings.Join(escaped, ",")
+}
+
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// writeFolded writes a content line, folding it into 75-octet chunks without
+// splitting multi-byte characters.
+func writeFolded(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines start with a space that counts toward the limit
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,277 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoCalendar is returned by Parse when the input has no VCALENDAR object.
+var ErrNoCalendar = errors.New("ical: no VCALENDAR object found")
+
+// property is one unfolded content line.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads a VCALENDAR object and returns its events and to-dos.
+// Components other than VEVENT and VTODO, and properties LifeQuest does not
+// use, are ignored. Times with a TZID are converted using the system zone
+// database; floating times are treated as UTC.
+func Parse(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cal   *Calendar
+		stack []string
+		event *Event
+		todo  *Todo
+	)
+
+	for i, line := range lines {
+		lineNo := i + 1
+		if line == "" {
+			continue
+		}
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("ical: line %d: %w", lineNo, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			component := strings.ToUpper(prop.value)
+			if len(stack) == 0 {
+				if component != "VCALENDAR" {
+					return nil, fmt.Errorf("ical: line %d: unexpected %s outside VCALENDAR", lineNo, component)
+				}
+				cal = &Calendar{}
+			} else if len(stack) == 1 {
+				switch component {
+				case "VEVENT":
+					event = &Event{}
+				case "VTODO":
+					todo = &Todo{}
+				}
+			}
+			stack = append(stack, component)
+			continue
+		case "END":
+			component := strings.ToUpper(prop.value)
+			if len(stack) == 0 || stack[len(stack)-1] != component {
+				return nil, fmt.Errorf("ical: line %d: unexpected END:%s", lineNo, component)
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 1 {
+				if event != nil {
+					cal.Events = append(cal.Events, *event)
+					event = nil
+				}
+				if todo != nil {
+					cal.Todos = append(cal.Todos, *todo)
+					todo = nil
+				}
+			}
+			if len(stack) == 0 {
+				return cal, nil
+			}
+			continue
+		}
+
+		switch {
+		case len(stack) == 1 && prop.name == "X-WR-CALNAME":
+			cal.Name = unescapeText(prop.value)
+		case len(stack) == 2 && event != nil:
+			err = event.set(prop)
+		case len(stack) == 2 && todo != nil:
+			err = todo.set(prop)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ical: line %d: %w", lineNo, err)
+		}
+	}
+
+	if cal == nil {
+		return nil, ErrNoCalendar
+	}
+	return nil, errors.New("ical: unterminated VCALENDAR")
+}
+
+func (e *Event) set(prop property) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		e.UID = prop.value
+	case "SUMMARY":
+		e.Summary = unescapeText(prop.value)
+	case "DESCRIPTION":
+		e.Description = unescapeText(prop.value)
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseTime(prop)
+	case "DTEND":
+		e.End, _, err = parseTime(prop)
+	case "DTSTAMP":
+		e.Stamp, _, err = parseTime(prop)
+	case "CATEGORIES":
+		e.Categories = append(e.Categories, splitList(prop.value)...)
+	}
+	return err
+}
+
+func (t *Todo) set(prop property) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		t.UID = prop.value
+	case "SUMMARY":
+		t.Summary = unescapeText(prop.value)
+	case "DESCRIPTION":
+		t.Description = unescapeText(prop.value)
+	case "DTSTART":
+		t.Start, _, err = parseTime(prop)
+	case "DUE":
+		t.Due, t.AllDay, err = parseTime(prop)
+	case "DTSTAMP":
+		t.Stamp, _, err = parseTime(prop)
+	case "COMPLETED":
+		var completed time.Time
+		if completed, _, err = parseTime(prop); err == nil {
+			t.Completed = &completed
+		}
+	case "STATUS":
+		t.Status = strings.ToUpper(prop.value)
+	case "PRIORITY":
+		t.Priority, err = strconv.Atoi(prop.value)
+		if err != nil || t.Priority < 0 || t.Priority > 9 {
+			err = fmt.Errorf("invalid PRIORITY %q", prop.value)
+		}
+	case "CATEGORIES":
+		t.Categories = append(t.Categories, splitList(prop.value)...)
+	}
+	return err
+}
+
+// unfold reads content lines, joining folded continuation lines.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseProperty splits a content line into name, parameters and value.
+func parseProperty(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return prop, fmt.Errorf("malformed content line %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			continue
+		}
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	prop.value = line[colon+1:]
+	return prop, nil
+}
+
+// parseTime parses a DATE or DATE-TIME property and reports whether it was a
+// DATE value.
+func parseTime(prop property) (time.Time, bool, error) {
+	value := prop.value
+	if prop.params["VALUE"] == "DATE" || len(value) == len(dateFormat) {
+		t, err := time.Parse(dateFormat, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s date %q", prop.name, value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeFormat, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s time %q", prop.name, value)
+		}
+		return t, false, nil
+	}
+
+	loc := time.UTC
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			loc = zone
+		}
+	}
+	t, err := time.ParseInLocation(strings.TrimSuffix(dateTimeFormat, "Z"), value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %s time %q", prop.name, value)
+	}
+	return t.UTC(), false, nil
+}
+
+func unescapeText(value string) string {
+	return textUnescaper.Replace(value)
+}
+
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+// splitList splits a comma-separated TEXT list, honouring escaped commas.
+func splitList(value string) []string {
+	var (
+		items   []string
+		current strings.Builder
+	)
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			current.WriteByte(value[i])
+			current.WriteByte(value[i+1])
+			i++
+			continue
+		}
+		if value[i] == ',' {
+			items = append(items, unescapeText(current.String()))
+			current.Reset()
+			continue
+		}
+		current.WriteByte(value[i])
+	}
+	return append(items, unescapeText(current.String()))
+}
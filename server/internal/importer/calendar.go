@@ -0,0 +1,195 @@
+package importer
+
+import (
+	"context"
+	"time"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/ical"
+	"lifequest-server/internal/utils"
+)
+
+// Item kinds produced by calendar imports.
+const (
+	KindEvent = "event"
+	KindTodo  = "todo"
+)
+
+const calendarSource = "ical:"
+
+// calendarEntry is a VEVENT or VTODO mapped onto task fields.
+type calendarEntry struct {
+	item        Item
+	description string
+	priority    string
+}
+
+// Calendar creates a task in projectID for every dated VTODO and VEVENT in
+// cal. Entries whose UID was imported before are reported as duplicates;
+// completed or cancelled to-dos and entries without a date are skipped.
+func Calendar(ctx context.Context, client *db.PrismaClient, userID, projectID string, cal *ical.Calendar, dryRun bool) (*Report, error) {
+	project, err := client.Project.FindFirst(
+		db.Project.ID.Equals(projectID),
+		db.Project.UserID.Equals(userID),
+		db.Project.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	entries := calendarEntries(cal)
+
+	externalIDs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.item.UID != "" {
+			externalIDs = append(externalIDs, calendarSource+entry.item.UID)
+		}
+	}
+	imported, err := client.Task.FindMany(
+		db.Task.UserID.Equals(userID),
+		db.Task.ExternalID.In(externalIDs),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]string, len(imported))
+	for _, task := range imported {
+		if externalID, ok := task.ExternalID(); ok {
+			existing[externalID] = task.ID
+		}
+	}
+
+	report := &Report{DryRun: dryRun, Items: []Item{}}
+	seen := map[string]bool{}
+	var txs []db.PrismaTransaction
+
+	for _, entry := range entries {
+		item := entry.item
+		externalID := calendarSource + item.UID
+
+		switch {
+		case item.Action == ActionSkip:
+		case existing[externalID] != "":
+			item.Action = ActionDuplicate
+			item.Reason = "already imported"
+			item.ID = existing[externalID]
+		case seen[externalID]:
+			item.Action = ActionDuplicate
+			item.Reason = "repeated in file"
+		default:
+			seen[externalID] = true
+			item.Action = ActionCreate
+			if !dryRun {
+				item.ID = utils.GenerateUUID()
+				params := []db.TaskSetParam{
+					db.Task.ID.Set(item.ID),
+					db.Task.Priority.Set(entry.priority),
+					db.Task.DueDate.SetIfPresent(item.DueDate),
+					db.Task.ExternalID.Set(externalID),
+				}
+				if entry.description != "" {
+					params = append(params, db.Task.Description.Set(entry.description))
+				}
+				txs = append(txs, client.Task.CreateOne(
+					db.Task.Title.Set(item.Title),
+					db.Task.User.Link(db.User.ID.Equals(userID)),
+					db.Task.Project.Link(db.Project.ID.Equals(project.ID)),
+					params...,
+				).Tx())
+			}
+		}
+
+		report.add(item)
+	}
+
+	if dryRun || len(txs) == 0 {
+		return report, nil
+	}
+
+	txs = append(txs, client.Project.FindUnique(
+		db.Project.ID.Equals(project.ID),
+	).Update(
+		db.Project.TaskCount.Increment(report.Created),
+		db.Project.UpdatedAt.Set(time.Now()),
+	).Tx())
+
+	if err := client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func calendarEntries(cal *ical.Calendar) []calendarEntry {
+	entries := make([]calendarEntry, 0, len(cal.Todos)+len(cal.Events))
+
+	for _, todo := range cal.Todos {
+		due := todo.Due
+		if due.IsZero() {
+			due = todo.Start
+		}
+		entry := newCalendarEntry(KindTodo, todo.UID, todo.Summary, todo.Description, due)
+		entry.priority = calendarPriority(todo.Priority)
+		switch todo.Status {
+		case ical.TodoCompleted:
+			skip(&entry.item, "already completed")
+		case ical.TodoCancelled:
+			skip(&entry.item, "cancelled")
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, event := range cal.Events {
+		entries = append(entries, newCalendarEntry(KindEvent, event.UID, event.Summary, event.Description, event.Start))
+	}
+
+	return entries
+}
+
+func newCalendarEntry(kind, uid, summary, description string, due time.Time) calendarEntry {
+	entry := calendarEntry{
+		item: Item{
+			Kind:  kind,
+			UID:   uid,
+			Title: summary,
+		},
+		description: description,
+		priority:    "medium",
+	}
+	if !due.IsZero() {
+		entry.item.DueDate = &due
+	}
+
+	switch {
+	case uid == "":
+		skip(&entry.item, "missing UID")
+	case summary == "":
+		skip(&entry.item, "missing summary")
+	case due.IsZero():
+		skip(&entry.item, "no due date")
+	}
+	return entry
+}
+
+func skip(item *Item, reason string) {
+	if item.Action == ActionSkip {
+		return
+	}
+	item.Action = ActionSkip
+	item.Reason = reason
+}
+
+// calendarPriority maps RFC 5545 PRIORITY (1 highest, 9 lowest, 0 undefined)
+// onto task priorities.
+func calendarPriority(priority int) string {
+	switch {
+	case priority >= 1 && priority <= 4:
+		return "high"
+	case priority >= 6:
+		return "low"
+	default:
+		return "medium"
+	}
+}
@@ -0,0 +1,192 @@
+package importer
+
+import (
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Defaults for CSV rows that leave the folder or project empty.
+const (
+	defaultFolderName  = "Imported"
+	defaultProjectName = "Inbox"
+)
+
+// ParseCSV reads the generic CSV format: a header row followed by one task
+// per row. Columns are matched by name, case-insensitively:
+//
+//	title        required
+//	folder       folder name, "Imported" when empty
+//	project      project name, "Inbox" when empty
+//	description
+//	due_date     YYYY-MM-DD or RFC 3339
+//	priority     low, medium or high
+//	status       "completed" or "done" imports the task as completed
+//	labels       separated by ";" or ","
+//	checklist    items separated by ";", done items prefixed with "[x] "
+//	id           stable row ID; derived from folder, project and title when absent
+//
+// Folders and projects are identified by name, so re-importing an edited
+// file adds new rows to the projects created the first time.
+func ParseCSV(r io.Reader) (*Plan, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv: file is empty")
+		}
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New(`csv: missing required "title" column`)
+	}
+
+	plan := &Plan{Source: FormatCSV}
+	folders := map[string]int{}
+	projects := map[string][2]int{}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		folderName := field("folder")
+		if folderName == "" {
+			folderName = defaultFolderName
+		}
+		projectName := field("project")
+		if projectName == "" {
+			projectName = defaultProjectName
+		}
+
+		folderKey := strings.ToLower(folderName)
+		fi, ok := folders[folderKey]
+		if !ok {
+			fi = len(plan.Folders)
+			folders[folderKey] = fi
+			plan.Folders = append(plan.Folders, PlanFolder{
+				ExternalID: "folder:" + folderKey,
+				Name:       folderName,
+			})
+		}
+
+		projectKey := folderKey + "/" + strings.ToLower(projectName)
+		pi, ok := projects[projectKey]
+		if !ok {
+			pi = [2]int{fi, len(plan.Folders[fi].Projects)}
+			projects[projectKey] = pi
+			plan.Folders[fi].Projects = append(plan.Folders[fi].Projects, PlanProject{
+				ExternalID: "project:" + projectKey,
+				Name:       projectName,
+			})
+		}
+
+		task := PlanTask{
+			ExternalID:  field("id"),
+			Title:       field("title"),
+			Description: field("description"),
+			Priority:    normalizePriority(field("priority")),
+			Labels:      splitLabels(field("labels")),
+			Checklist:   parseChecklist(field("checklist")),
+		}
+		if task.ExternalID == "" {
+			sum := sha1.Sum([]byte(projectKey + "\x00" + task.Title))
+			task.ExternalID = hex.EncodeToString(sum[:])
+		}
+		task.ExternalID = "task:" + task.ExternalID
+
+		switch strings.ToLower(field("status")) {
+		case "completed", "done":
+			task.Completed = true
+		}
+
+		if value := field("due_date"); value != "" {
+			due, err := parseDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("csv: line %d: %w", line, err)
+			}
+			task.DueDate = &due
+		}
+
+		project := &plan.Folders[pi[0]].Projects[pi[1]]
+		project.Tasks = append(project.Tasks, task)
+	}
+
+	return plan, nil
+}
+
+func splitLabels(value string) []string {
+	var labels []string
+	for _, label := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' }) {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func parseChecklist(value string) []ChecklistItem {
+	var items []ChecklistItem
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		item := ChecklistItem{Title: entry}
+		lower := strings.ToLower(entry)
+		if strings.HasPrefix(lower, "[x]") {
+			item = ChecklistItem{Title: strings.TrimSpace(entry[3:]), Done: true}
+		} else if strings.HasPrefix(lower, "[ ]") {
+			item.Title = strings.TrimSpace(entry[3:])
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// normalizePriority maps a priority name onto low, medium or high, and
+// anything else onto "" so the task default applies.
+func normalizePriority(value string) string {
+	switch strings.ToLower(value) {
+	case "low":
+		return "low"
+	case "medium", "normal":
+		return "medium"
+	case "high", "urgent":
+		return "high"
+	}
+	return ""
+}
+
+// parseDate accepts the date formats found in exports: plain dates, RFC 3339
+// timestamps and timestamps without a zone, which are taken as UTC.
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
@@ -0,0 +1,58 @@
+// Package importer creates LifeQuest folders, projects and tasks from files
+// exported by other tools. Every import can run as a dry run that reports what would change
+// without writing anything, and imported items remember their source ID so
+// re-running an import skips what already exists.
+package importer
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrProjectNotFound is returned when the target project does not exist or
+// belongs to another user.
+var ErrProjectNotFound = errors.New("project not found")
+
+// Actions reported for each item in a Report.
+const (
+	ActionCreate    = "create"
+	ActionDuplicate = "duplicate"
+	ActionSkip      = "skip"
+)
+
+// Item describes what an import does, or would do, with one source entry.
+type Item struct {
+	Kind    string     `json:"kind"`
+	UID     string     `json:"uid"`
+	Title   string     `json:"title"`
+	DueDate *time.Time `json:"dueDate"`
+	Action  string     `json:"action"`
+	Reason  string     `json:"reason,omitempty"`
+	// ID is the existing record for duplicates and, unless DryRun, the new
+	// record for created items.
+	ID string `json:"id,omitempty"`
+}
+
+// Report summarises an import.
+type Report struct {
+	DryRun     bool   `json:"dryRun"`
+	Created    int    `json:"created"`
+	Duplicates int    `json:"duplicates"`
+	Skipped    int    `json:"skipped"`
+	Items      []Item `json:"items"`
+
+	// planned holds the records Apply has decided to create so far.
+	planned map[string]bool
+}
+
+func (r *Report) add(item Item) {
+	switch item.Action {
+	case ActionCreate:
+		r.Created++
+	case ActionDuplicate:
+		r.Duplicates++
+	case ActionSkip:
+		r.Skipped++
+	}
+	r.Items = append(r.Items, item)
+}
@@ -0,0 +1,416 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/tags"
+	"lifequest-server/internal/utils"
+)
+
+// Supported export formats.
+const (
+	FormatCSV         = "csv"
+	FormatTrello      = "trello"
+	FormatTodoistJSON = "todoist-json"
+	FormatTodoistCSV  = "todoist-csv"
+)
+
+// Item kinds produced by Apply.
+const (
+	KindFolder  = "folder"
+	KindProject = "project"
+	KindTask    = "task"
+)
+
+// ErrUnknownFormat is returned by Parse for formats it has no adapter for.
+var ErrUnknownFormat = errors.New("unknown import format")
+
+// Plan is the source-independent content of an export: folders holding
+// projects holding tasks. External IDs are unique within the source and are
+// prefixed with it when stored, so re-running an import finds what an
+// earlier run created.
+type Plan struct {
+	Source  string
+	Folders []PlanFolder
+}
+
+type PlanFolder struct {
+	ExternalID string
+	Name       string
+	Projects   []PlanProject
+}
+
+type PlanProject struct {
+	ExternalID  string
+	Name        string
+	Description string
+	Tasks       []PlanTask
+}
+
+type PlanTask struct {
+	ExternalID  string
+	Title       string
+	Description string
+	Priority    string
+	DueDate     *time.Time
+	Completed   bool
+	Labels      []string
+	Checklist   []ChecklistItem
+}
+
+type ChecklistItem struct {
+	Title string
+	Done  bool
+}
+
+// Parse reads an export in the given format. name is the uploaded file name;
+// Todoist CSV backups hold one project per file and take its name from it.
+func Parse(format, name string, r io.Reader) (*Plan, error) {
+	switch format {
+	case FormatCSV:
+		return ParseCSV(r)
+	case FormatTrello:
+		return ParseTrello(r)
+	case FormatTodoistJSON:
+		return ParseTodoistJSON(r)
+	case FormatTodoistCSV:
+		return ParseTodoistCSV(r, strings.TrimSuffix(name, ".csv"))
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// Apply creates the plan's folders, projects and tasks for userID. Records
+// imported by an earlier run are reported as duplicates and reused, so new
+// tasks still land in previously imported projects. Labels tag the tasks
+// they're on. Completed tasks are created completed without awarding XP.
+// Nothing is written on a dry run; otherwise everything is written in a
+// single transaction.
+func Apply(ctx context.Context, client *db.PrismaClient, userID string, plan *Plan, dryRun bool) (*Report, error) {
+	existing, err := loadImported(ctx, client, userID, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{DryRun: dryRun, Items: []Item{}}
+	now := time.Now()
+
+	var folderTxs, projectTxs, tagTxs, taskTxs, counterTxs []db.PrismaTransaction
+
+	// Labels are matched to the user's tags by name ignoring case; tags that
+	// don't exist yet are created with the first task using them.
+	tagIDs := map[string]string{}
+	if !dryRun {
+		existingTags, err := tags.ByKey(ctx, client, userID, planLabels(plan))
+		if err != nil {
+			return nil, err
+		}
+		for key, tag := range existingTags {
+			tagIDs[key] = tag.ID
+		}
+	}
+	labelTags := func(labels []string) []string {
+		var ids []string
+		seen := map[string]bool{}
+		for _, label := range labels {
+			name, err := tags.Normalize(label)
+			if err != nil {
+				continue
+			}
+			key := tags.Key(name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			id, ok := tagIDs[key]
+			if !ok {
+				id = utils.GenerateUUID()
+				tagIDs[key] = id
+				tagTxs = append(tagTxs, client.Tag.CreateOne(
+					db.Tag.Name.Set(name),
+					db.Tag.Key.Set(key),
+					db.Tag.User.Link(db.User.ID.Equals(userID)),
+					db.Tag.ID.Set(id),
+				).Tx())
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	for _, folder := range plan.Folders {
+		folderExternalID := plan.Source + ":" + folder.ExternalID
+		folderID, folderNew := resolve(report, Item{Kind: KindFolder, UID: folder.ExternalID, Title: folder.Name}, existing, folderExternalID, dryRun)
+		if folderID == "" {
+			continue
+		}
+
+		newProjects := 0
+		for _, project := range folder.Projects {
+			projectExternalID := plan.Source + ":" + project.ExternalID
+			projectID, projectNew := resolve(report, Item{Kind: KindProject, UID: project.ExternalID, Title: project.Name}, existing, projectExternalID, dryRun)
+			if projectID == "" {
+				continue
+			}
+
+			newTasks, newCompleted := 0, 0
+			for _, task := range project.Tasks {
+				item := Item{Kind: KindTask, UID: task.ExternalID, Title: task.Title, DueDate: task.DueDate}
+				if task.Title == "" {
+					skip(&item, "missing title")
+					report.add(item)
+					continue
+				}
+				taskExternalID := plan.Source + ":" + task.ExternalID
+				taskID, taskNew := resolve(report, item, existing, taskExternalID, dryRun)
+				if !taskNew {
+					continue
+				}
+				newTasks++
+				if task.Completed {
+					newCompleted++
+				}
+				if !dryRun {
+					taskTxs = append(taskTxs, createTask(client, userID, projectID, taskID, taskExternalID, &task, labelTags(task.Labels), now)...)
+				}
+			}
+
+			switch {
+			case dryRun:
+			case projectNew:
+				params := []db.ProjectSetParam{
+					db.Project.ID.Set(projectID),
+					db.Project.ExternalID.Set(projectExternalID),
+					db.Project.TaskCount.Set(newTasks),
+					db.Project.CompletedTaskCount.Set(newCompleted),
+				}
+				if project.Description != "" {
+					params = append(params, db.Project.Description.Set(project.Description))
+				}
+				projectTxs = append(projectTxs, client.Project.CreateOne(
+					db.Project.Name.Set(project.Name),
+					db.Project.User.Link(db.User.ID.Equals(userID)),
+					db.Project.Folder.Link(db.Folder.ID.Equals(folderID)),
+					params...,
+				).Tx())
+			case newTasks > 0:
+				counterTxs = append(counterTxs, client.Project.FindUnique(
+					db.Project.ID.Equals(projectID),
+				).Update(
+					db.Project.TaskCount.Increment(newTasks),
+					db.Project.CompletedTaskCount.Increment(newCompleted),
+					db.Project.UpdatedAt.Set(now),
+				).Tx())
+			}
+			if projectNew {
+				newProjects++
+			}
+		}
+
+		switch {
+		case dryRun:
+		case folderNew:
+			folderTxs = append(folderTxs, client.Folder.CreateOne(
+				db.Folder.Name.Set(folder.Name),
+				db.Folder.User.Link(db.User.ID.Equals(userID)),
+				db.Folder.ID.Set(folderID),
+				db.Folder.ExternalID.Set(folderExternalID),
+				db.Folder.ProjectCount.Set(newProjects),
+			).Tx())
+		case newProjects > 0:
+			counterTxs = append(counterTxs, client.Folder.FindUnique(
+				db.Folder.ID.Equals(folderID),
+			).Update(
+				db.Folder.ProjectCount.Increment(newProjects),
+				db.Folder.UpdatedAt.Set(now),
+			).Tx())
+		}
+	}
+
+	txs := append(append(append(append(folderTxs, projectTxs...), tagTxs...), taskTxs...), counterTxs...)
+	if dryRun || len(txs) == 0 {
+		return report, nil
+	}
+	if err := client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// resolve reports item and returns the ID of the record it maps to, and
+// whether that record still has to be created. Records imported by an
+// earlier run, or earlier in the same file, resolve to the existing ID; items
+// without a source ID are skipped and resolve to "". ids maps kind-qualified
+// stored external IDs to record IDs and is extended with the IDs of records
+// to create, which are placeholders on a dry run and are not reported.
+func resolve(report *Report, item Item, ids map[string]string, externalID string, dryRun bool) (string, bool) {
+	key := item.Kind + " " + externalID
+	if item.UID == "" {
+		skip(&item, "missing source ID")
+		report.add(item)
+		return "", false
+	}
+
+	if id, ok := ids[key]; ok {
+		item.Action = ActionDuplicate
+		item.Reason = "already imported"
+		if report.planned[key] {
+			item.Reason = "repeated in file"
+		}
+		if !dryRun || !report.planned[key] {
+			item.ID = id
+		}
+		report.add(item)
+		return id, false
+	}
+
+	id := utils.GenerateUUID()
+	ids[key] = id
+	if report.planned == nil {
+		report.planned = map[string]bool{}
+	}
+	report.planned[key] = true
+
+	item.Action = ActionCreate
+	if !dryRun {
+		item.ID = id
+	}
+	report.add(item)
+	return id, true
+}
+
+// createTask returns the writes that create the task, its checklist items
+// as subtasks and its links to tagIDs.
+func createTask(client *db.PrismaClient, userID, projectID, taskID, externalID string, task *PlanTask, tagIDs []string, now time.Time) []db.PrismaTransaction {
+	priority := task.Priority
+	if priority == "" {
+		priority = "medium"
+	}
+	done := 0
+	for _, check := range task.Checklist {
+		if check.Done {
+			done++
+		}
+	}
+	params := []db.TaskSetParam{
+		db.Task.ID.Set(taskID),
+		db.Task.ExternalID.Set(externalID),
+		db.Task.Priority.Set(priority),
+		db.Task.DueDate.SetIfPresent(task.DueDate),
+		db.Task.SubtaskCount.Set(len(task.Checklist)),
+		db.Task.SubtasksDone.Set(done),
+	}
+	if task.Description != "" {
+		params = append(params, db.Task.Description.Set(task.Description))
+	}
+	if task.Completed {
+		params = append(params,
+			db.Task.Status.Set("completed"),
+			db.Task.CompletedAt.Set(now),
+		)
+	}
+
+	txs := []db.PrismaTransaction{client.Task.CreateOne(
+		db.Task.Title.Set(task.Title),
+		db.Task.User.Link(db.User.ID.Equals(userID)),
+		db.Task.Project.Link(db.Project.ID.Equals(projectID)),
+		params...,
+	).Tx()}
+	for i, check := range task.Checklist {
+		subtaskParams := []db.SubtaskSetParam{
+			db.Subtask.ID.Set(utils.GenerateUUID()),
+			db.Subtask.Position.Set(i),
+		}
+		if check.Done {
+			subtaskParams = append(subtaskParams,
+				db.Subtask.Completed.Set(true),
+				db.Subtask.CompletedAt.Set(now),
+			)
+		}
+		txs = append(txs, client.Subtask.CreateOne(
+			db.Subtask.Title.Set(check.Title),
+			db.Subtask.Task.Link(db.Task.ID.Equals(taskID)),
+			subtaskParams...,
+		).Tx())
+	}
+	for _, tagID := range tagIDs {
+		txs = append(txs, tags.Link(client, taskID, tagID))
+	}
+	return txs
+}
+
+// planLabels returns every label used in the plan.
+func planLabels(plan *Plan) []string {
+	var labels []string
+	for _, folder := range plan.Folders {
+		for _, project := range folder.Projects {
+			for _, task := range project.Tasks {
+				labels = append(labels, task.Labels...)
+			}
+		}
+	}
+	return labels
+}
+
+// loadImported maps the kind-qualified stored external IDs of the plan's
+// records that already exist to their IDs.
+func loadImported(ctx context.Context, client *db.PrismaClient, userID string, plan *Plan) (map[string]string, error) {
+	var folderIDs, projectIDs, taskIDs []string
+	for _, folder := range plan.Folders {
+		folderIDs = append(folderIDs, plan.Source+":"+folder.ExternalID)
+		for _, project := range folder.Projects {
+			projectIDs = append(projectIDs, plan.Source+":"+project.ExternalID)
+			for _, task := range project.Tasks {
+				taskIDs = append(taskIDs, plan.Source+":"+task.ExternalID)
+			}
+		}
+	}
+
+	existing := map[string]string{}
+
+	folders, err := client.Folder.FindMany(
+		db.Folder.UserID.Equals(userID),
+		db.Folder.ExternalID.In(folderIDs),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		if externalID, ok := folder.ExternalID(); ok {
+			existing[KindFolder+" "+externalID] = folder.ID
+		}
+	}
+
+	projects, err := client.Project.FindMany(
+		db.Project.UserID.Equals(userID),
+		db.Project.ExternalID.In(projectIDs),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		if externalID, ok := project.ExternalID(); ok {
+			existing[KindProject+" "+externalID] = project.ID
+		}
+	}
+
+	tasks, err := client.Task.FindMany(
+		db.Task.UserID.Equals(userID),
+		db.Task.ExternalID.In(taskIDs),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		if externalID, ok := task.ExternalID(); ok {
+			existing[KindTask+" "+externalID] = task.ID
+		}
+	}
+
+	return existing, nil
+}
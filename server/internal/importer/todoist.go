@@ -0,0 +1,264 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	todoistSource = "todoist"
+
+	// todoistFolderName is the folder Todoist projects are imported into.
+	todoistFolderName = "Todoist"
+)
+
+// todoistID accepts both the numeric IDs of older Todoist APIs and the
+// string IDs of current ones.
+type todoistID string
+
+func (id *todoistID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*id = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = todoistID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = todoistID(n.String())
+	return nil
+}
+
+type todoistItem struct {
+	ID          todoistID `json:"id"`
+	ProjectID   todoistID `json:"project_id"`
+	ParentID    todoistID `json:"parent_id"`
+	Content     string    `json:"content"`
+	Description string    `json:"description"`
+	Priority    int       `json:"priority"`
+	Checked     bool      `json:"checked"`
+	IsDeleted   bool      `json:"is_deleted"`
+	Labels      []string  `json:"labels"`
+	Due         *struct {
+		Date string `json:"date"`
+	} `json:"due"`
+}
+
+type todoistBackup struct {
+	Projects []struct {
+		ID         todoistID `json:"id"`
+		Name       string    `json:"name"`
+		IsArchived bool      `json:"is_archived"`
+		IsDeleted  bool      `json:"is_deleted"`
+	} `json:"projects"`
+	Items []todoistItem `json:"items"`
+	Tasks []todoistItem `json:"tasks"`
+}
+
+// ParseTodoistJSON reads a Todoist sync or REST API dump with "projects" and
+// "items" (or "tasks"). Projects are imported into a single "Todoist" folder;
+// sub-tasks become checklist items of their top-level task.
+func ParseTodoistJSON(r io.Reader) (*Plan, error) {
+	var backup todoistBackup
+	if err := json.NewDecoder(r).Decode(&backup); err != nil {
+		return nil, err
+	}
+	items := append(backup.Items, backup.Tasks...)
+	if backup.Projects == nil {
+		return nil, errors.New("todoist: no projects found")
+	}
+
+	folder := PlanFolder{ExternalID: "folder", Name: todoistFolderName}
+	projects := map[todoistID]int{}
+	for _, project := range backup.Projects {
+		if project.IsArchived || project.IsDeleted {
+			continue
+		}
+		projects[project.ID] = len(folder.Projects)
+		folder.Projects = append(folder.Projects, PlanProject{
+			ExternalID: "project:" + string(project.ID),
+			Name:       project.Name,
+		})
+	}
+
+	byID := map[todoistID]*todoistItem{}
+	for i := range items {
+		byID[items[i].ID] = &items[i]
+	}
+	// root follows parent links up to the top-level task.
+	root := func(item *todoistItem) *todoistItem {
+		for depth := 0; item.ParentID != "" && depth < 10; depth++ {
+			parent, ok := byID[item.ParentID]
+			if !ok {
+				break
+			}
+			item = parent
+		}
+		return item
+	}
+
+	tasks := map[todoistID]*PlanTask{}
+	var order []todoistID
+	for i := range items {
+		item := &items[i]
+		if item.IsDeleted || item.ParentID != "" {
+			continue
+		}
+		if _, ok := projects[item.ProjectID]; !ok {
+			continue
+		}
+		task := &PlanTask{
+			ExternalID:  "task:" + string(item.ID),
+			Title:       item.Content,
+			Description: item.Description,
+			Priority:    todoistPriority(5 - item.Priority),
+			Completed:   item.Checked,
+			Labels:      item.Labels,
+		}
+		if item.Due != nil && item.Due.Date != "" {
+			if due, err := parseDate(item.Due.Date); err == nil {
+				task.DueDate = &due
+			}
+		}
+		tasks[item.ID] = task
+		order = append(order, item.ID)
+	}
+
+	for i := range items {
+		item := &items[i]
+		if item.IsDeleted || item.ParentID == "" {
+			continue
+		}
+		if task, ok := tasks[root(item).ID]; ok {
+			task.Checklist = append(task.Checklist, ChecklistItem{Title: item.Content, Done: item.Checked})
+		}
+	}
+
+	for _, id := range order {
+		i := projects[byID[id].ProjectID]
+		folder.Projects[i].Tasks = append(folder.Projects[i].Tasks, *tasks[id])
+	}
+
+	return &Plan{Source: todoistSource, Folders: []PlanFolder{folder}}, nil
+}
+
+// ParseTodoistCSV reads a Todoist project CSV backup. Each file holds one
+// project, named projectName. Rows with INDENT 1 are tasks and deeper rows
+// become checklist items of the task above them; sections and notes are
+// ignored. Labels are taken from @words in the content.
+func ParseTodoistCSV(r io.Reader, projectName string) (*Plan, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"TYPE", "CONTENT"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("todoist: missing %s column", required)
+		}
+	}
+	if projectName == "" {
+		projectName = todoistFolderName
+	}
+
+	project := PlanProject{ExternalID: "project:" + strings.ToLower(projectName), Name: projectName}
+	var current *PlanTask
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		if field("TYPE") != "task" {
+			continue
+		}
+		content, labels := todoistLabels(field("CONTENT"))
+		indent, _ := strconv.Atoi(field("INDENT"))
+		priority, _ := strconv.Atoi(field("PRIORITY"))
+
+		if indent > 1 && current != nil {
+			current.Checklist = append(current.Checklist, ChecklistItem{Title: content})
+			continue
+		}
+
+		project.Tasks = append(project.Tasks, PlanTask{
+			// Todoist CSV rows have no IDs; position plus content keeps
+			// re-imports of the same backup stable.
+			ExternalID:  fmt.Sprintf("task:%s:%d:%s", strings.ToLower(projectName), len(project.Tasks), content),
+			Title:       content,
+			Description: field("DESCRIPTION"),
+			Priority:    todoistPriority(priority),
+			Labels:      labels,
+		})
+		current = &project.Tasks[len(project.Tasks)-1]
+		if due, err := parseDate(field("DATE")); err == nil {
+			current.DueDate = &due
+		}
+	}
+
+	return &Plan{
+		Source: todoistSource,
+		Folders: []PlanFolder{{
+			ExternalID: "folder",
+			Name:       todoistFolderName,
+			Projects:   []PlanProject{project},
+		}},
+	}, nil
+}
+
+// todoistPriority maps Todoist's p1 (most urgent) to p4 (none) onto task
+// priorities. The API numbers them the other way round, 4 being p1.
+func todoistPriority(p int) string {
+	switch p {
+	case 1:
+		return "high"
+	case 3:
+		return "low"
+	case 2:
+		return "medium"
+	}
+	return ""
+}
+
+// todoistLabels removes @label words from content and returns them.
+func todoistLabels(content string) (string, []string) {
+	var (
+		words  []string
+		labels []string
+	)
+	for _, word := range strings.Fields(content) {
+		if len(word) > 1 && strings.HasPrefix(word, "@") {
+			labels = append(labels, word[1:])
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " "), labels
+}
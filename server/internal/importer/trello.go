@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+type trelloBoard struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Desc  string `json:"desc"`
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID          string     `json:"id"`
+		Name        string     `json:"name"`
+		Desc        string     `json:"desc"`
+		IDList      string     `json:"idList"`
+		Due         *time.Time `json:"due"`
+		DueComplete bool       `json:"dueComplete"`
+		Closed      bool       `json:"closed"`
+		Labels      []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string `json:"idCard"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+// ParseTrello reads a Trello board JSON export (Menu > Print, export and
+// share > Export as JSON). The board becomes a folder and each open list a
+// project. Archived cards and lists are left out; cards marked due-complete
+// import as completed.
+func ParseTrello(r io.Reader) (*Plan, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, err
+	}
+	if board.ID == "" || board.Lists == nil {
+		return nil, errors.New("trello: not a board export")
+	}
+
+	checklists := map[string][]ChecklistItem{}
+	for _, checklist := range board.Checklists {
+		for _, check := range checklist.CheckItems {
+			checklists[checklist.IDCard] = append(checklists[checklist.IDCard], ChecklistItem{
+				Title: check.Name,
+				Done:  check.State == "complete",
+			})
+		}
+	}
+
+	folder := PlanFolder{ExternalID: "board:" + board.ID, Name: board.Name}
+	lists := map[string]int{}
+	for _, list := range board.Lists {
+		if list.Closed {
+			continue
+		}
+		lists[list.ID] = len(folder.Projects)
+		folder.Projects = append(folder.Projects, PlanProject{
+			ExternalID: "list:" + list.ID,
+			Name:       list.Name,
+		})
+	}
+
+	for _, card := range board.Cards {
+		i, ok := lists[card.IDList]
+		if !ok || card.Closed {
+			continue
+		}
+
+		task := PlanTask{
+			ExternalID:  "card:" + card.ID,
+			Title:       card.Name,
+			Description: card.Desc,
+			DueDate:     card.Due,
+			Completed:   card.DueComplete,
+			Checklist:   checklists[card.ID],
+		}
+		for _, label := range card.Labels {
+			name := label.Name
+			if name == "" {
+				name = label.Color
+			}
+			// Labels named after a priority set it instead of being listed.
+			if priority := normalizePriority(name); priority != "" && task.Priority == "" {
+				task.Priority = priority
+				continue
+			}
+			if name = strings.TrimSpace(name); name != "" {
+				task.Labels = append(task.Labels, name)
+			}
+		}
+
+		folder.Projects[i].Tasks = append(folder.Projects[i].Tasks, task)
+	}
+
+	return &Plan{Source: FormatTrello, Folders: []PlanFolder{folder}}, nil
+}
@@ -0,0 +1,104 @@
+// Package live fans out events to GraphQL subscriptions. Once Start has run,
+// events travel through Postgres notifications, so that those published by
+// the REST server reach subscribers of the GraphQL server; without it they
+// only reach subscribers in the publishing process. Events are best effort:
+// a subscriber that falls behind, or whose process is reconnecting to the
+// database, misses events rather than holding up the publisher.
+package live
+
+import (
+	"log"
+	"sync"
+)
+
+// Buffer is how many events a subscriber can fall behind by before it
+// starts missing them.
+const Buffer = 16
+
+// Task event actions.
+const (
+	ActionCommentAdded   = "comment.added"
+	ActionCommentEdited  = "comment.edited"
+	ActionCommentDeleted = "comment.deleted"
+
+	ActionAttachmentAdded   = "attachment.added"
+	ActionAttachmentDeleted = "attachment.deleted"
+)
+
+// TaskEvent reports a change to a task to subscribers of its project.
+type TaskEvent struct {
+	Action string
+	TaskID string
+	UserID string // owner of the task
+}
+
+// NotificationEvent reports a new notification to its recipient.
+type NotificationEvent struct {
+	NotificationID string
+}
+
+var (
+	mu          sync.Mutex
+	subscribers = map[string]map[chan interface{}]struct{}{}
+)
+
+// ProjectTopic carries TaskEvents for the project's tasks.
+func ProjectTopic(projectID string) string {
+	return "project:" + projectID
+}
+
+// UserTopic carries NotificationEvents for the user.
+func UserTopic(userID string) string {
+	return "user:" + userID
+}
+
+// Subscribe returns a channel of the events published to topic, and a
+// function that unsubscribes and closes the channel.
+func Subscribe(topic string) (<-chan interface{}, func()) {
+	ch := make(chan interface{}, Buffer)
+
+	mu.Lock()
+	if subscribers[topic] == nil {
+		subscribers[topic] = map[chan interface{}]struct{}{}
+	}
+	subscribers[topic][ch] = struct{}{}
+	mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			mu.Lock()
+			delete(subscribers[topic], ch)
+			if len(subscribers[topic]) == 0 {
+				delete(subscribers, topic)
+			}
+			mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish sends event to every subscriber of topic without blocking: through
+// Postgres once Start has run, and to this process's subscribers directly
+// before.
+func Publish(topic string, event interface{}) {
+	if publisher == nil {
+		deliver(topic, event)
+		return
+	}
+	if err := notify(topic, event); err != nil {
+		log.Printf("live: failed to publish to %s: %v", topic, err)
+	}
+}
+
+// deliver sends event to this process's subscribers of topic.
+func deliver(topic string, event interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	for ch := range subscribers[topic] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
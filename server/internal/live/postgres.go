@@ -0,0 +1,140 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"lifequest-server/internal/db"
+)
+
+// Channel is the Postgres notification channel events travel on between
+// processes.
+const Channel = "live_events"
+
+// retryDelay is how long Listen waits before reconnecting after losing its
+// connection.
+const retryDelay = 5 * time.Second
+
+var publisher *db.PrismaClient
+
+// message is the payload of a notification on Channel.
+type message struct {
+	Topic        string             `json:"topic"`
+	Task         *TaskEvent         `json:"task,omitempty"`
+	Notification *NotificationEvent `json:"notification,omitempty"`
+}
+
+// Start makes Publish send events through Postgres with client, so that they
+// reach the subscribers of every process running Listen, this one included.
+func Start(client *db.PrismaClient) {
+	publisher = client
+}
+
+// notify sends event to topic's subscribers in every listening process.
+func notify(topic string, event interface{}) error {
+	m := message{Topic: topic}
+	switch event := event.(type) {
+	case TaskEvent:
+		m.Task = &event
+	case NotificationEvent:
+		m.Notification = &event
+	default:
+		return fmt.Errorf("can't send %T between processes", event)
+	}
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = publisher.Prisma.ExecuteRaw(`SELECT pg_notify($1, $2)`, Channel, string(payload)).Exec(ctx)
+	return err
+}
+
+// Listen delivers the events published through Postgres by any process to
+// this process's subscribers until ctx is cancelled. It connects to the
+// database at databaseURL, the URL Prisma uses, and reconnects after losing
+// the connection; events sent in between are missed.
+func Listen(ctx context.Context, databaseURL string) {
+	go func() {
+		for {
+			err := listen(ctx, databaseURL)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("live: lost the Postgres listener, reconnecting: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryDelay):
+			}
+		}
+	}()
+}
+
+func listen(ctx context.Context, databaseURL string) error {
+	config, err := pgconn.ParseConfig(connString(databaseURL))
+	if err != nil {
+		return err
+	}
+	config.OnNotification = func(_ *pgconn.PgConn, notification *pgconn.Notification) {
+		receive(notification.Payload)
+	}
+	conn, err := pgconn.ConnectConfig(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if err := conn.Exec(ctx, "LISTEN "+Channel).Close(); err != nil {
+		return err
+	}
+	for {
+		if err := conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// receive delivers a notification's event to this process's subscribers.
+func receive(payload string) {
+	var m message
+	if err := json.Unmarshal([]byte(payload), &m); err != nil {
+		log.Printf("live: dropping malformed event: %v", err)
+		return
+	}
+	switch {
+	case m.Task != nil:
+		deliver(m.Topic, *m.Task)
+	case m.Notification != nil:
+		deliver(m.Topic, *m.Notification)
+	}
+}
+
+// prismaParams are the connection URL parameters only Prisma understands,
+// which Postgres would reject as unknown settings.
+var prismaParams = []string{
+	"schema", "connection_limit", "pool_timeout", "pgbouncer",
+	"statement_cache_size", "socket_timeout", "sslaccept", "sslidentity",
+}
+
+// connString turns a Prisma connection URL into one pgconn accepts.
+func connString(databaseURL string) string {
+	u, err := url.Parse(databaseURL)
+	if err != nil || u.Scheme == "" {
+		return databaseURL
+	}
+	query := u.Query()
+	for _, param := range prismaParams {
+		query.Del(param)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,91 @@
+package live
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+func TestConnString(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"postgresql://app:secret@db:5432/lifequest", "postgresql://app:secret@db:5432/lifequest"},
+		{"postgresql://app@db/lifequest?schema=public", "postgresql://app@db/lifequest"},
+		{"postgres://db/lifequest?schema=public&sslmode=require&connection_limit=5", "postgres://db/lifequest?sslmode=require"},
+		{"host=db dbname=lifequest", "host=db dbname=lifequest"},
+	}
+	for _, tt := range tests {
+		if got := connString(tt.url); got != tt.want {
+			t.Errorf("connString(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestReceive(t *testing.T) {
+	events, unsubscribe := Subscribe(ProjectTopic("p1"))
+	defer unsubscribe()
+
+	receive(`not json`)
+	receive(`{"topic":"project:p2","task":{"Action":"comment.added","TaskID":"t2","UserID":"u1"}}`)
+	receive(`{"topic":"project:p1","task":{"Action":"comment.added","TaskID":"t1","UserID":"u1"}}`)
+
+	select {
+	case event := <-events:
+		want := TaskEvent{Action: ActionCommentAdded, TaskID: "t1", UserID: "u1"}
+		if event != want {
+			t.Errorf("got %#v, want %#v", event, want)
+		}
+	default:
+		t.Fatal("no event delivered")
+	}
+	select {
+	case event := <-events:
+		t.Errorf("unexpected event %#v", event)
+	default:
+	}
+}
+
+// TestPublishThroughPostgres sends an event through the database named by
+// TEST_DATABASE_URL, and is skipped without one.
+func TestPublishThroughPostgres(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	client := db.NewClient(db.WithDatasourceURL(url))
+	if err := client.Prisma.Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Prisma.Disconnect()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	Start(client)
+	defer Start(nil)
+	Listen(ctx, url)
+
+	events, unsubscribe := Subscribe(UserTopic("u1"))
+	defer unsubscribe()
+
+	// The listener connects in the background; publish until it hears.
+	want := NotificationEvent{NotificationID: "n1"}
+	deadline := time.After(10 * time.Second)
+	for {
+		Publish(UserTopic("u1"), want)
+		select {
+		case event := <-events:
+			if event != want {
+				t.Fatalf("got %#v, want %#v", event, want)
+			}
+			return
+		case <-time.After(200 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("no event delivered")
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Package mail sends plain-text email through the SMTP server configured in
+// the environment, or logs it when none is.
+package mail
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Message is a plain-text email to one recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers messages.
+type Sender interface {
+	Send(msg Message) error
+}
+
+var (
+	defaultSender Sender
+	defaultOnce   sync.Once
+)
+
+// Default returns the sender configured by FromEnv, created on first use.
+func Default() Sender {
+	defaultOnce.Do(func() {
+		defaultSender = FromEnv()
+	})
+	return defaultSender
+}
+
+// FromEnv returns an SMTP sender for SMTP_ADDR (host:port) sending from
+// SMTP_FROM, authenticating with SMTP_USERNAME and SMTP_PASSWORD when set,
+// or a sender that only logs messages when SMTP_ADDR is not set.
+func FromEnv() Sender {
+	addr := os.Getenv("SMTP_ADDR")
+	if addr == "" {
+		return LogSender{}
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = "LifeQuest <noreply@lifequest.local>"
+	}
+	sender := &SMTPSender{Addr: addr, From: from}
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		sender.Auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+	return sender
+}
+
+// AppURL is the base URL of the web client, used to link back to it from
+// emails: APP_URL, or the development server.
+func AppURL() string {
+	if value := os.Getenv("APP_URL"); value != "" {
+		return strings.TrimRight(value, "/")
+	}
+	return "http://localhost:5173"
+}
+
+// SMTPSender sends through an SMTP server.
+type SMTPSender struct {
+	Addr string
+	From string
+	Auth smtp.Auth
+}
+
+// Send delivers msg to its recipient.
+func (s *SMTPSender) Send(msg Message) error {
+	if strings.ContainsAny(msg.To+msg.Subject, "\r\n") {
+		return fmt.Errorf("mail: header contains a line break")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	from := s.From
+	if i := strings.LastIndex(from, "<"); i >= 0 {
+		from = strings.TrimSuffix(from[i+1:], ">")
+	}
+	return smtp.SendMail(s.Addr, s.Auth, from, []string{msg.To}, []byte(b.String()))
+}
+
+// LogSender logs messages instead of sending them, for development.
+type LogSender struct{}
+
+// Send logs msg.
+func (LogSender) Send(msg Message) error {
+	log.Printf("mail: to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -2,9 +2,10 @@ package middleware
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/auth"
 )
 
 func AuthMiddleware() gin.HandlerFunc {
@@ -17,27 +18,20 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		// Extract token from "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
+		token, ok := auth.BearerToken(authHeader)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
 			c.Abort()
 			return
 		}
 
-		token := parts[1]
-		
-		// TODO: Verify JWT token with Clerk or implement JWT verification
-		// For now, we'll just check if token exists
-		if token == "" {
+		userID, ok := auth.UserID(token)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
-		// Extract user ID from token (mock implementation)
-		// In a real implementation, you'd decode the JWT and extract the user ID
-		userID := "user_123" // This should come from the decoded JWT
-
 		// Store user ID in context for handlers to use
 		c.Set("userID", userID)
 		c.Next()
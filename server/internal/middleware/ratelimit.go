@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"lifequest-server/internal/ratelimit"
+)
+
+// RateLimit limits requests of the signed-in user, or of the client's IP
+// address before authentication, under the limiter's query rule for reads
+// and its mutation rule for writes.
+func RateLimit(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rule := limiter.Mutations
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			rule = limiter.Queries
+		}
+		limit(c, limiter, rule)
+	}
+}
+
+// AuthRateLimit limits authentication requests under the limiter's auth
+// rule; put it before the authentication it protects.
+func AuthRateLimit(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit(c, limiter, limiter.Auth)
+	}
+}
+
+func limit(c *gin.Context, limiter *ratelimit.Limiter, rule ratelimit.Rule) {
+	userID, _ := c.Get("userID")
+	id, _ := userID.(string)
+	result := limiter.Take(c.Request.Context(), rule, ratelimit.Caller(id, c.ClientIP()))
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(int(result.Reset.Seconds())))
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+		c.Abort()
+		return
+	}
+	c.Next()
+}
@@ -0,0 +1,117 @@
+// Package notifications stores in-app notifications and announces new ones
+// to the recipient's live subscriptions.
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/live"
+	"lifequest-server/internal/pagination"
+	"lifequest-server/internal/utils"
+)
+
+// Notification types stored on Notification.type.
+const (
+	TypeCommentMention      = "comment_mention"
+	TypeCollaborationInvite = "collaboration_invite"
+)
+
+var ErrNotFound = errors.New("notification not found")
+
+// Create stores a notification for userID with data encoded as JSON and
+// announces it on the user's live topic.
+func Create(ctx context.Context, client *db.PrismaClient, userID, kind, title, message string, data interface{}) (*db.NotificationModel, error) {
+	params := []db.NotificationSetParam{
+		db.Notification.ID.Set(utils.GenerateUUID()),
+	}
+	if data != nil {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, db.Notification.Data.Set(string(encoded)))
+	}
+
+	notification, err := client.Notification.CreateOne(
+		db.Notification.Type.Set(kind),
+		db.Notification.Title.Set(title),
+		db.Notification.Message.Set(message),
+		db.Notification.User.Link(db.User.ID.Equals(userID)),
+		params...,
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	live.Publish(live.UserTopic(userID), live.NotificationEvent{NotificationID: notification.ID})
+	return notification, nil
+}
+
+// List returns a page of the user's notifications, newest first by
+// default.
+func List(ctx context.Context, client *db.PrismaClient, userID string, unreadOnly bool, args pagination.Args) (*pagination.Page[db.NotificationModel], error) {
+	filters := []db.NotificationWhereParam{
+		db.Notification.UserID.Equals(userID),
+	}
+	if unreadOnly {
+		filters = append(filters, db.Notification.ReadAt.IsNull())
+	}
+	return pagination.Fetch(pagination.Notifications, args, func(where []db.NotificationWhereParam, order []db.NotificationOrderByParam, take int) ([]db.NotificationModel, error) {
+		return client.Notification.FindMany(append(filters, where...)...).OrderBy(order...).Take(take).Exec(ctx)
+	})
+}
+
+// Get returns one of the user's notifications.
+func Get(ctx context.Context, client *db.PrismaClient, userID, id string) (*db.NotificationModel, error) {
+	notification, err := client.Notification.FindFirst(
+		db.Notification.ID.Equals(id),
+		db.Notification.UserID.Equals(userID),
+	).Exec(ctx)
+	if db.IsErrNotFound(err) {
+		return nil, ErrNotFound
+	}
+	return notification, err
+}
+
+// UnreadCount returns how many of the user's notifications are unread.
+func UnreadCount(ctx context.Context, client *db.PrismaClient, userID string) (int, error) {
+	unread, err := client.Notification.FindMany(
+		db.Notification.UserID.Equals(userID),
+		db.Notification.ReadAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(unread), nil
+}
+
+// MarkRead marks one of the user's notifications as read.
+func MarkRead(ctx context.Context, client *db.PrismaClient, userID, id string) (*db.NotificationModel, error) {
+	notification, err := Get(ctx, client, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, read := notification.ReadAt(); read {
+		return notification, nil
+	}
+	return client.Notification.FindUnique(
+		db.Notification.ID.Equals(notification.ID),
+	).Update(
+		db.Notification.ReadAt.Set(time.Now()),
+	).Exec(ctx)
+}
+
+// MarkAllRead marks all of the user's notifications as read.
+func MarkAllRead(ctx context.Context, client *db.PrismaClient, userID string) error {
+	_, err := client.Notification.FindMany(
+		db.Notification.UserID.Equals(userID),
+		db.Notification.ReadAt.IsNull(),
+	).Update(
+		db.Notification.ReadAt.Set(time.Now()),
+	).Exec(ctx)
+	return err
+}
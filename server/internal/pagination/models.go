@@ -0,0 +1,258 @@
+package pagination
+
+import (
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+// Sort keys of each model, the default first. Names and titles sort in the
+// database collation; keys on optional columns put missing values last
+// ascending and first descending.
+var (
+	Projects         = projectModel()         // createdAt, updatedAt, name, dueDate
+	Tasks            = taskModel()            // createdAt, updatedAt, dueDate, title
+	Sprints          = sprintModel()          // startDate, createdAt, name
+	PomodoroSessions = pomodoroSessionModel() // startTime, createdAt
+	Notifications    = notificationModel()    // createdAt
+	Folders          = folderModel()          // createdAt, name, updatedAt
+)
+
+func projectModel() *Model[db.ProjectModel, db.ProjectWhereParam, db.ProjectOrderByParam] {
+	m := &Model[db.ProjectModel, db.ProjectWhereParam, db.ProjectOrderByParam]{
+		ID: func(row *db.ProjectModel) string { return row.ID },
+		IDAfter: func(id string, desc bool) db.ProjectWhereParam {
+			if desc {
+				return db.Project.ID.Lt(id)
+			}
+			return db.Project.ID.Gt(id)
+		},
+		IDOrder: func(order db.SortOrder) db.ProjectOrderByParam { return db.Project.ID.Order(order) },
+		Or:      func(params ...db.ProjectWhereParam) db.ProjectWhereParam { return db.Project.Or(params...) },
+		And:     func(params ...db.ProjectWhereParam) db.ProjectWhereParam { return db.Project.And(params...) },
+		Not:     func(params ...db.ProjectWhereParam) db.ProjectWhereParam { return db.Project.Not(params...) },
+	}
+	Sort(m, "createdAt", false, Column[db.ProjectModel, db.ProjectWhereParam, db.ProjectOrderByParam, time.Time]{
+		Get:    func(row *db.ProjectModel) (time.Time, bool) { return row.CreatedAt, true },
+		Gt:     func(v time.Time) db.ProjectWhereParam { return db.Project.CreatedAt.Gt(v) },
+		Lt:     func(v time.Time) db.ProjectWhereParam { return db.Project.CreatedAt.Lt(v) },
+		Equals: func(v time.Time) db.ProjectWhereParam { return db.Project.CreatedAt.Equals(v) },
+		Order:  func(order db.SortOrder) db.ProjectOrderByParam { return db.Project.CreatedAt.Order(order) },
+	}, Time)
+	Sort(m, "updatedAt", true, Column[db.ProjectModel, db.ProjectWhereParam, db.ProjectOrderByParam, time.Time]{
+		Get:    func(row *db.ProjectModel) (time.Time, bool) { return row.UpdatedAt, true },
+		Gt:     func(v time.Time) db.ProjectWhereParam { return db.Project.UpdatedAt.Gt(v) },
+		Lt:     func(v time.Time) db.ProjectWhereParam { return db.Project.UpdatedAt.Lt(v) },
+		Equals: func(v time.Time) db.ProjectWhereParam { return db.Project.UpdatedAt.Equals(v) },
+		Order:  func(order db.SortOrder) db.ProjectOrderByParam { return db.Project.UpdatedAt.Order(order) },
+	}, Time)
+	Sort(m, "name", false, Column[db.ProjectModel, db.ProjectWhereParam, db.ProjectOrderByParam, string]{
+		Get:    func(row *db.ProjectModel) (string, bool) { return row.Name, true },
+		Gt:     func(v string) db.ProjectWhereParam { return db.Project.Name.Gt(v) },
+		Lt:     func(v string) db.ProjectWhereParam { return db.Project.Name.Lt(v) },
+		Equals: func(v string) db.ProjectWhereParam { return db.Project.Name.Equals(v) },
+		Order:  func(order db.SortOrder) db.ProjectOrderByParam { return db.Project.Name.Order(order) },
+	}, String)
+	Sort(m, "dueDate", false, Column[db.ProjectModel, db.ProjectWhereParam, db.ProjectOrderByParam, time.Time]{
+		Get:    func(row *db.ProjectModel) (time.Time, bool) { return row.DueDate() },
+		Gt:     func(v time.Time) db.ProjectWhereParam { return db.Project.DueDate.Gt(v) },
+		Lt:     func(v time.Time) db.ProjectWhereParam { return db.Project.DueDate.Lt(v) },
+		Equals: func(v time.Time) db.ProjectWhereParam { return db.Project.DueDate.Equals(v) },
+		IsNull: func() db.ProjectWhereParam { return db.Project.DueDate.IsNull() },
+		Order:  func(order db.SortOrder) db.ProjectOrderByParam { return db.Project.DueDate.Order(order) },
+	}, Time)
+	return m
+}
+
+func taskModel() *Model[db.TaskModel, db.TaskWhereParam, db.TaskOrderByParam] {
+	m := &Model[db.TaskModel, db.TaskWhereParam, db.TaskOrderByParam]{
+		ID: func(row *db.TaskModel) string { return row.ID },
+		IDAfter: func(id string, desc bool) db.TaskWhereParam {
+			if desc {
+				return db.Task.ID.Lt(id)
+			}
+			return db.Task.ID.Gt(id)
+		},
+		IDOrder: func(order db.SortOrder) db.TaskOrderByParam { return db.Task.ID.Order(order) },
+		Or:      func(params ...db.TaskWhereParam) db.TaskWhereParam { return db.Task.Or(params...) },
+		And:     func(params ...db.TaskWhereParam) db.TaskWhereParam { return db.Task.And(params...) },
+		Not:     func(params ...db.TaskWhereParam) db.TaskWhereParam { return db.Task.Not(params...) },
+	}
+	Sort(m, "createdAt", false, Column[db.TaskModel, db.TaskWhereParam, db.TaskOrderByParam, time.Time]{
+		Get:    func(row *db.TaskModel) (time.Time, bool) { return row.CreatedAt, true },
+		Gt:     func(v time.Time) db.TaskWhereParam { return db.Task.CreatedAt.Gt(v) },
+		Lt:     func(v time.Time) db.TaskWhereParam { return db.Task.CreatedAt.Lt(v) },
+		Equals: func(v time.Time) db.TaskWhereParam { return db.Task.CreatedAt.Equals(v) },
+		Order:  func(order db.SortOrder) db.TaskOrderByParam { return db.Task.CreatedAt.Order(order) },
+	}, Time)
+	Sort(m, "updatedAt", true, Column[db.TaskModel, db.TaskWhereParam, db.TaskOrderByParam, time.Time]{
+		Get:    func(row *db.TaskModel) (time.Time, bool) { return row.UpdatedAt, true },
+		Gt:     func(v time.Time) db.TaskWhereParam { return db.Task.UpdatedAt.Gt(v) },
+		Lt:     func(v time.Time) db.TaskWhereParam { return db.Task.UpdatedAt.Lt(v) },
+		Equals: func(v time.Time) db.TaskWhereParam { return db.Task.UpdatedAt.Equals(v) },
+		Order:  func(order db.SortOrder) db.TaskOrderByParam { return db.Task.UpdatedAt.Order(order) },
+	}, Time)
+	Sort(m, "dueDate", false, Column[db.TaskModel, db.TaskWhereParam, db.TaskOrderByParam, time.Time]{
+		Get:    func(row *db.TaskModel) (time.Time, bool) { return row.DueDate() },
+		Gt:     func(v time.Time) db.TaskWhereParam { return db.Task.DueDate.Gt(v) },
+		Lt:     func(v time.Time) db.TaskWhereParam { return db.Task.DueDate.Lt(v) },
+		Equals: func(v time.Time) db.TaskWhereParam { return db.Task.DueDate.Equals(v) },
+		IsNull: func() db.TaskWhereParam { return db.Task.DueDate.IsNull() },
+		Order:  func(order db.SortOrder) db.TaskOrderByParam { return db.Task.DueDate.Order(order) },
+	}, Time)
+	Sort(m, "title", false, Column[db.TaskModel, db.TaskWhereParam, db.TaskOrderByParam, string]{
+		Get:    func(row *db.TaskModel) (string, bool) { return row.Title, true },
+		Gt:     func(v string) db.TaskWhereParam { return db.Task.Title.Gt(v) },
+		Lt:     func(v string) db.TaskWhereParam { return db.Task.Title.Lt(v) },
+		Equals: func(v string) db.TaskWhereParam { return db.Task.Title.Equals(v) },
+		Order:  func(order db.SortOrder) db.TaskOrderByParam { return db.Task.Title.Order(order) },
+	}, String)
+	return m
+}
+
+func sprintModel() *Model[db.SprintModel, db.SprintWhereParam, db.SprintOrderByParam] {
+	m := &Model[db.SprintModel, db.SprintWhereParam, db.SprintOrderByParam]{
+		ID: func(row *db.SprintModel) string { return row.ID },
+		IDAfter: func(id string, desc bool) db.SprintWhereParam {
+			if desc {
+				return db.Sprint.ID.Lt(id)
+			}
+			return db.Sprint.ID.Gt(id)
+		},
+		IDOrder: func(order db.SortOrder) db.SprintOrderByParam { return db.Sprint.ID.Order(order) },
+		Or:      func(params ...db.SprintWhereParam) db.SprintWhereParam { return db.Sprint.Or(params...) },
+		And:     func(params ...db.SprintWhereParam) db.SprintWhereParam { return db.Sprint.And(params...) },
+		Not:     func(params ...db.SprintWhereParam) db.SprintWhereParam { return db.Sprint.Not(params...) },
+	}
+	Sort(m, "startDate", true, Column[db.SprintModel, db.SprintWhereParam, db.SprintOrderByParam, time.Time]{
+		Get:    func(row *db.SprintModel) (time.Time, bool) { return row.StartDate, true },
+		Gt:     func(v time.Time) db.SprintWhereParam { return db.Sprint.StartDate.Gt(v) },
+		Lt:     func(v time.Time) db.SprintWhereParam { return db.Sprint.StartDate.Lt(v) },
+		Equals: func(v time.Time) db.SprintWhereParam { return db.Sprint.StartDate.Equals(v) },
+		Order:  func(order db.SortOrder) db.SprintOrderByParam { return db.Sprint.StartDate.Order(order) },
+	}, Time)
+	Sort(m, "createdAt", true, Column[db.SprintModel, db.SprintWhereParam, db.SprintOrderByParam, time.Time]{
+		Get:    func(row *db.SprintModel) (time.Time, bool) { return row.CreatedAt, true },
+		Gt:     func(v time.Time) db.SprintWhereParam { return db.Sprint.CreatedAt.Gt(v) },
+		Lt:     func(v time.Time) db.SprintWhereParam { return db.Sprint.CreatedAt.Lt(v) },
+		Equals: func(v time.Time) db.SprintWhereParam { return db.Sprint.CreatedAt.Equals(v) },
+		Order:  func(order db.SortOrder) db.SprintOrderByParam { return db.Sprint.CreatedAt.Order(order) },
+	}, Time)
+	Sort(m, "name", false, Column[db.SprintModel, db.SprintWhereParam, db.SprintOrderByParam, string]{
+		Get:    func(row *db.SprintModel) (string, bool) { return row.Name, true },
+		Gt:     func(v string) db.SprintWhereParam { return db.Sprint.Name.Gt(v) },
+		Lt:     func(v string) db.SprintWhereParam { return db.Sprint.Name.Lt(v) },
+		Equals: func(v string) db.SprintWhereParam { return db.Sprint.Name.Equals(v) },
+		Order:  func(order db.SortOrder) db.SprintOrderByParam { return db.Sprint.Name.Order(order) },
+	}, String)
+	return m
+}
+
+func pomodoroSessionModel() *Model[db.PomodoroSessionModel, db.PomodoroSessionWhereParam, db.PomodoroSessionOrderByParam] {
+	m := &Model[db.PomodoroSessionModel, db.PomodoroSessionWhereParam, db.PomodoroSessionOrderByParam]{
+		ID: func(row *db.PomodoroSessionModel) string { return row.ID },
+		IDAfter: func(id string, desc bool) db.PomodoroSessionWhereParam {
+			if desc {
+				return db.PomodoroSession.ID.Lt(id)
+			}
+			return db.PomodoroSession.ID.Gt(id)
+		},
+		IDOrder: func(order db.SortOrder) db.PomodoroSessionOrderByParam { return db.PomodoroSession.ID.Order(order) },
+		Or: func(params ...db.PomodoroSessionWhereParam) db.PomodoroSessionWhereParam {
+			return db.PomodoroSession.Or(params...)
+		},
+		And: func(params ...db.PomodoroSessionWhereParam) db.PomodoroSessionWhereParam {
+			return db.PomodoroSession.And(params...)
+		},
+		Not: func(params ...db.PomodoroSessionWhereParam) db.PomodoroSessionWhereParam {
+			return db.PomodoroSession.Not(params...)
+		},
+	}
+	Sort(m, "startTime", true, Column[db.PomodoroSessionModel, db.PomodoroSessionWhereParam, db.PomodoroSessionOrderByParam, time.Time]{
+		Get:    func(row *db.PomodoroSessionModel) (time.Time, bool) { return row.StartTime, true },
+		Gt:     func(v time.Time) db.PomodoroSessionWhereParam { return db.PomodoroSession.StartTime.Gt(v) },
+		Lt:     func(v time.Time) db.PomodoroSessionWhereParam { return db.PomodoroSession.StartTime.Lt(v) },
+		Equals: func(v time.Time) db.PomodoroSessionWhereParam { return db.PomodoroSession.StartTime.Equals(v) },
+		Order: func(order db.SortOrder) db.PomodoroSessionOrderByParam {
+			return db.PomodoroSession.StartTime.Order(order)
+		},
+	}, Time)
+	Sort(m, "createdAt", true, Column[db.PomodoroSessionModel, db.PomodoroSessionWhereParam, db.PomodoroSessionOrderByParam, time.Time]{
+		Get:    func(row *db.PomodoroSessionModel) (time.Time, bool) { return row.CreatedAt, true },
+		Gt:     func(v time.Time) db.PomodoroSessionWhereParam { return db.PomodoroSession.CreatedAt.Gt(v) },
+		Lt:     func(v time.Time) db.PomodoroSessionWhereParam { return db.PomodoroSession.CreatedAt.Lt(v) },
+		Equals: func(v time.Time) db.PomodoroSessionWhereParam { return db.PomodoroSession.CreatedAt.Equals(v) },
+		Order: func(order db.SortOrder) db.PomodoroSessionOrderByParam {
+			return db.PomodoroSession.CreatedAt.Order(order)
+		},
+	}, Time)
+	return m
+}
+
+func notificationModel() *Model[db.NotificationModel, db.NotificationWhereParam, db.NotificationOrderByParam] {
+	m := &Model[db.NotificationModel, db.NotificationWhereParam, db.NotificationOrderByParam]{
+		ID: func(row *db.NotificationModel) string { return row.ID },
+		IDAfter: func(id string, desc bool) db.NotificationWhereParam {
+			if desc {
+				return db.Notification.ID.Lt(id)
+			}
+			return db.Notification.ID.Gt(id)
+		},
+		IDOrder: func(order db.SortOrder) db.NotificationOrderByParam { return db.Notification.ID.Order(order) },
+		Or: func(params ...db.NotificationWhereParam) db.NotificationWhereParam {
+			return db.Notification.Or(params...)
+		},
+		And: func(params ...db.NotificationWhereParam) db.NotificationWhereParam {
+			return db.Notification.And(params...)
+		},
+		Not: func(params ...db.NotificationWhereParam) db.NotificationWhereParam {
+			return db.Notification.Not(params...)
+		},
+	}
+	Sort(m, "createdAt", true, Column[db.NotificationModel, db.NotificationWhereParam, db.NotificationOrderByParam, time.Time]{
+		Get:    func(row *db.NotificationModel) (time.Time, bool) { return row.CreatedAt, true },
+		Gt:     func(v time.Time) db.NotificationWhereParam { return db.Notification.CreatedAt.Gt(v) },
+		Lt:     func(v time.Time) db.NotificationWhereParam { return db.Notification.CreatedAt.Lt(v) },
+		Equals: func(v time.Time) db.NotificationWhereParam { return db.Notification.CreatedAt.Equals(v) },
+		Order:  func(order db.SortOrder) db.NotificationOrderByParam { return db.Notification.CreatedAt.Order(order) },
+	}, Time)
+	return m
+}
+
+func folderModel() *Model[db.FolderModel, db.FolderWhereParam, db.FolderOrderByParam] {
+	m := &Model[db.FolderModel, db.FolderWhereParam, db.FolderOrderByParam]{
+		ID: func(row *db.FolderModel) string { return row.ID },
+		IDAfter: func(id string, desc bool) db.FolderWhereParam {
+			if desc {
+				return db.Folder.ID.Lt(id)
+			}
+			return db.Folder.ID.Gt(id)
+		},
+		IDOrder: func(order db.SortOrder) db.FolderOrderByParam { return db.Folder.ID.Order(order) },
+		Or:      func(params ...db.FolderWhereParam) db.FolderWhereParam { return db.Folder.Or(params...) },
+		And:     func(params ...db.FolderWhereParam) db.FolderWhereParam { return db.Folder.And(params...) },
+		Not:     func(params ...db.FolderWhereParam) db.FolderWhereParam { return db.Folder.Not(params...) },
+	}
+	Sort(m, "createdAt", false, Column[db.FolderModel, db.FolderWhereParam, db.FolderOrderByParam, time.Time]{
+		Get:    func(row *db.FolderModel) (time.Time, bool) { return row.CreatedAt, true },
+		Gt:     func(v time.Time) db.FolderWhereParam { return db.Folder.CreatedAt.Gt(v) },
+		Lt:     func(v time.Time) db.FolderWhereParam { return db.Folder.CreatedAt.Lt(v) },
+		Equals: func(v time.Time) db.FolderWhereParam { return db.Folder.CreatedAt.Equals(v) },
+		Order:  func(order db.SortOrder) db.FolderOrderByParam { return db.Folder.CreatedAt.Order(order) },
+	}, Time)
+	Sort(m, "name", false, Column[db.FolderModel, db.FolderWhereParam, db.FolderOrderByParam, string]{
+		Get:    func(row *db.FolderModel) (string, bool) { return row.Name, true },
+		Gt:     func(v string) db.FolderWhereParam { return db.Folder.Name.Gt(v) },
+		Lt:     func(v string) db.FolderWhereParam { return db.Folder.Name.Lt(v) },
+		Equals: func(v string) db.FolderWhereParam { return db.Folder.Name.Equals(v) },
+		Order:  func(order db.SortOrder) db.FolderOrderByParam { return db.Folder.Name.Order(order) },
+	}, String)
+	Sort(m, "updatedAt", true, Column[db.FolderModel, db.FolderWhereParam, db.FolderOrderByParam, time.Time]{
+		Get:    func(row *db.FolderModel) (time.Time, bool) { return row.UpdatedAt, true },
+		Gt:     func(v time.Time) db.FolderWhereParam { return db.Folder.UpdatedAt.Gt(v) },
+		Lt:     func(v time.Time) db.FolderWhereParam { return db.Folder.UpdatedAt.Lt(v) },
+		Equals: func(v time.Time) db.FolderWhereParam { return db.Folder.UpdatedAt.Equals(v) },
+		Order:  func(order db.SortOrder) db.FolderOrderByParam { return db.Folder.UpdatedAt.Order(order) },
+	}, Time)
+	return m
+}
@@ -0,0 +1,309 @@
+// Package pagination implements cursor pagination over Prisma models.
+// Pages are keyset-based: a cursor holds the sort key value and ID of the
+// last row of a page, and the next page starts strictly after it in
+// (key, ID) order, so rows created or deleted between requests don't shift
+// later pages. Cursors are opaque to clients and only valid with the sort
+// and direction they were issued for.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = errors.New("limit must be between 1 and 200")
+	ErrInvalidSort   = errors.New("unknown sort key")
+)
+
+// Args select a page: up to First rows after the cursor After, sorted by
+// the key Sort. A zero First is DefaultLimit, an empty Sort the model's
+// default key, and a nil Descending the key's natural direction.
+type Args struct {
+	First      int
+	After      string
+	Sort       string
+	Descending *bool
+}
+
+// Page is a page of rows with the cursor of each.
+type Page[T any] struct {
+	Rows            []T
+	Cursors         []string
+	HasNextPage     bool
+	HasPreviousPage bool // the page starts after a cursor
+}
+
+// StartCursor returns the cursor of the first row, or "" for an empty page.
+func (p *Page[T]) StartCursor() string {
+	if len(p.Cursors) == 0 {
+		return ""
+	}
+	return p.Cursors[0]
+}
+
+// EndCursor returns the cursor of the last row, or "" for an empty page.
+func (p *Page[T]) EndCursor() string {
+	if len(p.Cursors) == 0 {
+		return ""
+	}
+	return p.Cursors[len(p.Cursors)-1]
+}
+
+// cursor is the decoded form of a cursor.
+type cursor struct {
+	Sort       string  `json:"s"`
+	Descending bool    `json:"d"`
+	Value      *string `json:"v"` // nil for a null key
+	ID         string  `json:"id"`
+}
+
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(s string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(data, &c) != nil || c.ID == "" {
+		return cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Model describes a model to paginate: T its rows and W and O its where and
+// order-by parameters.
+type Model[T, W, O any] struct {
+	ID      func(row *T) string
+	IDAfter func(id string, desc bool) W
+	IDOrder func(order db.SortOrder) O
+	Or      func(params ...W) W
+	And     func(params ...W) W
+	Not     func(params ...W) W
+
+	keys       map[string]key[T, W, O]
+	defaultKey string
+}
+
+// Column is a sortable column of a model with values of type V.
+type Column[T, W, O, V any] struct {
+	Get    func(row *T) (V, bool) // false for a null value
+	Gt     func(value V) W
+	Lt     func(value V) W
+	Equals func(value V) W
+	IsNull func() W // set for optional columns
+	Order  func(order db.SortOrder) O
+}
+
+type key[T, W, O any] struct {
+	descending bool
+	value      func(row *T) *string
+	order      func(desc bool) []O
+	after      func(value *string, id string, desc bool) (W, error)
+}
+
+// Sort registers a sort key with its natural direction. The first key
+// registered is the default.
+func Sort[T, W, O, V any](m *Model[T, W, O], name string, descending bool, column Column[T, W, O, V], codec Codec[V]) {
+	if m.keys == nil {
+		m.keys = map[string]key[T, W, O]{}
+		m.defaultKey = name
+	}
+	m.keys[name] = key[T, W, O]{
+		descending: descending,
+		value: func(row *T) *string {
+			v, ok := column.Get(row)
+			if !ok {
+				return nil
+			}
+			s := codec.Encode(v)
+			return &s
+		},
+		order: func(desc bool) []O {
+			order := db.SortOrderAsc
+			if desc {
+				order = db.SortOrderDesc
+			}
+			return []O{column.Order(order), m.IDOrder(order)}
+		},
+		after: func(value *string, id string, desc bool) (W, error) {
+			// Nulls sort last ascending and first descending, as in Postgres.
+			past := column.Gt
+			if desc {
+				past = column.Lt
+			}
+			if value == nil {
+				if column.IsNull == nil {
+					var zero W
+					return zero, ErrInvalidCursor
+				}
+				nulls := m.And(column.IsNull(), m.IDAfter(id, desc))
+				if desc {
+					return m.Or(nulls, m.Not(column.IsNull())), nil
+				}
+				return nulls, nil
+			}
+			v, err := codec.Decode(*value)
+			if err != nil {
+				var zero W
+				return zero, ErrInvalidCursor
+			}
+			after := m.Or(past(v), m.And(column.Equals(v), m.IDAfter(id, desc)))
+			if column.IsNull != nil && !desc {
+				after = m.Or(after, column.IsNull())
+			}
+			return after, nil
+		},
+	}
+}
+
+// ValidSort reports whether the model can be sorted by name.
+func (m *Model[T, W, O]) ValidSort(name string) bool {
+	_, ok := m.keys[name]
+	return ok
+}
+
+// Fetch returns the page args select. find runs the query with the
+// conditions, order and row count given, on top of the caller's filters.
+func Fetch[T, W, O any](m *Model[T, W, O], args Args, find func(where []W, order []O, take int) ([]T, error)) (*Page[T], error) {
+	limit := args.First
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if limit < 1 || limit > MaxLimit {
+		return nil, ErrInvalidLimit
+	}
+	name := args.Sort
+	if name == "" {
+		name = m.defaultKey
+	}
+	k, ok := m.keys[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrInvalidSort, name)
+	}
+	desc := k.descending
+	if args.Descending != nil {
+		desc = *args.Descending
+	}
+
+	var where []W
+	if args.After != "" {
+		c, err := decode(args.After)
+		if err != nil {
+			return nil, err
+		}
+		if c.Sort != name || c.Descending != desc {
+			return nil, ErrInvalidCursor
+		}
+		after, err := k.after(c.Value, c.ID, desc)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, after)
+	}
+
+	rows, err := find(where, k.order(desc), limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &Page[T]{HasPreviousPage: args.After != ""}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.HasNextPage = true
+	}
+	page.Rows = rows
+	page.Cursors = make([]string, len(rows))
+	for i := range rows {
+		page.Cursors[i] = cursor{
+			Sort:       name,
+			Descending: desc,
+			Value:      k.value(&rows[i]),
+			ID:         m.ID(&rows[i]),
+		}.encode()
+	}
+	return page, nil
+}
+
+// Codec converts sort key values to and from their cursor form.
+type Codec[V any] struct {
+	Encode func(V) string
+	Decode func(string) (V, error)
+}
+
+var (
+	Time = Codec[time.Time]{
+		Encode: func(t time.Time) string { return t.UTC().Format(time.RFC3339Nano) },
+		Decode: func(s string) (time.Time, error) { return time.Parse(time.RFC3339Nano, s) },
+	}
+	String = Codec[string]{
+		Encode: func(s string) string { return s },
+		Decode: func(s string) (string, error) { return s, nil },
+	}
+)
+
+// Slice returns the page args select from rows already sorted by the key
+// name, for orders the database can't produce. Its cursors continue after
+// the row they were issued for wherever that row has moved; a cursor for a
+// row that is gone is invalid.
+func Slice[T any](rows []T, id func(row *T) string, name string, args Args) (*Page[T], error) {
+	limit := args.First
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if limit < 1 || limit > MaxLimit {
+		return nil, ErrInvalidLimit
+	}
+	desc := args.Descending != nil && *args.Descending
+	if desc {
+		reversed := make([]T, len(rows))
+		for i := range rows {
+			reversed[len(rows)-1-i] = rows[i]
+		}
+		rows = reversed
+	}
+
+	start := 0
+	if args.After != "" {
+		c, err := decode(args.After)
+		if err != nil {
+			return nil, err
+		}
+		if c.Sort != name || c.Descending != desc {
+			return nil, ErrInvalidCursor
+		}
+		start = -1
+		for i := range rows {
+			if id(&rows[i]) == c.ID {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	end := min(start+limit, len(rows))
+	page := &Page[T]{
+		Rows:            rows[start:end],
+		Cursors:         make([]string, end-start),
+		HasNextPage:     end < len(rows),
+		HasPreviousPage: args.After != "",
+	}
+	for i := range page.Rows {
+		page.Cursors[i] = cursor{Sort: name, Descending: desc, ID: id(&page.Rows[i])}.encode()
+	}
+	return page, nil
+}
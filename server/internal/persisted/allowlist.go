@@ -0,0 +1,88 @@
+package persisted
+
+import (
+	"context"
+	"crypto/subtle"
+	"os"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+
+// Allowlist rejects operations missing from its manifest unless they come
+// with one of the admin tokens.
+type Allowlist struct {
+	ids         map[string]bool
+	adminTokens []string
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = &Allowlist{}
+
+// NewAllowlist returns an allowlist of the manifest's operations that lets
+// adminTokens run any operation.
+func NewAllowlist(m *Manifest, adminTokens []string) *Allowlist {
+	a := &Allowlist{ids: map[string]bool{}, adminTokens: adminTokens}
+	for _, op := range m.Operations {
+		a.ids[op.ID] = true
+	}
+	return a
+}
+
+// FromEnv returns the allowlist of the manifest at
+// PERSISTED_QUERIES_MANIFEST with the comma-separated admin tokens of
+// GRAPHQL_ADMIN_TOKENS, or nil when no manifest is set and any operation is
+// allowed.
+func FromEnv() (*Allowlist, error) {
+	path := os.Getenv("PERSISTED_QUERIES_MANIFEST")
+	if path == "" {
+		return nil, nil
+	}
+	m, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []string
+	for _, token := range strings.Split(os.Getenv("GRAPHQL_ADMIN_TOKENS"), ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return NewAllowlist(m, tokens), nil
+}
+
+func (a *Allowlist) ExtensionName() string {
+	return "PersistedQueryAllowlist"
+}
+
+func (a *Allowlist) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (a *Allowlist) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if a.ids[Hash(opCtx.Doc)] || a.admin(opCtx.Headers.Get("Authorization")) {
+		return nil
+	}
+	err := gqlerror.Errorf("operation is not in the persisted query allowlist")
+	errcode.Set(err, errNotAllowed)
+	return err
+}
+
+func (a *Allowlist) admin(authorization string) bool {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	for _, admin := range a.adminTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(admin)) == 1 {
+			return true
+		}
+	}
+	return false
+}
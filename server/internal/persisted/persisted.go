@@ -0,0 +1,146 @@
+// Package persisted implements the persisted query allowlist: a manifest of
+// the operations the client ships with, generated from its source, outside
+// which the GraphQL endpoint only runs operations for admin tokens.
+// Operations are compared in a canonical form, so whitespace, comments, the
+// client's printing of a query and the __typename fields Apollo's cache adds
+// to every selection set don't matter.
+package persisted
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// ManifestFormat identifies Apollo's persisted query manifest format, which
+// manifests are written in.
+const ManifestFormat = "apollo-persisted-query-manifest"
+
+// Manifest lists persisted operations.
+type Manifest struct {
+	Format     string      `json:"format"`
+	Version    int         `json:"version"`
+	Operations []Operation `json:"operations"`
+}
+
+// Operation is a persisted operation; its ID is the Hash of its body.
+type Operation struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"` // query, mutation or subscription
+	Body string `json:"body"`
+}
+
+// Canonical returns doc as gqlparser's formatter prints it, without
+// comments.
+func Canonical(doc *ast.QueryDocument) string {
+	var b strings.Builder
+	formatter.NewFormatter(&b).FormatQueryDocument(doc)
+	return b.String()
+}
+
+// Hash returns the SHA-256 of doc's canonical form without __typename
+// fields, in hex. doc itself is left as it is, __typename fields and all.
+func Hash(doc *ast.QueryDocument) string {
+	stripped := &ast.QueryDocument{}
+	for _, op := range doc.Operations {
+		copied := *op
+		copied.SelectionSet = withoutTypename(op.SelectionSet)
+		stripped.Operations = append(stripped.Operations, &copied)
+	}
+	for _, fragment := range doc.Fragments {
+		copied := *fragment
+		copied.SelectionSet = withoutTypename(fragment.SelectionSet)
+		stripped.Fragments = append(stripped.Fragments, &copied)
+	}
+	sum := sha256.Sum256([]byte(Canonical(stripped)))
+	return hex.EncodeToString(sum[:])
+}
+
+// withoutTypename returns a copy of set without its unaliased __typename
+// fields, at any depth.
+func withoutTypename(set ast.SelectionSet) ast.SelectionSet {
+	var out ast.SelectionSet
+	for _, selection := range set {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Name == "__typename" && (selection.Alias == "" || selection.Alias == selection.Name) {
+				continue
+			}
+			copied := *selection
+			copied.SelectionSet = withoutTypename(selection.SelectionSet)
+			out = append(out, &copied)
+		case *ast.InlineFragment:
+			copied := *selection
+			copied.SelectionSet = withoutTypename(selection.SelectionSet)
+			out = append(out, &copied)
+		default:
+			out = append(out, selection)
+		}
+	}
+	return out
+}
+
+// operationPattern matches operations declared in client source as
+// export const NAME = `...` or gql`...`.
+var operationPattern = regexp.MustCompile("(?s)export const (\\w+) = (?:gql)?`(.*?)`")
+
+// Skipped is an operation of the client source left out of a manifest.
+type Skipped struct {
+	Name   string
+	Reason string
+}
+
+// Generate adds the operations declared in client source to m. Operations
+// that build their text at runtime or aren't valid against schema are
+// skipped, as the server couldn't run them anyway.
+func (m *Manifest) Generate(source string, schema *ast.Schema) []Skipped {
+	var skipped []Skipped
+	for _, match := range operationPattern.FindAllStringSubmatch(source, -1) {
+		name, body := match[1], match[2]
+		if strings.Contains(body, "${") {
+			skipped = append(skipped, Skipped{name, "interpolated"})
+			continue
+		}
+		doc, errs := gqlparser.LoadQuery(schema, body)
+		if len(errs) > 0 {
+			skipped = append(skipped, Skipped{name, errs.Error()})
+			continue
+		}
+		if len(doc.Operations) != 1 {
+			skipped = append(skipped, Skipped{name, "not a single operation"})
+			continue
+		}
+		m.Operations = append(m.Operations, Operation{
+			ID:   Hash(doc),
+			Name: doc.Operations[0].Name,
+			Type: string(doc.Operations[0].Operation),
+			Body: Canonical(doc),
+		})
+	}
+	return skipped
+}
+
+// Load reads a manifest from path.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if m.Format != ManifestFormat {
+		return nil, fmt.Errorf("%s: not a persisted query manifest", path)
+	}
+	return &m, nil
+}
@@ -0,0 +1,113 @@
+package persisted
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+var testSchema = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphqls", Input: `
+type Query {
+	task(id: ID!): Task
+	node(id: ID!): Node
+}
+interface Node { id: ID! }
+type Project implements Node { id: ID! name: String! }
+type Task implements Node { id: ID! title: String! project: Project }
+`})
+
+const clientSource = "export const GET_TASK = gql`\n" + `
+	query GetTask($id: ID!) {
+		task(id: $id) {
+			id
+			title
+			project { id name }
+		}
+	}
+` + "`;\n" + "export const GET_NODE = gql`\n" + `
+	query GetNode($id: ID!) {
+		node(id: $id) {
+			id
+			... on Task { ...TaskFields }
+		}
+	}
+	fragment TaskFields on Task { title }
+` + "`;\n"
+
+func testAllowlist(t *testing.T) *Allowlist {
+	t.Helper()
+	m := &Manifest{Format: ManifestFormat, Version: 1}
+	if skipped := m.Generate(clientSource, testSchema); len(skipped) > 0 {
+		t.Fatalf("Generate skipped %v", skipped)
+	}
+	if len(m.Operations) != 2 {
+		t.Fatalf("Generate found %d operations, want 2", len(m.Operations))
+	}
+	return NewAllowlist(m, []string{"admin-token"})
+}
+
+func run(t *testing.T, a *Allowlist, query, authorization string) (*ast.QueryDocument, error) {
+	t.Helper()
+	doc, errs := gqlparser.LoadQuery(testSchema, query)
+	if len(errs) > 0 {
+		t.Fatalf("LoadQuery: %v", errs)
+	}
+	headers := http.Header{}
+	if authorization != "" {
+		headers.Set("Authorization", authorization)
+	}
+	opCtx := &graphql.OperationContext{Doc: doc, Headers: headers}
+	if err := a.MutateOperationContext(context.Background(), opCtx); err != nil {
+		return doc, err
+	}
+	return opCtx.Doc, nil
+}
+
+func TestAllowlist(t *testing.T) {
+	a := testAllowlist(t)
+	tests := []struct {
+		name          string
+		query         string
+		authorization string
+		allowed       bool
+	}{
+		{"as written", `query GetTask($id: ID!) { task(id: $id) { id title project { id name } } }`, "", true},
+		{"reformatted with comments", "# from the task page\nquery GetTask($id: ID!) {\n  task(id: $id) {\n    id, title\n    project { id, name }\n  }\n}", "", true},
+		// What Apollo Client sends once InMemoryCache has added __typename.
+		{"with __typename", `query GetTask($id: ID!) { task(id: $id) { id title project { id name __typename } __typename } }`, "", true},
+		{"with __typename in fragments", `query GetNode($id: ID!) { node(id: $id) { id __typename ... on Task { ...TaskFields __typename } } } fragment TaskFields on Task { title __typename }`, "", true},
+		{"extra field", `query GetTask($id: ID!) { task(id: $id) { id title project { id name } } node(id: $id) { id } }`, "", false},
+		// An alias makes __typename part of the response shape.
+		{"aliased __typename", `query GetTask($id: ID!) { task(id: $id) { id title kind: __typename project { id name } } }`, "", false},
+		{"unknown operation", `query Other($id: ID!) { task(id: $id) { id } }`, "", false},
+		{"unknown operation as admin", `query Other($id: ID!) { task(id: $id) { id } }`, "Bearer admin-token", true},
+		{"unknown operation with another token", `query Other($id: ID!) { task(id: $id) { id } }`, "Bearer user-token", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := run(t, a, tt.query, tt.authorization)
+			if allowed := err == nil; allowed != tt.allowed {
+				t.Errorf("allowed = %v (%v), want %v", allowed, err, tt.allowed)
+			}
+		})
+	}
+}
+
+// The allowlist must leave __typename in the document it runs, or Apollo's
+// cache couldn't normalise the response.
+func TestHashKeepsDocument(t *testing.T) {
+	a := testAllowlist(t)
+	query := `query GetTask($id: ID!) { task(id: $id) { id title project { id name __typename } __typename } }`
+	doc, err := run(t, a, query, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := gqlparser.LoadQuery(testSchema, query)
+	if got, want := Canonical(doc), Canonical(want); got != want {
+		t.Errorf("document after the allowlist =\n%s\nwant\n%s", got, want)
+	}
+}
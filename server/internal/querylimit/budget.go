@@ -0,0 +1,128 @@
+package querylimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"lifequest-server/internal/auth"
+	"lifequest-server/internal/ratelimit"
+)
+
+const errBudgetExceeded = "COST_BUDGET_EXCEEDED"
+
+// Budget charges each caller the complexity of its operations. A caller can
+// spend Limit per Window; spending drains back continuously, so a caller
+// over budget waits only until enough has drained for its operation.
+type Budget struct {
+	Limit  int
+	Window time.Duration
+
+	mu      sync.Mutex
+	callers map[string]*spending
+	pruned  time.Time
+}
+
+type spending struct {
+	spent float64
+	at    time.Time
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = &Budget{}
+
+// NewBudget returns a budget of limit complexity per minute.
+func NewBudget(limit int) *Budget {
+	return &Budget{Limit: limit, Window: time.Minute, callers: map[string]*spending{}}
+}
+
+func (b *Budget) ExtensionName() string {
+	return "CostBudget"
+}
+
+func (b *Budget) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationContext charges the operation's complexity, as computed by
+// the ComplexityLimit extension, to the caller.
+func (b *Budget) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	stats, ok := opCtx.Stats.GetExtension("ComplexityLimit").(*extension.ComplexityStats)
+	if !ok {
+		return nil
+	}
+	wait := b.charge(Caller(ctx), stats.Complexity, time.Now())
+	if wait == 0 {
+		return nil
+	}
+	seconds := int(math.Ceil(wait.Seconds()))
+	err := gqlerror.Errorf("operation cost of %d exceeds the remaining budget; retry in %d seconds", stats.Complexity, seconds)
+	errcode.Set(err, errBudgetExceeded)
+	err.Extensions["retryAfter"] = seconds
+	return err
+}
+
+// charge spends cost for caller and returns 0, or how long the caller must
+// wait before it can spend cost.
+func (b *Budget) charge(caller string, cost int, now time.Time) time.Duration {
+	rate := float64(b.Limit) / b.Window.Seconds() // drained per second
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if now.Sub(b.pruned) > b.Window {
+		// After a full window every caller's spending has drained.
+		for key, s := range b.callers {
+			if now.Sub(s.at) > b.Window {
+				delete(b.callers, key)
+			}
+		}
+		b.pruned = now
+	}
+
+	s, ok := b.callers[caller]
+	if !ok {
+		s = &spending{at: now}
+		b.callers[caller] = s
+	}
+	s.spent = max(0, s.spent-now.Sub(s.at).Seconds()*rate)
+	s.at = now
+
+	over := s.spent + float64(cost) - float64(b.Limit)
+	if over > 0 {
+		return time.Duration(over / rate * float64(time.Second))
+	}
+	s.spent += float64(cost)
+	return 0
+}
+
+type callerKey struct{}
+
+// Middleware identifies the caller of each request the way the REST server
+// does, by the user auth.Middleware authenticated or by the client's address
+// without one. Put it after auth.Middleware.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		caller := ratelimit.Caller(auth.FromContext(r.Context()), host)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), callerKey{}, caller)))
+	})
+}
+
+// Caller returns the caller Middleware identified, or "" outside it.
+func Caller(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
@@ -0,0 +1,68 @@
+package querylimit
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+
+// Depth rejects operations whose fields nest deeper than Limit. Fragments
+// count as the fields they spread; introspection fields are not counted, so
+// clients can still load the schema.
+type Depth struct {
+	Limit int
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = Depth{}
+
+func (d Depth) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (d Depth) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d Depth) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Doc.Operations.ForName(opCtx.OperationName)
+	if op == nil {
+		return nil
+	}
+	if depth := depth(op.SelectionSet); depth > d.Limit {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.Limit)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+	return nil
+}
+
+func depth(set ast.SelectionSet) int {
+	deepest := 0
+	for _, selection := range set {
+		d := 0
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			d = 1 + depth(s.SelectionSet)
+		case *ast.InlineFragment:
+			d = depth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				d = depth(s.Definition.SelectionSet)
+			}
+		}
+		deepest = max(deepest, d)
+	}
+	return deepest
+}
@@ -0,0 +1,42 @@
+// Package querylimit protects the GraphQL endpoint from expensive
+// operations: it limits each caller's operation rate, rejects operations
+// nested too deeply and charges each caller the complexity of its
+// operations against a budget that refills over time. The complexity limit
+// itself is gqlgen's ComplexityLimit extension, which must run before
+// Budget.
+package querylimit
+
+import (
+	"os"
+	"strconv"
+)
+
+const (
+	defaultMaxComplexity = 10000
+	defaultMaxDepth      = 10
+	defaultBudget        = 100000
+)
+
+// Limits configure the GraphQL endpoint.
+type Limits struct {
+	MaxComplexity int // highest complexity of one operation
+	MaxDepth      int // deepest field nesting of one operation
+	Budget        int // complexity a caller can spend per minute
+}
+
+// FromEnv returns the limits set by GRAPHQL_MAX_COMPLEXITY,
+// GRAPHQL_MAX_DEPTH and GRAPHQL_COST_BUDGET, or 10000, 10 and 100000.
+func FromEnv() Limits {
+	return Limits{
+		MaxComplexity: envInt("GRAPHQL_MAX_COMPLEXITY", defaultMaxComplexity),
+		MaxDepth:      envInt("GRAPHQL_MAX_DEPTH", defaultMaxDepth),
+		Budget:        envInt("GRAPHQL_COST_BUDGET", defaultBudget),
+	}
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,54 @@
+package querylimit
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"lifequest-server/internal/ratelimit"
+)
+
+const errRateLimited = "RATE_LIMITED"
+
+// RateLimit limits each caller's operations under the limiter's mutation
+// rule for mutations and its query rule for queries and subscriptions.
+// Callers are keyed as Middleware identified them, like the REST server's,
+// so changing the token string doesn't get a fresh bucket, and with
+// RATE_LIMIT_STORE=postgres both servers share a user's buckets.
+type RateLimit struct {
+	Limiter *ratelimit.Limiter
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = RateLimit{}
+
+func (r RateLimit) ExtensionName() string {
+	return "RateLimit"
+}
+
+func (r RateLimit) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (r RateLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	rule := r.Limiter.Queries
+	if op := opCtx.Doc.Operations.ForName(opCtx.OperationName); op != nil && op.Operation == ast.Mutation {
+		rule = r.Limiter.Mutations
+	}
+	result := r.Limiter.Take(ctx, rule, Caller(ctx))
+	if result.Allowed {
+		return nil
+	}
+	seconds := int(result.RetryAfter.Seconds())
+	err := gqlerror.Errorf("too many %s; retry in %d seconds", rule.Name, seconds)
+	errcode.Set(err, errRateLimited)
+	err.Extensions["retryAfter"] = seconds
+	err.Extensions["limit"] = result.Limit
+	err.Extensions["remaining"] = result.Remaining
+	return err
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory keeps buckets in memory, for a single replica.
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	pruned  time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	per     time.Duration
+}
+
+// NewMemory returns a store with no buckets.
+func NewMemory() *Memory {
+	return &Memory{buckets: map[string]*bucket{}}
+}
+
+func (m *Memory) Take(ctx context.Context, key string, rule Rule) (Result, error) {
+	return m.take(key, rule, time.Now()), nil
+}
+
+func (m *Memory) take(key string, rule Rule, now time.Time) Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.Sub(m.pruned) > time.Minute {
+		// Buckets untouched for their rule's period are full again.
+		for k, b := range m.buckets {
+			if now.Sub(b.updated) > b.per {
+				delete(m.buckets, k)
+			}
+		}
+		m.pruned = now
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rule.Requests), updated: now, per: rule.Per}
+		m.buckets[key] = b
+	}
+	b.tokens = min(float64(rule.Requests), b.tokens+now.Sub(b.updated).Seconds()*rule.rate())
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return rule.result(allowed, b.tokens)
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+// refilled is the tokens in bucket b after refilling it up to $2 at $3 a
+// second since its last update.
+const refilled = `LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM (now() AT TIME ZONE 'UTC') - b.updated_at)::float8 * $3::float8)`
+
+// takeSQL refills and takes from the bucket with key $1 in one statement,
+// so replicas racing for a bucket are serialized on its row.
+const takeSQL = `
+INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+VALUES ($1, $2::float8 - 1, true, now() AT TIME ZONE 'UTC')
+ON CONFLICT (key) DO UPDATE SET
+	tokens = ` + refilled + ` - CASE WHEN ` + refilled + ` >= 1 THEN 1 ELSE 0 END,
+	allowed = ` + refilled + ` >= 1,
+	updated_at = now() AT TIME ZONE 'UTC'
+RETURNING tokens, allowed`
+
+const (
+	pruneInterval = time.Hour
+	pruneIdle     = 24 * time.Hour
+)
+
+// Postgres keeps buckets in the rate_limit_buckets table, shared by all
+// replicas. Buckets are keyed by Caller, a user ID or an IP address, so no
+// credentials are ever stored.
+type Postgres struct {
+	client *db.PrismaClient
+}
+
+// NewPostgres returns a store in client's database.
+func NewPostgres(client *db.PrismaClient) *Postgres {
+	return &Postgres{client: client}
+}
+
+func (p *Postgres) Take(ctx context.Context, key string, rule Rule) (Result, error) {
+	var rows []struct {
+		Tokens  float64 `json:"tokens"`
+		Allowed bool    `json:"allowed"`
+	}
+	err := p.client.Prisma.QueryRaw(takeSQL, key, rule.Requests, rule.rate()).Exec(ctx, &rows)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(rows) != 1 {
+		return Result{}, fmt.Errorf("rate limit bucket %s: got %d rows", key, len(rows))
+	}
+	return rule.result(rows[0].Allowed, rows[0].Tokens), nil
+}
+
+// Start deletes buckets idle for a day in the background; by then they
+// have refilled, as if they didn't exist.
+func Start(ctx context.Context, client *db.PrismaClient) {
+	go func() {
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_, err := client.RateLimitBucket.FindMany(
+					db.RateLimitBucket.UpdatedAt.Lt(time.Now().Add(-pruneIdle)),
+				).Delete().Exec(ctx)
+				if err != nil {
+					log.Printf("ratelimit: prune failed: %v", err)
+				}
+			}
+		}
+	}()
+}
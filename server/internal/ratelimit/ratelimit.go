@@ -0,0 +1,114 @@
+// Package ratelimit limits request rates with token buckets. Each caller
+// has a bucket per rule holding up to the rule's number of requests; a
+// request takes a token, and tokens refill evenly over the rule's period.
+// Buckets live in memory, or in Postgres when several replicas have to
+// share them.
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+// Rule allows Requests requests per Per, all at once at most.
+type Rule struct {
+	Name     string
+	Requests int
+	Per      time.Duration
+}
+
+// rate returns the tokens the rule refills per second.
+func (r Rule) rate() float64 {
+	return float64(r.Requests) / r.Per.Seconds()
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // until a token is available, when not allowed
+	Reset      time.Duration // until the bucket is full again
+}
+
+// result describes a bucket left with tokens after a take.
+func (r Rule) result(allowed bool, tokens float64) Result {
+	result := Result{
+		Allowed:   allowed,
+		Limit:     r.Requests,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(r.Requests) - tokens) / r.rate()),
+	}
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / r.rate())
+	}
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s)) * time.Second
+}
+
+// Store keeps token buckets.
+type Store interface {
+	// Take takes a token from key's bucket for rule.
+	Take(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+// Limiter applies separate rules to authentication, mutations and queries.
+type Limiter struct {
+	Store     Store
+	Auth      Rule
+	Mutations Rule
+	Queries   Rule
+}
+
+// FromEnv returns a limiter allowing RATE_LIMIT_AUTH authentication
+// requests, RATE_LIMIT_MUTATIONS mutations and RATE_LIMIT_QUERIES queries a
+// minute, or 20, 120 and 600. RATE_LIMIT_STORE=postgres keeps the buckets
+// in the database instead of memory.
+func FromEnv(client *db.PrismaClient) *Limiter {
+	var store Store = NewMemory()
+	if os.Getenv("RATE_LIMIT_STORE") == "postgres" {
+		store = NewPostgres(client)
+	}
+	return &Limiter{
+		Store:     store,
+		Auth:      Rule{Name: "auth", Requests: envInt("RATE_LIMIT_AUTH", 20), Per: time.Minute},
+		Mutations: Rule{Name: "mutations", Requests: envInt("RATE_LIMIT_MUTATIONS", 120), Per: time.Minute},
+		Queries:   Rule{Name: "queries", Requests: envInt("RATE_LIMIT_QUERIES", 600), Per: time.Minute},
+	}
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// Take takes a token for caller under rule. Requests are let through when
+// the store fails, so an outage of the database doesn't lock everyone out.
+func (l *Limiter) Take(ctx context.Context, rule Rule, caller string) Result {
+	result, err := l.Store.Take(ctx, rule.Name+":"+caller, rule)
+	if err != nil {
+		log.Printf("ratelimit: %v", err)
+		return Result{Allowed: true, Limit: rule.Requests, Remaining: rule.Requests}
+	}
+	return result
+}
+
+// Caller identifies a caller by user ID, or by IP address for anonymous
+// requests.
+func Caller(userID, ip string) string {
+	if userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + ip
+}
@@ -0,0 +1,279 @@
+// Package rrule parses and evaluates the subset of RFC 5545 recurrence rules
+// that recurring tasks support: FREQ=DAILY, WEEKLY and MONTHLY with
+// INTERVAL, BYDAY for weekly rules, BYMONTHDAY for monthly rules, and COUNT
+// or UNTIL to end the series.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequencies.
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+)
+
+// searchLimit bounds how far ahead Next looks for an occurrence, so a rule
+// that can never match (BYMONTHDAY=31 every 12 months from April) ends
+// instead of looping.
+const searchLimit = 10 * 366
+
+var (
+	ErrInvalid     = errors.New("invalid recurrence rule")
+	ErrUnsupported = errors.New("unsupported recurrence rule")
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Rule is a parsed recurrence rule. Zero Count and Until mean the series
+// doesn't end.
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday // weekly rules only, sorted
+	ByMonthDay []int          // monthly rules only; negative days count from the end of the month
+	Count      int
+	Until      time.Time
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR". An
+// "RRULE:" prefix is accepted.
+func Parse(value string) (Rule, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "RRULE:"), "rrule:")
+	if value == "" {
+		return Rule{}, fmt.Errorf("%w: empty rule", ErrInvalid)
+	}
+
+	rule := Rule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		val = strings.ToUpper(strings.TrimSpace(val))
+		if !ok || name == "" || val == "" {
+			return Rule{}, fmt.Errorf("%w: malformed part %q", ErrInvalid, part)
+		}
+		if seen[name] {
+			return Rule{}, fmt.Errorf("%w: %s given twice", ErrInvalid, name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			switch val {
+			case Daily, Weekly, Monthly:
+				rule.Freq = val
+			default:
+				return Rule{}, fmt.Errorf("%w: FREQ=%s", ErrUnsupported, val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > 999 {
+				return Rule{}, fmt.Errorf("%w: INTERVAL must be between 1 and 999", ErrInvalid)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("%w: COUNT must be positive", ErrInvalid)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return Rule{}, fmt.Errorf("%w: BYDAY=%s", ErrUnsupported, day)
+				}
+				if !containsWeekday(rule.ByDay, weekday) {
+					rule.ByDay = append(rule.ByDay, weekday)
+				}
+			}
+			sort.Slice(rule.ByDay, func(i, j int) bool { return rule.ByDay[i] < rule.ByDay[j] })
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return Rule{}, fmt.Errorf("%w: BYMONTHDAY=%s", ErrInvalid, day)
+				}
+				if !containsInt(rule.ByMonthDay, n) {
+					rule.ByMonthDay = append(rule.ByMonthDay, n)
+				}
+			}
+			sort.Ints(rule.ByMonthDay)
+		case "WKST":
+			if val != "MO" {
+				return Rule{}, fmt.Errorf("%w: only WKST=MO is supported", ErrUnsupported)
+			}
+		default:
+			return Rule{}, fmt.Errorf("%w: %s", ErrUnsupported, name)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return Rule{}, fmt.Errorf("%w: FREQ is required", ErrInvalid)
+	case rule.Count > 0 && !rule.Until.IsZero():
+		return Rule{}, fmt.Errorf("%w: COUNT and UNTIL can't be combined", ErrInvalid)
+	case len(rule.ByDay) > 0 && rule.Freq != Weekly:
+		return Rule{}, fmt.Errorf("%w: BYDAY is only supported on weekly rules", ErrUnsupported)
+	case len(rule.ByMonthDay) > 0 && rule.Freq != Monthly:
+		return Rule{}, fmt.Errorf("%w: BYMONTHDAY is only supported on monthly rules", ErrUnsupported)
+	}
+	return rule, nil
+}
+
+// String formats the rule in a canonical order, without the "RRULE:"
+// prefix.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = weekdayNames[day]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence strictly after after, for a series that
+// starts at start. Occurrences fall at start's time of day, and intervals
+// are counted from start's day, week or month. ok is false when the rule
+// has no further occurrence before UNTIL; COUNT is left to the caller,
+// which knows how many occurrences it has used.
+func (r Rule) Next(start, after time.Time) (time.Time, bool) {
+	day := start
+	if after.After(start) {
+		// Begin on after's calendar day at start's time of day.
+		y, m, d := after.In(start.Location()).Date()
+		day = time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	for i := 0; i < searchLimit; i, day = i+1, day.AddDate(0, 0, 1) {
+		if !day.After(after) || !r.matches(start, day) {
+			continue
+		}
+		if !r.Until.IsZero() && day.After(r.Until) {
+			return time.Time{}, false
+		}
+		return day, true
+	}
+	return time.Time{}, false
+}
+
+// matches reports whether day, at start's time of day, is an occurrence.
+func (r Rule) matches(start, day time.Time) bool {
+	switch r.Freq {
+	case Daily:
+		return daysBetween(start, day)%r.Interval == 0
+	case Weekly:
+		weeks := daysBetween(weekStart(start), weekStart(day)) / 7
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == start.Weekday()
+		}
+		return containsWeekday(r.ByDay, day.Weekday())
+	case Monthly:
+		months := (day.Year()-start.Year())*12 + int(day.Month()-start.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByMonthDay) == 0 {
+			return day.Day() == start.Day()
+		}
+		last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+		for _, monthDay := range r.ByMonthDay {
+			if monthDay == day.Day() || last+monthDay+1 == day.Day() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// daysBetween counts calendar days from a to b, ignoring the time of day.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	from := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	to := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// weekStart returns the Monday of t's week.
+func weekStart(t time.Time) time.Time {
+	return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// A date-only UNTIL includes the whole day.
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: UNTIL=%s", ErrInvalid, value)
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
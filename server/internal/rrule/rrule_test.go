@@ -0,0 +1,125 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string // String of the parsed rule
+		err   error
+	}{
+		{value: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{value: "RRULE:freq=weekly;byday=fr,mo,mo", want: "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=-1,1;INTERVAL=2", want: "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=-1,1"},
+		{value: "FREQ=DAILY;COUNT=5;WKST=MO", want: "FREQ=DAILY;COUNT=5"},
+		{value: "FREQ=DAILY;UNTIL=20250108", want: "FREQ=DAILY;UNTIL=20250108T235959Z"},
+		{value: "FREQ=DAILY;UNTIL=20250108T080000Z", want: "FREQ=DAILY;UNTIL=20250108T080000Z"},
+
+		{value: "", err: ErrInvalid},
+		{value: "INTERVAL=2", err: ErrInvalid},
+		{value: "FREQ=DAILY;FREQ=WEEKLY", err: ErrInvalid},
+		{value: "FREQ=DAILY;INTERVAL=0", err: ErrInvalid},
+		{value: "FREQ=DAILY;COUNT=2;UNTIL=20250108", err: ErrInvalid},
+		{value: "FREQ=DAILY;UNTIL=tomorrow", err: ErrInvalid},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=0", err: ErrInvalid},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=-32", err: ErrInvalid},
+		{value: "FREQ=YEARLY", err: ErrUnsupported},
+		{value: "FREQ=DAILY;BYDAY=MO", err: ErrUnsupported},
+		{value: "FREQ=WEEKLY;BYMONTHDAY=1", err: ErrUnsupported},
+		{value: "FREQ=WEEKLY;WKST=SU", err: ErrUnsupported},
+		{value: "FREQ=WEEKLY;BYDAY=1MO", err: ErrUnsupported},
+	}
+	for _, tt := range tests {
+		rule, err := Parse(tt.value)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.value, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.value, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	at := func(value string) time.Time {
+		t.Helper()
+		parsed, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		after string
+		want  string // "" when there is no next occurrence
+	}{
+		{"daily", "FREQ=DAILY", "2025-01-06 09:00", "2025-01-06 09:00", "2025-01-07 09:00"},
+		{"before start", "FREQ=DAILY", "2025-01-06 09:00", "2025-01-01 12:00", "2025-01-06 09:00"},
+		{"daily interval", "FREQ=DAILY;INTERVAL=3", "2025-01-06 09:00", "2025-01-08 10:00", "2025-01-09 09:00"},
+		{"earlier on the day", "FREQ=DAILY;INTERVAL=3", "2025-01-06 09:00", "2025-01-09 08:00", "2025-01-09 09:00"},
+		{"daily interval across month", "FREQ=DAILY;INTERVAL=10", "2025-01-26 09:00", "2025-01-26 09:00", "2025-02-05 09:00"},
+
+		{"weekly", "FREQ=WEEKLY", "2025-01-06 09:00", "2025-01-06 09:00", "2025-01-13 09:00"},
+		{"weekly days", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "2025-01-06 09:00", "2025-01-06 09:00", "2025-01-10 09:00"},
+		{"weekly skips a week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "2025-01-06 09:00", "2025-01-10 09:00", "2025-01-20 09:00"},
+		{"weekly interval across month", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "2025-01-06 09:00", "2025-01-24 09:00", "2025-02-03 09:00"},
+		{"weeks start on monday", "FREQ=WEEKLY;INTERVAL=2", "2025-01-12 09:00", "2025-01-12 09:00", "2025-01-26 09:00"},
+		{"sunday in a skipped week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU", "2025-01-06 09:00", "2025-01-06 09:00", "2025-01-12 09:00"},
+		{"weekly interval across year", "FREQ=WEEKLY;INTERVAL=3", "2024-12-16 09:00", "2024-12-16 09:00", "2025-01-06 09:00"},
+
+		{"monthly", "FREQ=MONTHLY", "2025-01-15 09:00", "2025-01-15 09:00", "2025-02-15 09:00"},
+		{"monthly skips short months", "FREQ=MONTHLY", "2025-01-31 09:00", "2025-01-31 09:00", "2025-03-31 09:00"},
+		{"monthly interval", "FREQ=MONTHLY;INTERVAL=2", "2025-01-31 09:00", "2025-01-31 09:00", "2025-03-31 09:00"},
+		{"monthly interval across year", "FREQ=MONTHLY;INTERVAL=5", "2025-10-15 09:00", "2025-10-15 09:00", "2026-03-15 09:00"},
+		{"last day", "FREQ=MONTHLY;BYMONTHDAY=-1", "2025-01-06 09:00", "2025-01-06 09:00", "2025-01-31 09:00"},
+		{"last day of february", "FREQ=MONTHLY;BYMONTHDAY=-1", "2025-01-06 09:00", "2025-01-31 09:00", "2025-02-28 09:00"},
+		{"last day of leap february", "FREQ=MONTHLY;BYMONTHDAY=-1", "2024-01-06 09:00", "2024-01-31 09:00", "2024-02-29 09:00"},
+		{"second to last day", "FREQ=MONTHLY;BYMONTHDAY=-2", "2025-01-06 09:00", "2025-01-30 09:00", "2025-02-27 09:00"},
+		{"first and last day", "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=1,-1", "2025-01-06 09:00", "2025-01-31 09:00", "2025-03-01 09:00"},
+		{"day 31", "FREQ=MONTHLY;BYMONTHDAY=31", "2025-01-06 09:00", "2025-01-31 09:00", "2025-03-31 09:00"},
+		{"day -31", "FREQ=MONTHLY;BYMONTHDAY=-31", "2025-01-06 09:00", "2025-01-06 09:00", "2025-03-01 09:00"},
+
+		{"until date includes the day", "FREQ=DAILY;UNTIL=20250108", "2025-01-06 09:00", "2025-01-07 09:00", "2025-01-08 09:00"},
+		{"until date ends the series", "FREQ=DAILY;UNTIL=20250108", "2025-01-06 09:00", "2025-01-08 09:00", ""},
+		{"until time", "FREQ=DAILY;UNTIL=20250108T080000Z", "2025-01-06 09:00", "2025-01-07 09:00", ""},
+		{"until skips to past end", "FREQ=WEEKLY;UNTIL=20250110", "2025-01-06 09:00", "2025-01-06 09:00", ""},
+
+		{"unreachable", "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=31", "2025-04-30 09:00", "2025-04-30 09:00", ""},
+		{"unreachable february", "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30", "2025-02-01 09:00", "2025-02-01 09:00", ""},
+		{"past search limit", "FREQ=DAILY;INTERVAL=999", "2025-01-06 09:00", "2025-01-06 09:00", "2027-10-02 09:00"},
+		{"interval beyond search limit", "FREQ=MONTHLY;INTERVAL=999", "2025-01-06 09:00", "2025-01-06 09:00", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := rule.Next(at(tt.start), at(tt.after))
+			if tt.want == "" {
+				if ok {
+					t.Errorf("Next = %v, want none", got)
+				}
+				return
+			}
+			if !ok || !got.Equal(at(tt.want)) {
+				t.Errorf("Next = %v, %v, want %s", got, ok, tt.want)
+			}
+		})
+	}
+}
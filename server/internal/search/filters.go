@@ -0,0 +1,144 @@
+package search
+
+import (
+	"time"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/tags"
+	"lifequest-server/internal/tasks"
+)
+
+// Scope is the set of tasks a search runs over: the tasks of a user, of a
+// project whoever created them, or both when both are set.
+type Scope struct {
+	UserID    string
+	ProjectID string
+}
+
+// Where returns the conditions selecting the live tasks in scope that match
+// the query's filters. Relative dues are taken from now.
+func (q Query) Where(scope Scope, now time.Time) []db.TaskWhereParam {
+	where := []db.TaskWhereParam{
+		db.Task.DeletedAt.IsNull(),
+	}
+	if scope.UserID != "" {
+		where = append(where, db.Task.UserID.Equals(scope.UserID))
+	}
+	if scope.ProjectID != "" {
+		where = append(where, db.Task.ProjectID.Equals(scope.ProjectID))
+	}
+
+	anyOf := map[string][]db.TaskWhereParam{}
+	var fields []string
+	for _, filter := range q.Filters {
+		condition := filter.condition(now)
+		switch {
+		case filter.Negated:
+			where = append(where, db.Task.Not(condition))
+		case filter.Field == FieldTag:
+			where = append(where, condition)
+		default:
+			if _, ok := anyOf[filter.Field]; !ok {
+				fields = append(fields, filter.Field)
+			}
+			anyOf[filter.Field] = append(anyOf[filter.Field], condition)
+		}
+	}
+	for _, field := range fields {
+		if conditions := anyOf[field]; len(conditions) == 1 {
+			where = append(where, conditions[0])
+		} else {
+			where = append(where, db.Task.Or(conditions...))
+		}
+	}
+	return where
+}
+
+func (f Filter) condition(now time.Time) db.TaskWhereParam {
+	switch f.Field {
+	case FieldTag:
+		return db.Task.Tags.Some(
+			db.TaskTag.Tag.Where(db.Tag.Key.Equals(tags.Key(f.Value))),
+		)
+	case FieldPriority:
+		return db.Task.Priority.Equals(f.Value)
+	case FieldStatus:
+		return db.Task.Status.Equals(f.Value)
+	case FieldProject:
+		return db.Task.Project.Where(
+			db.Project.Name.Equals(f.Value),
+			db.Project.Name.Mode(db.QueryModeInsensitive),
+		)
+	}
+	return f.Due.condition(now)
+}
+
+func (d *Due) condition(now time.Time) db.TaskWhereParam {
+	today := startOfDay(now)
+	switch d.Keyword {
+	case "today":
+		return dueBetween(today, today.AddDate(0, 0, 1))
+	case "tomorrow":
+		return dueBetween(today.AddDate(0, 0, 1), today.AddDate(0, 0, 2))
+	case "overdue":
+		return db.Task.And(
+			db.Task.DueDate.Lt(now),
+			db.Task.Not(db.Task.Status.Equals(tasks.StatusCompleted)),
+		)
+	case "none":
+		return db.Task.DueDate.IsNull()
+	}
+
+	// Dates compare by whole UTC days, offsets by the exact time.
+	from, to := d.Date, d.Date.AddDate(0, 0, 1)
+	if d.Date.IsZero() {
+		from = d.target(now)
+		to = from
+		if d.Op == "=" {
+			from = startOfDay(from)
+			to = from.AddDate(0, 0, 1)
+		}
+	}
+	switch d.Op {
+	case "<":
+		return db.Task.DueDate.Lt(from)
+	case "<=":
+		if d.Date.IsZero() {
+			return db.Task.DueDate.Lte(to)
+		}
+		return db.Task.DueDate.Lt(to)
+	case ">":
+		if d.Date.IsZero() {
+			return db.Task.DueDate.Gt(to)
+		}
+		return db.Task.DueDate.Gte(to)
+	case ">=":
+		return db.Task.DueDate.Gte(from)
+	}
+	return dueBetween(from, to)
+}
+
+// target is now moved by the offset.
+func (d *Due) target(now time.Time) time.Time {
+	switch d.Unit {
+	case "h":
+		return now.Add(time.Duration(d.Offset) * time.Hour)
+	case "w":
+		return now.AddDate(0, 0, 7*d.Offset)
+	case "m":
+		return now.AddDate(0, d.Offset, 0)
+	}
+	return now.AddDate(0, 0, d.Offset)
+}
+
+func dueBetween(from, to time.Time) db.TaskWhereParam {
+	return db.Task.And(
+		db.Task.DueDate.Gte(from),
+		db.Task.DueDate.Lt(to),
+	)
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
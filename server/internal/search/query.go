@@ -0,0 +1,233 @@
+// Package search implements task search: a small query language of field
+// filters and free text, where the text is matched with Postgres full-text
+// search over task titles, descriptions and comments.
+//
+// A query is a list of space-separated terms:
+//
+//	tag:work              tasks carrying the tag (all given tags must match)
+//	priority:high         low, medium or high
+//	status:todo           todo, in-progress, in-review or completed (done)
+//	project:"Learning Go" the project's name, ignoring case
+//	due:<7d               due before 7 days from now; also <=, >, >= and =,
+//	                      with h, d, w or m offsets, YYYY-MM-DD dates, or
+//	                      today, tomorrow, overdue and none
+//	word "some phrase"    full-text terms
+//
+// Values with spaces are quoted, and a leading "-" negates a term. Repeating
+// priority, status, project or due matches any of the values.
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fields filters can use.
+const (
+	FieldTag      = "tag"
+	FieldPriority = "priority"
+	FieldStatus   = "status"
+	FieldProject  = "project"
+	FieldDue      = "due"
+)
+
+var ErrInvalid = errors.New("invalid search query")
+
+var priorities = map[string]bool{"low": true, "medium": true, "high": true}
+
+// statuses maps accepted spellings to stored task statuses.
+var statuses = map[string]string{
+	"todo":        "todo",
+	"in-progress": "in-progress",
+	"in_progress": "in-progress",
+	"inprogress":  "in-progress",
+	"in-review":   "in-review",
+	"in_review":   "in-review",
+	"inreview":    "in-review",
+	"completed":   "completed",
+	"done":        "completed",
+}
+
+// Term is a full-text word or quoted phrase.
+type Term struct {
+	Text    string
+	Phrase  bool
+	Negated bool
+}
+
+// Filter is a field:value term. Values are normalized: priorities and
+// statuses to their stored form, dues to a parsed Due.
+type Filter struct {
+	Field   string
+	Value   string
+	Due     *Due
+	Negated bool
+}
+
+// Due is a condition on the due date: either one of the keywords today,
+// tomorrow, overdue and none, or a comparison with a date or an offset from
+// now.
+type Due struct {
+	Keyword string
+	Op      string // <, <=, >, >= or =
+	Date    time.Time
+	Offset  int    // with Unit, relative to now; used when Date is zero
+	Unit    string // h, d, w or m
+}
+
+// Query is a parsed search query.
+type Query struct {
+	Terms   []Term
+	Filters []Filter
+}
+
+// Text formats the full-text terms in websearch_to_tsquery syntax.
+func (q Query) Text() string {
+	parts := make([]string, len(q.Terms))
+	for i, term := range q.Terms {
+		text := term.Text
+		if term.Phrase {
+			text = `"` + strings.ReplaceAll(text, `"`, " ") + `"`
+		}
+		if term.Negated {
+			text = "-" + text
+		}
+		parts[i] = text
+	}
+	return strings.Join(parts, " ")
+}
+
+// Parse reads a query. Unknown field names are searched as text.
+func Parse(input string) (Query, error) {
+	var q Query
+	for _, token := range tokenize(input) {
+		negated := false
+		if strings.HasPrefix(token, "-") && len(token) > 1 {
+			negated, token = true, token[1:]
+		}
+
+		if name, value, ok := strings.Cut(token, ":"); ok && !strings.HasPrefix(token, `"`) {
+			field := strings.ToLower(name)
+			if isField(field) {
+				filter, err := parseFilter(field, unquote(value))
+				if err != nil {
+					return Query{}, err
+				}
+				filter.Negated = negated
+				q.Filters = append(q.Filters, filter)
+				continue
+			}
+		}
+
+		phrase := strings.HasPrefix(token, `"`)
+		text := strings.TrimSpace(unquote(token))
+		if text == "" {
+			continue
+		}
+		q.Terms = append(q.Terms, Term{Text: text, Phrase: phrase, Negated: negated})
+	}
+	return q, nil
+}
+
+func isField(name string) bool {
+	switch name {
+	case FieldTag, FieldPriority, FieldStatus, FieldProject, FieldDue:
+		return true
+	}
+	return false
+}
+
+func parseFilter(field, value string) (Filter, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Filter{}, fmt.Errorf("%w: %s needs a value", ErrInvalid, field)
+	}
+	filter := Filter{Field: field, Value: value}
+
+	switch field {
+	case FieldPriority:
+		filter.Value = strings.ToLower(value)
+		if !priorities[filter.Value] {
+			return Filter{}, fmt.Errorf("%w: priority must be low, medium or high", ErrInvalid)
+		}
+	case FieldStatus:
+		status, ok := statuses[strings.ToLower(value)]
+		if !ok {
+			return Filter{}, fmt.Errorf("%w: status must be todo, in-progress, in-review or completed", ErrInvalid)
+		}
+		filter.Value = status
+	case FieldDue:
+		due, err := parseDue(strings.ToLower(value))
+		if err != nil {
+			return Filter{}, err
+		}
+		filter.Due = &due
+	}
+	return filter, nil
+}
+
+func parseDue(value string) (Due, error) {
+	switch value {
+	case "today", "tomorrow", "overdue", "none":
+		return Due{Keyword: value}, nil
+	}
+
+	due := Due{Op: "="}
+	for _, op := range []string{"<=", ">=", "<", ">", "="} {
+		if strings.HasPrefix(value, op) {
+			due.Op, value = op, value[len(op):]
+			break
+		}
+	}
+
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		due.Date = date
+		return due, nil
+	}
+	if len(value) >= 2 {
+		unit := value[len(value)-1:]
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil && strings.Contains("hdwm", unit) && n > -10000 && n < 10000 {
+			due.Offset, due.Unit = n, unit
+			return due, nil
+		}
+	}
+	return Due{}, fmt.Errorf("%w: due must be today, tomorrow, overdue, none, a date like 2025-01-31 or an offset like <7d", ErrInvalid)
+}
+
+// tokenize splits input on whitespace outside double quotes. Quotes are
+// kept so callers can tell phrases and quoted values apart; an unterminated
+// quote runs to the end of the input.
+func tokenize(input string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t' || r == '\n' || r == '\r'):
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// unquote removes surrounding double quotes, including an unterminated
+// opening one.
+func unquote(value string) string {
+	value = strings.TrimPrefix(value, `"`)
+	return strings.TrimSuffix(value, `"`)
+}
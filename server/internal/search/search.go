@@ -0,0 +1,394 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"html"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/pagination"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// Orders hits can be sorted in. Each has a natural direction, which
+// Options.Reverse flips. With text, tasks without a due date stay last
+// either way and titles ignore case; without text the database sorts, so
+// titles follow its collation and reversing the due order puts undated tasks
+// first, as pagination's keys do.
+const (
+	SortRelevance = "relevance" // best match first
+	SortUpdated   = "updated"   // most recently updated first
+	SortCreated   = "created"   // newest first
+	SortDue       = "due"       // soonest first
+	SortPriority  = "priority"  // high first
+	SortTitle     = "title"     // alphabetical, ignoring case
+)
+
+var ErrInvalidSort = errors.New("sort must be relevance, updated, created, due, priority or title")
+
+var errNoScope = errors.New("search needs a user or a project to search in")
+
+// Options control the hits Search returns. The zero value sorts by
+// relevance with text and by last update without, up to DefaultLimit hits.
+// After continues from a cursor of an earlier page with the same query and
+// order.
+type Options struct {
+	Sort    string
+	Reverse bool
+	Limit   int
+	After   string
+}
+
+// Fields highlights are taken from.
+const (
+	HighlightTitle       = "title"
+	HighlightDescription = "description"
+	HighlightComment     = "comment"
+)
+
+// Highlight is a snippet of a matching field with the matched words wrapped
+// in <mark> tags. The rest of the snippet is HTML-escaped.
+type Highlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
+
+// Hit is a task matching a search. Rank is zero without full-text terms.
+type Hit struct {
+	Task       db.TaskModel `json:"task"`
+	Rank       float64      `json:"rank"`
+	Highlights []Highlight  `json:"highlights"`
+}
+
+// match is a row of the full-text query.
+type match struct {
+	ID          string  `json:"id"`
+	Rank        float64 `json:"rank"`
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	Comment     *string `json:"comment"`
+}
+
+// matchesSQL ranks the tasks in scope against the text. Filters apply
+// afterwards, to the best 1000 matches. Highlights are delimited with control
+// characters, which can't occur in stored text, and turned into marks after
+// the snippet is escaped.
+const matchesSQL = `
+SELECT t."id",
+    ts_rank_cd(t."search_vector", q.query)::float8 AS "rank",
+    ts_headline('english', t."title", q.query, o.options) AS "title",
+    CASE WHEN t."description" IS NOT NULL
+        THEN ts_headline('english', t."description", q.query, o.options)
+    END AS "description",
+    (SELECT ts_headline('english', c."body", q.query, o.options)
+        FROM "task_comments" c
+        WHERE c."task_id" = t."id" AND to_tsvector('english', c."body") @@ q.query
+        ORDER BY ts_rank_cd(to_tsvector('english', c."body"), q.query) DESC
+        LIMIT 1) AS "comment"
+FROM "tasks" t,
+    websearch_to_tsquery('english', $2) AS q(query),
+    (SELECT 'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxWords=30, MinWords=10, MaxFragments=2' AS options) AS o
+WHERE ($1 = '' OR t."user_id" = $1)
+    AND ($3 = '' OR t."project_id" = $3)
+    AND t."deleted_at" IS NULL
+    AND (numnode(q.query) = 0 OR t."search_vector" @@ q.query)
+ORDER BY "rank" DESC, t."updated_at" DESC
+LIMIT 1000`
+
+// Search returns the tasks in scope matching the query, loaded with the
+// given relations and sorted as the options ask.
+func Search(ctx context.Context, client *db.PrismaClient, scope Scope, q Query, opts Options, with ...db.TaskRelationWith) ([]Hit, error) {
+	page, err := Page(ctx, client, scope, q, opts, with...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Rows, nil
+}
+
+// Page is Search with the cursor of each hit, to continue after with
+// Options.After. Without text the sort, cursor and limit go to the database;
+// text matches are the best 1000, sorted and paged in memory.
+func Page(ctx context.Context, client *db.PrismaClient, scope Scope, q Query, opts Options, with ...db.TaskRelationWith) (*pagination.Page[Hit], error) {
+	if scope.UserID == "" && scope.ProjectID == "" {
+		return nil, errNoScope
+	}
+	if opts.Sort == "" {
+		opts.Sort = SortUpdated
+		if len(q.Terms) > 0 {
+			opts.Sort = SortRelevance
+		}
+	}
+	if !ValidSort(opts.Sort) {
+		return nil, ErrInvalidSort
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultLimit
+	}
+	opts.Limit = min(opts.Limit, MaxLimit)
+	where := q.Where(scope, time.Now())
+
+	if len(q.Terms) > 0 {
+		hits, err := textHits(ctx, client, scope, q, where, with)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(hits, func(i, j int) bool {
+			return compare(&hits[i], &hits[j], opts) < 0
+		})
+		name := opts.Sort
+		if opts.Reverse {
+			name = "-" + name
+		}
+		return pagination.Slice(hits, func(hit *Hit) string { return hit.Task.ID }, name, pagination.Args{
+			First: opts.Limit,
+			After: opts.After,
+		})
+	}
+
+	find := func(filters []db.TaskWhereParam) func([]db.TaskWhereParam, []db.TaskOrderByParam, int) ([]db.TaskModel, error) {
+		return func(after []db.TaskWhereParam, order []db.TaskOrderByParam, take int) ([]db.TaskModel, error) {
+			return client.Task.FindMany(append(append(append([]db.TaskWhereParam{}, where...), filters...), after...)...).
+				With(with...).OrderBy(order...).Take(take).Exec(ctx)
+		}
+	}
+	var page *pagination.Page[db.TaskModel]
+	var err error
+	if opts.Sort == SortPriority {
+		page, err = priorityPage(ctx, client, where, opts, find)
+	} else {
+		key := sortKeys[opts.Sort]
+		descending := key.descending != opts.Reverse
+		page, err = pagination.Fetch(pagination.Tasks, pagination.Args{
+			First:      opts.Limit,
+			After:      opts.After,
+			Sort:       key.name,
+			Descending: &descending,
+		}, find(nil))
+	}
+	if err != nil {
+		return nil, err
+	}
+	hits := &pagination.Page[Hit]{
+		Rows:            make([]Hit, len(page.Rows)),
+		Cursors:         page.Cursors,
+		HasNextPage:     page.HasNextPage,
+		HasPreviousPage: page.HasPreviousPage,
+	}
+	for i, task := range page.Rows {
+		hits.Rows[i] = Hit{Task: task, Highlights: []Highlight{}}
+	}
+	return hits, nil
+}
+
+// sortKeys are the pagination keys of the sorts the database can run, with the
+// direction of each sort's natural order.
+var sortKeys = map[string]struct {
+	name       string
+	descending bool
+}{
+	SortUpdated: {"updatedAt", true},
+	SortCreated: {"createdAt", true},
+	SortDue:     {"dueDate", false},
+	SortTitle:   {"title", false},
+}
+
+// priorityBuckets are the buckets of the priority sort, high first. Priorities
+// other than these sort with high, as compare has them.
+var priorityBuckets = []struct {
+	name  string
+	where db.TaskWhereParam
+}{
+	{"high", db.Task.Not(db.Task.Priority.In([]string{"medium", "low"}))},
+	{"medium", db.Task.Priority.Equals("medium")},
+	{"low", db.Task.Priority.Equals("low")},
+}
+
+// priorityCursor continues the priority sort: After is the cursor within
+// the bucket Priority.
+type priorityCursor struct {
+	Reverse  bool   `json:"r"`
+	Priority string `json:"p"`
+	After    string `json:"a"`
+}
+
+func (c priorityCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// priorityPage runs the priority sort as one keyset page per bucket, most
+// recently updated first within each, until the page is full.
+func priorityPage(ctx context.Context, client *db.PrismaClient, where []db.TaskWhereParam, opts Options,
+	find func([]db.TaskWhereParam) func([]db.TaskWhereParam, []db.TaskOrderByParam, int) ([]db.TaskModel, error)) (*pagination.Page[db.TaskModel], error) {
+	order := make([]int, len(priorityBuckets))
+	for i := range order {
+		order[i] = i
+		if opts.Reverse {
+			order[i] = len(priorityBuckets) - 1 - i
+		}
+	}
+
+	start, after := 0, ""
+	if opts.After != "" {
+		var c priorityCursor
+		data, err := base64.RawURLEncoding.DecodeString(opts.After)
+		if err != nil || json.Unmarshal(data, &c) != nil || c.Reverse != opts.Reverse {
+			return nil, pagination.ErrInvalidCursor
+		}
+		start = slices.IndexFunc(order, func(i int) bool { return priorityBuckets[i].name == c.Priority })
+		if start < 0 {
+			return nil, pagination.ErrInvalidCursor
+		}
+		after = c.After
+	}
+
+	page := &pagination.Page[db.TaskModel]{HasPreviousPage: opts.After != ""}
+	for n := start; n < len(order); n++ {
+		bucket := priorityBuckets[order[n]]
+		if len(page.Rows) == opts.Limit {
+			// The page filled up at the end of a bucket: look for a task in the
+			// rest.
+			rest := make([]db.TaskWhereParam, 0, len(order)-n)
+			for _, i := range order[n:] {
+				rest = append(rest, priorityBuckets[i].where)
+			}
+			more, err := find([]db.TaskWhereParam{db.Task.Or(rest...)})(nil, nil, 1)
+			if err != nil {
+				return nil, err
+			}
+			page.HasNextPage = len(more) > 0
+			break
+		}
+		part, err := pagination.Fetch(pagination.Tasks, pagination.Args{
+			First: opts.Limit - len(page.Rows),
+			After: after,
+			Sort:  "updatedAt",
+		}, find([]db.TaskWhereParam{bucket.where}))
+		if err != nil {
+			return nil, err
+		}
+		after = ""
+		page.Rows = append(page.Rows, part.Rows...)
+		for _, cursor := range part.Cursors {
+			page.Cursors = append(page.Cursors, priorityCursor{
+				Reverse:  opts.Reverse,
+				Priority: bucket.name,
+				After:    cursor,
+			}.encode())
+		}
+		if part.HasNextPage {
+			page.HasNextPage = true
+			break
+		}
+	}
+	return page, nil
+}
+
+// ValidSort reports whether Search can sort by sort.
+func ValidSort(sort string) bool {
+	switch sort {
+	case SortRelevance, SortUpdated, SortCreated, SortDue, SortPriority, SortTitle:
+		return true
+	}
+	return false
+}
+
+// textHits returns the full-text matches that also pass the filters.
+func textHits(ctx context.Context, client *db.PrismaClient, scope Scope, q Query, where []db.TaskWhereParam, with []db.TaskRelationWith) ([]Hit, error) {
+	var matches []match
+	err := client.Prisma.QueryRaw(matchesSQL, scope.UserID, q.Text(), scope.ProjectID).Exec(ctx, &matches)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return []Hit{}, nil
+	}
+	ids := make([]string, len(matches))
+	byID := make(map[string]match, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
+		byID[m.ID] = m
+	}
+
+	list, err := client.Task.FindMany(append(where, db.Task.ID.In(ids))...).With(with...).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hits := make([]Hit, len(list))
+	for i, task := range list {
+		m := byID[task.ID]
+		hits[i] = Hit{Task: task, Rank: m.Rank, Highlights: highlights(m)}
+	}
+	return hits, nil
+}
+
+var priorityOrder = map[string]int{"high": 0, "medium": 1, "low": 2}
+
+// compare orders two hits as opts ask, then the most recently updated
+// first.
+func compare(a, b *Hit, opts Options) int {
+	c := 0
+	switch opts.Sort {
+	case SortRelevance:
+		c = cmp.Compare(b.Rank, a.Rank)
+	case SortCreated:
+		c = b.Task.CreatedAt.Compare(a.Task.CreatedAt)
+	case SortDue:
+		aDue, aOk := a.Task.DueDate()
+		bDue, bOk := b.Task.DueDate()
+		if aOk != bOk {
+			if aOk {
+				return -1
+			}
+			return 1
+		}
+		if aOk {
+			c = aDue.Compare(bDue)
+		}
+	case SortPriority:
+		c = cmp.Compare(priorityOrder[a.Task.Priority], priorityOrder[b.Task.Priority])
+	case SortTitle:
+		c = strings.Compare(strings.ToLower(a.Task.Title), strings.ToLower(b.Task.Title))
+	}
+	if opts.Reverse {
+		c = -c
+	}
+	if c == 0 {
+		c = b.Task.UpdatedAt.Compare(a.Task.UpdatedAt)
+	}
+	if c == 0 {
+		c = strings.Compare(a.Task.ID, b.Task.ID)
+	}
+	return c
+}
+
+// highlights keeps the snippets with a match in them.
+func highlights(m match) []Highlight {
+	result := []Highlight{}
+	add := func(field string, snippet *string) {
+		if snippet != nil && strings.ContainsRune(*snippet, '\x02') {
+			result = append(result, Highlight{Field: field, Snippet: mark(*snippet)})
+		}
+	}
+	add(HighlightTitle, &m.Title)
+	add(HighlightDescription, m.Description)
+	add(HighlightComment, m.Comment)
+	return result
+}
+
+func mark(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, "\x02", "<mark>")
+	return strings.ReplaceAll(snippet, "\x03", "</mark>")
+}
@@ -0,0 +1,105 @@
+// Package sprints implements sprint workflows shared by the APIs.
+package sprints
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/webhooks"
+)
+
+// Sprint statuses stored on Sprint.status.
+const (
+	StatusPlanned   = "planned"
+	StatusActive    = "active"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+)
+
+var (
+	ErrNotFound      = errors.New("sprint not found")
+	ErrInvalidStatus = errors.New("invalid sprint status")
+	ErrInvalidDates  = errors.New("sprint must end after it starts")
+)
+
+// Changes are the fields Update sets; nil fields are left as they are.
+type Changes struct {
+	Name        *string
+	Description *string
+	Status      *string
+	StartDate   *time.Time
+	EndDate     *time.Time
+}
+
+// Update applies changes to the user's sprint. Moving the sprint to
+// completed publishes sprint.completed; the status guard in the update makes
+// completing the same sprint twice publish it only once.
+func Update(ctx context.Context, client *db.PrismaClient, userID, id string, changes Changes) (*db.SprintModel, error) {
+	sprint, err := client.Sprint.FindFirst(
+		db.Sprint.ID.Equals(id),
+		db.Sprint.UserID.Equals(userID),
+		db.Sprint.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	startDate, endDate := sprint.StartDate, sprint.EndDate
+	if changes.StartDate != nil {
+		startDate = *changes.StartDate
+	}
+	if changes.EndDate != nil {
+		endDate = *changes.EndDate
+	}
+	if !endDate.After(startDate) {
+		return nil, ErrInvalidDates
+	}
+
+	params := []db.SprintSetParam{
+		db.Sprint.Name.SetIfPresent(changes.Name),
+		db.Sprint.Description.SetIfPresent(changes.Description),
+		db.Sprint.StartDate.Set(startDate),
+		db.Sprint.EndDate.Set(endDate),
+	}
+	completing := false
+	if changes.Status != nil {
+		switch *changes.Status {
+		case StatusPlanned, StatusActive, StatusCancelled:
+			params = append(params, db.Sprint.Status.Set(*changes.Status))
+		case StatusCompleted:
+			completing = true
+		default:
+			return nil, ErrInvalidStatus
+		}
+	}
+
+	completed := false
+	if completing {
+		result, err := client.Sprint.FindMany(
+			db.Sprint.ID.Equals(id),
+			db.Sprint.Status.Not(StatusCompleted),
+		).Update(
+			db.Sprint.Status.Set(StatusCompleted),
+		).Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		completed = result.Count == 1
+	}
+
+	sprint, err = client.Sprint.FindUnique(
+		db.Sprint.ID.Equals(id),
+	).Update(params...).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if completed {
+		webhooks.Publish(userID, webhooks.EventSprintCompleted, sprint)
+	}
+	return sprint, nil
+}
@@ -0,0 +1,389 @@
+// Package tags implements per-user task tags. Names are unique per user
+// ignoring case, and tasks link to tags rather than copying their names, so
+// renaming a tag renames it on every task and merging moves every task onto
+// the tag that is kept.
+package tags
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/utils"
+)
+
+// MaxNameLength is the longest tag name, in characters.
+const MaxNameLength = 50
+
+var (
+	ErrNotFound      = errors.New("tag not found")
+	ErrTaskNotFound  = errors.New("task not found")
+	ErrNameRequired  = errors.New("tag name is required")
+	ErrNameTooLong   = errors.New("tag name must be at most 50 characters")
+	ErrNameTaken     = errors.New("a tag with this name already exists; merge the tags instead")
+	ErrMergeIntoSelf = errors.New("cannot merge a tag into itself")
+)
+
+// Tag is a tag with the number of tasks outside the trash that carry it.
+type Tag struct {
+	db.TagModel
+	TaskCount int `json:"taskCount"`
+}
+
+// Normalize trims a tag name and collapses runs of whitespace.
+func Normalize(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	switch {
+	case name == "":
+		return "", ErrNameRequired
+	case utf8.RuneCountInString(name) > MaxNameLength:
+		return "", ErrNameTooLong
+	}
+	return name, nil
+}
+
+// Key is the form names are compared in.
+func Key(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// List returns the user's tags by name with their usage counts.
+func List(ctx context.Context, client *db.PrismaClient, userID string) ([]Tag, error) {
+	list, err := client.Tag.FindMany(
+		db.Tag.UserID.Equals(userID),
+	).OrderBy(
+		db.Tag.Key.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := usage(ctx, client, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Tag, len(list))
+	for i, tag := range list {
+		result[i] = Tag{TagModel: tag, TaskCount: counts[tag.ID]}
+	}
+	return result, nil
+}
+
+func Get(ctx context.Context, client *db.PrismaClient, userID, id string) (*Tag, error) {
+	tag, err := find(ctx, client, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return withCount(ctx, client, userID, tag)
+}
+
+// Create adds a tag. An empty color leaves it unset.
+func Create(ctx context.Context, client *db.PrismaClient, userID, name, color string) (*Tag, error) {
+	name, err := Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+
+	params := []db.TagSetParam{db.Tag.ID.Set(utils.GenerateUUID())}
+	if color != "" {
+		params = append(params, db.Tag.Color.Set(color))
+	}
+	tag, err := client.Tag.CreateOne(
+		db.Tag.Name.Set(name),
+		db.Tag.Key.Set(Key(name)),
+		db.Tag.User.Link(db.User.ID.Equals(userID)),
+		params...,
+	).Exec(ctx)
+	if _, ok := db.IsErrUniqueConstraint(err); ok {
+		return nil, ErrNameTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Tag{TagModel: *tag}, nil
+}
+
+// Update renames the tag or changes its color; nil leaves a field
+// unchanged and an empty color clears it. Renaming onto another tag's name
+// fails with ErrNameTaken, since that is a merge.
+func Update(ctx context.Context, client *db.PrismaClient, userID, id string, name, color *string) (*Tag, error) {
+	if _, err := find(ctx, client, userID, id); err != nil {
+		return nil, err
+	}
+
+	var params []db.TagSetParam
+	if name != nil {
+		normalized, err := Normalize(*name)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, db.Tag.Name.Set(normalized), db.Tag.Key.Set(Key(normalized)))
+	}
+	if color != nil {
+		if *color == "" {
+			params = append(params, db.Tag.Color.SetOptional(nil))
+		} else {
+			params = append(params, db.Tag.Color.Set(*color))
+		}
+	}
+
+	tag, err := client.Tag.FindUnique(
+		db.Tag.ID.Equals(id),
+	).Update(params...).Exec(ctx)
+	if _, ok := db.IsErrUniqueConstraint(err); ok {
+		return nil, ErrNameTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withCount(ctx, client, userID, tag)
+}
+
+// Delete removes the tag from every task and deletes it.
+func Delete(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	if _, err := find(ctx, client, userID, id); err != nil {
+		return err
+	}
+	_, err := client.Tag.FindUnique(
+		db.Tag.ID.Equals(id),
+	).Delete().Exec(ctx)
+	return err
+}
+
+// Merge moves every task tagged with one of the source tags onto the
+// target tag and deletes the source tags, in a single transaction.
+func Merge(ctx context.Context, client *db.PrismaClient, userID string, sourceIDs []string, targetID string) (*Tag, error) {
+	target, err := find(ctx, client, userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	var sources []string
+	seen := map[string]bool{}
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return nil, ErrMergeIntoSelf
+		}
+		if seen[id] {
+			continue
+		}
+		if _, err := find(ctx, client, userID, id); err != nil {
+			return nil, err
+		}
+		seen[id] = true
+		sources = append(sources, id)
+	}
+	if len(sources) == 0 {
+		return withCount(ctx, client, userID, target)
+	}
+
+	tagged, err := client.TaskTag.FindMany(
+		db.TaskTag.TagID.Equals(target.ID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	links, err := client.TaskTag.FindMany(
+		db.TaskTag.TagID.In(sources),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// A task carrying several of the tags keeps a single link to the target.
+	onTarget := map[string]bool{}
+	for _, link := range tagged {
+		onTarget[link.TaskID] = true
+	}
+	var move []string
+	for _, link := range links {
+		if !onTarget[link.TaskID] {
+			onTarget[link.TaskID] = true
+			move = append(move, link.ID)
+		}
+	}
+
+	txs := []db.PrismaTransaction{
+		client.TaskTag.FindMany(
+			db.TaskTag.ID.In(move),
+		).Update(
+			db.TaskTag.TagID.Set(target.ID),
+		).Tx(),
+		client.Tag.FindMany(
+			db.Tag.ID.In(sources),
+		).Delete().Tx(),
+	}
+	if err := client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return nil, err
+	}
+	return Get(ctx, client, userID, target.ID)
+}
+
+// Resolve returns the user's tags with the given names, in order and
+// without repeats, creating the ones that don't exist yet.
+func Resolve(ctx context.Context, client *db.PrismaClient, userID string, names []string) ([]db.TagModel, error) {
+	existing, err := ByKey(ctx, client, userID, names)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []db.TagModel
+	seen := map[string]bool{}
+	for _, name := range names {
+		name, err := Normalize(name)
+		if err != nil {
+			return nil, err
+		}
+		key := Key(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		tag, ok := existing[key]
+		if !ok {
+			created, err := Create(ctx, client, userID, name, "")
+			if errors.Is(err, ErrNameTaken) {
+				// Created concurrently; use that one.
+				found, err := client.Tag.FindUnique(
+					db.Tag.UserIDKey(db.Tag.UserID.Equals(userID), db.Tag.Key.Equals(key)),
+				).Exec(ctx)
+				if err != nil {
+					return nil, err
+				}
+				created = &Tag{TagModel: *found}
+			} else if err != nil {
+				return nil, err
+			}
+			tag = created.TagModel
+		}
+		result = append(result, tag)
+	}
+	return result, nil
+}
+
+// ByKey maps the keys of the given names to the user's existing tags.
+func ByKey(ctx context.Context, client *db.PrismaClient, userID string, names []string) (map[string]db.TagModel, error) {
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = Key(name)
+	}
+	list, err := client.Tag.FindMany(
+		db.Tag.UserID.Equals(userID),
+		db.Tag.Key.In(keys),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]db.TagModel, len(list))
+	for _, tag := range list {
+		result[tag.Key] = tag
+	}
+	return result, nil
+}
+
+// SetTaskTags replaces the task's tags with the named ones, creating tags
+// that don't exist yet, and returns them in order.
+func SetTaskTags(ctx context.Context, client *db.PrismaClient, userID, taskID string, names []string) ([]db.TagModel, error) {
+	_, err := client.Task.FindFirst(
+		db.Task.ID.Equals(taskID),
+		db.Task.UserID.Equals(userID),
+		db.Task.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	list, err := Resolve(ctx, client, userID, names)
+	if err != nil {
+		return nil, err
+	}
+	links, err := client.TaskTag.FindMany(
+		db.TaskTag.TaskID.Equals(taskID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := map[string]bool{}
+	for _, link := range links {
+		linked[link.TagID] = true
+	}
+	ids := make([]string, len(list))
+	txs := []db.PrismaTransaction{}
+	for i, tag := range list {
+		ids[i] = tag.ID
+		if !linked[tag.ID] {
+			txs = append(txs, Link(client, taskID, tag.ID))
+		}
+	}
+	txs = append(txs, client.TaskTag.FindMany(
+		db.TaskTag.TaskID.Equals(taskID),
+		db.TaskTag.Not(db.TaskTag.TagID.In(ids)),
+	).Delete().Tx())
+
+	err = client.Prisma.Transaction(txs...).Exec(ctx)
+	if _, ok := db.IsErrUniqueConstraint(err); ok {
+		// A concurrent update linked the same tag; the result is the same.
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Link returns the write that tags a task, for callers creating the task
+// in the same transaction.
+func Link(client *db.PrismaClient, taskID, tagID string) db.PrismaTransaction {
+	return client.TaskTag.CreateOne(
+		db.TaskTag.Task.Link(db.Task.ID.Equals(taskID)),
+		db.TaskTag.Tag.Link(db.Tag.ID.Equals(tagID)),
+		db.TaskTag.ID.Set(utils.GenerateUUID()),
+	).Tx()
+}
+
+// usage counts the tasks outside the trash per tag, over the links
+// matching filters.
+func usage(ctx context.Context, client *db.PrismaClient, userID string, filters ...db.TaskTagWhereParam) (map[string]int, error) {
+	links, err := client.TaskTag.FindMany(append(filters,
+		db.TaskTag.Task.Where(
+			db.Task.UserID.Equals(userID),
+			db.Task.DeletedAt.IsNull(),
+		),
+	)...).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, link := range links {
+		counts[link.TagID]++
+	}
+	return counts, nil
+}
+
+func withCount(ctx context.Context, client *db.PrismaClient, userID string, tag *db.TagModel) (*Tag, error) {
+	counts, err := usage(ctx, client, userID, db.TaskTag.TagID.Equals(tag.ID))
+	if err != nil {
+		return nil, err
+	}
+	return &Tag{TagModel: *tag, TaskCount: counts[tag.ID]}, nil
+}
+
+func find(ctx context.Context, client *db.PrismaClient, userID, id string) (*db.TagModel, error) {
+	tag, err := client.Tag.FindFirst(
+		db.Tag.ID.Equals(id),
+		db.Tag.UserID.Equals(userID),
+	).Exec(ctx)
+	if db.IsErrNotFound(err) {
+		return nil, ErrNotFound
+	}
+	return tag, err
+}
@@ -0,0 +1,137 @@
+// Package tasks implements task workflows shared by the REST and GraphQL
+// APIs: completion with its XP award, subtasks, dependencies and recurring
+// tasks.
+package tasks
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/utils"
+	"lifequest-server/internal/webhooks"
+	"lifequest-server/internal/xp"
+)
+
+// StatusCompleted is the Task.status of finished tasks.
+const StatusCompleted = "completed"
+
+var ErrAlreadyCompleted = errors.New("task already completed")
+
+// completeSQL marks task $1 completed unless it already is, and only then
+// adds it to its project's counters and awards user $3 $2 XP through the
+// ledger entry with ID $4 and reason $5. Timestamps are stored in UTC, as
+// Prisma stores them.
+const completeSQL = `
+WITH done AS (
+	UPDATE tasks
+	SET status = 'completed', completed_at = now() AT TIME ZONE 'UTC', updated_at = now() AT TIME ZONE 'UTC'
+	WHERE id = $1 AND status <> 'completed' AND deleted_at IS NULL
+	RETURNING project_id
+), counted AS (
+	UPDATE projects
+	SET completed_task_count = completed_task_count + 1, xp_earned = xp_earned + $2::int, updated_at = now() AT TIME ZONE 'UTC'
+	WHERE id IN (SELECT project_id FROM done)
+), awarded AS (
+	UPDATE users
+	SET xp = xp + $2::int, total_xp = total_xp + $2::int, updated_at = now() AT TIME ZONE 'UTC'
+	WHERE id = $3 AND $2::int > 0 AND EXISTS (SELECT 1 FROM done)
+), recorded AS (
+	INSERT INTO xp_ledger (id, user_id, amount, reason, source_id, created_at)
+	SELECT $4, $3, $2::int, $5, $1, now() AT TIME ZONE 'UTC' FROM done WHERE $2::int > 0
+)
+SELECT count(*)::int AS "completed" FROM done`
+
+// Complete marks the task completed, credits its XP to the user and the
+// project, and publishes task.completed. XP its subtasks already earned is
+// deducted from the award. Tasks with unfinished dependencies are refused
+// with ErrBlocked unless force is set. Completing an occurrence of a
+// recurring task also credits its streak and schedules the next occurrence.
+// The status change, project counters and award are one statement, so they
+// happen together or not at all, and its status guard makes completing the
+// same task twice award XP only once.
+func Complete(ctx context.Context, client *db.PrismaClient, userID, taskID string, force bool) (*db.TaskModel, error) {
+	task, err := findTask(ctx, client, userID, taskID, authz.ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if !force && task.Status != StatusCompleted {
+		// Dependencies are between the tasks of the task's creator
+		graph, err := LoadGraph(ctx, client, task.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if graph.IsBlocked(taskID) {
+			return nil, ErrBlocked
+		}
+	}
+
+	award, err := completionAward(ctx, client, userID, task)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		Completed int `json:"completed"`
+	}
+	err = client.Prisma.QueryRaw(completeSQL, task.ID, award, userID, utils.GenerateUUID(), xp.ReasonTaskCompleted).Exec(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) != 1 || rows[0].Completed == 0 {
+		return nil, ErrAlreadyCompleted
+	}
+	if award > 0 {
+		if _, err := xp.SyncLevel(ctx, client, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	task, err = client.Task.FindFirst(
+		db.Task.ID.Equals(taskID),
+		db.Task.DeletedAt.IsNull(),
+	).With(
+		db.Task.Subtasks.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now, _ := task.CompletedAt()
+
+	// The completion stands even if the series can't move on; calendar-based
+	// series are caught up by the scheduler.
+	if err := advance(ctx, client, task, now); err != nil {
+		log.Printf("tasks: failed to advance the recurrence of %s: %v", task.ID, err)
+	}
+
+	webhooks.Publish(userID, webhooks.EventTaskCompleted, task)
+	return task, nil
+}
+
+// completionAward is the XP for completing task: its value less what its
+// subtasks already earned the user.
+func completionAward(ctx context.Context, client *db.PrismaClient, userID string, task *db.TaskModel) (int, error) {
+	subtasks, err := client.Subtask.FindMany(
+		db.Subtask.TaskID.Equals(task.ID),
+	).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(subtasks) == 0 {
+		return task.XpValue, nil
+	}
+	ids := make([]string, len(subtasks))
+	for i, subtask := range subtasks {
+		ids[i] = subtask.ID
+	}
+	_, held, err := xp.Credits(ctx, client, userID, ids)
+	if err != nil {
+		return 0, err
+	}
+	award := task.XpValue
+	for _, amount := range held {
+		award -= amount
+	}
+	return max(award, 0), nil
+}
@@ -0,0 +1,354 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/utils"
+)
+
+var (
+	ErrSelfDependency     = errors.New("a task cannot depend on itself")
+	ErrDependencyCycle    = errors.New("dependency would create a cycle")
+	ErrDependencyExists   = errors.New("dependency already exists")
+	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrBlocked            = errors.New("task has unfinished dependencies")
+)
+
+// Graph holds a user's live tasks and the dependencies between them.
+// Dependencies on trashed tasks are left out.
+type Graph struct {
+	tasks      map[string]*db.TaskModel
+	order      []string            // task IDs by creation
+	blockers   map[string][]string // task ID -> tasks it depends on
+	dependents map[string][]string // task ID -> tasks that depend on it
+}
+
+// CriticalPath is the longest chain of open tasks in a project, measured in
+// estimated pomodoros.
+type CriticalPath struct {
+	Tasks              []*db.TaskModel `json:"tasks"`
+	EstimatedPomodoros int             `json:"estimatedPomodoros"`
+}
+
+// LoadGraph reads the user's live tasks and their dependencies.
+func LoadGraph(ctx context.Context, client *db.PrismaClient, userID string) (*Graph, error) {
+	tasks, err := client.Task.FindMany(
+		db.Task.UserID.Equals(userID),
+		db.Task.DeletedAt.IsNull(),
+	).OrderBy(
+		db.Task.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies, err := client.TaskDependency.FindMany(
+		db.TaskDependency.Task.Where(
+			db.Task.UserID.Equals(userID),
+		),
+	).OrderBy(
+		db.TaskDependency.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &Graph{
+		tasks:      make(map[string]*db.TaskModel, len(tasks)),
+		order:      make([]string, len(tasks)),
+		blockers:   map[string][]string{},
+		dependents: map[string][]string{},
+	}
+	for i := range tasks {
+		graph.tasks[tasks[i].ID] = &tasks[i]
+		graph.order[i] = tasks[i].ID
+	}
+	for _, dependency := range dependencies {
+		if graph.tasks[dependency.TaskID] == nil || graph.tasks[dependency.DependsOnID] == nil {
+			continue
+		}
+		graph.blockers[dependency.TaskID] = append(graph.blockers[dependency.TaskID], dependency.DependsOnID)
+		graph.dependents[dependency.DependsOnID] = append(graph.dependents[dependency.DependsOnID], dependency.TaskID)
+	}
+	return graph, nil
+}
+
+// Dependencies are a task's live blockers and dependents.
+type Dependencies struct {
+	Blockers   []*db.TaskModel
+	Dependents []*db.TaskModel
+}
+
+// IsBlocked reports whether the task depends on a task that is not
+// completed yet.
+func (d *Dependencies) IsBlocked() bool {
+	for _, blocker := range d.Blockers {
+		if blocker.Status != StatusCompleted {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadDependencies reads the dependencies of the tasks with ids only, keyed
+// by task ID, so a page of tasks from any owners costs two queries instead
+// of their owners' whole graphs.
+// Dependencies on trashed tasks are left out, as in LoadGraph.
+func LoadDependencies(ctx context.Context, client *db.PrismaClient, ids []string) (map[string]*Dependencies, error) {
+	result := make(map[string]*Dependencies, len(ids))
+	for _, id := range ids {
+		result[id] = &Dependencies{}
+	}
+	edges, err := client.TaskDependency.FindMany(
+		db.TaskDependency.Or(
+			db.TaskDependency.TaskID.In(ids),
+			db.TaskDependency.DependsOnID.In(ids),
+		),
+	).OrderBy(
+		db.TaskDependency.CreatedAt.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ends := map[string]bool{}
+	for _, edge := range edges {
+		ends[edge.TaskID] = true
+		ends[edge.DependsOnID] = true
+	}
+	endIDs := make([]string, 0, len(ends))
+	for id := range ends {
+		endIDs = append(endIDs, id)
+	}
+	tasks, err := client.Task.FindMany(
+		db.Task.ID.In(endIDs),
+		db.Task.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	live := make(map[string]*db.TaskModel, len(tasks))
+	for i := range tasks {
+		live[tasks[i].ID] = &tasks[i]
+	}
+
+	for _, edge := range edges {
+		task, dependsOn := live[edge.TaskID], live[edge.DependsOnID]
+		if task == nil || dependsOn == nil {
+			continue
+		}
+		if d := result[edge.TaskID]; d != nil {
+			d.Blockers = append(d.Blockers, dependsOn)
+		}
+		if d := result[edge.DependsOnID]; d != nil {
+			d.Dependents = append(d.Dependents, task)
+		}
+	}
+	return result, nil
+}
+
+// Task returns the task with the given ID, or nil.
+func (g *Graph) Task(id string) *db.TaskModel {
+	return g.tasks[id]
+}
+
+// Blockers returns the tasks id depends on.
+func (g *Graph) Blockers(id string) []*db.TaskModel {
+	return g.lookup(g.blockers[id])
+}
+
+// Dependents returns the tasks that depend on id.
+func (g *Graph) Dependents(id string) []*db.TaskModel {
+	return g.lookup(g.dependents[id])
+}
+
+// OpenBlockers returns the tasks id depends on that are not completed yet.
+func (g *Graph) OpenBlockers(id string) []*db.TaskModel {
+	var open []*db.TaskModel
+	for _, blocker := range g.Blockers(id) {
+		if blocker.Status != StatusCompleted {
+			open = append(open, blocker)
+		}
+	}
+	return open
+}
+
+// IsBlocked reports whether id depends on a task that is not completed yet.
+func (g *Graph) IsBlocked(id string) bool {
+	return len(g.OpenBlockers(id)) > 0
+}
+
+// reaches reports whether to can be reached from from by following
+// dependencies.
+func (g *Graph) reaches(from, to string) bool {
+	seen := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == to {
+			return true
+		}
+		for _, blocker := range g.blockers[id] {
+			if !seen[blocker] {
+				seen[blocker] = true
+				queue = append(queue, blocker)
+			}
+		}
+	}
+	return false
+}
+
+// Sort orders tasks so that every task comes after the tasks it depends on.
+// Tasks that don't depend on each other keep their relative order.
+func (g *Graph) Sort(tasks []db.TaskModel) []db.TaskModel {
+	index := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		index[task.ID] = i
+	}
+
+	// Only dependencies between the given tasks count.
+	pending := make([]int, len(tasks))
+	for i, task := range tasks {
+		for _, blocker := range g.blockers[task.ID] {
+			if _, ok := index[blocker]; ok {
+				pending[i]++
+			}
+		}
+	}
+
+	sorted := make([]db.TaskModel, 0, len(tasks))
+	done := make([]bool, len(tasks))
+	for len(sorted) < len(tasks) {
+		next := -1
+		for i := range tasks {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			// Cycles are refused when dependencies are added; keep whatever
+			// is left in its original order should one slip through.
+			for i := range tasks {
+				if !done[i] {
+					sorted = append(sorted, tasks[i])
+				}
+			}
+			break
+		}
+		done[next] = true
+		sorted = append(sorted, tasks[next])
+		for _, dependent := range g.dependents[tasks[next].ID] {
+			if i, ok := index[dependent]; ok {
+				pending[i]--
+			}
+		}
+	}
+	return sorted
+}
+
+// CriticalPath returns the chain of open tasks in the project with the most
+// estimated pomodoros, following dependencies between them. Blockers in
+// other projects are not part of the path.
+func (g *Graph) CriticalPath(projectID string) CriticalPath {
+	var open []db.TaskModel
+	for _, id := range g.order {
+		if task := g.tasks[id]; task.ProjectID == projectID && task.Status != StatusCompleted {
+			open = append(open, *task)
+		}
+	}
+
+	// In dependency order every task's blockers have their totals already.
+	total := map[string]int{}
+	previous := map[string]string{}
+	last := ""
+	for _, task := range g.Sort(open) {
+		best := ""
+		for _, blocker := range g.blockers[task.ID] {
+			if _, ok := total[blocker]; ok && (best == "" || total[blocker] > total[best]) {
+				best = blocker
+			}
+		}
+		total[task.ID] = task.EstimatedPomodoros
+		if best != "" {
+			total[task.ID] += total[best]
+			previous[task.ID] = best
+		}
+		if last == "" || total[task.ID] > total[last] {
+			last = task.ID
+		}
+	}
+
+	path := CriticalPath{Tasks: []*db.TaskModel{}}
+	if last == "" {
+		return path
+	}
+	path.EstimatedPomodoros = total[last]
+	for id := last; id != ""; id = previous[id] {
+		path.Tasks = append([]*db.TaskModel{g.tasks[id]}, path.Tasks...)
+	}
+	return path
+}
+
+func (g *Graph) lookup(ids []string) []*db.TaskModel {
+	result := make([]*db.TaskModel, len(ids))
+	for i, id := range ids {
+		result[i] = g.tasks[id]
+	}
+	return result
+}
+
+// AddDependency records that taskID cannot be completed before dependsOnID.
+// Dependencies that would close a cycle are refused.
+func AddDependency(ctx context.Context, client *db.PrismaClient, userID, taskID, dependsOnID string) error {
+	if taskID == dependsOnID {
+		return ErrSelfDependency
+	}
+	graph, err := LoadGraph(ctx, client, userID)
+	if err != nil {
+		return err
+	}
+	if graph.Task(taskID) == nil || graph.Task(dependsOnID) == nil {
+		return ErrTaskNotFound
+	}
+	for _, blocker := range graph.blockers[taskID] {
+		if blocker == dependsOnID {
+			return ErrDependencyExists
+		}
+	}
+	if graph.reaches(dependsOnID, taskID) {
+		return ErrDependencyCycle
+	}
+
+	_, err = client.TaskDependency.CreateOne(
+		db.TaskDependency.Task.Link(db.Task.ID.Equals(taskID)),
+		db.TaskDependency.DependsOn.Link(db.Task.ID.Equals(dependsOnID)),
+		db.TaskDependency.ID.Set(utils.GenerateUUID()),
+	).Exec(ctx)
+	if _, ok := db.IsErrUniqueConstraint(err); ok {
+		return ErrDependencyExists
+	}
+	return err
+}
+
+// RemoveDependency drops the dependency of taskID on dependsOnID.
+func RemoveDependency(ctx context.Context, client *db.PrismaClient, userID, taskID, dependsOnID string) error {
+	if _, err := findTask(ctx, client, userID, taskID, authz.ActionUpdate); err != nil {
+		return err
+	}
+	result, err := client.TaskDependency.FindMany(
+		db.TaskDependency.TaskID.Equals(taskID),
+		db.TaskDependency.DependsOnID.Equals(dependsOnID),
+	).Delete().Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if result.Count == 0 {
+		return ErrDependencyNotFound
+	}
+	return nil
+}
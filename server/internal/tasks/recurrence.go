@@ -0,0 +1,391 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/rrule"
+	"lifequest-server/internal/tags"
+	"lifequest-server/internal/utils"
+	"lifequest-server/internal/xp"
+)
+
+const (
+	// StreakBonus is the extra XP for completing an occurrence, per
+	// consecutive completed occurrence before it, up to MaxStreakBonus.
+	StreakBonus    = 5
+	MaxStreakBonus = 50
+
+	scheduleInterval = time.Hour
+)
+
+var ErrNotRecurring = errors.New("task does not recur")
+
+// SetRecurrence makes the task recur by rule, an RRULE such as
+// "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", or changes the rule of the series it
+// belongs to. A new series starts at the task's due date, or now when it has
+// none, and the task becomes its first occurrence. With afterCompletion the
+// next occurrence is counted from the day the previous one was completed,
+// so FREQ=DAILY;INTERVAL=3 means three days after each completion.
+func SetRecurrence(ctx context.Context, client *db.PrismaClient, userID, taskID, rule string, afterCompletion bool) (*db.TaskRecurrenceModel, error) {
+	parsed, err := rrule.Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+	task, err := findTask(ctx, client, userID, taskID, authz.ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if seriesID, ok := task.RecurrenceID(); ok {
+		_, err := client.TaskRecurrence.FindUnique(
+			db.TaskRecurrence.ID.Equals(seriesID),
+		).Update(
+			db.TaskRecurrence.Rule.Set(parsed.String()),
+			db.TaskRecurrence.AfterCompletion.Set(afterCompletion),
+			db.TaskRecurrence.EndedAt.SetOptional(nil),
+		).Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		start, ok := task.DueDate()
+		if !ok {
+			start = time.Now().UTC().Truncate(time.Minute)
+		}
+		seriesID = utils.GenerateUUID()
+		err := client.Prisma.Transaction(
+			client.TaskRecurrence.CreateOne(
+				db.TaskRecurrence.Rule.Set(parsed.String()),
+				db.TaskRecurrence.StartsAt.Set(start),
+				db.TaskRecurrence.User.Link(db.User.ID.Equals(userID)),
+				db.TaskRecurrence.ID.Set(seriesID),
+				db.TaskRecurrence.AfterCompletion.Set(afterCompletion),
+			).Tx(),
+			client.Task.FindUnique(
+				db.Task.ID.Equals(task.ID),
+			).Update(
+				db.Task.Recurrence.Link(db.TaskRecurrence.ID.Equals(seriesID)),
+				db.Task.DueDate.Set(start),
+			).Tx(),
+		).Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		task.InnerTask.DueDate = &start
+		task.InnerTask.RecurrenceID = &seriesID
+	}
+
+	series, err := Recurrence(ctx, client, userID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	// A series whose latest occurrence is already finished continues right
+	// away.
+	if latest := series.Tasks(); len(latest) > 0 && latest[0].ID == task.ID && task.Status == StatusCompleted {
+		if err := schedule(ctx, client, series, task, time.Now()); err != nil {
+			return nil, err
+		}
+		return Recurrence(ctx, client, userID, task.ID)
+	}
+	return series, nil
+}
+
+// StopRecurrence ends the task's series. Its occurrences stay, with their
+// history, as ordinary tasks.
+func StopRecurrence(ctx context.Context, client *db.PrismaClient, userID, taskID string) (*db.TaskRecurrenceModel, error) {
+	task, err := findTask(ctx, client, userID, taskID, authz.ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+	seriesID, ok := task.RecurrenceID()
+	if !ok {
+		return nil, ErrNotRecurring
+	}
+	_, err = client.TaskRecurrence.FindUnique(
+		db.TaskRecurrence.ID.Equals(seriesID),
+	).Update(
+		db.TaskRecurrence.EndedAt.Set(time.Now()),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Recurrence(ctx, client, userID, task.ID)
+}
+
+// Recurrence returns the series the task belongs to, with its occurrences
+// newest first.
+func Recurrence(ctx context.Context, client *db.PrismaClient, userID, taskID string) (*db.TaskRecurrenceModel, error) {
+	task, err := findTask(ctx, client, userID, taskID, authz.ActionRead)
+	if err != nil {
+		return nil, err
+	}
+	seriesID, ok := task.RecurrenceID()
+	if !ok {
+		return nil, ErrNotRecurring
+	}
+	return client.TaskRecurrence.FindUnique(
+		db.TaskRecurrence.ID.Equals(seriesID),
+	).With(
+		db.TaskRecurrence.Tasks.Fetch(
+			db.Task.DeletedAt.IsNull(),
+		).OrderBy(
+			db.Task.DueDate.Order(db.SortOrderDesc),
+		),
+	).Exec(ctx)
+}
+
+// Start creates the next occurrence of calendar-based series whose
+// latest occurrence has come due, every scheduleInterval until ctx is
+// cancelled. The missed occurrence stays open, which breaks the streak.
+func Start(ctx context.Context, client *db.PrismaClient) {
+	go func() {
+		ticker := time.NewTicker(scheduleInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				scheduleDue(ctx, client, time.Now())
+			}
+		}
+	}()
+}
+
+func scheduleDue(ctx context.Context, client *db.PrismaClient, now time.Time) {
+	series, err := client.TaskRecurrence.FindMany(
+		db.TaskRecurrence.EndedAt.IsNull(),
+		db.TaskRecurrence.AfterCompletion.Equals(false),
+	).Exec(ctx)
+	if err != nil {
+		log.Printf("tasks: failed to load recurring tasks: %v", err)
+		return
+	}
+
+	for i := range series {
+		latest, err := latestOccurrence(ctx, client, series[i].ID)
+		if err != nil {
+			if !db.IsErrNotFound(err) {
+				log.Printf("tasks: failed to load the latest occurrence of %s: %v", series[i].ID, err)
+			}
+			continue
+		}
+		if due, _ := latest.DueDate(); due.After(now) {
+			continue
+		}
+		if err := schedule(ctx, client, &series[i], latest, now); err != nil {
+			log.Printf("tasks: failed to schedule the next occurrence of %s: %v", series[i].ID, err)
+			continue
+		}
+		if _, err := updateStreak(ctx, client, series[i].ID, now); err != nil {
+			log.Printf("tasks: failed to update the streak of %s: %v", series[i].ID, err)
+		}
+	}
+}
+
+// advance runs after an occurrence is completed: it updates the streak,
+// credits the streak bonus, and schedules the next occurrence when the
+// completed one was the latest.
+func advance(ctx context.Context, client *db.PrismaClient, task *db.TaskModel, now time.Time) error {
+	seriesID, ok := task.RecurrenceID()
+	if !ok {
+		return nil
+	}
+	series, err := client.TaskRecurrence.FindUnique(
+		db.TaskRecurrence.ID.Equals(seriesID),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	run, err := updateStreak(ctx, client, series.ID, now)
+	if err != nil {
+		return err
+	}
+	if len(run) > 1 && run[0] == task.ID {
+		bonus := min((len(run)-1)*StreakBonus, MaxStreakBonus)
+		if _, err := xp.Award(ctx, client, task.UserID, bonus, xp.ReasonStreakBonus, task.ID); err != nil {
+			return err
+		}
+	}
+
+	if _, ended := series.EndedAt(); ended {
+		return nil
+	}
+	latest, err := latestOccurrence(ctx, client, series.ID)
+	if err != nil {
+		return err
+	}
+	if latest.ID != task.ID {
+		return nil
+	}
+	return schedule(ctx, client, series, task, now)
+}
+
+// schedule creates the occurrence that follows latest, copying its details,
+// tags and an unchecked copy of its subtasks, or ends the series when COUNT or
+// UNTIL leaves none. Calendar-based series skip occurrences already in the
+// past.
+func schedule(ctx context.Context, client *db.PrismaClient, series *db.TaskRecurrenceModel, latest *db.TaskModel, now time.Time) error {
+	rule, err := rrule.Parse(series.Rule)
+	if err != nil {
+		return err
+	}
+
+	due, _ := latest.DueDate()
+	start, after := series.StartsAt, due
+	if series.AfterCompletion {
+		completedAt, ok := latest.CompletedAt()
+		if !ok {
+			return nil
+		}
+		y, m, d := completedAt.In(start.Location()).Date()
+		start = time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+		after = start
+	} else if now.After(after) {
+		after = now
+	}
+
+	next, ok := rule.Next(start, after)
+	if !ok || (rule.Count > 0 && series.Occurrences >= rule.Count) {
+		_, err := client.TaskRecurrence.FindUnique(
+			db.TaskRecurrence.ID.Equals(series.ID),
+		).Update(
+			db.TaskRecurrence.EndedAt.Set(now),
+		).Exec(ctx)
+		return err
+	}
+
+	subtasks, err := client.Subtask.FindMany(
+		db.Subtask.TaskID.Equals(latest.ID),
+	).OrderBy(
+		db.Subtask.Position.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	links, err := client.TaskTag.FindMany(
+		db.TaskTag.TaskID.Equals(latest.ID),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	id := utils.GenerateUUID()
+	txs := []db.PrismaTransaction{
+		client.Task.CreateOne(
+			db.Task.Title.Set(latest.Title),
+			db.Task.User.Link(db.User.ID.Equals(latest.UserID)),
+			db.Task.Project.Link(db.Project.ID.Equals(latest.ProjectID)),
+			db.Task.ID.Set(id),
+			db.Task.Description.SetIfPresent(latest.InnerTask.Description),
+			db.Task.Priority.Set(latest.Priority),
+			db.Task.XpValue.Set(latest.XpValue),
+			db.Task.EstimatedPomodoros.Set(latest.EstimatedPomodoros),
+			db.Task.AutoComplete.Set(latest.AutoComplete),
+			db.Task.SubtaskCount.Set(len(subtasks)),
+			db.Task.DueDate.Set(next),
+			db.Task.Recurrence.Link(db.TaskRecurrence.ID.Equals(series.ID)),
+		).Tx(),
+	}
+	for i, subtask := range subtasks {
+		txs = append(txs, client.Subtask.CreateOne(
+			db.Subtask.Title.Set(subtask.Title),
+			db.Subtask.Task.Link(db.Task.ID.Equals(id)),
+			db.Subtask.ID.Set(utils.GenerateUUID()),
+			db.Subtask.Position.Set(i),
+		).Tx())
+	}
+	for _, link := range links {
+		txs = append(txs, tags.Link(client, id, link.TagID))
+	}
+	txs = append(txs,
+		client.Project.FindUnique(
+			db.Project.ID.Equals(latest.ProjectID),
+		).Update(
+			db.Project.TaskCount.Increment(1),
+			db.Project.UpdatedAt.Set(now),
+		).Tx(),
+		client.TaskRecurrence.FindUnique(
+			db.TaskRecurrence.ID.Equals(series.ID),
+		).Update(
+			db.TaskRecurrence.Occurrences.Increment(1),
+		).Tx(),
+	)
+
+	err = client.Prisma.Transaction(txs...).Exec(ctx)
+	if _, ok := db.IsErrUniqueConstraint(err); ok {
+		// The scheduler and a completion raced; the occurrence exists.
+		return nil
+	}
+	return err
+}
+
+// updateStreak recounts the series' streaks and returns the IDs of the
+// occurrences in the current streak, newest first.
+func updateStreak(ctx context.Context, client *db.PrismaClient, seriesID string, now time.Time) ([]string, error) {
+	occurrences, err := client.Task.FindMany(
+		db.Task.RecurrenceID.Equals(seriesID),
+		db.Task.DeletedAt.IsNull(),
+	).OrderBy(
+		db.Task.DueDate.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	run, longest := streaks(occurrences, now)
+	_, err = client.TaskRecurrence.FindUnique(
+		db.TaskRecurrence.ID.Equals(seriesID),
+	).Update(
+		db.TaskRecurrence.CurrentStreak.Set(len(run)),
+		db.TaskRecurrence.LongestStreak.Set(longest),
+	).Exec(ctx)
+	return run, err
+}
+
+// streaks finds the run of completed occurrences that ends at the latest
+// one, returned newest first, and the length of the longest run.
+// occurrences are ordered by due date; an open occurrence that isn't due yet
+// doesn't break the current run.
+func streaks(occurrences []db.TaskModel, now time.Time) ([]string, int) {
+	longest, length := 0, 0
+	for _, occurrence := range occurrences {
+		if occurrence.Status == StatusCompleted {
+			length++
+			longest = max(longest, length)
+		} else {
+			length = 0
+		}
+	}
+
+	var run []string
+	for i := len(occurrences) - 1; i >= 0; i-- {
+		occurrence := occurrences[i]
+		if occurrence.Status != StatusCompleted {
+			due, _ := occurrence.DueDate()
+			if i == len(occurrences)-1 && due.After(now) {
+				continue
+			}
+			break
+		}
+		run = append(run, occurrence.ID)
+	}
+	return run, longest
+}
+
+func latestOccurrence(ctx context.Context, client *db.PrismaClient, seriesID string) (*db.TaskModel, error) {
+	return client.Task.FindFirst(
+		db.Task.RecurrenceID.Equals(seriesID),
+		db.Task.DeletedAt.IsNull(),
+	).OrderBy(
+		db.Task.DueDate.Order(db.SortOrderDesc),
+	).Exec(ctx)
+}
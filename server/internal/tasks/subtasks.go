@@ -0,0 +1,528 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/utils"
+	"lifequest-server/internal/xp"
+)
+
+var (
+	ErrTaskNotFound    = errors.New("task not found")
+	ErrSubtaskNotFound = errors.New("subtask not found")
+	ErrForbidden       = errors.New("your role on the project does not allow this")
+	ErrInvalidOrder    = errors.New("order must list each of the task's subtasks exactly once")
+	ErrHasSubtasks     = errors.New("a task with subtasks cannot become a subtask")
+	ErrSameTask        = errors.New("a task cannot become its own subtask")
+)
+
+// SubtaskUpdate holds the subtask fields to change; nil fields are left as
+// they are.
+type SubtaskUpdate struct {
+	Title     *string
+	Completed *bool
+}
+
+// Progress is the percentage of the task's subtasks that are done. Tasks
+// without subtasks are at 0 until they are completed.
+func Progress(task *db.TaskModel) int {
+	if task.SubtaskCount == 0 {
+		if task.Status == StatusCompleted {
+			return 100
+		}
+		return 0
+	}
+	return task.SubtasksDone * 100 / task.SubtaskCount
+}
+
+// subtaskXP is what completing one of the task's subtasks earns: together
+// they are worth half the task's XP, and completing the task awards the
+// rest.
+func subtaskXP(task *db.TaskModel) int {
+	if task.SubtaskCount == 0 || task.XpValue <= 0 {
+		return 0
+	}
+	return max(task.XpValue/2/task.SubtaskCount, 1)
+}
+
+// Subtasks returns the task's subtasks in order.
+func Subtasks(ctx context.Context, client *db.PrismaClient, userID, taskID string) ([]db.SubtaskModel, error) {
+	if _, err := findTask(ctx, client, userID, taskID, authz.ActionRead); err != nil {
+		return nil, err
+	}
+	return client.Subtask.FindMany(
+		db.Subtask.TaskID.Equals(taskID),
+	).OrderBy(
+		db.Subtask.Position.Order(db.SortOrderAsc),
+	).Exec(ctx)
+}
+
+// CreateSubtask adds a subtask to the end of the task's list.
+func CreateSubtask(ctx context.Context, client *db.PrismaClient, userID, taskID, title string) (*db.SubtaskModel, error) {
+	task, err := findTask(ctx, client, userID, taskID, authz.ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+	position, err := nextPosition(ctx, client, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	create := client.Subtask.CreateOne(
+		db.Subtask.Title.Set(title),
+		db.Subtask.Task.Link(db.Task.ID.Equals(task.ID)),
+		db.Subtask.ID.Set(utils.GenerateUUID()),
+		db.Subtask.Position.Set(position),
+	).Tx()
+	err = client.Prisma.Transaction(
+		create,
+		client.Task.FindUnique(
+			db.Task.ID.Equals(task.ID),
+		).Update(
+			db.Task.SubtaskCount.Increment(1),
+			db.Task.UpdatedAt.Set(time.Now()),
+		).Tx(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return create.Result(), nil
+}
+
+// UpdateSubtask renames or checks off a subtask. Checking one off while the
+// task is open earns its share of the task's XP; unchecking it takes that
+// back. The task completes itself when its last subtask is checked off and
+// it has auto-complete enabled.
+func UpdateSubtask(ctx context.Context, client *db.PrismaClient, userID, id string, update SubtaskUpdate) (*db.SubtaskModel, error) {
+	subtask, task, err := findSubtask(ctx, client, userID, id, authz.ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	params := []db.SubtaskSetParam{
+		db.Subtask.UpdatedAt.Set(now),
+	}
+	if update.Title != nil {
+		params = append(params, db.Subtask.Title.Set(*update.Title))
+	}
+
+	var txs, xpTxs []db.PrismaTransaction
+	if update.Completed != nil && *update.Completed != subtask.Completed {
+		xpDelta := 0
+		if *update.Completed {
+			params = append(params,
+				db.Subtask.Completed.Set(true),
+				db.Subtask.CompletedAt.Set(now),
+			)
+			if task.Status != StatusCompleted {
+				if xpDelta = subtaskXP(task); xpDelta > 0 {
+					xpTxs = xp.Adjust(client, userID, xpDelta, xp.ReasonSubtaskCompleted, subtask.ID)
+				}
+			}
+		} else {
+			params = append(params,
+				db.Subtask.Completed.Set(false),
+				db.Subtask.CompletedAt.SetOptional(nil),
+			)
+			if task.Status != StatusCompleted {
+				if xpTxs, xpDelta, err = reverseSubtaskXP(ctx, client, userID, subtask.ID); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		done := 1
+		if !*update.Completed {
+			done = -1
+		}
+		txs = append(txs,
+			client.Task.FindUnique(
+				db.Task.ID.Equals(task.ID),
+			).Update(
+				db.Task.SubtasksDone.Increment(done),
+				db.Task.UpdatedAt.Set(now),
+			).Tx(),
+			client.Project.FindUnique(
+				db.Project.ID.Equals(task.ProjectID),
+			).Update(
+				db.Project.XpEarned.Increment(xpDelta),
+			).Tx(),
+		)
+	}
+
+	updated := client.Subtask.FindUnique(
+		db.Subtask.ID.Equals(subtask.ID),
+	).Update(params...).Tx()
+	txs = append([]db.PrismaTransaction{updated}, txs...)
+
+	if err := commit(ctx, client, userID, txs, xpTxs); err != nil {
+		return nil, err
+	}
+	if err := autoComplete(ctx, client, userID, task.ID); err != nil {
+		return nil, err
+	}
+	return updated.Result(), nil
+}
+
+// ReorderSubtasks puts the task's subtasks in the order of ids, which must
+// list each of them once.
+func ReorderSubtasks(ctx context.Context, client *db.PrismaClient, userID, taskID string, ids []string) ([]db.SubtaskModel, error) {
+	if _, err := findTask(ctx, client, userID, taskID, authz.ActionUpdate); err != nil {
+		return nil, err
+	}
+	subtasks, err := Subtasks(ctx, client, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]bool, len(subtasks))
+	for _, subtask := range subtasks {
+		byID[subtask.ID] = true
+	}
+	if len(ids) != len(subtasks) {
+		return nil, ErrInvalidOrder
+	}
+	txs := make([]db.PrismaTransaction, 0, len(ids))
+	for position, id := range ids {
+		if !byID[id] {
+			return nil, ErrInvalidOrder
+		}
+		delete(byID, id)
+		txs = append(txs, client.Subtask.FindUnique(
+			db.Subtask.ID.Equals(id),
+		).Update(
+			db.Subtask.Position.Set(position),
+		).Tx())
+	}
+
+	if len(txs) > 0 {
+		if err := client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return Subtasks(ctx, client, userID, taskID)
+}
+
+// DeleteSubtask removes a subtask and takes back the XP it earned while the
+// task is open.
+func DeleteSubtask(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	subtask, task, err := findSubtask(ctx, client, userID, id, authz.ActionUpdate)
+	if err != nil {
+		return err
+	}
+
+	var xpTxs []db.PrismaTransaction
+	xpDelta := 0
+	if task.Status != StatusCompleted {
+		if xpTxs, xpDelta, err = reverseSubtaskXP(ctx, client, userID, subtask.ID); err != nil {
+			return err
+		}
+	}
+
+	taskParams := []db.TaskSetParam{
+		db.Task.SubtaskCount.Decrement(1),
+		db.Task.UpdatedAt.Set(time.Now()),
+	}
+	if subtask.Completed {
+		taskParams = append(taskParams, db.Task.SubtasksDone.Decrement(1))
+	}
+
+	txs := []db.PrismaTransaction{
+		client.Subtask.FindUnique(
+			db.Subtask.ID.Equals(subtask.ID),
+		).Delete().Tx(),
+		client.Task.FindUnique(
+			db.Task.ID.Equals(task.ID),
+		).Update(taskParams...).Tx(),
+		client.Project.FindUnique(
+			db.Project.ID.Equals(task.ProjectID),
+		).Update(
+			db.Project.XpEarned.Increment(xpDelta),
+		).Tx(),
+	}
+	if err := commit(ctx, client, userID, txs, xpTxs); err != nil {
+		return err
+	}
+	return autoComplete(ctx, client, userID, task.ID)
+}
+
+// SubtaskToTask turns a subtask into a task of its own in the same project.
+// A checked-off subtask becomes a completed task; the XP it earned stays
+// with the user.
+func SubtaskToTask(ctx context.Context, client *db.PrismaClient, userID, id string) (*db.TaskModel, error) {
+	subtask, parent, err := findSubtask(ctx, client, userID, id, authz.ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	params := []db.TaskSetParam{
+		db.Task.ID.Set(utils.GenerateUUID()),
+		db.Task.Priority.Set(parent.Priority),
+	}
+	projectParams := []db.ProjectSetParam{
+		db.Project.TaskCount.Increment(1),
+		db.Project.UpdatedAt.Set(now),
+	}
+	parentParams := []db.TaskSetParam{
+		db.Task.SubtaskCount.Decrement(1),
+		db.Task.UpdatedAt.Set(now),
+	}
+	if subtask.Completed {
+		completedAt, _ := subtask.CompletedAt()
+		params = append(params,
+			db.Task.Status.Set(StatusCompleted),
+			db.Task.CompletedAt.Set(completedAt),
+		)
+		projectParams = append(projectParams, db.Project.CompletedTaskCount.Increment(1))
+		parentParams = append(parentParams, db.Task.SubtasksDone.Decrement(1))
+	}
+
+	create := client.Task.CreateOne(
+		db.Task.Title.Set(subtask.Title),
+		db.Task.User.Link(db.User.ID.Equals(userID)),
+		db.Task.Project.Link(db.Project.ID.Equals(parent.ProjectID)),
+		params...,
+	).Tx()
+	err = client.Prisma.Transaction(
+		create,
+		client.Subtask.FindUnique(
+			db.Subtask.ID.Equals(subtask.ID),
+		).Delete().Tx(),
+		client.Task.FindUnique(
+			db.Task.ID.Equals(parent.ID),
+		).Update(parentParams...).Tx(),
+		client.Project.FindUnique(
+			db.Project.ID.Equals(parent.ProjectID),
+		).Update(projectParams...).Tx(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := autoComplete(ctx, client, userID, parent.ID); err != nil {
+		return nil, err
+	}
+	return create.Result(), nil
+}
+
+// TaskToSubtask turns task id into a subtask at the end of parentID's list
+// and removes the task, moving its attachments to parentID. Tasks with
+// subtasks of their own cannot be converted; a completed task becomes a
+// checked-off subtask.
+func TaskToSubtask(ctx context.Context, client *db.PrismaClient, userID, id, parentID string) (*db.SubtaskModel, error) {
+	if id == parentID {
+		return nil, ErrSameTask
+	}
+	task, err := findTask(ctx, client, userID, id, authz.ActionDelete)
+	if err != nil {
+		return nil, err
+	}
+	if task.SubtaskCount > 0 {
+		return nil, ErrHasSubtasks
+	}
+	parent, err := findTask(ctx, client, userID, parentID, authz.ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+	position, err := nextPosition(ctx, client, parent.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	completed := task.Status == StatusCompleted
+	params := []db.SubtaskSetParam{
+		db.Subtask.ID.Set(utils.GenerateUUID()),
+		db.Subtask.Position.Set(position),
+	}
+	parentParams := []db.TaskSetParam{
+		db.Task.SubtaskCount.Increment(1),
+		db.Task.UpdatedAt.Set(now),
+	}
+	projectParams := []db.ProjectSetParam{
+		db.Project.TaskCount.Decrement(1),
+		db.Project.UpdatedAt.Set(now),
+	}
+	if completed {
+		completedAt, ok := task.CompletedAt()
+		if !ok {
+			completedAt = now
+		}
+		params = append(params,
+			db.Subtask.Completed.Set(true),
+			db.Subtask.CompletedAt.Set(completedAt),
+		)
+		parentParams = append(parentParams, db.Task.SubtasksDone.Increment(1))
+		projectParams = append(projectParams, db.Project.CompletedTaskCount.Decrement(1))
+	}
+
+	create := client.Subtask.CreateOne(
+		db.Subtask.Title.Set(task.Title),
+		db.Subtask.Task.Link(db.Task.ID.Equals(parent.ID)),
+		params...,
+	).Tx()
+	err = client.Prisma.Transaction(
+		create,
+		client.Task.FindUnique(
+			db.Task.ID.Equals(parent.ID),
+		).Update(parentParams...).Tx(),
+		client.Project.FindUnique(
+			db.Project.ID.Equals(task.ProjectID),
+		).Update(projectParams...).Tx(),
+		client.TaskAttachment.FindMany(
+			db.TaskAttachment.TaskID.Equals(task.ID),
+		).Update(
+			db.TaskAttachment.TaskID.Set(parent.ID),
+		).Tx(),
+		client.Task.FindUnique(
+			db.Task.ID.Equals(task.ID),
+		).Delete().Tx(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := autoComplete(ctx, client, userID, parent.ID); err != nil {
+		return nil, err
+	}
+	return create.Result(), nil
+}
+
+// SetAutoComplete turns completing the task with its last subtask on or
+// off. Enabling it on a task whose subtasks are all done completes it.
+func SetAutoComplete(ctx context.Context, client *db.PrismaClient, userID, taskID string, enabled bool) (*db.TaskModel, error) {
+	task, err := findTask(ctx, client, userID, taskID, authz.ActionUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.Task.FindUnique(
+		db.Task.ID.Equals(task.ID),
+	).Update(
+		db.Task.AutoComplete.Set(enabled),
+		db.Task.UpdatedAt.Set(time.Now()),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := autoComplete(ctx, client, userID, task.ID); err != nil {
+		return nil, err
+	}
+	return findTask(ctx, client, userID, task.ID, authz.ActionRead)
+}
+
+// autoComplete completes the task if it has auto-complete enabled and all of
+// its subtasks are done. Tasks with unfinished dependencies stay open.
+func autoComplete(ctx context.Context, client *db.PrismaClient, userID, taskID string) error {
+	task, err := findTask(ctx, client, userID, taskID, authz.ActionUpdate)
+	if err != nil {
+		return err
+	}
+	if !task.AutoComplete || task.Status == StatusCompleted || task.SubtaskCount == 0 || task.SubtasksDone < task.SubtaskCount {
+		return nil
+	}
+	_, err = Complete(ctx, client, userID, task.ID, false)
+	if err != nil && !errors.Is(err, ErrAlreadyCompleted) && !errors.Is(err, ErrBlocked) {
+		return err
+	}
+	return nil
+}
+
+// reverseSubtaskXP returns the writes that take back what the subtask
+// earned, and the (negative) change in XP.
+func reverseSubtaskXP(ctx context.Context, client *db.PrismaClient, userID, subtaskID string) ([]db.PrismaTransaction, int, error) {
+	_, held, err := xp.Credits(ctx, client, userID, []string{subtaskID})
+	if err != nil {
+		return nil, 0, err
+	}
+	amount := held[subtaskID]
+	if amount <= 0 {
+		return nil, 0, nil
+	}
+	return xp.Adjust(client, userID, -amount, xp.ReasonSubtaskReopened, subtaskID), -amount, nil
+}
+
+// commit runs txs together with the XP writes and brings the user's level in
+// line with the new total.
+func commit(ctx context.Context, client *db.PrismaClient, userID string, txs, xpTxs []db.PrismaTransaction) error {
+	if err := client.Prisma.Transaction(append(txs, xpTxs...)...).Exec(ctx); err != nil {
+		return err
+	}
+	if len(xpTxs) == 0 {
+		return nil
+	}
+	_, err := xp.SyncLevel(ctx, client, userID)
+	return err
+}
+
+// findTask returns the live task when userID may take action on it.
+func findTask(ctx context.Context, client *db.PrismaClient, userID, id string, action authz.Action) (*db.TaskModel, error) {
+	task, err := authz.Task(ctx, client, id)
+	if errors.Is(err, authz.ErrNotFound) {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := authz.Authorize(ctx, client, userID, action, authz.TaskResource(task)); err != nil {
+		return nil, denied(err, ErrTaskNotFound)
+	}
+	return task, nil
+}
+
+// findSubtask returns the subtask and its live task when userID may take
+// action on the task.
+func findSubtask(ctx context.Context, client *db.PrismaClient, userID, id string, action authz.Action) (*db.SubtaskModel, *db.TaskModel, error) {
+	subtask, err := client.Subtask.FindFirst(
+		db.Subtask.ID.Equals(id),
+		db.Subtask.Task.Where(
+			db.Task.DeletedAt.IsNull(),
+		),
+	).With(
+		db.Subtask.Task.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return nil, nil, ErrSubtaskNotFound
+		}
+		return nil, nil, err
+	}
+	if err := authz.Authorize(ctx, client, userID, action, authz.TaskResource(subtask.Task())); err != nil {
+		return nil, nil, denied(err, ErrSubtaskNotFound)
+	}
+	return subtask, subtask.Task(), nil
+}
+
+// denied maps authorization failures to notFound or ErrForbidden.
+func denied(err, notFound error) error {
+	switch {
+	case errors.Is(err, authz.ErrNotFound):
+		return notFound
+	case errors.Is(err, authz.ErrForbidden):
+		return ErrForbidden
+	}
+	return err
+}
+
+// nextPosition is the position after the task's last subtask.
+func nextPosition(ctx context.Context, client *db.PrismaClient, taskID string) (int, error) {
+	last, err := client.Subtask.FindFirst(
+		db.Subtask.TaskID.Equals(taskID),
+	).OrderBy(
+		db.Subtask.Position.Order(db.SortOrderDesc),
+	).Exec(ctx)
+	if db.IsErrNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return last.Position + 1, nil
+}
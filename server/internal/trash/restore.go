@@ -0,0 +1,344 @@
+package trash
+
+import (
+	"context"
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+// Restore takes a trashed item, and everything trashed along with it, out of
+// the trash and credits back the XP it earned. targetID optionally names the
+// folder a project, or the project a task, is restored into; it is required
+// when the original container is still in the trash. A folder whose parent
+// is still in the trash is restored to the top level.
+func Restore(ctx context.Context, client *db.PrismaClient, userID, kind, id, targetID string) error {
+	switch kind {
+	case KindFolder:
+		return restoreFolder(ctx, client, userID, id)
+	case KindProject:
+		return restoreProject(ctx, client, userID, id, targetID)
+	case KindTask:
+		return restoreTask(ctx, client, userID, id, targetID)
+	case KindSprint:
+		return restoreSprint(ctx, client, userID, id)
+	case KindSession:
+		return restoreSession(ctx, client, userID, id)
+	}
+	return ErrUnknownKind
+}
+
+func restoreFolder(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	all, err := client.Folder.FindMany(
+		db.Folder.UserID.Equals(userID),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*db.FolderModel, len(all))
+	for i := range all {
+		byID[all[i].ID] = &all[i]
+	}
+	root, ok := byID[id]
+	if !ok {
+		return ErrNotFound
+	}
+	deletedAt, trashed := root.DeletedAt()
+	if !trashed {
+		return ErrNotFound
+	}
+	if expired(deletedAt, time.Now()) {
+		return ErrExpired
+	}
+
+	ids := subtree(all, id, func(folder *db.FolderModel) bool {
+		folderDeletedAt, _ := folder.DeletedAt()
+		return folderDeletedAt.Equal(deletedAt)
+	})
+
+	tasks, err := client.Task.FindMany(
+		db.Task.Project.Where(
+			db.Project.UserID.Equals(userID),
+			db.Project.FolderID.In(ids),
+		),
+		db.Task.DeletedAt.Equals(deletedAt),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	taskIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+
+	sources, err := withSubtasks(ctx, client, taskIDs)
+	if err != nil {
+		return err
+	}
+	xpTxs, _, err := restoreXP(ctx, client, sources)
+	if err != nil {
+		return err
+	}
+
+	txs := []db.PrismaTransaction{
+		client.Folder.FindMany(
+			db.Folder.ID.In(ids),
+			db.Folder.DeletedAt.Equals(deletedAt),
+		).Update(
+			db.Folder.DeletedAt.SetOptional(nil),
+		).Tx(),
+		client.Project.FindMany(
+			db.Project.UserID.Equals(userID),
+			db.Project.FolderID.In(ids),
+			db.Project.DeletedAt.Equals(deletedAt),
+		).Update(
+			db.Project.DeletedAt.SetOptional(nil),
+		).Tx(),
+		client.Task.FindMany(
+			db.Task.ID.In(taskIDs),
+		).Update(
+			db.Task.DeletedAt.SetOptional(nil),
+		).Tx(),
+	}
+	if parentID, ok := root.ParentID(); ok {
+		if _, parentTrashed := byID[parentID].DeletedAt(); parentTrashed {
+			txs = append(txs, client.Folder.FindUnique(
+				db.Folder.ID.Equals(root.ID),
+			).Update(
+				db.Folder.ParentID.SetOptional(nil),
+			).Tx())
+		}
+	}
+
+	return commit(ctx, client, txs, xpTxs)
+}
+
+func restoreProject(ctx context.Context, client *db.PrismaClient, userID, id, targetID string) error {
+	project, err := client.Project.FindFirst(
+		db.Project.ID.Equals(id),
+		db.Project.UserID.Equals(userID),
+		db.Project.Not(db.Project.DeletedAt.IsNull()),
+	).With(
+		db.Project.Folder.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	deletedAt, _ := project.DeletedAt()
+	if expired(deletedAt, time.Now()) {
+		return ErrExpired
+	}
+
+	folderID := project.FolderID
+	if targetID != "" {
+		_, err := client.Folder.FindFirst(
+			db.Folder.ID.Equals(targetID),
+			db.Folder.UserID.Equals(userID),
+			db.Folder.DeletedAt.IsNull(),
+		).Exec(ctx)
+		if err != nil {
+			if db.IsErrNotFound(err) {
+				return ErrTargetNotFound
+			}
+			return err
+		}
+		folderID = targetID
+	} else if _, folderTrashed := project.Folder().DeletedAt(); folderTrashed {
+		return ErrParentTrashed
+	}
+
+	tasks, err := client.Task.FindMany(
+		db.Task.ProjectID.Equals(project.ID),
+		db.Task.DeletedAt.Equals(deletedAt),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	taskIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+
+	sources, err := withSubtasks(ctx, client, taskIDs)
+	if err != nil {
+		return err
+	}
+	xpTxs, _, err := restoreXP(ctx, client, sources)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	txs := []db.PrismaTransaction{
+		client.Project.FindUnique(
+			db.Project.ID.Equals(project.ID),
+		).Update(
+			db.Project.DeletedAt.SetOptional(nil),
+			db.Project.Folder.Link(db.Folder.ID.Equals(folderID)),
+		).Tx(),
+		client.Task.FindMany(
+			db.Task.ID.In(taskIDs),
+		).Update(
+			db.Task.DeletedAt.SetOptional(nil),
+		).Tx(),
+		client.Folder.FindUnique(
+			db.Folder.ID.Equals(folderID),
+		).Update(
+			db.Folder.ProjectCount.Increment(1),
+			db.Folder.UpdatedAt.Set(now),
+		).Tx(),
+	}
+	// A project trashed along with its folder is still counted there.
+	if folderDeletedAt, _ := project.Folder().DeletedAt(); folderID != project.FolderID && folderDeletedAt.Equal(deletedAt) {
+		txs = append(txs, client.Folder.FindUnique(
+			db.Folder.ID.Equals(project.FolderID),
+		).Update(
+			db.Folder.ProjectCount.Decrement(1),
+			db.Folder.UpdatedAt.Set(now),
+		).Tx())
+	}
+	return commit(ctx, client, txs, xpTxs)
+}
+
+func restoreTask(ctx context.Context, client *db.PrismaClient, userID, id, targetID string) error {
+	task, err := client.Task.FindFirst(
+		db.Task.ID.Equals(id),
+		managedTasks(userID),
+		db.Task.Not(db.Task.DeletedAt.IsNull()),
+	).With(
+		db.Task.Project.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	deletedAt, _ := task.DeletedAt()
+	if expired(deletedAt, time.Now()) {
+		return ErrExpired
+	}
+
+	projectID := task.ProjectID
+	if targetID != "" {
+		_, err := client.Project.FindFirst(
+			db.Project.ID.Equals(targetID),
+			db.Project.UserID.Equals(userID),
+			db.Project.DeletedAt.IsNull(),
+		).Exec(ctx)
+		if err != nil {
+			if db.IsErrNotFound(err) {
+				return ErrTargetNotFound
+			}
+			return err
+		}
+		projectID = targetID
+	} else if _, projectTrashed := task.Project().DeletedAt(); projectTrashed {
+		return ErrParentTrashed
+	}
+
+	sources, err := withSubtasks(ctx, client, []string{task.ID})
+	if err != nil {
+		return err
+	}
+	xpTxs, credited, err := restoreXP(ctx, client, sources)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	txs := []db.PrismaTransaction{
+		client.Task.FindUnique(
+			db.Task.ID.Equals(task.ID),
+		).Update(
+			db.Task.DeletedAt.SetOptional(nil),
+			db.Task.Project.Link(db.Project.ID.Equals(projectID)),
+		).Tx(),
+		client.Project.FindUnique(
+			db.Project.ID.Equals(projectID),
+		).Update(
+			projectCounters(task, 1, sum(credited), now)...,
+		).Tx(),
+	}
+	// A task trashed along with its project is still counted there.
+	if projectDeletedAt, _ := task.Project().DeletedAt(); projectID != task.ProjectID && projectDeletedAt.Equal(deletedAt) {
+		txs = append(txs, client.Project.FindUnique(
+			db.Project.ID.Equals(task.ProjectID),
+		).Update(
+			projectCounters(task, -1, -sum(credited), now)...,
+		).Tx())
+	}
+	return commit(ctx, client, txs, xpTxs)
+}
+
+func restoreSprint(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	sprint, err := client.Sprint.FindFirst(
+		db.Sprint.ID.Equals(id),
+		db.Sprint.UserID.Equals(userID),
+		db.Sprint.Not(db.Sprint.DeletedAt.IsNull()),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	deletedAt, _ := sprint.DeletedAt()
+	if expired(deletedAt, time.Now()) {
+		return ErrExpired
+	}
+
+	xpTxs, _, err := restoreXP(ctx, client, []string{sprint.ID})
+	if err != nil {
+		return err
+	}
+
+	txs := []db.PrismaTransaction{
+		client.Sprint.FindUnique(
+			db.Sprint.ID.Equals(sprint.ID),
+		).Update(
+			db.Sprint.DeletedAt.SetOptional(nil),
+		).Tx(),
+	}
+	return commit(ctx, client, txs, xpTxs)
+}
+
+func restoreSession(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	session, err := client.PomodoroSession.FindFirst(
+		db.PomodoroSession.ID.Equals(id),
+		db.PomodoroSession.UserID.Equals(userID),
+		db.PomodoroSession.Not(db.PomodoroSession.DeletedAt.IsNull()),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	deletedAt, _ := session.DeletedAt()
+	if expired(deletedAt, time.Now()) {
+		return ErrExpired
+	}
+
+	xpTxs, _, err := restoreXP(ctx, client, []string{session.ID})
+	if err != nil {
+		return err
+	}
+
+	txs := []db.PrismaTransaction{
+		client.PomodoroSession.FindUnique(
+			db.PomodoroSession.ID.Equals(session.ID),
+		).Update(
+			db.PomodoroSession.DeletedAt.SetOptional(nil),
+		).Tx(),
+	}
+	return commit(ctx, client, txs, xpTxs)
+}
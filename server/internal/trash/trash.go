@@ -0,0 +1,485 @@
+// Package trash soft-deletes folders, projects, tasks, sprints and pomodoro
+// sessions and restores them within the retention period.
+//
+// Trashing stamps deletedAt on the item and everything inside it that is not
+// already in the trash, all with the same timestamp. Restoring clears the
+// stamp on exactly those rows, so items trashed separately before their
+// container stay in the trash. Folders and projects take every task in them
+// along, collaborators' included. XP earned by trashed items is taken back
+// through the XP ledger from whoever it was credited to, and credited to
+// them again on restore.
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"lifequest-server/internal/db"
+)
+
+// Kinds of trashed items.
+const (
+	KindFolder  = "folder"
+	KindProject = "project"
+	KindTask    = "task"
+	KindSprint  = "sprint"
+	KindSession = "session"
+)
+
+const (
+	defaultRetention = 30 * 24 * time.Hour
+
+	purgeInterval = time.Hour
+)
+
+var (
+	ErrNotFound       = errors.New("item not found in trash")
+	ErrExpired        = errors.New("item is past the trash retention period")
+	ErrParentTrashed  = errors.New("the containing folder or project is in the trash; restore it first or choose a target")
+	ErrTargetNotFound = errors.New("restore target not found")
+	ErrUnknownKind    = errors.New("unknown trash item kind")
+)
+
+// Entry is a trashed item that can be restored on its own.
+type Entry struct {
+	Kind      string    `json:"kind"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deletedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Retention returns how long trashed items can be restored before they are
+// purged: TRASH_RETENTION_DAYS days, or 30.
+func Retention() time.Duration {
+	if days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return defaultRetention
+}
+
+// Folder moves folder id, its subfolders and their projects and tasks to the
+// trash.
+func Folder(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	all, err := client.Folder.FindMany(
+		db.Folder.UserID.Equals(userID),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	var root *db.FolderModel
+	for i := range all {
+		if all[i].ID == id {
+			root = &all[i]
+		}
+	}
+	if root == nil {
+		return ErrNotFound
+	}
+	if _, trashed := root.DeletedAt(); trashed {
+		return ErrNotFound
+	}
+
+	ids := subtree(all, id, func(folder *db.FolderModel) bool {
+		_, trashed := folder.DeletedAt()
+		return !trashed
+	})
+
+	tasks, err := client.Task.FindMany(
+		db.Task.Project.Where(
+			db.Project.UserID.Equals(userID),
+			db.Project.FolderID.In(ids),
+		),
+		db.Task.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	taskIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+
+	sources, err := withSubtasks(ctx, client, taskIDs)
+	if err != nil {
+		return err
+	}
+	xpTxs, _, err := reverseXP(ctx, client, sources)
+	if err != nil {
+		return err
+	}
+	now := stamp()
+
+	txs := []db.PrismaTransaction{
+		client.Folder.FindMany(
+			db.Folder.ID.In(ids),
+			db.Folder.DeletedAt.IsNull(),
+		).Update(
+			db.Folder.DeletedAt.Set(now),
+		).Tx(),
+		client.Project.FindMany(
+			db.Project.UserID.Equals(userID),
+			db.Project.FolderID.In(ids),
+			db.Project.DeletedAt.IsNull(),
+		).Update(
+			db.Project.DeletedAt.Set(now),
+		).Tx(),
+		client.Task.FindMany(
+			db.Task.ID.In(taskIDs),
+		).Update(
+			db.Task.DeletedAt.Set(now),
+		).Tx(),
+	}
+	return commit(ctx, client, txs, xpTxs)
+}
+
+// Project moves project id and its tasks to the trash.
+func Project(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	project, err := client.Project.FindFirst(
+		db.Project.ID.Equals(id),
+		db.Project.UserID.Equals(userID),
+		db.Project.DeletedAt.IsNull(),
+	).With(
+		db.Project.Tasks.Fetch(
+			db.Task.DeletedAt.IsNull(),
+		),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	taskIDs := make([]string, len(project.Tasks()))
+	for i, task := range project.Tasks() {
+		taskIDs[i] = task.ID
+	}
+
+	sources, err := withSubtasks(ctx, client, taskIDs)
+	if err != nil {
+		return err
+	}
+	xpTxs, _, err := reverseXP(ctx, client, sources)
+	if err != nil {
+		return err
+	}
+	now := stamp()
+
+	txs := []db.PrismaTransaction{
+		client.Project.FindUnique(
+			db.Project.ID.Equals(project.ID),
+		).Update(
+			db.Project.DeletedAt.Set(now),
+		).Tx(),
+		client.Task.FindMany(
+			db.Task.ID.In(taskIDs),
+		).Update(
+			db.Task.DeletedAt.Set(now),
+		).Tx(),
+		client.Folder.FindUnique(
+			db.Folder.ID.Equals(project.FolderID),
+		).Update(
+			db.Folder.ProjectCount.Decrement(1),
+			db.Folder.UpdatedAt.Set(now),
+		).Tx(),
+	}
+	return commit(ctx, client, txs, xpTxs)
+}
+
+// Task moves task id to the trash.
+func Task(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	task, err := client.Task.FindFirst(
+		db.Task.ID.Equals(id),
+		managedTasks(userID),
+		db.Task.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	sources, err := withSubtasks(ctx, client, []string{task.ID})
+	if err != nil {
+		return err
+	}
+	xpTxs, held, err := reverseXP(ctx, client, sources)
+	if err != nil {
+		return err
+	}
+	now := stamp()
+
+	txs := []db.PrismaTransaction{
+		client.Task.FindUnique(
+			db.Task.ID.Equals(task.ID),
+		).Update(
+			db.Task.DeletedAt.Set(now),
+		).Tx(),
+		client.Project.FindUnique(
+			db.Project.ID.Equals(task.ProjectID),
+		).Update(
+			projectCounters(task, -1, -sum(held), now)...,
+		).Tx(),
+	}
+	return commit(ctx, client, txs, xpTxs)
+}
+
+// Sprint moves sprint id to the trash. Its tasks stay where they are and are
+// still linked to it when it is restored.
+func Sprint(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	sprint, err := client.Sprint.FindFirst(
+		db.Sprint.ID.Equals(id),
+		db.Sprint.UserID.Equals(userID),
+		db.Sprint.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	xpTxs, _, err := reverseXP(ctx, client, []string{sprint.ID})
+	if err != nil {
+		return err
+	}
+
+	txs := []db.PrismaTransaction{
+		client.Sprint.FindUnique(
+			db.Sprint.ID.Equals(sprint.ID),
+		).Update(
+			db.Sprint.DeletedAt.Set(stamp()),
+		).Tx(),
+	}
+	return commit(ctx, client, txs, xpTxs)
+}
+
+// Session moves pomodoro session id to the trash.
+func Session(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	session, err := client.PomodoroSession.FindFirst(
+		db.PomodoroSession.ID.Equals(id),
+		db.PomodoroSession.UserID.Equals(userID),
+		db.PomodoroSession.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		if db.IsErrNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	xpTxs, _, err := reverseXP(ctx, client, []string{session.ID})
+	if err != nil {
+		return err
+	}
+
+	txs := []db.PrismaTransaction{
+		client.PomodoroSession.FindUnique(
+			db.PomodoroSession.ID.Equals(session.ID),
+		).Update(
+			db.PomodoroSession.DeletedAt.Set(stamp()),
+		).Tx(),
+	}
+	return commit(ctx, client, txs, xpTxs)
+}
+
+// List returns the user's trashed items that can be restored on their own,
+// most recently trashed first. Items trashed along with their container are
+// restored with it and are not listed.
+func List(ctx context.Context, client *db.PrismaClient, userID string) ([]Entry, error) {
+	folders, err := client.Folder.FindMany(
+		db.Folder.UserID.Equals(userID),
+		db.Folder.Not(db.Folder.DeletedAt.IsNull()),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := client.Project.FindMany(
+		db.Project.UserID.Equals(userID),
+		db.Project.Not(db.Project.DeletedAt.IsNull()),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := client.Task.FindMany(
+		managedTasks(userID),
+		db.Task.Not(db.Task.DeletedAt.IsNull()),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sprints, err := client.Sprint.FindMany(
+		db.Sprint.UserID.Equals(userID),
+		db.Sprint.Not(db.Sprint.DeletedAt.IsNull()),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := client.PomodoroSession.FindMany(
+		db.PomodoroSession.UserID.Equals(userID),
+		db.PomodoroSession.Not(db.PomodoroSession.DeletedAt.IsNull()),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	folderStamps := make(map[string]time.Time, len(folders))
+	for _, folder := range folders {
+		folderStamps[folder.ID], _ = folder.DeletedAt()
+	}
+	projectStamps := make(map[string]time.Time, len(projects))
+	for _, project := range projects {
+		projectStamps[project.ID], _ = project.DeletedAt()
+	}
+
+	now := time.Now()
+	retention := Retention()
+	entries := []Entry{}
+	add := func(kind, id, name string, deletedAt, containerStamp time.Time) {
+		if deletedAt.Equal(containerStamp) || now.Sub(deletedAt) > retention {
+			return
+		}
+		entries = append(entries, Entry{
+			Kind:      kind,
+			ID:        id,
+			Name:      name,
+			DeletedAt: deletedAt,
+			ExpiresAt: deletedAt.Add(retention),
+		})
+	}
+
+	for _, folder := range folders {
+		parentID, _ := folder.ParentID()
+		add(KindFolder, folder.ID, folder.Name, folderStamps[folder.ID], folderStamps[parentID])
+	}
+	for _, project := range projects {
+		add(KindProject, project.ID, project.Name, projectStamps[project.ID], folderStamps[project.FolderID])
+	}
+	for _, task := range tasks {
+		deletedAt, _ := task.DeletedAt()
+		add(KindTask, task.ID, task.Title, deletedAt, projectStamps[task.ProjectID])
+	}
+	for _, sprint := range sprints {
+		deletedAt, _ := sprint.DeletedAt()
+		add(KindSprint, sprint.ID, sprint.Name, deletedAt, time.Time{})
+	}
+	for _, session := range sessions {
+		deletedAt, _ := session.DeletedAt()
+		name := fmt.Sprintf("%s session, %s", session.Type, session.StartTime.Format("2006-01-02 15:04"))
+		add(KindSession, session.ID, name, deletedAt, time.Time{})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// Start purges items past the retention period in the background until ctx
+// is cancelled.
+func Start(ctx context.Context, client *db.PrismaClient) {
+	go func() {
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := Purge(ctx, client, time.Now().Add(-Retention())); err != nil {
+					log.Printf("trash: purge failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Purge permanently deletes items trashed before cutoff. Deleting a folder or
+// project removes everything inside it.
+func Purge(ctx context.Context, client *db.PrismaClient, cutoff time.Time) error {
+	return client.Prisma.Transaction(
+		client.PomodoroSession.FindMany(
+			db.PomodoroSession.DeletedAt.Lt(cutoff),
+		).Delete().Tx(),
+		client.Sprint.FindMany(
+			db.Sprint.DeletedAt.Lt(cutoff),
+		).Delete().Tx(),
+		client.Task.FindMany(
+			db.Task.DeletedAt.Lt(cutoff),
+		).Delete().Tx(),
+		client.Project.FindMany(
+			db.Project.DeletedAt.Lt(cutoff),
+		).Delete().Tx(),
+		client.Folder.FindMany(
+			db.Folder.DeletedAt.Lt(cutoff),
+		).Delete().Tx(),
+	).Exec(ctx)
+}
+
+// managedTasks matches the tasks the user can trash and restore: their own
+// and every task in their projects, whoever created it.
+func managedTasks(userID string) db.TaskWhereParam {
+	return db.Task.Or(
+		db.Task.UserID.Equals(userID),
+		db.Task.Project.Where(db.Project.UserID.Equals(userID)),
+	)
+}
+
+// subtree returns id and the IDs of its descendants, descending only into
+// folders accepted by include.
+func subtree(all []db.FolderModel, id string, include func(*db.FolderModel) bool) []string {
+	children := map[string][]*db.FolderModel{}
+	for i := range all {
+		if parentID, ok := all[i].ParentID(); ok {
+			children[parentID] = append(children[parentID], &all[i])
+		}
+	}
+
+	ids := []string{id}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if include(child) {
+				ids = append(ids, child.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// projectCounters moves the project's task counters by delta for task and
+// its earned XP by xpDelta.
+func projectCounters(task *db.TaskModel, delta, xpDelta int, now time.Time) []db.ProjectSetParam {
+	params := []db.ProjectSetParam{
+		db.Project.TaskCount.Increment(delta),
+		db.Project.XpEarned.Increment(xpDelta),
+		db.Project.UpdatedAt.Set(now),
+	}
+	if task.Status == "completed" {
+		params = append(params, db.Project.CompletedTaskCount.Increment(delta))
+	}
+	return params
+}
+
+// stamp is the deletedAt for a trash operation, truncated to the stored
+// precision so restores can match it exactly.
+func stamp() time.Time {
+	return time.Now().Truncate(time.Millisecond)
+}
+
+func expired(deletedAt, now time.Time) bool {
+	return now.Sub(deletedAt) > Retention()
+}
@@ -0,0 +1,110 @@
+package trash
+
+import (
+	"context"
+	"slices"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/xp"
+)
+
+// xpWrites are the ledger writes of a trash operation and the users whose
+// XP they change.
+type xpWrites struct {
+	txs   []db.PrismaTransaction
+	users []string
+}
+
+func (w *xpWrites) adjust(client *db.PrismaClient, holding xp.Holding, amount int, reason string) {
+	w.txs = append(w.txs, xp.Adjust(client, holding.UserID, amount, reason, holding.SourceID)...)
+	if !slices.Contains(w.users, holding.UserID) {
+		w.users = append(w.users, holding.UserID)
+	}
+}
+
+// reverseXP returns the ledger writes that take back the XP sourceIDs
+// currently hold, from each user who holds it, and the amount held per
+// source.
+func reverseXP(ctx context.Context, client *db.PrismaClient, sourceIDs []string) (*xpWrites, map[string]int, error) {
+	writes := &xpWrites{}
+	if len(sourceIDs) == 0 {
+		return writes, map[string]int{}, nil
+	}
+	holdings, err := xp.Holdings(ctx, client, sourceIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	held := map[string]int{}
+	for _, holding := range holdings {
+		if holding.Held > 0 {
+			held[holding.SourceID] += holding.Held
+			writes.adjust(client, holding, -holding.Held, xp.ReasonTrashed)
+		}
+	}
+	return writes, held, nil
+}
+
+// restoreXP returns the ledger writes that credit each user again with what
+// they earned from sourceIDs, and the amount credited per source.
+func restoreXP(ctx context.Context, client *db.PrismaClient, sourceIDs []string) (*xpWrites, map[string]int, error) {
+	writes := &xpWrites{}
+	if len(sourceIDs) == 0 {
+		return writes, map[string]int{}, nil
+	}
+	holdings, err := xp.Holdings(ctx, client, sourceIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credited := map[string]int{}
+	for _, holding := range holdings {
+		if amount := holding.Earned - holding.Held; amount > 0 {
+			credited[holding.SourceID] += amount
+			writes.adjust(client, holding, amount, xp.ReasonRestored)
+		}
+	}
+	return writes, credited, nil
+}
+
+// commit runs txs together with the XP writes and brings the level of every
+// user whose XP changed in line with their new total.
+func commit(ctx context.Context, client *db.PrismaClient, txs []db.PrismaTransaction, writes *xpWrites) error {
+	if err := client.Prisma.Transaction(append(txs, writes.txs...)...).Exec(ctx); err != nil {
+		return err
+	}
+	for _, userID := range writes.users {
+		if _, err := xp.SyncLevel(ctx, client, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withSubtasks adds the subtasks of taskIDs to the XP sources, since
+// checking off a subtask earns part of its task's XP.
+func withSubtasks(ctx context.Context, client *db.PrismaClient, taskIDs []string) ([]string, error) {
+	if len(taskIDs) == 0 {
+		return taskIDs, nil
+	}
+	subtasks, err := client.Subtask.FindMany(
+		db.Subtask.TaskID.In(taskIDs),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := append([]string{}, taskIDs...)
+	for _, subtask := range subtasks {
+		ids = append(ids, subtask.ID)
+	}
+	return ids, nil
+}
+
+// sum adds up amounts over all sources.
+func sum(amounts map[string]int) int {
+	total := 0
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total
+}
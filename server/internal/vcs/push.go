@@ -0,0 +1,86 @@
+package vcs
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Supported providers.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Push is the provider-independent part of a push event.
+type Push struct {
+	Repository string
+	Branch     string
+	Commits    []Commit
+}
+
+// Commit is a single pushed commit.
+type Commit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+	Author  struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"author"`
+}
+
+var errMissingRepository = errors.New("push payload has no repository")
+
+// IsValidProvider reports whether provider is supported.
+func IsValidProvider(provider string) bool {
+	return provider == ProviderGitHub || provider == ProviderGitLab
+}
+
+// ParseGitHubPush decodes a GitHub push event payload.
+func ParseGitHubPush(body []byte) (*Push, error) {
+	var payload struct {
+		Ref        string `json:"ref"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		Commits []Commit `json:"commits"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Repository.FullName == "" {
+		return nil, errMissingRepository
+	}
+	return &Push{
+		Repository: payload.Repository.FullName,
+		Branch:     branchFromRef(payload.Ref),
+		Commits:    payload.Commits,
+	}, nil
+}
+
+// ParseGitLabPush decodes a GitLab "Push Hook" payload.
+func ParseGitLabPush(body []byte) (*Push, error) {
+	var payload struct {
+		Ref     string `json:"ref"`
+		Project struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+		} `json:"project"`
+		Commits []Commit `json:"commits"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Project.PathWithNamespace == "" {
+		return nil, errMissingRepository
+	}
+	return &Push{
+		Repository: payload.Project.PathWithNamespace,
+		Branch:     branchFromRef(payload.Ref),
+		Commits:    payload.Commits,
+	}, nil
+}
+
+func branchFromRef(ref string) string {
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
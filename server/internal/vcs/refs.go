@@ -0,0 +1,41 @@
+package vcs
+
+import "regexp"
+
+// Reference is a task mentioned in a commit message.
+type Reference struct {
+	TaskID string
+	// Closing is true when the reference follows a keyword such as
+	// "closes", "fixes" or "resolves".
+	Closing bool
+}
+
+var (
+	closingRef = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+(?:LQ-|#)([A-Za-z0-9]+)\b`)
+	mentionRef = regexp.MustCompile(`\bLQ-([A-Za-z0-9]+)\b`)
+)
+
+// ParseReferences extracts task references from a commit message. A task
+// referenced several times appears once, marked closing if any of its
+// references was.
+func ParseReferences(message string) []Reference {
+	var refs []Reference
+	index := map[string]int{}
+
+	add := func(taskID string, closing bool) {
+		if i, ok := index[taskID]; ok {
+			refs[i].Closing = refs[i].Closing || closing
+			return
+		}
+		index[taskID] = len(refs)
+		refs = append(refs, Reference{TaskID: taskID, Closing: closing})
+	}
+
+	for _, match := range closingRef.FindAllStringSubmatch(message, -1) {
+		add(match[1], true)
+	}
+	for _, match := range mentionRef.FindAllStringSubmatch(message, -1) {
+		add(match[1], false)
+	}
+	return refs
+}
@@ -0,0 +1,25 @@
+package vcs
+
+import (
+	"crypto/subtle"
+
+	"lifequest-server/internal/webhooks"
+)
+
+// Request headers used to authenticate and classify provider deliveries.
+const (
+	HeaderGitHubEvent     = "X-GitHub-Event"
+	HeaderGitHubSignature = "X-Hub-Signature-256"
+	HeaderGitLabEvent     = "X-Gitlab-Event"
+	HeaderGitLabToken     = "X-Gitlab-Token"
+)
+
+// VerifyGitHub checks the X-Hub-Signature-256 HMAC of body.
+func VerifyGitHub(secret string, body []byte, signature string) bool {
+	return webhooks.VerifyBody(secret, body, signature)
+}
+
+// VerifyGitLab compares the X-Gitlab-Token header with the shared secret.
+func VerifyGitLab(secret, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
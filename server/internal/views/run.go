@@ -0,0 +1,228 @@
+package views
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/search"
+	"lifequest-server/internal/tasks"
+)
+
+// Group is a run of a view's hits sharing a value of the view's grouping,
+// in the view's sort order.
+type Group struct {
+	Key   string       `json:"key"`
+	Label string       `json:"label"`
+	Hits  []search.Hit `json:"tasks"`
+}
+
+// Result is a view run: the matching tasks, grouped. Total counts the hits
+// before grouping; grouped by tag, a task appears under each of its tags.
+type Result struct {
+	View   db.SavedViewModel `json:"view"`
+	Total  int               `json:"total"`
+	Groups []Group           `json:"groups"`
+}
+
+var statusGroups = []Group{
+	{Key: "todo", Label: "To do"},
+	{Key: "in-progress", Label: "In progress"},
+	{Key: "in-review", Label: "In review"},
+	{Key: tasks.StatusCompleted, Label: "Completed"},
+}
+
+var priorityGroups = []Group{
+	{Key: "high", Label: "High"},
+	{Key: "medium", Label: "Medium"},
+	{Key: "low", Label: "Low"},
+}
+
+// dueGroups are in date order; earlier holds completed tasks due before
+// today.
+var dueGroups = []Group{
+	{Key: "overdue", Label: "Overdue"},
+	{Key: "earlier", Label: "Earlier"},
+	{Key: "today", Label: "Today"},
+	{Key: "tomorrow", Label: "Tomorrow"},
+	{Key: "week", Label: "Next 7 days"},
+	{Key: "later", Label: "Later"},
+	{Key: "none", Label: "No due date"},
+}
+
+// Run returns the tasks matching a view the user can see, loaded with the
+// given relations. A view limited to a project matches the project's tasks
+// whoever created them; any other view matches its owner's tasks.
+func Run(ctx context.Context, client *db.PrismaClient, userID, id string, limit int, with ...db.TaskRelationWith) (*Result, error) {
+	view, err := Get(ctx, client, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	q, err := search.Parse(view.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := search.Scope{UserID: view.UserID}
+	if projectID, ok := view.ProjectID(); ok {
+		scope = search.Scope{ProjectID: projectID}
+	}
+	opts := search.Options{Reverse: view.Reverse, Limit: limit}
+	opts.Sort, _ = view.Sort()
+
+	hits, err := search.Search(ctx, client, scope, q, opts, with...)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := group(ctx, client, view.GroupBy, hits, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &Result{View: *view, Total: len(hits), Groups: groups}, nil
+}
+
+// group splits hits by the grouping, leaving out empty groups.
+func group(ctx context.Context, client *db.PrismaClient, groupBy string, hits []search.Hit, now time.Time) ([]Group, error) {
+	switch groupBy {
+	case GroupStatus:
+		return fixed(statusGroups, hits, func(task *db.TaskModel) string {
+			return task.Status
+		}), nil
+	case GroupPriority:
+		return fixed(priorityGroups, hits, func(task *db.TaskModel) string {
+			return task.Priority
+		}), nil
+	case GroupDue:
+		return fixed(dueGroups, hits, func(task *db.TaskModel) string {
+			return dueGroup(task, now)
+		}), nil
+	case GroupProject:
+		return byProject(ctx, client, hits)
+	case GroupTag:
+		return byTag(ctx, client, hits)
+	}
+	return []Group{{Key: "all", Label: "All tasks", Hits: hits}}, nil
+}
+
+// fixed splits hits into the given groups by key.
+func fixed(groups []Group, hits []search.Hit, key func(*db.TaskModel) string) []Group {
+	index := map[string]int{}
+	result := make([]Group, len(groups))
+	for i, g := range groups {
+		index[g.Key] = i
+		result[i] = Group{Key: g.Key, Label: g.Label}
+	}
+	for _, hit := range hits {
+		if i, ok := index[key(&hit.Task)]; ok {
+			result[i].Hits = append(result[i].Hits, hit)
+		}
+	}
+	return nonEmpty(result)
+}
+
+func dueGroup(task *db.TaskModel, now time.Time) string {
+	due, ok := task.DueDate()
+	if !ok {
+		return "none"
+	}
+	y, m, d := now.UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	switch {
+	case due.Before(today) && task.Status == tasks.StatusCompleted:
+		return "earlier"
+	case due.Before(today):
+		return "overdue"
+	case due.Before(today.AddDate(0, 0, 1)):
+		return "today"
+	case due.Before(today.AddDate(0, 0, 2)):
+		return "tomorrow"
+	case due.Before(today.AddDate(0, 0, 8)):
+		return "week"
+	}
+	return "later"
+}
+
+// byProject groups hits by project, ordered by project name.
+func byProject(ctx context.Context, client *db.PrismaClient, hits []search.Hit) ([]Group, error) {
+	var ids []string
+	for _, hit := range hits {
+		ids = append(ids, hit.Task.ProjectID)
+	}
+	projects, err := client.Project.FindMany(
+		db.Project.ID.In(ids),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return strings.ToLower(projects[i].Name) < strings.ToLower(projects[j].Name)
+	})
+
+	groups := make([]Group, len(projects))
+	for i, project := range projects {
+		groups[i] = Group{Key: project.ID, Label: project.Name}
+	}
+	return fixed(groups, hits, func(task *db.TaskModel) string {
+		return task.ProjectID
+	}), nil
+}
+
+// byTag groups hits under each of their tags, ordered by tag name, with
+// untagged tasks last.
+func byTag(ctx context.Context, client *db.PrismaClient, hits []search.Hit) ([]Group, error) {
+	var ids []string
+	for _, hit := range hits {
+		ids = append(ids, hit.Task.ID)
+	}
+	links, err := client.TaskTag.FindMany(
+		db.TaskTag.TaskID.In(ids),
+	).With(
+		db.TaskTag.Tag.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tagged := map[string][]string{}
+	tagsByID := map[string]db.TagModel{}
+	for _, link := range links {
+		tagged[link.TaskID] = append(tagged[link.TaskID], link.TagID)
+		tagsByID[link.TagID] = *link.Tag()
+	}
+	var groups []Group
+	for _, tag := range tagsByID {
+		groups = append(groups, Group{Key: tag.ID, Label: tag.Name})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Label) < strings.ToLower(groups[j].Label)
+	})
+	groups = append(groups, Group{Key: "", Label: "No tag"})
+
+	index := map[string]int{}
+	for i, g := range groups {
+		index[g.Key] = i
+	}
+	untagged := len(groups) - 1
+	for _, hit := range hits {
+		tagIDs := tagged[hit.Task.ID]
+		if len(tagIDs) == 0 {
+			groups[untagged].Hits = append(groups[untagged].Hits, hit)
+		}
+		for _, tagID := range tagIDs {
+			groups[index[tagID]].Hits = append(groups[index[tagID]].Hits, hit)
+		}
+	}
+	return nonEmpty(groups), nil
+}
+
+func nonEmpty(groups []Group) []Group {
+	result := []Group{}
+	for _, g := range groups {
+		if len(g.Hits) > 0 {
+			result = append(result, g)
+		}
+	}
+	return result
+}
@@ -0,0 +1,281 @@
+// Package views implements saved views: named task searches a user keeps
+// with their sort and grouping, optionally limited to a project. A view
+// limited to a project can be shared with the project's members, who can
+// list and run it but not change it.
+package views
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"lifequest-server/internal/authz"
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/search"
+	"lifequest-server/internal/utils"
+)
+
+// Groupings stored on SavedView.groupBy.
+const (
+	GroupNone     = "none"
+	GroupStatus   = "status"
+	GroupPriority = "priority"
+	GroupProject  = "project"
+	GroupDue      = "due"
+	GroupTag      = "tag"
+)
+
+// MaxNameLength is the longest view name, in characters.
+const MaxNameLength = 100
+
+var (
+	ErrNotFound           = errors.New("saved view not found")
+	ErrNotOwner           = errors.New("only the owner can change a saved view")
+	ErrNameRequired       = errors.New("view name is required")
+	ErrNameTooLong        = errors.New("view name must be at most 100 characters")
+	ErrNameTaken          = errors.New("a saved view with this name already exists")
+	ErrInvalidGrouping    = errors.New("group by must be none, status, priority, project, due or tag")
+	ErrProjectNotFound    = errors.New("project not found")
+	ErrSharedNeedsProject = errors.New("only views limited to a project can be shared")
+)
+
+// Fields are the editable fields of a view; nil fields are left unchanged
+// on update and defaulted on create. An empty Sort restores the default
+// order and an empty ProjectID removes the project limit.
+type Fields struct {
+	Name      *string
+	Query     *string
+	Sort      *string
+	Reverse   *bool
+	GroupBy   *string
+	Pinned    *bool
+	ProjectID *string
+	Shared    *bool
+}
+
+// List returns the user's views and the views shared with them, the user's
+// pinned views first and then by name.
+func List(ctx context.Context, client *db.PrismaClient, userID string) ([]db.SavedViewModel, error) {
+	list, err := client.SavedView.FindMany(
+		visibleTo(userID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := func(view db.SavedViewModel) bool {
+		return view.Pinned && view.UserID == userID
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if pinned(list[i]) != pinned(list[j]) {
+			return pinned(list[i])
+		}
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+	return list, nil
+}
+
+// Get returns a view the user owns or that is shared with them.
+func Get(ctx context.Context, client *db.PrismaClient, userID, id string) (*db.SavedViewModel, error) {
+	view, err := client.SavedView.FindFirst(
+		db.SavedView.ID.Equals(id),
+		visibleTo(userID),
+	).Exec(ctx)
+	if db.IsErrNotFound(err) {
+		return nil, ErrNotFound
+	}
+	return view, err
+}
+
+// Create adds a view. Name is required; by default the view matches every
+// task of the user, ungrouped.
+func Create(ctx context.Context, client *db.PrismaClient, userID string, fields Fields) (*db.SavedViewModel, error) {
+	if fields.Name == nil {
+		return nil, ErrNameRequired
+	}
+	if err := validate(ctx, client, userID, fields, nil); err != nil {
+		return nil, err
+	}
+	if fields.ProjectID != nil && *fields.ProjectID == "" {
+		fields.ProjectID = nil
+	}
+	params := setParams(fields)
+
+	view, err := client.SavedView.CreateOne(
+		db.SavedView.Name.Set(normalize(*fields.Name)),
+		db.SavedView.User.Link(db.User.ID.Equals(userID)),
+		append([]db.SavedViewSetParam{db.SavedView.ID.Set(utils.GenerateUUID())}, params...)...,
+	).Exec(ctx)
+	if _, ok := db.IsErrUniqueConstraint(err); ok {
+		return nil, ErrNameTaken
+	}
+	return view, err
+}
+
+// Update changes a view the user owns.
+func Update(ctx context.Context, client *db.PrismaClient, userID, id string, fields Fields) (*db.SavedViewModel, error) {
+	view, err := owned(ctx, client, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(ctx, client, userID, fields, view); err != nil {
+		return nil, err
+	}
+	params := setParams(fields)
+	if fields.Name != nil {
+		params = append(params, db.SavedView.Name.Set(normalize(*fields.Name)))
+	}
+
+	updated, err := client.SavedView.FindUnique(
+		db.SavedView.ID.Equals(id),
+	).Update(params...).Exec(ctx)
+	if _, ok := db.IsErrUniqueConstraint(err); ok {
+		return nil, ErrNameTaken
+	}
+	return updated, err
+}
+
+// Delete removes a view the user owns.
+func Delete(ctx context.Context, client *db.PrismaClient, userID, id string) error {
+	if _, err := owned(ctx, client, userID, id); err != nil {
+		return err
+	}
+	_, err := client.SavedView.FindUnique(
+		db.SavedView.ID.Equals(id),
+	).Delete().Exec(ctx)
+	return err
+}
+
+// validate checks fields on their own and, on update, against the view's
+// current project and sharing.
+func validate(ctx context.Context, client *db.PrismaClient, userID string, fields Fields, view *db.SavedViewModel) error {
+	if fields.Name != nil {
+		name := normalize(*fields.Name)
+		switch {
+		case name == "":
+			return ErrNameRequired
+		case utf8.RuneCountInString(name) > MaxNameLength:
+			return ErrNameTooLong
+		}
+	}
+	if fields.Query != nil {
+		if _, err := search.Parse(*fields.Query); err != nil {
+			return err
+		}
+	}
+	if fields.Sort != nil && *fields.Sort != "" && !search.ValidSort(*fields.Sort) {
+		return search.ErrInvalidSort
+	}
+	if fields.GroupBy != nil && !validGrouping(*fields.GroupBy) {
+		return ErrInvalidGrouping
+	}
+
+	projectID, shared := "", false
+	if view != nil {
+		projectID, _ = view.ProjectID()
+		shared = view.Shared
+	}
+	if fields.ProjectID != nil {
+		projectID = *fields.ProjectID
+		if projectID != "" {
+			if err := member(ctx, client, userID, projectID); err != nil {
+				return err
+			}
+		}
+	}
+	if fields.Shared != nil {
+		shared = *fields.Shared
+	}
+	if shared && projectID == "" {
+		return ErrSharedNeedsProject
+	}
+	return nil
+}
+
+func setParams(fields Fields) []db.SavedViewSetParam {
+	var params []db.SavedViewSetParam
+	if fields.Query != nil {
+		params = append(params, db.SavedView.Query.Set(strings.TrimSpace(*fields.Query)))
+	}
+	if fields.Sort != nil {
+		if *fields.Sort == "" {
+			params = append(params, db.SavedView.Sort.SetOptional(nil))
+		} else {
+			params = append(params, db.SavedView.Sort.Set(*fields.Sort))
+		}
+	}
+	if fields.Reverse != nil {
+		params = append(params, db.SavedView.Reverse.Set(*fields.Reverse))
+	}
+	if fields.GroupBy != nil {
+		params = append(params, db.SavedView.GroupBy.Set(*fields.GroupBy))
+	}
+	if fields.Pinned != nil {
+		params = append(params, db.SavedView.Pinned.Set(*fields.Pinned))
+	}
+	if fields.ProjectID != nil {
+		if *fields.ProjectID == "" {
+			params = append(params, db.SavedView.Project.Unlink())
+		} else {
+			params = append(params, db.SavedView.Project.Link(db.Project.ID.Equals(*fields.ProjectID)))
+		}
+	}
+	if fields.Shared != nil {
+		params = append(params, db.SavedView.Shared.Set(*fields.Shared))
+	}
+	return params
+}
+
+func normalize(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func validGrouping(groupBy string) bool {
+	switch groupBy {
+	case GroupNone, GroupStatus, GroupPriority, GroupProject, GroupDue, GroupTag:
+		return true
+	}
+	return false
+}
+
+// visibleTo matches the user's views and the views shared with the members
+// of projects the user belongs to.
+func visibleTo(userID string) db.SavedViewWhereParam {
+	return db.SavedView.Or(
+		db.SavedView.UserID.Equals(userID),
+		db.SavedView.And(
+			db.SavedView.Shared.Equals(true),
+			db.SavedView.Project.Where(
+				db.Project.DeletedAt.IsNull(),
+				authz.MemberOf(userID),
+			),
+		),
+	)
+}
+
+func member(ctx context.Context, client *db.PrismaClient, userID, projectID string) error {
+	_, err := client.Project.FindFirst(
+		db.Project.ID.Equals(projectID),
+		db.Project.DeletedAt.IsNull(),
+		authz.MemberOf(userID),
+	).Exec(ctx)
+	if db.IsErrNotFound(err) {
+		return ErrProjectNotFound
+	}
+	return err
+}
+
+// owned returns the view if the user owns it; views shared with the user
+// fail with ErrNotOwner.
+func owned(ctx context.Context, client *db.PrismaClient, userID, id string) (*db.SavedViewModel, error) {
+	view, err := Get(ctx, client, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if view.UserID != userID {
+		return nil, ErrNotOwner
+	}
+	return view, nil
+}
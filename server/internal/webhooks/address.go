@@ -0,0 +1,66 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress rejects webhook URLs that reach the server's own network.
+var ErrBlockedAddress = errors.New("url must not point at a loopback, private or link-local address")
+
+// sharedAddressSpace is carrier-grade NAT space (RFC 6598), private in all
+// but name.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// blocked reports whether webhooks must not be sent to addr.
+func blocked(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return !addr.IsValid() ||
+		addr.IsUnspecified() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsMulticast() ||
+		sharedAddressSpace.Contains(addr)
+}
+
+// checkHost rejects a URL host that is, or resolves to, a blocked address.
+func checkHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if blocked(addr) {
+			return ErrBlockedAddress
+		}
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("url host %q could not be resolved", host)
+	}
+	for _, addr := range addrs {
+		if blocked(addr) {
+			return ErrBlockedAddress
+		}
+	}
+	return nil
+}
+
+// dialControl refuses connections to blocked addresses. It runs on the
+// address a name resolved to when the delivery is sent, as DNS may have
+// changed since the webhook was validated.
+func dialControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if blocked(addrPort.Addr()) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestBlocked(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"127.10.0.1", true},
+		{"::1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"fd00::1", true},
+		{"169.254.169.254", true}, // cloud metadata
+		{"fe80::1", true},
+		{"100.64.0.1", true},
+		{"224.0.0.1", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"93.184.215.14", false},
+		{"2606:2800:21f:cb07:6820:80da:af6b:8b2c", false},
+		{"172.32.0.1", false},
+		{"100.128.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := blocked(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("blocked(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	events := []string{EventTaskCompleted}
+	tests := []struct {
+		url     string
+		blocked bool
+	}{
+		{"https://93.184.215.14/hook", false},
+		{"https://[2606:2800:21f:cb07:6820:80da:af6b:8b2c]:8443/hook", false},
+		{"http://127.0.0.1:8080/hook", true},
+		{"http://[::1]/hook", true},
+		{"http://169.254.169.254/latest/meta-data/", true},
+		{"http://10.0.0.5/hook", true},
+		{"http://localhost/hook", true},
+	}
+	for _, tt := range tests {
+		err := Validate(context.Background(), tt.url, events)
+		if blocked := errors.Is(err, ErrBlockedAddress); blocked != tt.blocked {
+			t.Errorf("Validate(%s) = %v, want blocked %v", tt.url, err, tt.blocked)
+		}
+	}
+	for _, bad := range []string{"ftp://93.184.215.14/", "/relative", "https://"} {
+		if err := Validate(context.Background(), bad, events); err == nil {
+			t.Errorf("Validate(%s) = nil, want an error", bad)
+		}
+	}
+}
+
+// A host that resolved to a public address when the webhook was saved may
+// not resolve to one when it is sent to.
+func TestSendRefusesBlockedAddress(t *testing.T) {
+	reached := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer server.Close()
+
+	result := NewSender().Send(context.Background(), server.URL, "secret", EventTest, "delivery", []byte("{}"))
+	if !errors.Is(result.Err, ErrBlockedAddress) {
+		t.Errorf("Send to %s = %v, want ErrBlockedAddress", server.URL, result.Err)
+	}
+	if reached {
+		t.Error("Send reached the loopback server")
+	}
+}
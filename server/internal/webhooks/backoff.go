@@ -0,0 +1,27 @@
+package webhooks
+
+import "time"
+
+const (
+	// MaxAttempts is how many times a delivery is tried before it is marked failed.
+	MaxAttempts = 6
+
+	backoffBase = 30 * time.Second
+	backoffMax  = 2 * time.Hour
+)
+
+// Backoff returns how long to wait before retrying after the given number of
+// failed attempts: 30s, 1m, 2m, 4m, ... capped at two hours.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	delay := backoffBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= backoffMax {
+			return backoffMax
+		}
+	}
+	return delay
+}
@@ -0,0 +1,257 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"lifequest-server/internal/db"
+	"lifequest-server/internal/utils"
+)
+
+// Delivery statuses stored on WebhookDelivery.status.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+const retryInterval = 30 * time.Second
+
+// claimLease is how long a claimed retry is hidden from other retry loops.
+// An attempt records its outcome well within it; if the process dies first,
+// the delivery becomes due again once the lease runs out.
+const claimLease = 5 * time.Minute
+
+// Dispatcher records webhook deliveries and sends them, retrying failures
+// with exponential backoff until MaxAttempts is reached.
+type Dispatcher struct {
+	client *db.PrismaClient
+	sender *Sender
+}
+
+var dispatcher *Dispatcher
+
+// NewDispatcher returns a Dispatcher backed by client.
+func NewDispatcher(client *db.PrismaClient) *Dispatcher {
+	return &Dispatcher{client: client, sender: NewSender()}
+}
+
+// Start installs the package-level dispatcher and runs its retry loop until
+// ctx is cancelled.
+func Start(ctx context.Context, client *db.PrismaClient) {
+	dispatcher = NewDispatcher(client)
+	go dispatcher.Run(ctx)
+}
+
+// Default returns the dispatcher installed by Start, or nil.
+func Default() *Dispatcher {
+	return dispatcher
+}
+
+// Publish fans event out to the user's subscribed webhooks in the background.
+// It is a no-op when the dispatcher has not been started.
+func Publish(userID, event string, data interface{}) {
+	if dispatcher == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := dispatcher.Publish(ctx, userID, event, data); err != nil {
+			log.Printf("webhooks: failed to publish %s for user %s: %v", event, userID, err)
+		}
+	}()
+}
+
+// Publish creates a delivery for every active webhook of userID subscribed to
+// event and attempts each one immediately.
+func (d *Dispatcher) Publish(ctx context.Context, userID, event string, data interface{}) error {
+	hooks, err := d.client.Webhook.FindMany(
+		db.Webhook.UserID.Equals(userID),
+		db.Webhook.Active.Equals(true),
+		db.Webhook.Events.HasSome([]string{event, EventAll}),
+	).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range hooks {
+		delivery, err := d.enqueue(ctx, &hooks[i], event, data)
+		if err != nil {
+			log.Printf("webhooks: failed to enqueue %s for webhook %s: %v", event, hooks[i].ID, err)
+			continue
+		}
+		d.attempt(ctx, &hooks[i], delivery)
+	}
+	return nil
+}
+
+// SendTest delivers a webhook.test event synchronously and returns the logged
+// delivery, regardless of the webhook's event filters or active flag.
+func (d *Dispatcher) SendTest(ctx context.Context, hook *db.WebhookModel) (*db.WebhookDeliveryModel, error) {
+	delivery, err := d.enqueue(ctx, hook, EventTest, map[string]interface{}{
+		"webhookId": hook.ID,
+		"message":   "This is a test event from LifeQuest.",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d.attempt(ctx, hook, delivery), nil
+}
+
+// Redeliver resets a delivery and sends its original payload again.
+func (d *Dispatcher) Redeliver(ctx context.Context, hook *db.WebhookModel, delivery *db.WebhookDeliveryModel) (*db.WebhookDeliveryModel, error) {
+	reset, err := d.client.WebhookDelivery.FindUnique(
+		db.WebhookDelivery.ID.Equals(delivery.ID),
+	).Update(
+		db.WebhookDelivery.Status.Set(StatusPending),
+		db.WebhookDelivery.Attempts.Set(0),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return d.attempt(ctx, hook, reset), nil
+}
+
+// Run retries due pending deliveries every retryInterval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.retryDue(ctx)
+		}
+	}
+}
+
+// retryDue sends the oldest due deliveries of active webhooks. Deliveries of
+// paused webhooks wait, out of the batch, until the webhook is resumed.
+func (d *Dispatcher) retryDue(ctx context.Context) {
+	due, err := d.client.WebhookDelivery.FindMany(
+		db.WebhookDelivery.Status.Equals(StatusPending),
+		db.WebhookDelivery.NextAttemptAt.Lte(time.Now()),
+		db.WebhookDelivery.Webhook.Where(db.Webhook.Active.Equals(true)),
+	).With(
+		db.WebhookDelivery.Webhook.Fetch(),
+	).OrderBy(
+		db.WebhookDelivery.NextAttemptAt.Order(db.SortOrderAsc),
+	).Take(100).Exec(ctx)
+	if err != nil {
+		log.Printf("webhooks: failed to load due deliveries: %v", err)
+		return
+	}
+
+	for i := range due {
+		claimed, err := d.claim(ctx, &due[i])
+		if err != nil {
+			log.Printf("webhooks: failed to claim delivery %s: %v", due[i].ID, err)
+			continue
+		}
+		if claimed {
+			d.attempt(ctx, due[i].Webhook(), &due[i])
+		}
+	}
+}
+
+// claim pushes a due delivery's next attempt out by claimLease, unless
+// another retry loop got to it first. The REST and GraphQL servers both run
+// one, so only the loop whose update matched the attempt time it loaded
+// sends the delivery.
+func (d *Dispatcher) claim(ctx context.Context, delivery *db.WebhookDeliveryModel) (bool, error) {
+	due, ok := delivery.NextAttemptAt()
+	if !ok {
+		return false, nil
+	}
+	result, err := d.client.WebhookDelivery.FindMany(
+		db.WebhookDelivery.ID.Equals(delivery.ID),
+		db.WebhookDelivery.Status.Equals(StatusPending),
+		db.WebhookDelivery.NextAttemptAt.Equals(due),
+	).Update(
+		db.WebhookDelivery.NextAttemptAt.Set(time.Now().Add(claimLease)),
+	).Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	return result.Count == 1, nil
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, hook *db.WebhookModel, event string, data interface{}) (*db.WebhookDeliveryModel, error) {
+	id := utils.GenerateUUID()
+	payload, err := json.Marshal(Envelope{
+		ID:        id,
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return d.client.WebhookDelivery.CreateOne(
+		db.WebhookDelivery.Event.Set(event),
+		db.WebhookDelivery.Payload.Set(string(payload)),
+		db.WebhookDelivery.Webhook.Link(
+			db.Webhook.ID.Equals(hook.ID),
+		),
+		db.WebhookDelivery.ID.Set(id),
+		// The caller attempts immediately; this only lets the retry loop pick
+		// the delivery up if that attempt never gets recorded.
+		db.WebhookDelivery.NextAttemptAt.Set(time.Now().Add(Backoff(1))),
+	).Exec(ctx)
+}
+
+// attempt sends delivery once and records the outcome. Failures are scheduled
+// for another try until MaxAttempts is reached. The returned delivery reflects
+// the stored state; on a storage error the original delivery is returned.
+func (d *Dispatcher) attempt(ctx context.Context, hook *db.WebhookModel, delivery *db.WebhookDeliveryModel) *db.WebhookDeliveryModel {
+	result := d.sender.Send(ctx, hook.URL, hook.Secret, delivery.Event, delivery.ID, []byte(delivery.Payload))
+	attempts := delivery.Attempts + 1
+	now := time.Now()
+
+	params := []db.WebhookDeliverySetParam{
+		db.WebhookDelivery.Attempts.Set(attempts),
+		db.WebhookDelivery.UpdatedAt.Set(now),
+	}
+	if result.StatusCode != 0 {
+		params = append(params,
+			db.WebhookDelivery.ResponseCode.Set(result.StatusCode),
+			db.WebhookDelivery.ResponseBody.Set(result.Body),
+		)
+	}
+
+	switch {
+	case result.OK():
+		params = append(params,
+			db.WebhookDelivery.Status.Set(StatusSucceeded),
+			db.WebhookDelivery.DeliveredAt.Set(now),
+			db.WebhookDelivery.NextAttemptAt.SetOptional(nil),
+			db.WebhookDelivery.Error.SetOptional(nil),
+		)
+	case attempts >= MaxAttempts:
+		params = append(params,
+			db.WebhookDelivery.Status.Set(StatusFailed),
+			db.WebhookDelivery.NextAttemptAt.SetOptional(nil),
+			db.WebhookDelivery.Error.Set(result.Err.Error()),
+		)
+	default:
+		params = append(params,
+			db.WebhookDelivery.NextAttemptAt.Set(now.Add(Backoff(attempts))),
+			db.WebhookDelivery.Error.Set(result.Err.Error()),
+		)
+	}
+
+	updated, err := d.client.WebhookDelivery.FindUnique(
+		db.WebhookDelivery.ID.Equals(delivery.ID),
+	).Update(params...).Exec(ctx)
+	if err != nil {
+		log.Printf("webhooks: failed to record attempt for delivery %s: %v", delivery.ID, err)
+		return delivery
+	}
+	return updated
+}
@@ -0,0 +1,64 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Event names users can subscribe a webhook to. Only events something
+// publishes belong here, so that Validate rejects filters that would never
+// match: achievement.unlocked joins them once achievements are stored.
+const (
+	EventTaskCompleted    = "task.completed"
+	EventSprintCompleted  = "sprint.completed"
+	EventSessionCompleted = "session.completed"
+
+	// EventTest is only sent by the "send test event" action and ignores filters.
+	EventTest = "webhook.test"
+
+	// EventAll subscribes a webhook to every event.
+	EventAll = "*"
+)
+
+// Events lists every event a webhook can filter on.
+var Events = []string{
+	EventTaskCompleted,
+	EventSprintCompleted,
+	EventSessionCompleted,
+}
+
+// IsValidEvent reports whether name can be used as a webhook filter.
+func IsValidEvent(name string) bool {
+	if name == EventAll {
+		return true
+	}
+	for _, event := range Events {
+		if event == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks a webhook's target URL and event filters. The URL's host
+// must not resolve to a loopback, private or link-local address; the sender
+// checks again on every delivery.
+func Validate(ctx context.Context, rawURL string, events []string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return fmt.Errorf("url must be an absolute http(s) URL")
+	}
+	if err := checkHost(ctx, parsed.Hostname()); err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("at least one event is required")
+	}
+	for _, event := range events {
+		if !IsValidEvent(event) {
+			return fmt.Errorf("unknown event %q", event)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderEvent     = "X-LifeQuest-Event"
+	HeaderDelivery  = "X-LifeQuest-Delivery"
+	HeaderSignature = "X-LifeQuest-Signature"
+	HeaderTimestamp = "X-LifeQuest-Timestamp"
+)
+
+// maxResponseBody caps how much of a receiver's response is kept in the delivery log.
+const maxResponseBody = 4096
+
+// Envelope is the JSON body posted to webhook endpoints.
+type Envelope struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+// Result describes a single delivery attempt.
+type Result struct {
+	StatusCode int
+	Body       string
+	Err        error
+}
+
+// OK reports whether the receiver accepted the delivery.
+func (r Result) OK() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Sender posts signed payloads to webhook endpoints.
+type Sender struct {
+	Client    *http.Client
+	UserAgent string
+}
+
+// NewSender returns a Sender with a bounded request timeout that refuses to
+// connect to loopback, private and link-local addresses, redirects included.
+// It ignores proxy settings, which would hide the address it connects to.
+func NewSender() *Sender {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: dialControl}
+	return &Sender{
+		Client: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		UserAgent: "LifeQuest-Webhooks/1.0",
+	}
+}
+
+// Send posts body to url, signed with secret along with the time it is sent.
+func (s *Sender) Send(ctx context.Context, url, secret, event, deliveryID string, body []byte) Result {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Result{Err: err}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.UserAgent)
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, deliveryID)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+	req.Header.Set(HeaderTimestamp, timestamp)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return Result{Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	result := Result{StatusCode: resp.StatusCode, Body: string(respBody)}
+	if !result.OK() {
+		result.Err = fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return result
+}
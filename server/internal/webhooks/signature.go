@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const signaturePrefix = "sha256="
+
+// Sign returns the value of the X-LifeQuest-Signature header for a delivery
+// of body sent at timestamp, the X-LifeQuest-Timestamp header: an HMAC-SHA256
+// of "<timestamp>.<body>" keyed with the webhook secret. Signing the
+// timestamp lets receivers reject old deliveries replayed at them.
+func Sign(secret, timestamp string, body []byte) string {
+	return sign(secret, []byte(timestamp+"."), body)
+}
+
+// Verify checks a "sha256=<hex>" signature made by Sign in constant time.
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return verify(Sign(secret, timestamp, body), signature)
+}
+
+// VerifyBody checks a "sha256=<hex>" HMAC-SHA256 of body alone, as GitHub
+// signs its deliveries, in constant time.
+func VerifyBody(secret string, body []byte, signature string) bool {
+	return verify(sign(secret, body), signature)
+}
+
+func sign(secret string, parts ...[]byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	for _, part := range parts {
+		mac.Write(part)
+	}
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func verify(want, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(want), []byte(signature))
+}
+
+// GenerateSecret returns a random hex-encoded secret for a new webhook.
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"task.completed"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("secret", "1700000000", body); got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"task.completed"}`)
+	signature := Sign("secret", "1700000000", body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		signature string
+		want      bool
+	}{
+		{"as sent", "secret", "1700000000", string(body), signature, true},
+		// A replay can't move the delivery to a fresh timestamp.
+		{"other timestamp", "secret", "1700003600", string(body), signature, false},
+		{"other body", "secret", "1700000000", `{"event":"session.completed"}`, signature, false},
+		{"other secret", "other", "1700000000", string(body), signature, false},
+		{"without prefix", "secret", "1700000000", string(body), signature[len(signaturePrefix):], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.timestamp, []byte(tt.body), tt.signature); got != tt.want {
+				t.Errorf("Verify = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// GitHub signs the body alone.
+func TestVerifyBody(t *testing.T) {
+	body := []byte(`{"zen":"Keep it logically awesome."}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !VerifyBody("secret", body, signature) {
+		t.Error("VerifyBody rejected a valid signature")
+	}
+	if VerifyBody("other", body, signature) {
+		t.Error("VerifyBody accepted a signature made with another secret")
+	}
+}
@@ -0,0 +1,179 @@
+// Package xp credits and debits user XP through the XP ledger.
+package xp
+
+import (
+	"context"
+
+	"lifequest-server/internal/db"
+)
+
+// PerLevel matches the client's level progression.
+const PerLevel = 500
+
+// Reasons recorded on XP ledger entries.
+const (
+	ReasonTaskCompleted    = "task.completed"
+	ReasonSubtaskCompleted = "subtask.completed"
+	ReasonSubtaskReopened  = "subtask.reopened"
+	ReasonStreakBonus      = "recurrence.streak" // extra XP for keeping a recurring task's streak
+	ReasonHabitCompleted   = "habit.completed"   // a habit's target reached for a period
+	ReasonHabitReopened    = "habit.reopened"    // a check-in undone below the target
+	ReasonTrashed          = "trash.moved"       // reverses what the source earned
+	ReasonRestored         = "trash.restored"    // credits it again
+)
+
+// Award adds amount to the user's current and lifetime XP, records it in the
+// XP ledger, and keeps the stored level in step with the new total.
+func Award(ctx context.Context, client *db.PrismaClient, userID string, amount int, reason, sourceID string) (*db.UserModel, error) {
+	txs := Adjust(client, userID, amount, reason, sourceID)
+	if err := client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return nil, err
+	}
+	return SyncLevel(ctx, client, userID)
+}
+
+// AwardSkill is Award for XP that also counts towards a skill category.
+func AwardSkill(ctx context.Context, client *db.PrismaClient, userID, category string, amount int, reason, sourceID string) (*db.UserModel, error) {
+	txs := adjust(client, userID, amount, reason, sourceID, db.XpEntry.Category.Set(category))
+	if err := client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return nil, err
+	}
+	return SyncLevel(ctx, client, userID)
+}
+
+// Adjust returns the writes that add amount, which may be negative, to the
+// user's XP and record it in the ledger, for callers that need them in a
+// larger transaction. Call SyncLevel once it has run.
+func Adjust(client *db.PrismaClient, userID string, amount int, reason, sourceID string) []db.PrismaTransaction {
+	return adjust(client, userID, amount, reason, sourceID)
+}
+
+func adjust(client *db.PrismaClient, userID string, amount int, reason, sourceID string, params ...db.XpEntrySetParam) []db.PrismaTransaction {
+	return []db.PrismaTransaction{
+		client.User.FindUnique(
+			db.User.ID.Equals(userID),
+		).Update(
+			db.User.Xp.Increment(amount),
+			db.User.TotalXp.Increment(amount),
+		).Tx(),
+		client.XpEntry.CreateOne(
+			db.XpEntry.Amount.Set(amount),
+			db.XpEntry.Reason.Set(reason),
+			db.XpEntry.User.Link(db.User.ID.Equals(userID)),
+			append([]db.XpEntrySetParam{db.XpEntry.SourceID.Set(sourceID)}, params...)...,
+		).Tx(),
+	}
+}
+
+// SyncLevel sets the user's level from their lifetime XP.
+func SyncLevel(ctx context.Context, client *db.PrismaClient, userID string) (*db.UserModel, error) {
+	user, err := client.User.FindUnique(
+		db.User.ID.Equals(userID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	level := max(user.TotalXp, 0)/PerLevel + 1
+	if level == user.Level {
+		return user, nil
+	}
+
+	return client.User.FindUnique(
+		db.User.ID.Equals(userID),
+	).Update(
+		db.User.Level.Set(level),
+	).Exec(ctx)
+}
+
+// Credits sums the ledger per source for sourceIDs: earned is what the
+// sources were awarded, held is how much of it the user currently has.
+// Sources whose XP was reversed when they were trashed hold nothing.
+func Credits(ctx context.Context, client *db.PrismaClient, userID string, sourceIDs []string) (earned, held map[string]int, err error) {
+	entries, err := client.XpEntry.FindMany(
+		db.XpEntry.UserID.Equals(userID),
+		db.XpEntry.SourceID.In(sourceIDs),
+	).Exec(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	earned, held = map[string]int{}, map[string]int{}
+	for _, entry := range entries {
+		sourceID, _ := entry.SourceID()
+		held[sourceID] += entry.Amount
+		if entry.Reason != ReasonTrashed && entry.Reason != ReasonRestored {
+			earned[sourceID] += entry.Amount
+		}
+	}
+	return earned, held, nil
+}
+
+// Holding is what one user earned from one source and how much of it they
+// currently hold.
+type Holding struct {
+	UserID   string
+	SourceID string
+	Earned   int
+	Held     int
+}
+
+// Holdings sums the ledger per user and source for sourceIDs, whoever was
+// credited: a collaborator who completed a task in someone else's project
+// holds its XP. Holdings are ordered as sourceIDs, then by user.
+func Holdings(ctx context.Context, client *db.PrismaClient, sourceIDs []string) ([]Holding, error) {
+	entries, err := client.XpEntry.FindMany(
+		db.XpEntry.SourceID.In(sourceIDs),
+	).OrderBy(
+		db.XpEntry.UserID.Order(db.SortOrderAsc),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ userID, sourceID string }
+	sums := map[key]*Holding{}
+	bySource := map[string][]*Holding{}
+	for _, entry := range entries {
+		sourceID, _ := entry.SourceID()
+		k := key{entry.UserID, sourceID}
+		holding := sums[k]
+		if holding == nil {
+			holding = &Holding{UserID: entry.UserID, SourceID: sourceID}
+			sums[k] = holding
+			bySource[sourceID] = append(bySource[sourceID], holding)
+		}
+		holding.Held += entry.Amount
+		if entry.Reason != ReasonTrashed && entry.Reason != ReasonRestored {
+			holding.Earned += entry.Amount
+		}
+	}
+
+	var result []Holding
+	for _, sourceID := range sourceIDs {
+		for _, holding := range bySource[sourceID] {
+			result = append(result, *holding)
+		}
+		delete(bySource, sourceID)
+	}
+	return result, nil
+}
+
+// ByCategory sums the user's XP per skill category. XP that counts towards
+// no category is left out.
+func ByCategory(ctx context.Context, client *db.PrismaClient, userID string) (map[string]int, error) {
+	entries, err := client.XpEntry.FindMany(
+		db.XpEntry.UserID.Equals(userID),
+		db.XpEntry.Not(db.XpEntry.Category.IsNull()),
+	).Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := map[string]int{}
+	for _, entry := range entries {
+		category, _ := entry.Category()
+		totals[category] += entry.Amount
+	}
+	return totals, nil
+}
@@ -0,0 +1,5 @@
+-- AlterTable
+ALTER TABLE "tasks" ADD COLUMN     "external_id" TEXT;
+
+-- CreateIndex
+CREATE UNIQUE INDEX "tasks_user_id_external_id_key" ON "tasks"("user_id", "external_id");
@@ -0,0 +1,11 @@
+-- AlterTable
+ALTER TABLE "folders" ADD COLUMN     "external_id" TEXT;
+
+-- AlterTable
+ALTER TABLE "projects" ADD COLUMN     "external_id" TEXT;
+
+-- CreateIndex
+CREATE UNIQUE INDEX "folders_user_id_external_id_key" ON "folders"("user_id", "external_id");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "projects_user_id_external_id_key" ON "projects"("user_id", "external_id");
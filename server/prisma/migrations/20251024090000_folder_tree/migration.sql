@@ -0,0 +1,8 @@
+-- AlterTable
+ALTER TABLE "folders" ADD COLUMN     "parent_id" TEXT;
+
+-- CreateIndex
+CREATE INDEX "folders_parent_id_idx" ON "folders"("parent_id");
+
+-- AddForeignKey
+ALTER TABLE "folders" ADD CONSTRAINT "folders_parent_id_fkey" FOREIGN KEY ("parent_id") REFERENCES "folders"("id") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,18 @@
+-- AlterTable
+ALTER TABLE "folders" ADD COLUMN     "archived_at" TIMESTAMP(3),
+ADD COLUMN     "deleted_at" TIMESTAMP(3);
+
+-- AlterTable
+ALTER TABLE "projects" ADD COLUMN     "deleted_at" TIMESTAMP(3);
+
+-- AlterTable
+ALTER TABLE "tasks" ADD COLUMN     "deleted_at" TIMESTAMP(3);
+
+-- CreateIndex
+CREATE INDEX "folders_user_id_deleted_at_idx" ON "folders"("user_id", "deleted_at");
+
+-- CreateIndex
+CREATE INDEX "projects_user_id_deleted_at_idx" ON "projects"("user_id", "deleted_at");
+
+-- CreateIndex
+CREATE INDEX "tasks_user_id_deleted_at_idx" ON "tasks"("user_id", "deleted_at");
@@ -0,0 +1,11 @@
+-- AlterTable
+ALTER TABLE "pomodoro_sessions" ADD COLUMN     "deleted_at" TIMESTAMP(3);
+
+-- AlterTable
+ALTER TABLE "sprints" ADD COLUMN     "deleted_at" TIMESTAMP(3);
+
+-- CreateIndex
+CREATE INDEX "pomodoro_sessions_user_id_deleted_at_idx" ON "pomodoro_sessions"("user_id", "deleted_at");
+
+-- CreateIndex
+CREATE INDEX "sprints_user_id_deleted_at_idx" ON "sprints"("user_id", "deleted_at");
@@ -0,0 +1,24 @@
+-- AlterTable
+ALTER TABLE "tasks" ADD COLUMN     "auto_complete" BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN     "subtask_count" INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN     "subtasks_done" INTEGER NOT NULL DEFAULT 0;
+
+-- CreateTable
+CREATE TABLE "subtasks" (
+    "id" TEXT NOT NULL,
+    "task_id" TEXT NOT NULL,
+    "title" TEXT NOT NULL,
+    "completed" BOOLEAN NOT NULL DEFAULT false,
+    "position" INTEGER NOT NULL DEFAULT 0,
+    "completed_at" TIMESTAMP(3),
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "subtasks_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "subtasks_task_id_position_idx" ON "subtasks"("task_id", "position");
+
+-- AddForeignKey
+ALTER TABLE "subtasks" ADD CONSTRAINT "subtasks_task_id_fkey" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE ON UPDATE CASCADE;
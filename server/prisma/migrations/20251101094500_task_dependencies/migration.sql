@@ -0,0 +1,21 @@
+-- CreateTable
+CREATE TABLE "task_dependencies" (
+    "id" TEXT NOT NULL,
+    "task_id" TEXT NOT NULL,
+    "depends_on_id" TEXT NOT NULL,
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "task_dependencies_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "task_dependencies_depends_on_id_idx" ON "task_dependencies"("depends_on_id");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "task_dependencies_task_id_depends_on_id_key" ON "task_dependencies"("task_id", "depends_on_id");
+
+-- AddForeignKey
+ALTER TABLE "task_dependencies" ADD CONSTRAINT "task_dependencies_task_id_fkey" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "task_dependencies" ADD CONSTRAINT "task_dependencies_depends_on_id_fkey" FOREIGN KEY ("depends_on_id") REFERENCES "tasks"("id") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,28 @@
+-- CreateTable
+CREATE TABLE "task_attachments" (
+    "id" TEXT NOT NULL,
+    "task_id" TEXT,
+    "user_id" TEXT NOT NULL,
+    "filename" TEXT NOT NULL,
+    "mime_type" TEXT NOT NULL,
+    "size" INTEGER NOT NULL,
+    "storage_key" TEXT NOT NULL,
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "task_attachments_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "task_attachments_storage_key_key" ON "task_attachments"("storage_key");
+
+-- CreateIndex
+CREATE INDEX "task_attachments_task_id_idx" ON "task_attachments"("task_id");
+
+-- CreateIndex
+CREATE INDEX "task_attachments_user_id_idx" ON "task_attachments"("user_id");
+
+-- AddForeignKey
+ALTER TABLE "task_attachments" ADD CONSTRAINT "task_attachments_task_id_fkey" FOREIGN KEY ("task_id") REFERENCES "tasks"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "task_attachments" ADD CONSTRAINT "task_attachments_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,31 @@
+-- AlterTable
+ALTER TABLE "tasks" ADD COLUMN     "recurrence_id" TEXT;
+
+-- CreateTable
+CREATE TABLE "task_recurrences" (
+    "id" TEXT NOT NULL,
+    "user_id" TEXT NOT NULL,
+    "rule" TEXT NOT NULL,
+    "after_completion" BOOLEAN NOT NULL DEFAULT false,
+    "starts_at" TIMESTAMP(3) NOT NULL,
+    "occurrences" INTEGER NOT NULL DEFAULT 1,
+    "current_streak" INTEGER NOT NULL DEFAULT 0,
+    "longest_streak" INTEGER NOT NULL DEFAULT 0,
+    "ended_at" TIMESTAMP(3),
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "task_recurrences_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "task_recurrences_user_id_idx" ON "task_recurrences"("user_id");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "tasks_recurrence_id_due_date_key" ON "tasks"("recurrence_id", "due_date");
+
+-- AddForeignKey
+ALTER TABLE "tasks" ADD CONSTRAINT "tasks_recurrence_id_fkey" FOREIGN KEY ("recurrence_id") REFERENCES "task_recurrences"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "task_recurrences" ADD CONSTRAINT "task_recurrences_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE;
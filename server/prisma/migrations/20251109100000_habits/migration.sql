@@ -0,0 +1,54 @@
+-- AlterTable
+ALTER TABLE "xp_ledger" ADD COLUMN     "category" TEXT;
+
+-- CreateTable
+CREATE TABLE "habits" (
+    "id" TEXT NOT NULL,
+    "user_id" TEXT NOT NULL,
+    "name" TEXT NOT NULL,
+    "description" TEXT,
+    "icon" TEXT,
+    "color" TEXT,
+    "category" TEXT NOT NULL DEFAULT 'personal',
+    "period" TEXT NOT NULL DEFAULT 'daily',
+    "target_count" INTEGER NOT NULL DEFAULT 1,
+    "xp_value" INTEGER NOT NULL DEFAULT 10,
+    "current_streak" INTEGER NOT NULL DEFAULT 0,
+    "longest_streak" INTEGER NOT NULL DEFAULT 0,
+    "archived_at" TIMESTAMP(3),
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "habits_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "habit_check_ins" (
+    "id" TEXT NOT NULL,
+    "habit_id" TEXT NOT NULL,
+    "user_id" TEXT NOT NULL,
+    "date" TIMESTAMP(3) NOT NULL,
+    "count" INTEGER NOT NULL DEFAULT 1,
+    "note" TEXT,
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "habit_check_ins_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "habits_user_id_idx" ON "habits"("user_id");
+
+-- CreateIndex
+CREATE INDEX "habit_check_ins_habit_id_date_idx" ON "habit_check_ins"("habit_id", "date");
+
+-- CreateIndex
+CREATE INDEX "habit_check_ins_user_id_date_idx" ON "habit_check_ins"("user_id", "date");
+
+-- AddForeignKey
+ALTER TABLE "habits" ADD CONSTRAINT "habits_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "habit_check_ins" ADD CONSTRAINT "habit_check_ins_habit_id_fkey" FOREIGN KEY ("habit_id") REFERENCES "habits"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "habit_check_ins" ADD CONSTRAINT "habit_check_ins_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE;
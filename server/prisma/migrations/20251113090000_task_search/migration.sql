@@ -0,0 +1,42 @@
+-- AlterTable
+ALTER TABLE "tasks" ADD COLUMN     "search_vector" tsvector;
+
+-- CreateIndex
+CREATE INDEX "tasks_search_vector_idx" ON "tasks" USING GIN ("search_vector");
+
+-- Weights rank title matches above description matches above comment matches.
+CREATE FUNCTION task_search_vector(title TEXT, description TEXT, task_id TEXT) RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('english', coalesce($1, '')), 'A')
+        || setweight(to_tsvector('english', coalesce($2, '')), 'B')
+        || setweight(to_tsvector('english', coalesce(
+            (SELECT string_agg("body", ' ') FROM "task_comments" WHERE "task_id" = $3), '')), 'C');
+$$ LANGUAGE sql STABLE;
+
+CREATE FUNCTION tasks_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW."search_vector" := task_search_vector(NEW."title", NEW."description", NEW."id");
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "tasks_search_vector_update"
+    BEFORE INSERT OR UPDATE OF "title", "description" ON "tasks"
+    FOR EACH ROW EXECUTE FUNCTION tasks_search_vector_update();
+
+CREATE FUNCTION task_comments_search_vector_update() RETURNS trigger AS $$
+DECLARE
+    changed TEXT := CASE WHEN TG_OP = 'DELETE' THEN OLD."task_id" ELSE NEW."task_id" END;
+BEGIN
+    UPDATE "tasks"
+    SET "search_vector" = task_search_vector("title", "description", "id")
+    WHERE "id" = changed;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "task_comments_search_vector_update"
+    AFTER INSERT OR UPDATE OF "body" OR DELETE ON "task_comments"
+    FOR EACH ROW EXECUTE FUNCTION task_comments_search_vector_update();
+
+-- Backfill
+UPDATE "tasks" SET "search_vector" = task_search_vector("title", "description", "id");
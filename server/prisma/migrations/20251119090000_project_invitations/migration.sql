@@ -0,0 +1,45 @@
+-- The owner of a project is projects.user_id; collaborator rows hold the
+-- other roles only.
+UPDATE "project_collaborators" SET "role" = 'admin' WHERE "role" = 'owner';
+DELETE FROM "project_collaborators" c USING "projects" p WHERE c."project_id" = p."id" AND c."user_id" = p."user_id";
+ALTER TABLE "project_collaborators" ADD CONSTRAINT "project_collaborators_role_check" CHECK ("role" IN ('admin', 'member', 'viewer'));
+
+-- CreateTable
+CREATE TABLE "project_invitations" (
+    "id" TEXT NOT NULL,
+    "project_id" TEXT NOT NULL,
+    "invited_by_id" TEXT NOT NULL,
+    "invitee_id" TEXT,
+    "email" TEXT NOT NULL,
+    "role" TEXT NOT NULL DEFAULT 'member',
+    "token_hash" TEXT NOT NULL,
+    "status" TEXT NOT NULL DEFAULT 'pending',
+    "expires_at" TIMESTAMP(3) NOT NULL,
+    "responded_at" TIMESTAMP(3),
+    "created_at" TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMP(3) NOT NULL,
+
+    CONSTRAINT "project_invitations_pkey" PRIMARY KEY ("id"),
+    CONSTRAINT "project_invitations_role_check" CHECK ("role" IN ('admin', 'member', 'viewer'))
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "project_invitations_token_hash_key" ON "project_invitations"("token_hash");
+
+-- CreateIndex
+CREATE INDEX "project_invitations_project_id_status_idx" ON "project_invitations"("project_id", "status");
+
+-- CreateIndex
+CREATE INDEX "project_invitations_email_status_idx" ON "project_invitations"("email", "status");
+
+-- CreateIndex
+CREATE INDEX "project_invitations_invitee_id_status_idx" ON "project_invitations"("invitee_id", "status");
+
+-- AddForeignKey
+ALTER TABLE "project_invitations" ADD CONSTRAINT "project_invitations_project_id_fkey" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "project_invitations" ADD CONSTRAINT "project_invitations_invited_by_id_fkey" FOREIGN KEY ("invited_by_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "project_invitations" ADD CONSTRAINT "project_invitations_invitee_id_fkey" FOREIGN KEY ("invitee_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE;